@@ -0,0 +1,252 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/factory"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/mapper"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/service"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
+)
+
+// CatalogController serves the admin API for subscription types, plans and
+// coupons.
+type CatalogController struct {
+	catalogService *service.CatalogService
+	logger         logrus.FieldLogger
+}
+
+func NewCatalogController(catalogService *service.CatalogService) *CatalogController {
+	return &CatalogController{
+		catalogService: catalogService,
+		logger:         factory.NewModuleLogger("catalog-controller"),
+	}
+}
+
+func (c *CatalogController) CreateSubscriptionType(ctx echo.Context) error {
+	req, err := types.NewCreateSubscriptionTypeRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.catalogService.CreateSubscriptionType(ctx.Request().Context(), req)
+	if err != nil {
+		return c.handleError(ctx, err, "Create subscription type failed")
+	}
+
+	return ctx.JSON(http.StatusCreated, &types.SubscriptionTypeResponse{
+		SubscriptionType: mapper.SubscriptionTypeToProto(item),
+	})
+}
+
+func (c *CatalogController) UpdateSubscriptionType(ctx echo.Context) error {
+	req, err := types.NewUpdateSubscriptionTypeRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.catalogService.UpdateSubscriptionType(ctx.Request().Context(), req)
+	if err != nil {
+		return c.handleError(ctx, err, "Update subscription type failed")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.SubscriptionTypeResponse{
+		SubscriptionType: mapper.SubscriptionTypeToProto(item),
+	})
+}
+
+func (c *CatalogController) ArchiveSubscriptionType(ctx echo.Context) error {
+	req, err := types.NewArchiveSubscriptionTypeRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.catalogService.ArchiveSubscriptionType(ctx.Request().Context(), req.GetId())
+	if err != nil {
+		return c.handleError(ctx, err, "Archive subscription type failed")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.SubscriptionTypeResponse{
+		SubscriptionType: mapper.SubscriptionTypeToProto(item),
+	})
+}
+
+func (c *CatalogController) CreatePlanType(ctx echo.Context) error {
+	req, err := types.NewCreatePlanTypeRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.catalogService.CreatePlanType(ctx.Request().Context(), req)
+	if err != nil {
+		return c.handleError(ctx, err, "Create plan type failed")
+	}
+
+	return ctx.JSON(http.StatusCreated, &types.PlanTypeResponse{
+		PlanType: mapper.PlanTypeToProto(item),
+	})
+}
+
+func (c *CatalogController) UpdatePlanType(ctx echo.Context) error {
+	req, err := types.NewUpdatePlanTypeRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.catalogService.UpdatePlanType(ctx.Request().Context(), req)
+	if err != nil {
+		return c.handleError(ctx, err, "Update plan type failed")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.PlanTypeResponse{
+		PlanType: mapper.PlanTypeToProto(item),
+	})
+}
+
+func (c *CatalogController) ArchivePlanType(ctx echo.Context) error {
+	req, err := types.NewArchivePlanTypeRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.catalogService.ArchivePlanType(ctx.Request().Context(), req.GetId())
+	if err != nil {
+		return c.handleError(ctx, err, "Archive plan type failed")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.PlanTypeResponse{
+		PlanType: mapper.PlanTypeToProto(item),
+	})
+}
+
+func (c *CatalogController) CreateCoupon(ctx echo.Context) error {
+	req, err := types.NewCreateCouponRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.catalogService.CreateCoupon(ctx.Request().Context(), req)
+	if err != nil {
+		return c.handleError(ctx, err, "Create coupon failed")
+	}
+
+	return ctx.JSON(http.StatusCreated, &types.CouponResponse{
+		Coupon: mapper.CouponToProto(item),
+	})
+}
+
+func (c *CatalogController) GetCoupon(ctx echo.Context) error {
+	req, err := types.NewGetCouponRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.catalogService.GetCoupon(ctx.Request().Context(), req.GetId())
+	if err != nil {
+		return c.handleError(ctx, err, "Get coupon failed")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.CouponResponse{
+		Coupon: mapper.CouponToProto(item),
+	})
+}
+
+func (c *CatalogController) ListCoupons(ctx echo.Context) error {
+	items, err := c.catalogService.ListCoupons(ctx.Request().Context())
+	if err != nil {
+		return c.handleError(ctx, err, "List coupons failed")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.ListCouponsResponse{
+		Coupons: mapper.CouponsToProto(items),
+	})
+}
+
+func (c *CatalogController) UpdateCoupon(ctx echo.Context) error {
+	req, err := types.NewUpdateCouponRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.catalogService.UpdateCoupon(ctx.Request().Context(), req)
+	if err != nil {
+		return c.handleError(ctx, err, "Update coupon failed")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.CouponResponse{
+		Coupon: mapper.CouponToProto(item),
+	})
+}
+
+func (c *CatalogController) ListCouponRedemptions(ctx echo.Context) error {
+	req, err := types.NewListCouponRedemptionsRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	items, err := c.catalogService.ListCouponRedemptions(ctx.Request().Context(), req.GetCouponId())
+	if err != nil {
+		return c.handleError(ctx, err, "List coupon redemptions failed")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.ListCouponRedemptionsResponse{
+		CouponRedemptions: mapper.CouponRedemptionsToProto(items),
+	})
+}
+
+func (c *CatalogController) handleError(ctx echo.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidRequest), errors.Is(err, service.ErrInvalidStatus), errors.Is(err, service.ErrNoFieldsToUpdate):
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	case errors.Is(err, service.ErrSubscriptionTypeNotFound):
+		return c.writeError(ctx, http.StatusNotFound, "subscription type not found")
+	case errors.Is(err, service.ErrPlanTypeNotFound):
+		return c.writeError(ctx, http.StatusNotFound, "plan type not found")
+	case errors.Is(err, service.ErrPlanCodeAlreadyExists):
+		return c.writeError(ctx, http.StatusConflict, "plan code already exists")
+	case errors.Is(err, service.ErrCouponNotFound):
+		return c.writeError(ctx, http.StatusNotFound, "coupon not found")
+	case errors.Is(err, service.ErrCouponCodeAlreadyExists):
+		return c.writeError(ctx, http.StatusConflict, "coupon code already exists")
+	default:
+		c.logger.WithError(err).Error(message)
+		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+	}
+}
+
+func (c *CatalogController) writeError(ctx echo.Context, statusCode int, message string) error {
+	return ctx.JSON(statusCode, &types.ErrorResponse{Error: message})
+}
@@ -7,14 +7,16 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"github.com/sirupsen/logrus"
+	authmiddleware "github.com/vibast-solutions/lib-go-auth/middleware"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/factory"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/mapper"
+	appmiddleware "github.com/vibast-solutions/ms-go-subscriptions/app/middleware"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/service"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
 )
 
 type paymentCallbackService interface {
-	PaymentCallback(ctx context.Context, req *types.PaymentCallbackRequest) error
+	PaymentCallback(ctx context.Context, req *types.PaymentCallbackRequest) (*service.PaymentCallbackResult, error)
 }
 
 type SubscriptionController struct {
@@ -61,6 +63,49 @@ func (c *SubscriptionController) ListSubscriptionTypes(ctx echo.Context) error {
 	})
 }
 
+func (c *SubscriptionController) ListPlanTypes(ctx echo.Context) error {
+	req, err := types.NewListPlanTypesRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid query params")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	items, err := c.subscriptionService.ListPlanTypes(ctx.Request().Context(), req)
+	if err != nil {
+		c.logger.WithError(err).Error("List plan types failed")
+		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.ListPlanTypesResponse{
+		PlanTypes: mapper.PlanTypesToProto(items),
+	})
+}
+
+func (c *SubscriptionController) GetPlanType(ctx echo.Context) error {
+	req, err := types.NewGetPlanTypeRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.subscriptionService.GetPlanType(ctx.Request().Context(), req.GetId())
+	if err != nil {
+		if errors.Is(err, service.ErrPlanTypeNotFound) {
+			return c.writeError(ctx, http.StatusNotFound, "plan type not found")
+		}
+		c.logger.WithError(err).Error("Get plan type failed")
+		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.PlanTypeResponse{
+		PlanType: mapper.PlanTypeToProto(item),
+	})
+}
+
 func (c *SubscriptionController) CreateSubscription(ctx echo.Context) error {
 	req, err := types.NewCreateSubscriptionRequestFromContext(ctx)
 	if err != nil {
@@ -70,15 +115,21 @@ func (c *SubscriptionController) CreateSubscription(ctx echo.Context) error {
 		return c.writeError(ctx, http.StatusBadRequest, err.Error())
 	}
 
-	result, err := c.subscriptionService.CreateSubscription(ctx.Request().Context(), req)
+	result, err := c.subscriptionService.CreateSubscription(actorContext(ctx), req)
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrInvalidRequest), errors.Is(err, service.ErrStartAtRequired):
 			return c.writeError(ctx, http.StatusBadRequest, err.Error())
 		case errors.Is(err, service.ErrSubscriptionTypeNotFound):
 			return c.writeError(ctx, http.StatusNotFound, "subscription type not found")
+		case errors.Is(err, service.ErrPlanTypeNotFound):
+			return c.writeError(ctx, http.StatusNotFound, "plan type not found")
+		case errors.Is(err, service.ErrCouponNotFound):
+			return c.writeError(ctx, http.StatusNotFound, "coupon not found")
 		case errors.Is(err, service.ErrSubscriptionAlreadyExists):
 			return c.writeError(ctx, http.StatusConflict, "subscription already exists")
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrConcurrentModification), errors.Is(err, service.ErrCouponNotRedeemable):
+			return c.writeError(ctx, http.StatusConflict, err.Error())
 		default:
 			c.logger.WithError(err).Error("Create subscription failed")
 			return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
@@ -109,6 +160,7 @@ func (c *SubscriptionController) GetSubscription(ctx echo.Context) error {
 		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
 	}
 
+	ctx.Response().Header().Set("ETag", types.SubscriptionETag(item.Version))
 	return ctx.JSON(http.StatusOK, &types.SubscriptionEnvelopeResponse{
 		Subscription: mapper.SubscriptionToProto(item),
 	})
@@ -117,20 +169,24 @@ func (c *SubscriptionController) GetSubscription(ctx echo.Context) error {
 func (c *SubscriptionController) ListSubscriptions(ctx echo.Context) error {
 	req, err := types.NewListSubscriptionsRequestFromContext(ctx)
 	if err != nil {
-		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+		return c.writeError(ctx, http.StatusBadRequest, "invalid query params")
 	}
 	if err := req.Validate(); err != nil {
 		return c.writeError(ctx, http.StatusBadRequest, err.Error())
 	}
 
-	items, err := c.subscriptionService.ListSubscriptions(ctx.Request().Context(), req)
+	result, err := c.subscriptionService.ListSubscriptions(ctx.Request().Context(), req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidRequest) || errors.Is(err, service.ErrInvalidPageToken) {
+			return c.writeError(ctx, http.StatusBadRequest, err.Error())
+		}
 		c.logger.WithError(err).Error("List subscriptions failed")
 		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
 	}
 
 	return ctx.JSON(http.StatusOK, &types.ListSubscriptionsResponse{
-		Subscriptions: mapper.SubscriptionsToProto(items),
+		Subscriptions: mapper.SubscriptionsToProto(result.Subscriptions),
+		NextPageToken: result.NextPageToken,
 	})
 }
 
@@ -143,19 +199,22 @@ func (c *SubscriptionController) UpdateSubscription(ctx echo.Context) error {
 		return c.writeError(ctx, http.StatusBadRequest, err.Error())
 	}
 
-	item, err := c.subscriptionService.UpdateSubscription(ctx.Request().Context(), req)
+	item, err := c.subscriptionService.UpdateSubscription(actorContext(ctx), req)
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrInvalidStatus), errors.Is(err, service.ErrNoFieldsToUpdate):
 			return c.writeError(ctx, http.StatusBadRequest, err.Error())
 		case errors.Is(err, service.ErrSubscriptionNotFound):
 			return c.writeError(ctx, http.StatusNotFound, "subscription not found")
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrConcurrentModification):
+			return c.writeError(ctx, http.StatusConflict, err.Error())
 		default:
 			c.logger.WithError(err).Error("Update subscription failed")
 			return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
 		}
 	}
 
+	ctx.Response().Header().Set("ETag", types.SubscriptionETag(item.Version))
 	return ctx.JSON(http.StatusOK, &types.SubscriptionEnvelopeResponse{
 		Subscription: mapper.SubscriptionToProto(item),
 	})
@@ -170,15 +229,20 @@ func (c *SubscriptionController) DeleteSubscription(ctx echo.Context) error {
 		return c.writeError(ctx, http.StatusBadRequest, err.Error())
 	}
 
-	item, err := c.subscriptionService.DeleteSubscription(ctx.Request().Context(), req.GetId())
+	item, err := c.subscriptionService.DeleteSubscription(actorContext(ctx), req)
 	if err != nil {
-		if errors.Is(err, service.ErrSubscriptionNotFound) {
+		switch {
+		case errors.Is(err, service.ErrSubscriptionNotFound):
 			return c.writeError(ctx, http.StatusNotFound, "subscription not found")
+		case errors.Is(err, service.ErrConcurrentModification):
+			return c.writeError(ctx, http.StatusConflict, err.Error())
+		default:
+			c.logger.WithError(err).Error("Delete subscription failed")
+			return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
 		}
-		c.logger.WithError(err).Error("Delete subscription failed")
-		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
 	}
 
+	ctx.Response().Header().Set("ETag", types.SubscriptionETag(item.Version))
 	return ctx.JSON(http.StatusOK, &types.MessageResponse{
 		Message:      "Subscription deleted successfully",
 		Subscription: mapper.SubscriptionToProto(item),
@@ -187,6 +251,39 @@ func (c *SubscriptionController) DeleteSubscription(ctx echo.Context) error {
 
 func (c *SubscriptionController) CancelSubscription(ctx echo.Context) error {
 	req, err := types.NewCancelSubscriptionRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	result, err := c.subscriptionService.CancelSubscription(actorContext(ctx), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest):
+			return c.writeError(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrSubscriptionNotFound):
+			return c.writeError(ctx, http.StatusNotFound, "subscription not found")
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrConcurrentModification):
+			return c.writeError(ctx, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrPaymentDeclined):
+			return c.writeError(ctx, http.StatusPaymentRequired, err.Error())
+		default:
+			c.logger.WithError(err).Error("Cancel subscription failed")
+			return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, &types.CancelSubscriptionResponse{
+		Message:             "Subscription cancelled successfully",
+		Subscription:        mapper.SubscriptionToProto(result.Subscription),
+		RefundedAmountCents: result.RefundedAmountCents,
+	})
+}
+
+func (c *SubscriptionController) UndoCancellation(ctx echo.Context) error {
+	req, err := types.NewUndoCancellationRequestFromContext(ctx)
 	if err != nil {
 		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
 	}
@@ -194,21 +291,153 @@ func (c *SubscriptionController) CancelSubscription(ctx echo.Context) error {
 		return c.writeError(ctx, http.StatusBadRequest, err.Error())
 	}
 
-	item, err := c.subscriptionService.CancelSubscription(ctx.Request().Context(), req.GetId())
+	item, err := c.subscriptionService.UndoCancellation(actorContext(ctx), req.GetId())
 	if err != nil {
-		if errors.Is(err, service.ErrSubscriptionNotFound) {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest):
+			return c.writeError(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrSubscriptionNotFound):
 			return c.writeError(ctx, http.StatusNotFound, "subscription not found")
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrConcurrentModification):
+			return c.writeError(ctx, http.StatusConflict, err.Error())
+		default:
+			c.logger.WithError(err).Error("Undo cancellation failed")
+			return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
 		}
-		c.logger.WithError(err).Error("Cancel subscription failed")
-		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
 	}
 
 	return ctx.JSON(http.StatusOK, &types.MessageResponse{
-		Message:      "Subscription cancelled successfully",
+		Message:      "Subscription cancellation undone",
+		Subscription: mapper.SubscriptionToProto(item),
+	})
+}
+
+func (c *SubscriptionController) PauseSubscription(ctx echo.Context) error {
+	req, err := types.NewPauseSubscriptionRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.subscriptionService.PauseSubscription(actorContext(ctx), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest):
+			return c.writeError(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrSubscriptionNotFound):
+			return c.writeError(ctx, http.StatusNotFound, "subscription not found")
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrConcurrentModification):
+			return c.writeError(ctx, http.StatusConflict, err.Error())
+		default:
+			c.logger.WithError(err).Error("Pause subscription failed")
+			return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, &types.MessageResponse{
+		Message:      "Subscription paused successfully",
+		Subscription: mapper.SubscriptionToProto(item),
+	})
+}
+
+func (c *SubscriptionController) ResumeSubscription(ctx echo.Context) error {
+	req, err := types.NewResumeSubscriptionRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.subscriptionService.ResumeSubscription(actorContext(ctx), req.GetId())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSubscriptionNotFound):
+			return c.writeError(ctx, http.StatusNotFound, "subscription not found")
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrConcurrentModification):
+			return c.writeError(ctx, http.StatusConflict, err.Error())
+		default:
+			c.logger.WithError(err).Error("Resume subscription failed")
+			return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, &types.MessageResponse{
+		Message:      "Subscription resumed successfully",
 		Subscription: mapper.SubscriptionToProto(item),
 	})
 }
 
+func (c *SubscriptionController) ChangePlan(ctx echo.Context) error {
+	req, err := types.NewChangePlanRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	result, err := c.subscriptionService.ChangePlan(actorContext(ctx), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest):
+			return c.writeError(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrSubscriptionNotFound):
+			return c.writeError(ctx, http.StatusNotFound, "subscription not found")
+		case errors.Is(err, service.ErrPlanTypeNotFound):
+			return c.writeError(ctx, http.StatusNotFound, "plan type not found")
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrConcurrentModification):
+			return c.writeError(ctx, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrPaymentDeclined):
+			return c.writeError(ctx, http.StatusPaymentRequired, err.Error())
+		default:
+			c.logger.WithError(err).Error("Change plan failed")
+			return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, &types.ChangePlanResponse{
+		Subscription:        mapper.SubscriptionToProto(result.Subscription),
+		ProratedAmountCents: result.ProratedAmountCents,
+		PaymentUrl:          result.PaymentURL,
+	})
+}
+
+func (c *SubscriptionController) UpdateQuantity(ctx echo.Context) error {
+	req, err := types.NewUpdateQuantityRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	result, err := c.subscriptionService.UpdateQuantity(actorContext(ctx), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest):
+			return c.writeError(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrSubscriptionNotFound):
+			return c.writeError(ctx, http.StatusNotFound, "subscription not found")
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrConcurrentModification):
+			return c.writeError(ctx, http.StatusConflict, err.Error())
+		case errors.Is(err, service.ErrPaymentDeclined):
+			return c.writeError(ctx, http.StatusPaymentRequired, err.Error())
+		default:
+			c.logger.WithError(err).Error("Update quantity failed")
+			return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, &types.UpdateQuantityResponse{
+		Subscription:        mapper.SubscriptionToProto(result.Subscription),
+		ProratedAmountCents: result.ProratedAmountCents,
+		PaymentUrl:          result.PaymentURL,
+	})
+}
+
 func (c *SubscriptionController) PaymentCallback(ctx echo.Context) error {
 	req, err := types.NewPaymentCallbackRequestFromContext(ctx)
 	if err != nil {
@@ -218,19 +447,90 @@ func (c *SubscriptionController) PaymentCallback(ctx echo.Context) error {
 		return c.writeError(ctx, http.StatusBadRequest, err.Error())
 	}
 
-	if err := c.paymentCallbackService.PaymentCallback(ctx.Request().Context(), req); err != nil {
+	result, err := c.paymentCallbackService.PaymentCallback(actorContext(ctx), req)
+	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrInvalidRequest):
 			return c.writeError(ctx, http.StatusBadRequest, err.Error())
 		case errors.Is(err, service.ErrSubscriptionNotFound):
 			return c.writeError(ctx, http.StatusNotFound, "subscription not found")
+		case errors.Is(err, service.ErrPaymentAttemptNotFound):
+			return c.writeError(ctx, http.StatusNotFound, "payment attempt not found")
+		case errors.Is(err, service.ErrPaymentAttemptSuperseded), errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrConcurrentModification):
+			return c.writeError(ctx, http.StatusConflict, err.Error())
 		default:
 			c.logger.WithError(err).Error("Payment callback failed")
 			return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
 		}
 	}
 
-	return ctx.JSON(http.StatusOK, &types.MessageResponse{Message: "Payment processed successfully"})
+	message := "Payment processed successfully"
+	if result.AlreadyProcessed {
+		message = "Payment already processed"
+	}
+	return ctx.JSON(http.StatusOK, &types.PaymentCallbackResponse{
+		Message:          message,
+		Subscription:     mapper.SubscriptionToProto(result.Subscription),
+		AlreadyProcessed: result.AlreadyProcessed,
+	})
+}
+
+func (c *SubscriptionController) ListPaymentAttempts(ctx echo.Context) error {
+	req, err := types.NewListPaymentAttemptsRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	items, err := c.subscriptionService.ListPaymentAttempts(ctx.Request().Context(), req.GetSubscriptionId())
+	if err != nil {
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			return c.writeError(ctx, http.StatusNotFound, "subscription not found")
+		}
+		c.logger.WithError(err).Error("List payment attempts failed")
+		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.ListPaymentAttemptsResponse{
+		PaymentAttempts: mapper.PaymentAttemptsToProto(items),
+	})
+}
+
+func (c *SubscriptionController) ListSubscriptionEvents(ctx echo.Context) error {
+	req, err := types.NewListSubscriptionEventsRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	items, err := c.subscriptionService.ListSubscriptionEvents(ctx.Request().Context(), req.GetSubscriptionId())
+	if err != nil {
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			return c.writeError(ctx, http.StatusNotFound, "subscription not found")
+		}
+		c.logger.WithError(err).Error("List subscription events failed")
+		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.ListSubscriptionEventsResponse{
+		SubscriptionEvents: mapper.SubscriptionEventsToProto(items),
+	})
+}
+
+// actorContext attributes subscription changes made by the request to the signed
+// webhook provider or, failing that, to the authenticated internal caller.
+func actorContext(ctx echo.Context) context.Context {
+	actor := service.Actor{RequestID: ctx.Response().Header().Get(echo.HeaderXRequestID)}
+	if provider := appmiddleware.WebhookProviderFromContext(ctx); provider != "" {
+		actor.Name = "payment_callback:" + provider
+	} else if caller, err := authmiddleware.CallerServiceFromContext(ctx); err == nil {
+		actor.Name = caller
+	}
+	return service.WithActor(ctx.Request().Context(), actor)
 }
 
 func (c *SubscriptionController) writeError(ctx echo.Context, statusCode int, message string) error {
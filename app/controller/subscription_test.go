@@ -12,6 +12,7 @@ import (
 	"github.com/labstack/echo/v4"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/service"
 	"github.com/vibast-solutions/ms-go-subscriptions/config"
 )
@@ -21,7 +22,7 @@ type controllerSubRepo struct {
 	updateFn                func(ctx context.Context, subscription *entity.Subscription) error
 	findByIDFn              func(ctx context.Context, id uint64) (*entity.Subscription, error)
 	findByTypeAndIdentityFn func(ctx context.Context, subscriptionTypeID uint64, userID, email *string) (*entity.Subscription, error)
-	listFn                  func(ctx context.Context, userID, email string) ([]*entity.Subscription, error)
+	listFn                  func(ctx context.Context, filter repository.SubscriptionFilter) ([]*entity.Subscription, error)
 }
 
 func (r *controllerSubRepo) Create(ctx context.Context, subscription *entity.Subscription) error {
@@ -52,25 +53,37 @@ func (r *controllerSubRepo) FindByTypeAndIdentity(ctx context.Context, subscript
 	return nil, nil
 }
 
-func (r *controllerSubRepo) List(ctx context.Context, userID, email string) ([]*entity.Subscription, error) {
+func (r *controllerSubRepo) List(ctx context.Context, filter repository.SubscriptionFilter) ([]*entity.Subscription, error) {
 	if r.listFn != nil {
-		return r.listFn(ctx, userID, email)
+		return r.listFn(ctx, filter)
 	}
 	return nil, nil
 }
 
-func (r *controllerSubRepo) ListDueAutoRenew(context.Context, time.Time) ([]*entity.Subscription, error) {
+func (r *controllerSubRepo) ClaimDueAutoRenew(context.Context, time.Time, repository.SubscriptionClaim) ([]*entity.Subscription, error) {
 	return nil, nil
 }
 
-func (r *controllerSubRepo) ListPendingPaymentStale(context.Context, time.Time) ([]*entity.Subscription, error) {
+func (r *controllerSubRepo) ClaimPendingPaymentStale(context.Context, time.Time, repository.SubscriptionClaim) ([]*entity.Subscription, error) {
 	return nil, nil
 }
 
-func (r *controllerSubRepo) ListExpiredActive(context.Context, time.Time) ([]*entity.Subscription, error) {
+func (r *controllerSubRepo) ClaimExpiredActive(context.Context, time.Time, repository.SubscriptionClaim) ([]*entity.Subscription, error) {
 	return nil, nil
 }
 
+func (r *controllerSubRepo) ClaimDueResume(context.Context, time.Time, repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
+func (r *controllerSubRepo) ReleaseClaims(context.Context, string) error {
+	return nil
+}
+
+func (r *controllerSubRepo) HasUsedTrial(context.Context, uint64, *string, *string) (bool, error) {
+	return false, nil
+}
+
 type controllerSubTypeRepo struct {
 	listFn     func(ctx context.Context, typeFilter string, hasStatus bool, status int32) ([]*entity.SubscriptionType, error)
 	findByIDFn func(ctx context.Context, id uint64) (*entity.SubscriptionType, error)
@@ -91,21 +104,135 @@ func (r *controllerSubTypeRepo) FindByID(ctx context.Context, id uint64) (*entit
 }
 
 type controllerPlanTypeRepo struct {
-	findFn func(ctx context.Context, subscriptionTypeID uint64) (*entity.PlanType, error)
+	listFn func(ctx context.Context, subscriptionTypeID uint64) ([]*entity.PlanType, error)
+}
+
+func (r *controllerPlanTypeRepo) List(ctx context.Context, subscriptionTypeID uint64) ([]*entity.PlanType, error) {
+	if r.listFn != nil {
+		return r.listFn(ctx, subscriptionTypeID)
+	}
+	return nil, nil
+}
+
+func (r *controllerPlanTypeRepo) FindByID(context.Context, uint64) (*entity.PlanType, error) {
+	return nil, nil
+}
+
+type controllerPaymentAttemptRepo struct {
+	listFn func(ctx context.Context, subscriptionID uint64) ([]*entity.PaymentAttempt, error)
+}
+
+func (r *controllerPaymentAttemptRepo) Create(_ context.Context, attempt *entity.PaymentAttempt) error {
+	attempt.ID = 1
+	return nil
+}
+
+func (r *controllerPaymentAttemptRepo) Update(context.Context, *entity.PaymentAttempt) error {
+	return nil
+}
+
+func (r *controllerPaymentAttemptRepo) CompletePending(context.Context, *entity.PaymentAttempt) error {
+	return nil
 }
 
-func (r *controllerPlanTypeRepo) FindBySubscriptionTypeID(ctx context.Context, subscriptionTypeID uint64) (*entity.PlanType, error) {
-	if r.findFn != nil {
-		return r.findFn(ctx, subscriptionTypeID)
+func (r *controllerPaymentAttemptRepo) SupersedePending(context.Context, uint64, string, uint64, time.Time) error {
+	return nil
+}
+
+func (r *controllerPaymentAttemptRepo) FindByTransactionID(context.Context, string) (*entity.PaymentAttempt, error) {
+	return nil, nil
+}
+
+func (r *controllerPaymentAttemptRepo) ListBySubscriptionID(ctx context.Context, subscriptionID uint64) ([]*entity.PaymentAttempt, error) {
+	if r.listFn != nil {
+		return r.listFn(ctx, subscriptionID)
 	}
 	return nil, nil
 }
 
+type controllerOutboxMessageRepo struct{}
+
+func (r *controllerOutboxMessageRepo) Create(context.Context, *entity.OutboxMessage) error {
+	return nil
+}
+
+func (r *controllerOutboxMessageRepo) Update(context.Context, *entity.OutboxMessage) error {
+	return nil
+}
+
+func (r *controllerOutboxMessageRepo) ClaimDue(context.Context, repository.OutboxClaim) ([]*entity.OutboxMessage, error) {
+	return nil, nil
+}
+
+func (r *controllerOutboxMessageRepo) ReleaseClaims(context.Context, string) error {
+	return nil
+}
+
+type controllerTxManager struct{}
+
+func (m *controllerTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type controllerCouponRepo struct{}
+
+func (r *controllerCouponRepo) FindByID(context.Context, uint64) (*entity.Coupon, error) {
+	return nil, nil
+}
+
+func (r *controllerCouponRepo) FindByCode(context.Context, string) (*entity.Coupon, error) {
+	return nil, nil
+}
+
+func (r *controllerCouponRepo) IncrementRedemptions(context.Context, uint64, time.Time) error {
+	return nil
+}
+
+func (r *controllerCouponRepo) DecrementRedemptions(context.Context, uint64, time.Time) error {
+	return nil
+}
+
+type controllerCouponRedemptionRepo struct{}
+
+func (r *controllerCouponRedemptionRepo) Create(context.Context, *entity.CouponRedemption) error {
+	return nil
+}
+
+func (r *controllerCouponRedemptionRepo) Update(context.Context, *entity.CouponRedemption) error {
+	return nil
+}
+
+func (r *controllerCouponRedemptionRepo) FindActiveBySubscriptionID(context.Context, uint64) (*entity.CouponRedemption, error) {
+	return nil, nil
+}
+
+func (r *controllerCouponRedemptionRepo) EndActive(context.Context, uint64, time.Time) error {
+	return nil
+}
+
+func (r *controllerCouponRedemptionRepo) Delete(context.Context, uint64) error {
+	return nil
+}
+
+type controllerSubscriptionEventRepo struct{}
+
+func (r *controllerSubscriptionEventRepo) Create(context.Context, *entity.SubscriptionEvent) error {
+	return nil
+}
+
+func (r *controllerSubscriptionEventRepo) ListBySubscriptionID(context.Context, uint64) ([]*entity.SubscriptionEvent, error) {
+	return nil, nil
+}
+
 type controllerPaymentService struct {
 	result payment.Result
 }
 
-func (s *controllerPaymentService) ProcessSubscriptionPayment(context.Context, uint64, uint64, *string, *string) payment.Result {
+func (s *controllerPaymentService) ProcessSubscriptionPayment(context.Context, payment.Charge) payment.Result {
+	return s.result
+}
+
+func (s *controllerPaymentService) ProcessSubscriptionAdjustment(context.Context, payment.Adjustment) payment.Result {
 	return s.result
 }
 
@@ -113,11 +240,17 @@ func newControllerForTest(repo *controllerSubRepo, stRepo *controllerSubTypeRepo
 	cfg := config.SubscriptionConfig{
 		RenewBeforeEndMinutes:       time.Hour,
 		RenewalRetryIntervalMinutes: time.Minute,
-		MaxRenewalRetryAgeMinutes:   2 * time.Hour,
+		DunningSchedule:             []time.Duration{time.Hour, 2 * time.Hour},
 		PendingPaymentTimeout:       5 * time.Minute,
 	}
-	subscriptionSvc := service.NewSubscriptionService(repo, stRepo, planRepo, paySvc, cfg)
-	paymentCallbackSvc := service.NewPaymentCallbackService(repo, cfg)
+	attemptRepo := &controllerPaymentAttemptRepo{}
+	eventRepo := &controllerSubscriptionEventRepo{}
+	outboxRepo := &controllerOutboxMessageRepo{}
+	couponRepo := &controllerCouponRepo{}
+	redemptionRepo := &controllerCouponRedemptionRepo{}
+	txManager := &controllerTxManager{}
+	subscriptionSvc := service.NewSubscriptionService(repo, stRepo, planRepo, attemptRepo, eventRepo, outboxRepo, couponRepo, redemptionRepo, txManager, paySvc, cfg)
+	paymentCallbackSvc := service.NewPaymentCallbackService(repo, planRepo, attemptRepo, eventRepo, outboxRepo, redemptionRepo, txManager, cfg)
 	return NewSubscriptionController(subscriptionSvc, paymentCallbackSvc)
 }
 
@@ -228,6 +361,27 @@ func TestUpdateSubscriptionValidationError(t *testing.T) {
 	}
 }
 
+func TestUpdateSubscriptionInvalidTransition(t *testing.T) {
+	ctrl := newControllerForTest(
+		&controllerSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 3, Status: entity.SubscriptionStatusInactive}, nil
+		}},
+		&controllerSubTypeRepo{}, &controllerPlanTypeRepo{}, &controllerPaymentService{},
+	)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPatch, "/subscriptions/3", bytes.NewBufferString(`{"status":10}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("3")
+
+	_ = ctrl.UpdateSubscription(ctx)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+}
+
 func TestDeleteSubscriptionNotFound(t *testing.T) {
 	ctrl := newControllerForTest(
 		&controllerSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) { return nil, nil }},
@@ -246,6 +400,107 @@ func TestDeleteSubscriptionNotFound(t *testing.T) {
 	}
 }
 
+func TestDeleteSubscriptionStaleIfMatch(t *testing.T) {
+	ctrl := newControllerForTest(
+		&controllerSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 3, Status: entity.SubscriptionStatusActive, Version: 5}, nil
+		}},
+		&controllerSubTypeRepo{}, &controllerPlanTypeRepo{}, &controllerPaymentService{},
+	)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/subscriptions/3", nil)
+	req.Header.Set("If-Match", `"4"`)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("3")
+
+	_ = ctrl.DeleteSubscription(ctx)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+}
+
+func TestGetSubscriptionSetsETag(t *testing.T) {
+	ctrl := newControllerForTest(
+		&controllerSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 3, Status: entity.SubscriptionStatusActive, Version: 5}, nil
+		}},
+		&controllerSubTypeRepo{}, &controllerPlanTypeRepo{}, &controllerPaymentService{},
+	)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/subscriptions/3", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("3")
+
+	_ = ctrl.GetSubscription(ctx)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != `"5"` {
+		t.Fatalf("expected 200 with ETag \"5\", got %d %q", rec.Code, rec.Header().Get("ETag"))
+	}
+}
+
+func TestChangePlanInactiveSubscription(t *testing.T) {
+	planTypeID := uint64(20)
+	ctrl := newControllerForTest(
+		&controllerSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 3, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusInactive}, nil
+		}},
+		&controllerSubTypeRepo{}, &controllerPlanTypeRepo{}, &controllerPaymentService{},
+	)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/subscriptions/3/change-plan", bytes.NewBufferString(`{"plan_type_id":21}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("3")
+
+	_ = ctrl.ChangePlan(ctx)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+}
+
+func TestUndoCancellationAfterImmediateCancel(t *testing.T) {
+	planTypeID := uint64(20)
+	canceledAt := time.Now().UTC()
+	ctrl := newControllerForTest(
+		&controllerSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 3, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusInactive, EndAt: &canceledAt, CanceledAt: &canceledAt}, nil
+		}},
+		&controllerSubTypeRepo{}, &controllerPlanTypeRepo{}, &controllerPaymentService{},
+	)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/subscriptions/3/undo-cancellation", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("3")
+
+	_ = ctrl.UndoCancellation(ctx)
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("expected 409, got %d", rec.Code)
+	}
+}
+
+func TestPauseSubscriptionInvalidResumeAt(t *testing.T) {
+	ctrl := newControllerForTest(&controllerSubRepo{}, &controllerSubTypeRepo{}, &controllerPlanTypeRepo{}, &controllerPaymentService{})
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/subscriptions/3/pause", bytes.NewBufferString(`{"resume_at":"next month"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("3")
+
+	_ = ctrl.PauseSubscription(ctx)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
+
 func TestListSubscriptionTypesInvalidStatus(t *testing.T) {
 	ctrl := newControllerForTest(&controllerSubRepo{}, &controllerSubTypeRepo{}, &controllerPlanTypeRepo{}, &controllerPaymentService{})
 	e := echo.New()
@@ -275,3 +530,34 @@ func TestPaymentCallbackNotFound(t *testing.T) {
 		t.Fatalf("expected 404, got %d", rec.Code)
 	}
 }
+
+func TestListPaymentAttemptsNotFound(t *testing.T) {
+	ctrl := newControllerForTest(
+		&controllerSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) { return nil, nil }},
+		&controllerSubTypeRepo{}, &controllerPlanTypeRepo{}, &controllerPaymentService{},
+	)
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/subscriptions/4/payment-attempts", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("4")
+
+	_ = ctrl.ListPaymentAttempts(ctx)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d", rec.Code)
+	}
+}
+
+func TestListSubscriptionsInvalidPageToken(t *testing.T) {
+	ctrl := newControllerForTest(&controllerSubRepo{}, &controllerSubTypeRepo{}, &controllerPlanTypeRepo{}, &controllerPaymentService{})
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/subscriptions?page_token=garbage", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	_ = ctrl.ListSubscriptions(ctx)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", rec.Code)
+	}
+}
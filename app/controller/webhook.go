@@ -0,0 +1,158 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/factory"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/mapper"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/service"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
+)
+
+type WebhookController struct {
+	webhookService *service.WebhookService
+	logger         logrus.FieldLogger
+}
+
+func NewWebhookController(webhookService *service.WebhookService) *WebhookController {
+	return &WebhookController{
+		webhookService: webhookService,
+		logger:         factory.NewModuleLogger("webhooks-controller"),
+	}
+}
+
+func (c *WebhookController) CreateWebhookEndpoint(ctx echo.Context) error {
+	req, err := types.NewCreateWebhookEndpointRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request body")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.webhookService.CreateWebhookEndpoint(actorContext(ctx), req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRequest) {
+			return c.writeError(ctx, http.StatusBadRequest, err.Error())
+		}
+		c.logger.WithError(err).Error("Create webhook endpoint failed")
+		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+	}
+
+	return ctx.JSON(http.StatusCreated, &types.CreateWebhookEndpointResponse{
+		WebhookEndpoint: mapper.WebhookEndpointToProto(item),
+		Secret:          item.Secret,
+	})
+}
+
+func (c *WebhookController) GetWebhookEndpoint(ctx echo.Context) error {
+	req, err := types.NewGetWebhookEndpointRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.webhookService.GetWebhookEndpoint(ctx.Request().Context(), req.GetId())
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookEndpointNotFound) {
+			return c.writeError(ctx, http.StatusNotFound, "webhook endpoint not found")
+		}
+		c.logger.WithError(err).Error("Get webhook endpoint failed")
+		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.WebhookEndpointResponse{
+		WebhookEndpoint: mapper.WebhookEndpointToProto(item),
+	})
+}
+
+func (c *WebhookController) ListWebhookEndpoints(ctx echo.Context) error {
+	items, err := c.webhookService.ListWebhookEndpoints(ctx.Request().Context())
+	if err != nil {
+		c.logger.WithError(err).Error("List webhook endpoints failed")
+		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.ListWebhookEndpointsResponse{
+		WebhookEndpoints: mapper.WebhookEndpointsToProto(items),
+	})
+}
+
+func (c *WebhookController) UpdateWebhookEndpoint(ctx echo.Context) error {
+	req, err := types.NewUpdateWebhookEndpointRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	item, err := c.webhookService.UpdateWebhookEndpoint(ctx.Request().Context(), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest), errors.Is(err, service.ErrInvalidStatus), errors.Is(err, service.ErrNoFieldsToUpdate):
+			return c.writeError(ctx, http.StatusBadRequest, err.Error())
+		case errors.Is(err, service.ErrWebhookEndpointNotFound):
+			return c.writeError(ctx, http.StatusNotFound, "webhook endpoint not found")
+		default:
+			c.logger.WithError(err).Error("Update webhook endpoint failed")
+			return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+		}
+	}
+
+	return ctx.JSON(http.StatusOK, &types.WebhookEndpointResponse{
+		WebhookEndpoint: mapper.WebhookEndpointToProto(item),
+	})
+}
+
+func (c *WebhookController) DeleteWebhookEndpoint(ctx echo.Context) error {
+	req, err := types.NewDeleteWebhookEndpointRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	if err := c.webhookService.DeleteWebhookEndpoint(ctx.Request().Context(), req.GetId()); err != nil {
+		if errors.Is(err, service.ErrWebhookEndpointNotFound) {
+			return c.writeError(ctx, http.StatusNotFound, "webhook endpoint not found")
+		}
+		c.logger.WithError(err).Error("Delete webhook endpoint failed")
+		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.MessageResponse{Message: "Webhook endpoint deleted successfully"})
+}
+
+func (c *WebhookController) ListWebhookDeliveries(ctx echo.Context) error {
+	req, err := types.NewListWebhookDeliveriesRequestFromContext(ctx)
+	if err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, "invalid request")
+	}
+	if err := req.Validate(); err != nil {
+		return c.writeError(ctx, http.StatusBadRequest, err.Error())
+	}
+
+	items, err := c.webhookService.ListWebhookDeliveries(ctx.Request().Context(), req.GetWebhookEndpointId())
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookEndpointNotFound) {
+			return c.writeError(ctx, http.StatusNotFound, "webhook endpoint not found")
+		}
+		c.logger.WithError(err).Error("List webhook deliveries failed")
+		return c.writeError(ctx, http.StatusInternalServerError, "internal server error")
+	}
+
+	return ctx.JSON(http.StatusOK, &types.ListWebhookDeliveriesResponse{
+		WebhookDeliveries: mapper.WebhookDeliveriesToProto(items),
+	})
+}
+
+func (c *WebhookController) writeError(ctx echo.Context, statusCode int, message string) error {
+	return ctx.JSON(statusCode, &types.ErrorResponse{Error: message})
+}
@@ -0,0 +1,81 @@
+package entity
+
+import "time"
+
+const (
+	CouponStatusDisabled int32 = 0
+	CouponStatusActive   int32 = 10
+)
+
+// Coupon durations: how many billing periods of a subscription a redeemed
+// coupon discounts.
+const (
+	CouponDurationOnce      = "once"
+	CouponDurationRepeating = "repeating"
+	CouponDurationForever   = "forever"
+)
+
+// Coupon is a discount code customers redeem when subscribing. A coupon takes
+// either PercentOff percent or AmountOffCents in Currency off the plan price.
+// An empty PlanTypeIDs list applies the coupon to every plan, and a
+// MaxRedemptions of 0 does not limit redemptions.
+type Coupon struct {
+	ID                uint64
+	Code              string
+	Description       string
+	PercentOff        int32
+	AmountOffCents    int64
+	Currency          string
+	Duration          string
+	DurationInPeriods int32
+	MaxRedemptions    int32
+	TimesRedeemed     int32
+	PlanTypeIDs       []uint64
+	ExpiresAt         *time.Time
+	Status            int32
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
+
+// AppliesTo reports whether the coupon discounts planType. Amount-off coupons
+// only apply to plans billed in their currency.
+func (c *Coupon) AppliesTo(planType *PlanType) bool {
+	if c.AmountOffCents > 0 && c.Currency != planType.Currency {
+		return false
+	}
+	if len(c.PlanTypeIDs) == 0 {
+		return true
+	}
+	for _, id := range c.PlanTypeIDs {
+		if id == planType.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// DiscountCents is the amount the coupon takes off priceCents. The discount
+// never exceeds the price.
+func (c *Coupon) DiscountCents(priceCents int64) int64 {
+	discount := c.AmountOffCents
+	if c.PercentOff > 0 {
+		discount = priceCents * int64(c.PercentOff) / 100
+	}
+	if discount > priceCents {
+		return priceCents
+	}
+	return discount
+}
+
+// Periods is the number of billing periods a redemption is discounted for, or 0
+// when every period is.
+func (c *Coupon) Periods() int32 {
+	switch c.Duration {
+	case CouponDurationOnce:
+		return 1
+	case CouponDurationRepeating:
+		return c.DurationInPeriods
+	default:
+		return 0
+	}
+}
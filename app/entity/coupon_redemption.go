@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	CouponRedemptionStatusEnded  int32 = 0
+	CouponRedemptionStatusActive int32 = 10
+)
+
+// CouponRedemption records a coupon applied to a subscription. DurationPeriods
+// is copied from the coupon when it is redeemed, 0 discounting every period, and
+// PeriodsApplied counts the paid periods the discount was taken off. A
+// subscription has at most one active redemption.
+type CouponRedemption struct {
+	ID              uint64
+	CouponID        uint64
+	SubscriptionID  uint64
+	DurationPeriods int32
+	PeriodsApplied  int32
+	Status          int32
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// Exhausted reports whether every discounted period has been used.
+func (r *CouponRedemption) Exhausted() bool {
+	return r.DurationPeriods > 0 && r.PeriodsApplied >= r.DurationPeriods
+}
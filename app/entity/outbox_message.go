@@ -0,0 +1,57 @@
+package entity
+
+import "time"
+
+const (
+	OutboxMessageStatusFailed    int32 = 0
+	OutboxMessageStatusPending   int32 = 1
+	OutboxMessageStatusPublished int32 = 10
+)
+
+// Domain events published for subscriptions.
+const (
+	OutboxEventSubscriptionTrialStarted    = "subscription.trial_started"
+	OutboxEventSubscriptionActivated       = "subscription.activated"
+	OutboxEventSubscriptionRenewed         = "subscription.renewed"
+	OutboxEventSubscriptionCancelled       = "subscription.cancelled"
+	OutboxEventSubscriptionUncancelled     = "subscription.uncancelled"
+	OutboxEventSubscriptionPaused          = "subscription.paused"
+	OutboxEventSubscriptionResumed         = "subscription.resumed"
+	OutboxEventSubscriptionPastDue         = "subscription.past_due"
+	OutboxEventSubscriptionExpired         = "subscription.expired"
+	OutboxEventSubscriptionDeactivated     = "subscription.deactivated"
+	OutboxEventSubscriptionPlanChanged     = "subscription.plan_changed"
+	OutboxEventSubscriptionQuantityChanged = "subscription.quantity_changed"
+)
+
+// OutboxEventTypes lists every domain event type, in documentation order.
+var OutboxEventTypes = []string{
+	OutboxEventSubscriptionTrialStarted,
+	OutboxEventSubscriptionActivated,
+	OutboxEventSubscriptionRenewed,
+	OutboxEventSubscriptionCancelled,
+	OutboxEventSubscriptionUncancelled,
+	OutboxEventSubscriptionPaused,
+	OutboxEventSubscriptionResumed,
+	OutboxEventSubscriptionPastDue,
+	OutboxEventSubscriptionExpired,
+	OutboxEventSubscriptionDeactivated,
+	OutboxEventSubscriptionPlanChanged,
+	OutboxEventSubscriptionQuantityChanged,
+}
+
+// OutboxMessage is a domain event stored with the subscription change that caused
+// it and published later by the relay.
+type OutboxMessage struct {
+	ID             uint64
+	SubscriptionID uint64
+	EventType      string
+	Payload        []byte
+	Status         int32
+	Attempts       int
+	LastError      string
+	NextAttemptAt  time.Time
+	PublishedAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
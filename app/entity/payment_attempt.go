@@ -0,0 +1,50 @@
+package entity
+
+import "time"
+
+const (
+	PaymentAttemptStatusFailed     int32 = 0
+	PaymentAttemptStatusPending    int32 = 1
+	PaymentAttemptStatusSuperseded int32 = 2
+	PaymentAttemptStatusSucceeded  int32 = 10
+)
+
+const (
+	PaymentAttemptKindInitial = "initial"
+	PaymentAttemptKindRenewal = "renewal"
+	// PaymentAttemptKindPlanChange bills the prorated difference of an immediate
+	// plan change. Credits are recorded with a negative amount.
+	PaymentAttemptKindPlanChange = "plan_change"
+	// PaymentAttemptKindRefund credits the unused part of the period when a
+	// subscription is cancelled immediately. It is recorded with a negative amount.
+	PaymentAttemptKindRefund = "refund"
+	// PaymentAttemptKindQuantityChange bills the prorated difference of an
+	// immediate seat quantity change. Credits are recorded with a negative amount.
+	PaymentAttemptKindQuantityChange = "quantity_change"
+	// PaymentAttemptKindReversal gives back an adjustment the provider accepted
+	// when the subscription change it paid for could not be saved. Its amount is
+	// the adjustment's, negated.
+	PaymentAttemptKindReversal = "reversal"
+)
+
+type PaymentAttempt struct {
+	ID             uint64
+	SubscriptionID uint64
+	PlanTypeID     uint64
+	Kind           string
+	AmountCents    int64
+	Currency       string
+	// DiscountCents is the coupon discount taken off the price of all seats; AmountCents
+	// is what was charged.
+	DiscountCents int64
+	// Quantity is the number of seats charged for. Quantity change attempts
+	// carry the new quantity, applied once the charge succeeds.
+	Quantity              int32
+	ProviderTransactionID *string
+	ResultType            string
+	Status                int32
+	Error                 string
+	CompletedAt           *time.Time
+	CreatedAt             time.Time
+	UpdatedAt             time.Time
+}
@@ -2,6 +2,22 @@ package entity
 
 import "time"
 
+const (
+	PlanTypeStatusArchived int32 = 0
+	PlanTypeStatusActive   int32 = 10
+)
+
+// Billing interval units. A plan is billed every IntervalCount units.
+const (
+	PlanIntervalDay   = "day"
+	PlanIntervalWeek  = "week"
+	PlanIntervalMonth = "month"
+	PlanIntervalYear  = "year"
+)
+
+// PlanType is a priced plan of a `plan` subscription type. Archived plans cannot
+// be chosen for new subscriptions but keep renewing existing ones. Plans with
+// TrialDays start new subscribers with a free trial.
 type PlanType struct {
 	ID                 uint64
 	SubscriptionTypeID uint64
@@ -10,8 +26,11 @@ type PlanType struct {
 	Description        string
 	PriceCents         int64
 	Currency           string
-	DurationDays       int32
+	IntervalUnit       string
+	IntervalCount      int32
 	Features           string
+	Status             int32
+	TrialDays          int32
 	CreatedAt          time.Time
 	UpdatedAt          time.Time
 }
@@ -6,19 +6,41 @@ const (
 	SubscriptionStatusInactive       int32 = 0
 	SubscriptionStatusProcessing     int32 = 1
 	SubscriptionStatusPendingPayment int32 = 2
+	SubscriptionStatusPaused         int32 = 3
+	SubscriptionStatusPastDue        int32 = 4
+	SubscriptionStatusTrialing       int32 = 5
 	SubscriptionStatusActive         int32 = 10
 )
 
 type Subscription struct {
 	ID                 uint64
 	SubscriptionTypeID uint64
+	PlanTypeID         *uint64
+	PendingPlanTypeID  *uint64
 	UserID             *string
 	Email              *string
 	Status             int32
 	StartAt            *time.Time
 	EndAt              *time.Time
 	RenewAt            *time.Time
-	AutoRenew          bool
-	CreatedAt          time.Time
-	UpdatedAt          time.Time
+	TrialEndAt         *time.Time
+	CanceledAt         *time.Time
+	CancelReason       *string
+	PausedAt           *time.Time
+	ResumeAt           *time.Time
+	// RenewalRetryCount counts the renewal charges that did not succeed since the
+	// last paid period; it drives the dunning schedule.
+	RenewalRetryCount int32
+	// BillingAnchorDay is the day of month monthly and yearly periods end on,
+	// clamped to shorter months. Zero means the day of the current end_at.
+	BillingAnchorDay int32
+	// Quantity is the number of seats billed; every charge is the plan price
+	// times Quantity.
+	Quantity  int32
+	AutoRenew bool
+	// Version counts the writes to the subscription. An update only applies to
+	// the version it was read at, so concurrent writers cannot overwrite each other.
+	Version   int64
+	CreatedAt time.Time
+	UpdatedAt time.Time
 }
@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+const (
+	SubscriptionEventFieldStatus            = "status"
+	SubscriptionEventFieldPlanTypeID        = "plan_type_id"
+	SubscriptionEventFieldPendingPlanTypeID = "pending_plan_type_id"
+	SubscriptionEventFieldStartAt           = "start_at"
+	SubscriptionEventFieldEndAt             = "end_at"
+	SubscriptionEventFieldRenewAt           = "renew_at"
+	SubscriptionEventFieldAutoRenew         = "auto_renew"
+	SubscriptionEventFieldCanceledAt        = "canceled_at"
+	SubscriptionEventFieldQuantity          = "quantity"
+)
+
+// SubscriptionEventReasonRenewalStarted marks the move of a subscription to
+// processing by the renewal job, which tells an unfinished renewal apart from
+// other processing subscriptions.
+const SubscriptionEventReasonRenewalStarted = "renewal_started"
+
+// SubscriptionEvent records one changed field of a subscription together with who
+// changed it and why.
+type SubscriptionEvent struct {
+	ID             uint64
+	SubscriptionID uint64
+	Field          string
+	OldValue       *string
+	NewValue       *string
+	Actor          string
+	RequestID      *string
+	Reason         string
+	CreatedAt      time.Time
+}
@@ -2,6 +2,16 @@ package entity
 
 import "time"
 
+const (
+	SubscriptionTypeStatusInactive int32 = 0
+	SubscriptionTypeStatusActive   int32 = 10
+)
+
+const (
+	SubscriptionTypeEmail = "email"
+	SubscriptionTypePlan  = "plan"
+)
+
 type SubscriptionType struct {
 	ID          uint64
 	Type        string
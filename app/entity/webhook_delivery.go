@@ -0,0 +1,28 @@
+package entity
+
+import "time"
+
+const (
+	WebhookDeliveryStatusDead      int32 = 0
+	WebhookDeliveryStatusPending   int32 = 1
+	WebhookDeliveryStatusSucceeded int32 = 10
+)
+
+// WebhookDelivery is one domain event queued for one webhook endpoint, together
+// with the outcome of its latest delivery attempt.
+type WebhookDelivery struct {
+	ID                uint64
+	WebhookEndpointID uint64
+	EventID           uint64
+	EventType         string
+	SubscriptionID    uint64
+	Payload           []byte
+	Status            int32
+	Attempts          int
+	ResponseStatus    int
+	LastError         string
+	NextAttemptAt     time.Time
+	DeliveredAt       *time.Time
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+}
@@ -0,0 +1,35 @@
+package entity
+
+import "time"
+
+const (
+	WebhookEndpointStatusDisabled int32 = 0
+	WebhookEndpointStatusActive   int32 = 10
+)
+
+// WebhookEndpoint is a client URL notified about subscription domain events. An
+// empty EventTypes list subscribes the endpoint to every event.
+type WebhookEndpoint struct {
+	ID          uint64
+	URL         string
+	Description string
+	EventTypes  []string
+	Secret      string
+	Status      int32
+	CreatedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Accepts reports whether the endpoint subscribed to eventType.
+func (e *WebhookEndpoint) Accepts(eventType string) bool {
+	if len(e.EventTypes) == 0 {
+		return true
+	}
+	for _, item := range e.EventTypes {
+		if item == eventType {
+			return true
+		}
+	}
+	return false
+}
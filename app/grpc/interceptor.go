@@ -8,6 +8,9 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	authmiddleware "github.com/vibast-solutions/lib-go-auth/middleware"
+	appmiddleware "github.com/vibast-solutions/ms-go-subscriptions/app/middleware"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/service"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -76,11 +79,47 @@ func RecoveryInterceptor() grpc.UnaryServerInterceptor {
 	}
 }
 
+// AdminAccessInterceptor rejects calls to the given methods unless the caller
+// authenticated by the internal auth interceptor has admin access. It must be
+// chained after internal auth.
+func AdminAccessInterceptor(access *appmiddleware.AdminAccess, methods ...string) grpc.UnaryServerInterceptor {
+	adminMethods := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		adminMethods[method] = struct{}{}
+	}
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if _, ok := adminMethods[info.FullMethod]; !ok {
+			return handler(ctx, req)
+		}
+
+		caller, _ := authmiddleware.CallerServiceFromGRPCContext(ctx)
+		if !access.Allows(caller) {
+			loggerWithContext(ctx).WithFields(logrus.Fields{
+				"caller": caller,
+				"method": info.FullMethod,
+			}).Warn("Admin access denied")
+			return nil, status.Error(codes.PermissionDenied, "admin access required")
+		}
+		return handler(ctx, req)
+	}
+}
+
 func RequestIDFromContext(ctx context.Context) string {
 	requestID, _ := ctx.Value(requestIDContextKey{}).(string)
 	return requestID
 }
 
+// actorContext attributes subscription changes made by the call to the
+// authenticated internal caller.
+func actorContext(ctx context.Context) context.Context {
+	actor := service.Actor{RequestID: RequestIDFromContext(ctx)}
+	if caller, err := authmiddleware.CallerServiceFromGRPCContext(ctx); err == nil {
+		actor.Name = caller
+	}
+	return service.WithActor(ctx, actor)
+}
+
 func loggerWithContext(ctx context.Context) *logrus.Entry {
 	entry := logrus.NewEntry(logrus.StandardLogger())
 	if requestID := RequestIDFromContext(ctx); requestID != "" {
@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	appmiddleware "github.com/vibast-solutions/ms-go-subscriptions/app/middleware"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -70,3 +71,20 @@ func TestLoggingInterceptorPassThrough(t *testing.T) {
 		t.Fatalf("unexpected response: %v", resp)
 	}
 }
+
+func TestAdminAccessInterceptor(t *testing.T) {
+	interceptor := AdminAccessInterceptor(appmiddleware.NewAdminAccess([]string{"backoffice-service"}), "/subscriptions.SubscriptionsService/CreatePlanType")
+	handler := func(context.Context, interface{}) (interface{}, error) {
+		return "ok", nil
+	}
+
+	_, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/subscriptions.SubscriptionsService/CreatePlanType"}, handler)
+	if status.Code(err) != codes.PermissionDenied {
+		t.Fatalf("expected codes.PermissionDenied without admin caller, got %v", err)
+	}
+
+	resp, err := interceptor(context.Background(), nil, &grpc.UnaryServerInfo{FullMethod: "/subscriptions.SubscriptionsService/ListPlanTypes"}, handler)
+	if err != nil || resp != "ok" {
+		t.Fatalf("expected non-admin method to pass through, got resp=%v err=%v", resp, err)
+	}
+}
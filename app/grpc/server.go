@@ -11,20 +11,47 @@ import (
 	"google.golang.org/grpc/status"
 )
 
+// AdminMethods lists the RPCs that require admin access on top of internal auth.
+var AdminMethods = []string{
+	types.SubscriptionsService_CreateSubscriptionType_FullMethodName,
+	types.SubscriptionsService_UpdateSubscriptionType_FullMethodName,
+	types.SubscriptionsService_ArchiveSubscriptionType_FullMethodName,
+	types.SubscriptionsService_CreatePlanType_FullMethodName,
+	types.SubscriptionsService_UpdatePlanType_FullMethodName,
+	types.SubscriptionsService_ArchivePlanType_FullMethodName,
+	types.SubscriptionsService_CreateCoupon_FullMethodName,
+	types.SubscriptionsService_GetCoupon_FullMethodName,
+	types.SubscriptionsService_ListCoupons_FullMethodName,
+	types.SubscriptionsService_UpdateCoupon_FullMethodName,
+	types.SubscriptionsService_ListCouponRedemptions_FullMethodName,
+	types.SubscriptionsService_CreateWebhookEndpoint_FullMethodName,
+	types.SubscriptionsService_UpdateWebhookEndpoint_FullMethodName,
+	types.SubscriptionsService_DeleteWebhookEndpoint_FullMethodName,
+}
+
 type paymentCallbackService interface {
-	PaymentCallback(ctx context.Context, req *types.PaymentCallbackRequest) error
+	PaymentCallback(ctx context.Context, req *types.PaymentCallbackRequest) (*service.PaymentCallbackResult, error)
 }
 
 type Server struct {
 	types.UnimplementedSubscriptionsServiceServer
 	subscriptionService    *service.SubscriptionService
 	paymentCallbackService paymentCallbackService
+	webhookService         *service.WebhookService
+	catalogService         *service.CatalogService
 }
 
-func NewServer(subscriptionService *service.SubscriptionService, paymentCallbackService paymentCallbackService) *Server {
+func NewServer(
+	subscriptionService *service.SubscriptionService,
+	paymentCallbackService paymentCallbackService,
+	webhookService *service.WebhookService,
+	catalogService *service.CatalogService,
+) *Server {
 	return &Server{
 		subscriptionService:    subscriptionService,
 		paymentCallbackService: paymentCallbackService,
+		webhookService:         webhookService,
+		catalogService:         catalogService,
 	}
 }
 
@@ -53,21 +80,205 @@ func (s *Server) ListSubscriptionTypes(ctx context.Context, req *types.ListSubsc
 	}, nil
 }
 
+func (s *Server) ListPlanTypes(ctx context.Context, req *types.ListPlanTypesRequest) (*types.ListPlanTypesResponse, error) {
+	l := loggerWithContext(ctx)
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	items, err := s.subscriptionService.ListPlanTypes(ctx, req)
+	if err != nil {
+		l.WithError(err).Error("List plan types failed")
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &types.ListPlanTypesResponse{
+		PlanTypes: mapper.PlanTypesToProto(items),
+	}, nil
+}
+
+func (s *Server) GetPlanType(ctx context.Context, req *types.GetPlanTypeRequest) (*types.PlanTypeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.subscriptionService.GetPlanType(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, service.ErrPlanTypeNotFound) {
+			return nil, status.Error(codes.NotFound, "plan type not found")
+		}
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &types.PlanTypeResponse{PlanType: mapper.PlanTypeToProto(item)}, nil
+}
+
+func (s *Server) CreateSubscriptionType(ctx context.Context, req *types.CreateSubscriptionTypeRequest) (*types.SubscriptionTypeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.catalogService.CreateSubscriptionType(ctx, req)
+	if err != nil {
+		return nil, catalogError(ctx, err, "Create subscription type failed")
+	}
+
+	return &types.SubscriptionTypeResponse{SubscriptionType: mapper.SubscriptionTypeToProto(item)}, nil
+}
+
+func (s *Server) UpdateSubscriptionType(ctx context.Context, req *types.UpdateSubscriptionTypeRequest) (*types.SubscriptionTypeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.catalogService.UpdateSubscriptionType(ctx, req)
+	if err != nil {
+		return nil, catalogError(ctx, err, "Update subscription type failed")
+	}
+
+	return &types.SubscriptionTypeResponse{SubscriptionType: mapper.SubscriptionTypeToProto(item)}, nil
+}
+
+func (s *Server) ArchiveSubscriptionType(ctx context.Context, req *types.ArchiveSubscriptionTypeRequest) (*types.SubscriptionTypeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.catalogService.ArchiveSubscriptionType(ctx, req.GetId())
+	if err != nil {
+		return nil, catalogError(ctx, err, "Archive subscription type failed")
+	}
+
+	return &types.SubscriptionTypeResponse{SubscriptionType: mapper.SubscriptionTypeToProto(item)}, nil
+}
+
+func (s *Server) CreatePlanType(ctx context.Context, req *types.CreatePlanTypeRequest) (*types.PlanTypeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.catalogService.CreatePlanType(ctx, req)
+	if err != nil {
+		return nil, catalogError(ctx, err, "Create plan type failed")
+	}
+
+	return &types.PlanTypeResponse{PlanType: mapper.PlanTypeToProto(item)}, nil
+}
+
+func (s *Server) UpdatePlanType(ctx context.Context, req *types.UpdatePlanTypeRequest) (*types.PlanTypeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.catalogService.UpdatePlanType(ctx, req)
+	if err != nil {
+		return nil, catalogError(ctx, err, "Update plan type failed")
+	}
+
+	return &types.PlanTypeResponse{PlanType: mapper.PlanTypeToProto(item)}, nil
+}
+
+func (s *Server) ArchivePlanType(ctx context.Context, req *types.ArchivePlanTypeRequest) (*types.PlanTypeResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.catalogService.ArchivePlanType(ctx, req.GetId())
+	if err != nil {
+		return nil, catalogError(ctx, err, "Archive plan type failed")
+	}
+
+	return &types.PlanTypeResponse{PlanType: mapper.PlanTypeToProto(item)}, nil
+}
+
+func (s *Server) CreateCoupon(ctx context.Context, req *types.CreateCouponRequest) (*types.CouponResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.catalogService.CreateCoupon(ctx, req)
+	if err != nil {
+		return nil, catalogError(ctx, err, "Create coupon failed")
+	}
+
+	return &types.CouponResponse{Coupon: mapper.CouponToProto(item)}, nil
+}
+
+func (s *Server) GetCoupon(ctx context.Context, req *types.GetCouponRequest) (*types.CouponResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.catalogService.GetCoupon(ctx, req.GetId())
+	if err != nil {
+		return nil, catalogError(ctx, err, "Get coupon failed")
+	}
+
+	return &types.CouponResponse{Coupon: mapper.CouponToProto(item)}, nil
+}
+
+func (s *Server) ListCoupons(ctx context.Context, req *types.ListCouponsRequest) (*types.ListCouponsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	items, err := s.catalogService.ListCoupons(ctx)
+	if err != nil {
+		return nil, catalogError(ctx, err, "List coupons failed")
+	}
+
+	return &types.ListCouponsResponse{Coupons: mapper.CouponsToProto(items)}, nil
+}
+
+func (s *Server) UpdateCoupon(ctx context.Context, req *types.UpdateCouponRequest) (*types.CouponResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.catalogService.UpdateCoupon(ctx, req)
+	if err != nil {
+		return nil, catalogError(ctx, err, "Update coupon failed")
+	}
+
+	return &types.CouponResponse{Coupon: mapper.CouponToProto(item)}, nil
+}
+
+func (s *Server) ListCouponRedemptions(ctx context.Context, req *types.ListCouponRedemptionsRequest) (*types.ListCouponRedemptionsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	items, err := s.catalogService.ListCouponRedemptions(ctx, req.GetCouponId())
+	if err != nil {
+		return nil, catalogError(ctx, err, "List coupon redemptions failed")
+	}
+
+	return &types.ListCouponRedemptionsResponse{CouponRedemptions: mapper.CouponRedemptionsToProto(items)}, nil
+}
+
 func (s *Server) CreateSubscription(ctx context.Context, req *types.CreateSubscriptionRequest) (*types.CreateSubscriptionResponse, error) {
 	l := loggerWithContext(ctx)
 	if err := req.Validate(); err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	result, err := s.subscriptionService.CreateSubscription(ctx, req)
+	result, err := s.subscriptionService.CreateSubscription(actorContext(ctx), req)
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrInvalidRequest), errors.Is(err, service.ErrStartAtRequired):
 			return nil, status.Error(codes.InvalidArgument, err.Error())
 		case errors.Is(err, service.ErrSubscriptionTypeNotFound):
 			return nil, status.Error(codes.NotFound, "subscription type not found")
+		case errors.Is(err, service.ErrPlanTypeNotFound):
+			return nil, status.Error(codes.NotFound, "plan type not found")
+		case errors.Is(err, service.ErrCouponNotFound):
+			return nil, status.Error(codes.NotFound, "coupon not found")
 		case errors.Is(err, service.ErrSubscriptionAlreadyExists):
 			return nil, status.Error(codes.AlreadyExists, "subscription already exists")
+		case errors.Is(err, service.ErrConcurrentModification):
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrCouponNotRedeemable):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		default:
 			l.WithError(err).Error("Create subscription failed")
 			return nil, status.Error(codes.Internal, "internal server error")
@@ -101,13 +312,17 @@ func (s *Server) ListSubscriptions(ctx context.Context, req *types.ListSubscript
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	items, err := s.subscriptionService.ListSubscriptions(ctx, req)
+	result, err := s.subscriptionService.ListSubscriptions(ctx, req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidRequest) || errors.Is(err, service.ErrInvalidPageToken) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
 		return nil, status.Error(codes.Internal, "internal server error")
 	}
 
 	return &types.ListSubscriptionsResponse{
-		Subscriptions: mapper.SubscriptionsToProto(items),
+		Subscriptions: mapper.SubscriptionsToProto(result.Subscriptions),
+		NextPageToken: result.NextPageToken,
 	}, nil
 }
 
@@ -116,13 +331,17 @@ func (s *Server) UpdateSubscription(ctx context.Context, req *types.UpdateSubscr
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	item, err := s.subscriptionService.UpdateSubscription(ctx, req)
+	item, err := s.subscriptionService.UpdateSubscription(actorContext(ctx), req)
 	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrInvalidStatus), errors.Is(err, service.ErrNoFieldsToUpdate):
 			return nil, status.Error(codes.InvalidArgument, err.Error())
 		case errors.Is(err, service.ErrSubscriptionNotFound):
 			return nil, status.Error(codes.NotFound, "subscription not found")
+		case errors.Is(err, service.ErrConcurrentModification):
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, service.ErrInvalidTransition):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		default:
 			return nil, status.Error(codes.Internal, "internal server error")
 		}
@@ -136,48 +355,380 @@ func (s *Server) DeleteSubscription(ctx context.Context, req *types.DeleteSubscr
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	item, err := s.subscriptionService.DeleteSubscription(ctx, req.GetId())
+	item, err := s.subscriptionService.DeleteSubscription(actorContext(ctx), req)
 	if err != nil {
-		if errors.Is(err, service.ErrSubscriptionNotFound) {
+		switch {
+		case errors.Is(err, service.ErrSubscriptionNotFound):
 			return nil, status.Error(codes.NotFound, "subscription not found")
+		case errors.Is(err, service.ErrConcurrentModification):
+			return nil, status.Error(codes.Aborted, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, "internal server error")
 		}
-		return nil, status.Error(codes.Internal, "internal server error")
 	}
 
 	return &types.MessageResponse{Message: "Subscription deleted successfully", Subscription: mapper.SubscriptionToProto(item)}, nil
 }
 
-func (s *Server) CancelSubscription(ctx context.Context, req *types.CancelSubscriptionRequest) (*types.MessageResponse, error) {
+func (s *Server) CancelSubscription(ctx context.Context, req *types.CancelSubscriptionRequest) (*types.CancelSubscriptionResponse, error) {
+	l := loggerWithContext(ctx)
 	if err := req.Validate(); err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	item, err := s.subscriptionService.CancelSubscription(ctx, req.GetId())
+	result, err := s.subscriptionService.CancelSubscription(actorContext(ctx), req)
 	if err != nil {
-		if errors.Is(err, service.ErrSubscriptionNotFound) {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, service.ErrSubscriptionNotFound):
 			return nil, status.Error(codes.NotFound, "subscription not found")
+		case errors.Is(err, service.ErrConcurrentModification):
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrPaymentDeclined):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			l.WithError(err).Error("Cancel subscription failed")
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &types.CancelSubscriptionResponse{
+		Message:             "Subscription cancelled successfully",
+		Subscription:        mapper.SubscriptionToProto(result.Subscription),
+		RefundedAmountCents: result.RefundedAmountCents,
+	}, nil
+}
+
+func (s *Server) UndoCancellation(ctx context.Context, req *types.UndoCancellationRequest) (*types.MessageResponse, error) {
+	l := loggerWithContext(ctx)
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.subscriptionService.UndoCancellation(actorContext(ctx), req.GetId())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, service.ErrSubscriptionNotFound):
+			return nil, status.Error(codes.NotFound, "subscription not found")
+		case errors.Is(err, service.ErrConcurrentModification):
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, service.ErrInvalidTransition):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			l.WithError(err).Error("Undo cancellation failed")
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &types.MessageResponse{Message: "Subscription cancellation undone", Subscription: mapper.SubscriptionToProto(item)}, nil
+}
+
+func (s *Server) PauseSubscription(ctx context.Context, req *types.PauseSubscriptionRequest) (*types.MessageResponse, error) {
+	l := loggerWithContext(ctx)
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.subscriptionService.PauseSubscription(actorContext(ctx), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, service.ErrSubscriptionNotFound):
+			return nil, status.Error(codes.NotFound, "subscription not found")
+		case errors.Is(err, service.ErrConcurrentModification):
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, service.ErrInvalidTransition):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			l.WithError(err).Error("Pause subscription failed")
+			return nil, status.Error(codes.Internal, "internal server error")
 		}
-		return nil, status.Error(codes.Internal, "internal server error")
 	}
 
-	return &types.MessageResponse{Message: "Subscription cancelled successfully", Subscription: mapper.SubscriptionToProto(item)}, nil
+	return &types.MessageResponse{Message: "Subscription paused successfully", Subscription: mapper.SubscriptionToProto(item)}, nil
 }
 
-func (s *Server) PaymentCallback(ctx context.Context, req *types.PaymentCallbackRequest) (*types.MessageResponse, error) {
+func (s *Server) ResumeSubscription(ctx context.Context, req *types.ResumeSubscriptionRequest) (*types.MessageResponse, error) {
+	l := loggerWithContext(ctx)
 	if err := req.Validate(); err != nil {
 		return nil, status.Error(codes.InvalidArgument, err.Error())
 	}
 
-	if err := s.paymentCallbackService.PaymentCallback(ctx, req); err != nil {
+	item, err := s.subscriptionService.ResumeSubscription(actorContext(ctx), req.GetId())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrSubscriptionNotFound):
+			return nil, status.Error(codes.NotFound, "subscription not found")
+		case errors.Is(err, service.ErrConcurrentModification):
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, service.ErrInvalidTransition):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			l.WithError(err).Error("Resume subscription failed")
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &types.MessageResponse{Message: "Subscription resumed successfully", Subscription: mapper.SubscriptionToProto(item)}, nil
+}
+
+func (s *Server) ChangePlan(ctx context.Context, req *types.ChangePlanRequest) (*types.ChangePlanResponse, error) {
+	l := loggerWithContext(ctx)
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := s.subscriptionService.ChangePlan(actorContext(ctx), req)
+	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrInvalidRequest):
 			return nil, status.Error(codes.InvalidArgument, err.Error())
 		case errors.Is(err, service.ErrSubscriptionNotFound):
 			return nil, status.Error(codes.NotFound, "subscription not found")
+		case errors.Is(err, service.ErrPlanTypeNotFound):
+			return nil, status.Error(codes.NotFound, "plan type not found")
+		case errors.Is(err, service.ErrConcurrentModification):
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrPaymentDeclined):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
 		default:
+			l.WithError(err).Error("Change plan failed")
 			return nil, status.Error(codes.Internal, "internal server error")
 		}
 	}
 
-	return &types.MessageResponse{Message: "Payment processed successfully"}, nil
+	return &types.ChangePlanResponse{
+		Subscription:        mapper.SubscriptionToProto(result.Subscription),
+		ProratedAmountCents: result.ProratedAmountCents,
+		PaymentUrl:          result.PaymentURL,
+	}, nil
+}
+
+func (s *Server) UpdateQuantity(ctx context.Context, req *types.UpdateQuantityRequest) (*types.UpdateQuantityResponse, error) {
+	l := loggerWithContext(ctx)
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := s.subscriptionService.UpdateQuantity(actorContext(ctx), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, service.ErrSubscriptionNotFound):
+			return nil, status.Error(codes.NotFound, "subscription not found")
+		case errors.Is(err, service.ErrConcurrentModification):
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, service.ErrInvalidTransition), errors.Is(err, service.ErrPaymentDeclined):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			l.WithError(err).Error("Update quantity failed")
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &types.UpdateQuantityResponse{
+		Subscription:        mapper.SubscriptionToProto(result.Subscription),
+		ProratedAmountCents: result.ProratedAmountCents,
+		PaymentUrl:          result.PaymentURL,
+	}, nil
+}
+
+func (s *Server) PaymentCallback(ctx context.Context, req *types.PaymentCallbackRequest) (*types.PaymentCallbackResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	result, err := s.paymentCallbackService.PaymentCallback(actorContext(ctx), req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, service.ErrSubscriptionNotFound):
+			return nil, status.Error(codes.NotFound, "subscription not found")
+		case errors.Is(err, service.ErrPaymentAttemptNotFound):
+			return nil, status.Error(codes.NotFound, "payment attempt not found")
+		case errors.Is(err, service.ErrConcurrentModification):
+			return nil, status.Error(codes.Aborted, err.Error())
+		case errors.Is(err, service.ErrPaymentAttemptSuperseded), errors.Is(err, service.ErrInvalidTransition):
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		default:
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	message := "Payment processed successfully"
+	if result.AlreadyProcessed {
+		message = "Payment already processed"
+	}
+	return &types.PaymentCallbackResponse{
+		Message:          message,
+		Subscription:     mapper.SubscriptionToProto(result.Subscription),
+		AlreadyProcessed: result.AlreadyProcessed,
+	}, nil
+}
+
+func (s *Server) ListPaymentAttempts(ctx context.Context, req *types.ListPaymentAttemptsRequest) (*types.ListPaymentAttemptsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	items, err := s.subscriptionService.ListPaymentAttempts(ctx, req.GetSubscriptionId())
+	if err != nil {
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			return nil, status.Error(codes.NotFound, "subscription not found")
+		}
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &types.ListPaymentAttemptsResponse{
+		PaymentAttempts: mapper.PaymentAttemptsToProto(items),
+	}, nil
+}
+
+func (s *Server) ListSubscriptionEvents(ctx context.Context, req *types.ListSubscriptionEventsRequest) (*types.ListSubscriptionEventsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	items, err := s.subscriptionService.ListSubscriptionEvents(ctx, req.GetSubscriptionId())
+	if err != nil {
+		if errors.Is(err, service.ErrSubscriptionNotFound) {
+			return nil, status.Error(codes.NotFound, "subscription not found")
+		}
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &types.ListSubscriptionEventsResponse{
+		SubscriptionEvents: mapper.SubscriptionEventsToProto(items),
+	}, nil
+}
+
+func (s *Server) CreateWebhookEndpoint(ctx context.Context, req *types.CreateWebhookEndpointRequest) (*types.CreateWebhookEndpointResponse, error) {
+	l := loggerWithContext(ctx)
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.webhookService.CreateWebhookEndpoint(actorContext(ctx), req)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidRequest) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		l.WithError(err).Error("Create webhook endpoint failed")
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &types.CreateWebhookEndpointResponse{
+		WebhookEndpoint: mapper.WebhookEndpointToProto(item),
+		Secret:          item.Secret,
+	}, nil
+}
+
+func (s *Server) GetWebhookEndpoint(ctx context.Context, req *types.GetWebhookEndpointRequest) (*types.WebhookEndpointResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.webhookService.GetWebhookEndpoint(ctx, req.GetId())
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookEndpointNotFound) {
+			return nil, status.Error(codes.NotFound, "webhook endpoint not found")
+		}
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &types.WebhookEndpointResponse{WebhookEndpoint: mapper.WebhookEndpointToProto(item)}, nil
+}
+
+func (s *Server) ListWebhookEndpoints(ctx context.Context, req *types.ListWebhookEndpointsRequest) (*types.ListWebhookEndpointsResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	items, err := s.webhookService.ListWebhookEndpoints(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &types.ListWebhookEndpointsResponse{
+		WebhookEndpoints: mapper.WebhookEndpointsToProto(items),
+	}, nil
+}
+
+func (s *Server) UpdateWebhookEndpoint(ctx context.Context, req *types.UpdateWebhookEndpointRequest) (*types.WebhookEndpointResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	item, err := s.webhookService.UpdateWebhookEndpoint(ctx, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvalidRequest), errors.Is(err, service.ErrInvalidStatus), errors.Is(err, service.ErrNoFieldsToUpdate):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, service.ErrWebhookEndpointNotFound):
+			return nil, status.Error(codes.NotFound, "webhook endpoint not found")
+		default:
+			return nil, status.Error(codes.Internal, "internal server error")
+		}
+	}
+
+	return &types.WebhookEndpointResponse{WebhookEndpoint: mapper.WebhookEndpointToProto(item)}, nil
+}
+
+func (s *Server) DeleteWebhookEndpoint(ctx context.Context, req *types.DeleteWebhookEndpointRequest) (*types.MessageResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	if err := s.webhookService.DeleteWebhookEndpoint(ctx, req.GetId()); err != nil {
+		if errors.Is(err, service.ErrWebhookEndpointNotFound) {
+			return nil, status.Error(codes.NotFound, "webhook endpoint not found")
+		}
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &types.MessageResponse{Message: "Webhook endpoint deleted successfully"}, nil
+}
+
+func (s *Server) ListWebhookDeliveries(ctx context.Context, req *types.ListWebhookDeliveriesRequest) (*types.ListWebhookDeliveriesResponse, error) {
+	if err := req.Validate(); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	items, err := s.webhookService.ListWebhookDeliveries(ctx, req.GetWebhookEndpointId())
+	if err != nil {
+		if errors.Is(err, service.ErrWebhookEndpointNotFound) {
+			return nil, status.Error(codes.NotFound, "webhook endpoint not found")
+		}
+		return nil, status.Error(codes.Internal, "internal server error")
+	}
+
+	return &types.ListWebhookDeliveriesResponse{
+		WebhookDeliveries: mapper.WebhookDeliveriesToProto(items),
+	}, nil
+}
+
+func catalogError(ctx context.Context, err error, message string) error {
+	switch {
+	case errors.Is(err, service.ErrInvalidRequest), errors.Is(err, service.ErrInvalidStatus), errors.Is(err, service.ErrNoFieldsToUpdate):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.Is(err, service.ErrSubscriptionTypeNotFound):
+		return status.Error(codes.NotFound, "subscription type not found")
+	case errors.Is(err, service.ErrPlanTypeNotFound):
+		return status.Error(codes.NotFound, "plan type not found")
+	case errors.Is(err, service.ErrPlanCodeAlreadyExists):
+		return status.Error(codes.AlreadyExists, "plan code already exists")
+	case errors.Is(err, service.ErrCouponNotFound):
+		return status.Error(codes.NotFound, "coupon not found")
+	case errors.Is(err, service.ErrCouponCodeAlreadyExists):
+		return status.Error(codes.AlreadyExists, "coupon code already exists")
+	default:
+		loggerWithContext(ctx).WithError(err).Error(message)
+		return status.Error(codes.Internal, "internal server error")
+	}
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/service"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
 	"github.com/vibast-solutions/ms-go-subscriptions/config"
@@ -20,7 +21,7 @@ type grpcSubRepo struct {
 	updateFn                func(ctx context.Context, subscription *entity.Subscription) error
 	findByIDFn              func(ctx context.Context, id uint64) (*entity.Subscription, error)
 	findByTypeAndIdentityFn func(ctx context.Context, subscriptionTypeID uint64, userID, email *string) (*entity.Subscription, error)
-	listFn                  func(ctx context.Context, userID, email string) ([]*entity.Subscription, error)
+	listFn                  func(ctx context.Context, filter repository.SubscriptionFilter) ([]*entity.Subscription, error)
 }
 
 func (r *grpcSubRepo) Create(ctx context.Context, subscription *entity.Subscription) error {
@@ -51,25 +52,37 @@ func (r *grpcSubRepo) FindByTypeAndIdentity(ctx context.Context, subscriptionTyp
 	return nil, nil
 }
 
-func (r *grpcSubRepo) List(ctx context.Context, userID, email string) ([]*entity.Subscription, error) {
+func (r *grpcSubRepo) List(ctx context.Context, filter repository.SubscriptionFilter) ([]*entity.Subscription, error) {
 	if r.listFn != nil {
-		return r.listFn(ctx, userID, email)
+		return r.listFn(ctx, filter)
 	}
 	return nil, nil
 }
 
-func (r *grpcSubRepo) ListDueAutoRenew(context.Context, time.Time) ([]*entity.Subscription, error) {
+func (r *grpcSubRepo) ClaimDueAutoRenew(context.Context, time.Time, repository.SubscriptionClaim) ([]*entity.Subscription, error) {
 	return nil, nil
 }
 
-func (r *grpcSubRepo) ListPendingPaymentStale(context.Context, time.Time) ([]*entity.Subscription, error) {
+func (r *grpcSubRepo) ClaimPendingPaymentStale(context.Context, time.Time, repository.SubscriptionClaim) ([]*entity.Subscription, error) {
 	return nil, nil
 }
 
-func (r *grpcSubRepo) ListExpiredActive(context.Context, time.Time) ([]*entity.Subscription, error) {
+func (r *grpcSubRepo) ClaimExpiredActive(context.Context, time.Time, repository.SubscriptionClaim) ([]*entity.Subscription, error) {
 	return nil, nil
 }
 
+func (r *grpcSubRepo) ClaimDueResume(context.Context, time.Time, repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+	return nil, nil
+}
+
+func (r *grpcSubRepo) ReleaseClaims(context.Context, string) error {
+	return nil
+}
+
+func (r *grpcSubRepo) HasUsedTrial(context.Context, uint64, *string, *string) (bool, error) {
+	return false, nil
+}
+
 type grpcSubTypeRepo struct {
 	listFn     func(ctx context.Context, typeFilter string, hasStatus bool, status int32) ([]*entity.SubscriptionType, error)
 	findByIDFn func(ctx context.Context, id uint64) (*entity.SubscriptionType, error)
@@ -82,6 +95,14 @@ func (r *grpcSubTypeRepo) List(ctx context.Context, typeFilter string, hasStatus
 	return nil, nil
 }
 
+func (r *grpcSubTypeRepo) Create(context.Context, *entity.SubscriptionType) error {
+	return nil
+}
+
+func (r *grpcSubTypeRepo) Update(context.Context, *entity.SubscriptionType) error {
+	return nil
+}
+
 func (r *grpcSubTypeRepo) FindByID(ctx context.Context, id uint64) (*entity.SubscriptionType, error) {
 	if r.findByIDFn != nil {
 		return r.findByIDFn(ctx, id)
@@ -90,34 +111,208 @@ func (r *grpcSubTypeRepo) FindByID(ctx context.Context, id uint64) (*entity.Subs
 }
 
 type grpcPlanRepo struct {
-	findFn func(ctx context.Context, subscriptionTypeID uint64) (*entity.PlanType, error)
+	listFn func(ctx context.Context, subscriptionTypeID uint64) ([]*entity.PlanType, error)
 }
 
-func (r *grpcPlanRepo) FindBySubscriptionTypeID(ctx context.Context, subscriptionTypeID uint64) (*entity.PlanType, error) {
-	if r.findFn != nil {
-		return r.findFn(ctx, subscriptionTypeID)
+func (r *grpcPlanRepo) List(ctx context.Context, subscriptionTypeID uint64) ([]*entity.PlanType, error) {
+	if r.listFn != nil {
+		return r.listFn(ctx, subscriptionTypeID)
 	}
 	return nil, nil
 }
 
+func (r *grpcPlanRepo) Create(context.Context, *entity.PlanType) error {
+	return nil
+}
+
+func (r *grpcPlanRepo) Update(context.Context, *entity.PlanType) error {
+	return nil
+}
+
+func (r *grpcPlanRepo) FindByID(context.Context, uint64) (*entity.PlanType, error) {
+	return nil, nil
+}
+
+type grpcPaymentAttemptRepo struct {
+	listFn func(ctx context.Context, subscriptionID uint64) ([]*entity.PaymentAttempt, error)
+}
+
+func (r *grpcPaymentAttemptRepo) Create(_ context.Context, attempt *entity.PaymentAttempt) error {
+	attempt.ID = 1
+	return nil
+}
+
+func (r *grpcPaymentAttemptRepo) Update(context.Context, *entity.PaymentAttempt) error {
+	return nil
+}
+
+func (r *grpcPaymentAttemptRepo) CompletePending(context.Context, *entity.PaymentAttempt) error {
+	return nil
+}
+
+func (r *grpcPaymentAttemptRepo) SupersedePending(context.Context, uint64, string, uint64, time.Time) error {
+	return nil
+}
+
+func (r *grpcPaymentAttemptRepo) FindByTransactionID(context.Context, string) (*entity.PaymentAttempt, error) {
+	return nil, nil
+}
+
+func (r *grpcPaymentAttemptRepo) ListBySubscriptionID(ctx context.Context, subscriptionID uint64) ([]*entity.PaymentAttempt, error) {
+	if r.listFn != nil {
+		return r.listFn(ctx, subscriptionID)
+	}
+	return nil, nil
+}
+
+type grpcOutboxMessageRepo struct{}
+
+func (r *grpcOutboxMessageRepo) Create(context.Context, *entity.OutboxMessage) error {
+	return nil
+}
+
+func (r *grpcOutboxMessageRepo) Update(context.Context, *entity.OutboxMessage) error {
+	return nil
+}
+
+func (r *grpcOutboxMessageRepo) ClaimDue(context.Context, repository.OutboxClaim) ([]*entity.OutboxMessage, error) {
+	return nil, nil
+}
+
+func (r *grpcOutboxMessageRepo) ReleaseClaims(context.Context, string) error {
+	return nil
+}
+
+type grpcTxManager struct{}
+
+func (m *grpcTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+type grpcCouponRepo struct{}
+
+func (r *grpcCouponRepo) FindByID(context.Context, uint64) (*entity.Coupon, error) {
+	return nil, nil
+}
+
+func (r *grpcCouponRepo) FindByCode(context.Context, string) (*entity.Coupon, error) {
+	return nil, nil
+}
+
+func (r *grpcCouponRepo) IncrementRedemptions(context.Context, uint64, time.Time) error {
+	return nil
+}
+
+func (r *grpcCouponRepo) DecrementRedemptions(context.Context, uint64, time.Time) error {
+	return nil
+}
+
+func (r *grpcCouponRepo) Create(context.Context, *entity.Coupon) error {
+	return nil
+}
+
+func (r *grpcCouponRepo) Update(context.Context, *entity.Coupon) error {
+	return nil
+}
+
+func (r *grpcCouponRepo) List(context.Context) ([]*entity.Coupon, error) {
+	return nil, nil
+}
+
+type grpcCouponRedemptionRepo struct{}
+
+func (r *grpcCouponRedemptionRepo) Create(context.Context, *entity.CouponRedemption) error {
+	return nil
+}
+
+func (r *grpcCouponRedemptionRepo) Update(context.Context, *entity.CouponRedemption) error {
+	return nil
+}
+
+func (r *grpcCouponRedemptionRepo) FindActiveBySubscriptionID(context.Context, uint64) (*entity.CouponRedemption, error) {
+	return nil, nil
+}
+
+func (r *grpcCouponRedemptionRepo) EndActive(context.Context, uint64, time.Time) error {
+	return nil
+}
+
+func (r *grpcCouponRedemptionRepo) Delete(context.Context, uint64) error {
+	return nil
+}
+
+func (r *grpcCouponRedemptionRepo) ListByCouponID(context.Context, uint64) ([]*entity.CouponRedemption, error) {
+	return nil, nil
+}
+
+type grpcSubscriptionEventRepo struct{}
+
+func (r *grpcSubscriptionEventRepo) Create(context.Context, *entity.SubscriptionEvent) error {
+	return nil
+}
+
+func (r *grpcSubscriptionEventRepo) ListBySubscriptionID(context.Context, uint64) ([]*entity.SubscriptionEvent, error) {
+	return nil, nil
+}
+
 type grpcPayment struct {
 	result payment.Result
 }
 
-func (p *grpcPayment) ProcessSubscriptionPayment(context.Context, uint64, uint64, *string, *string) payment.Result {
+func (p *grpcPayment) ProcessSubscriptionPayment(context.Context, payment.Charge) payment.Result {
 	return p.result
 }
 
+func (p *grpcPayment) ProcessSubscriptionAdjustment(context.Context, payment.Adjustment) payment.Result {
+	return p.result
+}
+
+type grpcWebhookEndpointRepo struct{}
+
+func (r *grpcWebhookEndpointRepo) Create(context.Context, *entity.WebhookEndpoint) error { return nil }
+func (r *grpcWebhookEndpointRepo) Update(context.Context, *entity.WebhookEndpoint) error { return nil }
+func (r *grpcWebhookEndpointRepo) FindByID(context.Context, uint64) (*entity.WebhookEndpoint, error) {
+	return nil, nil
+}
+func (r *grpcWebhookEndpointRepo) List(context.Context) ([]*entity.WebhookEndpoint, error) {
+	return nil, nil
+}
+func (r *grpcWebhookEndpointRepo) ListActive(context.Context) ([]*entity.WebhookEndpoint, error) {
+	return nil, nil
+}
+func (r *grpcWebhookEndpointRepo) Delete(context.Context, uint64) error {
+	return repository.ErrWebhookEndpointNotFound
+}
+
+type grpcWebhookDeliveryRepo struct{}
+
+func (r *grpcWebhookDeliveryRepo) Create(context.Context, *entity.WebhookDelivery) error { return nil }
+func (r *grpcWebhookDeliveryRepo) Update(context.Context, *entity.WebhookDelivery) error { return nil }
+func (r *grpcWebhookDeliveryRepo) ClaimDue(context.Context, repository.WebhookDeliveryClaim) ([]*entity.WebhookDelivery, error) {
+	return nil, nil
+}
+func (r *grpcWebhookDeliveryRepo) ReleaseClaims(context.Context, string) error { return nil }
+func (r *grpcWebhookDeliveryRepo) ListByEndpointID(context.Context, uint64, int) ([]*entity.WebhookDelivery, error) {
+	return nil, nil
+}
+
 func newGRPCServerForTest(repo *grpcSubRepo, stRepo *grpcSubTypeRepo, planRepo *grpcPlanRepo, pay *grpcPayment) *Server {
 	cfg := config.SubscriptionConfig{
 		RenewBeforeEndMinutes:       time.Hour,
 		RenewalRetryIntervalMinutes: time.Minute,
-		MaxRenewalRetryAgeMinutes:   2 * time.Hour,
+		DunningSchedule:             []time.Duration{time.Hour, 2 * time.Hour},
 		PendingPaymentTimeout:       5 * time.Minute,
 	}
-	svc := service.NewSubscriptionService(repo, stRepo, planRepo, pay, cfg)
-	paymentCallbackSvc := service.NewPaymentCallbackService(repo, cfg)
-	return NewServer(svc, paymentCallbackSvc)
+	attemptRepo := &grpcPaymentAttemptRepo{}
+	eventRepo := &grpcSubscriptionEventRepo{}
+	outboxRepo := &grpcOutboxMessageRepo{}
+	couponRepo := &grpcCouponRepo{}
+	redemptionRepo := &grpcCouponRedemptionRepo{}
+	txManager := &grpcTxManager{}
+	svc := service.NewSubscriptionService(repo, stRepo, planRepo, attemptRepo, eventRepo, outboxRepo, couponRepo, redemptionRepo, txManager, pay, cfg)
+	paymentCallbackSvc := service.NewPaymentCallbackService(repo, planRepo, attemptRepo, eventRepo, outboxRepo, redemptionRepo, txManager, cfg)
+	webhookSvc := service.NewWebhookService(&grpcWebhookEndpointRepo{}, &grpcWebhookDeliveryRepo{}, nil, config.WebhookConfig{})
+	return NewServer(svc, paymentCallbackSvc, webhookSvc, service.NewCatalogService(stRepo, planRepo, couponRepo, redemptionRepo))
 }
 
 func TestCreateSubscriptionInvalidArgument(t *testing.T) {
@@ -188,6 +383,34 @@ func TestUpdateSubscriptionInvalidStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateSubscriptionInvalidTransition(t *testing.T) {
+	srv := newGRPCServerForTest(
+		&grpcSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 1, Status: entity.SubscriptionStatusInactive}, nil
+		}},
+		&grpcSubTypeRepo{}, &grpcPlanRepo{}, &grpcPayment{},
+	)
+
+	_, err := srv.UpdateSubscription(context.Background(), &types.UpdateSubscriptionRequest{Id: 1, HasStatus: true, Status: entity.SubscriptionStatusActive})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition, got %v", err)
+	}
+}
+
+func TestUpdateSubscriptionStaleVersion(t *testing.T) {
+	srv := newGRPCServerForTest(
+		&grpcSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 1, Status: entity.SubscriptionStatusActive, Version: 4}, nil
+		}},
+		&grpcSubTypeRepo{}, &grpcPlanRepo{}, &grpcPayment{},
+	)
+
+	_, err := srv.UpdateSubscription(context.Background(), &types.UpdateSubscriptionRequest{Id: 1, HasAutoRenew: true, ExpectedVersion: 3})
+	if status.Code(err) != codes.Aborted {
+		t.Fatalf("expected Aborted, got %v", err)
+	}
+}
+
 func TestDeleteSubscriptionNotFound(t *testing.T) {
 	srv := newGRPCServerForTest(
 		&grpcSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) { return nil, nil }},
@@ -200,14 +423,105 @@ func TestDeleteSubscriptionNotFound(t *testing.T) {
 	}
 }
 
+func TestChangePlanNotFound(t *testing.T) {
+	srv := newGRPCServerForTest(
+		&grpcSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) { return nil, nil }},
+		&grpcSubTypeRepo{}, &grpcPlanRepo{}, &grpcPayment{},
+	)
+
+	_, err := srv.ChangePlan(context.Background(), &types.ChangePlanRequest{Id: 2, PlanTypeId: 21})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+}
+
+func TestCancelSubscriptionRefundNeedsImmediateMode(t *testing.T) {
+	srv := newGRPCServerForTest(&grpcSubRepo{}, &grpcSubTypeRepo{}, &grpcPlanRepo{}, &grpcPayment{})
+
+	_, err := srv.CancelSubscription(context.Background(), &types.CancelSubscriptionRequest{Id: 2, Refund: true})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestResumeSubscriptionNotPaused(t *testing.T) {
+	srv := newGRPCServerForTest(
+		&grpcSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 2, Status: entity.SubscriptionStatusActive}, nil
+		}},
+		&grpcSubTypeRepo{}, &grpcPlanRepo{}, &grpcPayment{},
+	)
+
+	_, err := srv.ResumeSubscription(context.Background(), &types.ResumeSubscriptionRequest{Id: 2})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Fatalf("expected FailedPrecondition, got %v", err)
+	}
+}
+
 func TestPaymentCallbackInternalError(t *testing.T) {
 	srv := newGRPCServerForTest(
 		&grpcSubRepo{findByIDFn: func(context.Context, uint64) (*entity.Subscription, error) { return nil, errors.New("db down") }},
 		&grpcSubTypeRepo{}, &grpcPlanRepo{}, &grpcPayment{},
 	)
 
-	_, err := srv.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 1, Status: "success"})
+	_, err := srv.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 1, Status: "success", TransactionId: "tx-1"})
 	if status.Code(err) != codes.Internal {
 		t.Fatalf("expected Internal, got %v", err)
 	}
 }
+
+func TestListPaymentAttemptsInvalidArgument(t *testing.T) {
+	srv := newGRPCServerForTest(&grpcSubRepo{}, &grpcSubTypeRepo{}, &grpcPlanRepo{}, &grpcPayment{})
+
+	_, err := srv.ListPaymentAttempts(context.Background(), &types.ListPaymentAttemptsRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestWebhookEndpointNotFound(t *testing.T) {
+	srv := newGRPCServerForTest(&grpcSubRepo{}, &grpcSubTypeRepo{}, &grpcPlanRepo{}, &grpcPayment{})
+
+	_, err := srv.GetWebhookEndpoint(context.Background(), &types.GetWebhookEndpointRequest{Id: 4})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	_, err = srv.DeleteWebhookEndpoint(context.Background(), &types.DeleteWebhookEndpointRequest{Id: 4})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+}
+
+func TestGetPlanTypeNotFound(t *testing.T) {
+	srv := newGRPCServerForTest(&grpcSubRepo{}, &grpcSubTypeRepo{}, &grpcPlanRepo{}, &grpcPayment{})
+
+	_, err := srv.GetPlanType(context.Background(), &types.GetPlanTypeRequest{Id: 7})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	_, err = srv.GetPlanType(context.Background(), &types.GetPlanTypeRequest{})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
+
+func TestCreatePlanTypeSubscriptionTypeNotFound(t *testing.T) {
+	srv := newGRPCServerForTest(&grpcSubRepo{}, &grpcSubTypeRepo{}, &grpcPlanRepo{}, &grpcPayment{})
+
+	_, err := srv.CreatePlanType(context.Background(), &types.CreatePlanTypeRequest{
+		SubscriptionTypeId: 9,
+		PlanCode:           "premium-yearly",
+		DisplayName:        "Premium Yearly",
+		PriceCents:         19900,
+		Currency:           "USD",
+		IntervalUnit:       "year",
+		IntervalCount:      1,
+	})
+	if status.Code(err) != codes.NotFound {
+		t.Fatalf("expected NotFound, got %v", err)
+	}
+	_, err = srv.CreatePlanType(context.Background(), &types.CreatePlanTypeRequest{SubscriptionTypeId: 9, PlanCode: "Premium Yearly"})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected InvalidArgument, got %v", err)
+	}
+}
@@ -30,6 +30,94 @@ func SubscriptionTypesToProto(items []*entity.SubscriptionType) []*types.Subscri
 	return result
 }
 
+func PlanTypeToProto(item *entity.PlanType) *types.PlanType {
+	if item == nil {
+		return nil
+	}
+
+	return &types.PlanType{
+		Id:                 item.ID,
+		SubscriptionTypeId: item.SubscriptionTypeID,
+		PlanCode:           item.PlanCode,
+		DisplayName:        item.DisplayName,
+		Description:        item.Description,
+		PriceCents:         item.PriceCents,
+		Currency:           item.Currency,
+		IntervalUnit:       item.IntervalUnit,
+		IntervalCount:      item.IntervalCount,
+		Features:           item.Features,
+		CreatedAt:          item.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:          item.UpdatedAt.UTC().Format(time.RFC3339),
+		Status:             item.Status,
+		TrialDays:          item.TrialDays,
+	}
+}
+
+func PlanTypesToProto(items []*entity.PlanType) []*types.PlanType {
+	result := make([]*types.PlanType, 0, len(items))
+	for _, item := range items {
+		result = append(result, PlanTypeToProto(item))
+	}
+	return result
+}
+
+func CouponToProto(item *entity.Coupon) *types.Coupon {
+	if item == nil {
+		return nil
+	}
+
+	return &types.Coupon{
+		Id:                item.ID,
+		Code:              item.Code,
+		Description:       item.Description,
+		PercentOff:        item.PercentOff,
+		AmountOffCents:    item.AmountOffCents,
+		Currency:          item.Currency,
+		Duration:          item.Duration,
+		DurationInPeriods: item.DurationInPeriods,
+		MaxRedemptions:    item.MaxRedemptions,
+		TimesRedeemed:     item.TimesRedeemed,
+		PlanTypeIds:       item.PlanTypeIDs,
+		ExpiresAt:         formatTime(item.ExpiresAt),
+		Status:            item.Status,
+		CreatedAt:         item.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:         item.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func CouponsToProto(items []*entity.Coupon) []*types.Coupon {
+	result := make([]*types.Coupon, 0, len(items))
+	for _, item := range items {
+		result = append(result, CouponToProto(item))
+	}
+	return result
+}
+
+func CouponRedemptionToProto(item *entity.CouponRedemption) *types.CouponRedemption {
+	if item == nil {
+		return nil
+	}
+
+	return &types.CouponRedemption{
+		Id:              item.ID,
+		CouponId:        item.CouponID,
+		SubscriptionId:  item.SubscriptionID,
+		DurationPeriods: item.DurationPeriods,
+		PeriodsApplied:  item.PeriodsApplied,
+		Status:          item.Status,
+		CreatedAt:       item.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:       item.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func CouponRedemptionsToProto(items []*entity.CouponRedemption) []*types.CouponRedemption {
+	result := make([]*types.CouponRedemption, 0, len(items))
+	for _, item := range items {
+		result = append(result, CouponRedemptionToProto(item))
+	}
+	return result
+}
+
 func SubscriptionToProto(item *entity.Subscription) *types.Subscription {
 	if item == nil {
 		return nil
@@ -38,13 +126,23 @@ func SubscriptionToProto(item *entity.Subscription) *types.Subscription {
 	return &types.Subscription{
 		Id:                 item.ID,
 		SubscriptionTypeId: item.SubscriptionTypeID,
+		PlanTypeId:         derefUint64(item.PlanTypeID),
+		PendingPlanTypeId:  derefUint64(item.PendingPlanTypeID),
 		UserId:             derefString(item.UserID),
 		Email:              derefString(item.Email),
 		Status:             item.Status,
 		StartAt:            formatTime(item.StartAt),
 		EndAt:              formatTime(item.EndAt),
 		RenewAt:            formatTime(item.RenewAt),
+		TrialEndAt:         formatTime(item.TrialEndAt),
+		CanceledAt:         formatTime(item.CanceledAt),
+		CancelReason:       derefString(item.CancelReason),
+		PausedAt:           formatTime(item.PausedAt),
+		ResumeAt:           formatTime(item.ResumeAt),
+		RenewalRetryCount:  item.RenewalRetryCount,
+		Quantity:           item.Quantity,
 		AutoRenew:          item.AutoRenew,
+		Version:            item.Version,
 		CreatedAt:          item.CreatedAt.UTC().Format(time.RFC3339),
 		UpdatedAt:          item.UpdatedAt.UTC().Format(time.RFC3339),
 	}
@@ -58,6 +156,125 @@ func SubscriptionsToProto(items []*entity.Subscription) []*types.Subscription {
 	return result
 }
 
+func PaymentAttemptToProto(item *entity.PaymentAttempt) *types.PaymentAttempt {
+	if item == nil {
+		return nil
+	}
+
+	return &types.PaymentAttempt{
+		Id:             item.ID,
+		SubscriptionId: item.SubscriptionID,
+		PlanTypeId:     item.PlanTypeID,
+		Kind:           item.Kind,
+		AmountCents:    item.AmountCents,
+		Currency:       item.Currency,
+		DiscountCents:  item.DiscountCents,
+		Quantity:       item.Quantity,
+		TransactionId:  derefString(item.ProviderTransactionID),
+		ResultType:     item.ResultType,
+		Status:         item.Status,
+		Error:          item.Error,
+		CompletedAt:    formatTime(item.CompletedAt),
+		CreatedAt:      item.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:      item.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func PaymentAttemptsToProto(items []*entity.PaymentAttempt) []*types.PaymentAttempt {
+	result := make([]*types.PaymentAttempt, 0, len(items))
+	for _, item := range items {
+		result = append(result, PaymentAttemptToProto(item))
+	}
+	return result
+}
+
+func SubscriptionEventToProto(item *entity.SubscriptionEvent) *types.SubscriptionEvent {
+	if item == nil {
+		return nil
+	}
+
+	return &types.SubscriptionEvent{
+		Id:             item.ID,
+		SubscriptionId: item.SubscriptionID,
+		Field:          item.Field,
+		OldValue:       derefString(item.OldValue),
+		NewValue:       derefString(item.NewValue),
+		Actor:          item.Actor,
+		RequestId:      derefString(item.RequestID),
+		Reason:         item.Reason,
+		CreatedAt:      item.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func SubscriptionEventsToProto(items []*entity.SubscriptionEvent) []*types.SubscriptionEvent {
+	result := make([]*types.SubscriptionEvent, 0, len(items))
+	for _, item := range items {
+		result = append(result, SubscriptionEventToProto(item))
+	}
+	return result
+}
+
+// WebhookEndpointToProto never exposes the signing secret.
+func WebhookEndpointToProto(item *entity.WebhookEndpoint) *types.WebhookEndpoint {
+	if item == nil {
+		return nil
+	}
+
+	return &types.WebhookEndpoint{
+		Id:          item.ID,
+		Url:         item.URL,
+		Description: item.Description,
+		EventTypes:  item.EventTypes,
+		Status:      item.Status,
+		CreatedBy:   item.CreatedBy,
+		CreatedAt:   item.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:   item.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func WebhookEndpointsToProto(items []*entity.WebhookEndpoint) []*types.WebhookEndpoint {
+	result := make([]*types.WebhookEndpoint, 0, len(items))
+	for _, item := range items {
+		result = append(result, WebhookEndpointToProto(item))
+	}
+	return result
+}
+
+func WebhookDeliveryToProto(item *entity.WebhookDelivery) *types.WebhookDelivery {
+	if item == nil {
+		return nil
+	}
+
+	nextAttemptAt := ""
+	if item.Status == entity.WebhookDeliveryStatusPending {
+		nextAttemptAt = item.NextAttemptAt.UTC().Format(time.RFC3339)
+	}
+
+	return &types.WebhookDelivery{
+		Id:                item.ID,
+		WebhookEndpointId: item.WebhookEndpointID,
+		EventId:           item.EventID,
+		EventType:         item.EventType,
+		SubscriptionId:    item.SubscriptionID,
+		Status:            item.Status,
+		Attempts:          int32(item.Attempts),
+		ResponseStatus:    int32(item.ResponseStatus),
+		LastError:         item.LastError,
+		NextAttemptAt:     nextAttemptAt,
+		DeliveredAt:       formatTime(item.DeliveredAt),
+		CreatedAt:         item.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt:         item.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+}
+
+func WebhookDeliveriesToProto(items []*entity.WebhookDelivery) []*types.WebhookDelivery {
+	result := make([]*types.WebhookDelivery, 0, len(items))
+	for _, item := range items {
+		result = append(result, WebhookDeliveryToProto(item))
+	}
+	return result
+}
+
 func derefString(v *string) string {
 	if v == nil {
 		return ""
@@ -65,6 +282,13 @@ func derefString(v *string) string {
 	return *v
 }
 
+func derefUint64(v *uint64) uint64 {
+	if v == nil {
+		return 0
+	}
+	return *v
+}
+
 func formatTime(v *time.Time) string {
 	if v == nil {
 		return ""
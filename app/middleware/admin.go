@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	authmiddleware "github.com/vibast-solutions/lib-go-auth/middleware"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/factory"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
+)
+
+// AdminAccess restricts catalog management to the internal callers listed in
+// APP_ADMIN_SERVICES. It runs after internal auth, which identifies the caller;
+// with an empty list nobody is an admin.
+type AdminAccess struct {
+	services map[string]struct{}
+	logger   logrus.FieldLogger
+}
+
+func NewAdminAccess(services []string) *AdminAccess {
+	set := make(map[string]struct{}, len(services))
+	for _, service := range services {
+		set[service] = struct{}{}
+	}
+	return &AdminAccess{
+		services: set,
+		logger:   factory.NewModuleLogger("admin-access"),
+	}
+}
+
+// Allows reports whether the authenticated caller service has admin access.
+func (a *AdminAccess) Allows(caller string) bool {
+	if caller == "" {
+		return false
+	}
+	_, ok := a.services[caller]
+	return ok
+}
+
+func (a *AdminAccess) RequireAdmin(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		caller, _ := authmiddleware.CallerServiceFromContext(c)
+		if !a.Allows(caller) {
+			a.logger.WithFields(logrus.Fields{
+				"caller":     caller,
+				"uri":        c.Request().RequestURI,
+				"request_id": c.Response().Header().Get(echo.HeaderXRequestID),
+			}).Warn("Admin access denied")
+			return c.JSON(http.StatusForbidden, &types.ErrorResponse{Error: "admin access required"})
+		}
+		return next(c)
+	}
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	authmiddleware "github.com/vibast-solutions/lib-go-auth/middleware"
+)
+
+func serveAdmin(t *testing.T, access *AdminAccess, caller string) int {
+	t.Helper()
+
+	handler := access.RequireAdmin(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	rec := httptest.NewRecorder()
+	c := echo.New().NewContext(httptest.NewRequest(http.MethodPost, "/admin/plan-types", nil), rec)
+	if caller != "" {
+		c.Set(authmiddleware.ContextKeyCallerService, caller)
+	}
+	if err := handler(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return rec.Code
+}
+
+func TestRequireAdmin(t *testing.T) {
+	access := NewAdminAccess([]string{"backoffice-service"})
+
+	if code := serveAdmin(t, access, "backoffice-service"); code != http.StatusOK {
+		t.Fatalf("expected admin caller to pass, got %d", code)
+	}
+	if code := serveAdmin(t, access, "checkout-service"); code != http.StatusForbidden {
+		t.Fatalf("expected 403 for non-admin caller, got %d", code)
+	}
+	if code := serveAdmin(t, access, ""); code != http.StatusForbidden {
+		t.Fatalf("expected 403 without caller, got %d", code)
+	}
+	if code := serveAdmin(t, NewAdminAccess(nil), "backoffice-service"); code != http.StatusForbidden {
+		t.Fatalf("expected 403 when no admin services are configured, got %d", code)
+	}
+}
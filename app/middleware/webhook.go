@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/factory"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/webhooksig"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+const (
+	WebhookProviderHeader  = "X-Webhook-Provider"
+	WebhookTimestampHeader = webhooksig.TimestampHeader
+	WebhookSignatureHeader = webhooksig.SignatureHeader
+
+	webhookMaxBodyBytes = 1 << 20
+
+	contextKeyWebhookProvider = "webhook_provider"
+)
+
+// WebhookProviderFromContext returns the provider whose signature was verified for
+// the request, or an empty string when the request was authorized otherwise.
+func WebhookProviderFromContext(c echo.Context) string {
+	provider, _ := c.Get(contextKeyWebhookProvider).(string)
+	return provider
+}
+
+// WebhookSignatureMiddleware authenticates payment provider webhooks signed with a
+// per-provider shared secret (HMAC-SHA256). Requests older or newer than the
+// tolerance are rejected so captured deliveries cannot be replayed later.
+type WebhookSignatureMiddleware struct {
+	secrets   map[string]string
+	tolerance time.Duration
+	now       func() time.Time
+	logger    logrus.FieldLogger
+}
+
+func NewWebhookSignatureMiddleware(cfg config.PaymentConfig) *WebhookSignatureMiddleware {
+	return &WebhookSignatureMiddleware{
+		secrets:   cfg.WebhookSecrets,
+		tolerance: cfg.WebhookTolerance,
+		now:       time.Now,
+		logger:    factory.NewModuleLogger("webhook-signature"),
+	}
+}
+
+// RequireSignatureOr verifies signed requests and hands unsigned ones to fallback,
+// so internal callers can keep using their API key. A request that carries a
+// signature never falls back, even when the signature is wrong.
+func (m *WebhookSignatureMiddleware) RequireSignatureOr(fallback echo.MiddlewareFunc) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		withFallback := fallback(next)
+		return func(c echo.Context) error {
+			if strings.TrimSpace(c.Request().Header.Get(WebhookSignatureHeader)) == "" {
+				return withFallback(c)
+			}
+
+			provider, reason := m.verify(c)
+			if reason != "" {
+				m.logger.WithFields(logrus.Fields{
+					"provider":   provider,
+					"reason":     reason,
+					"remote_ip":  c.RealIP(),
+					"request_id": c.Response().Header().Get(echo.HeaderXRequestID),
+				}).Warn("Webhook signature rejected")
+				return c.JSON(http.StatusUnauthorized, &types.ErrorResponse{Error: "invalid webhook signature"})
+			}
+
+			c.Set(contextKeyWebhookProvider, provider)
+			return next(c)
+		}
+	}
+}
+
+// verify checks the signature headers and returns the provider name and, when the
+// request must be rejected, the reason.
+func (m *WebhookSignatureMiddleware) verify(c echo.Context) (string, string) {
+	req := c.Request()
+	provider := strings.ToLower(strings.TrimSpace(req.Header.Get(WebhookProviderHeader)))
+	if provider == "" {
+		return "", "missing provider header"
+	}
+	secret, ok := m.secrets[provider]
+	if !ok {
+		return provider, "unknown provider"
+	}
+
+	timestamp, err := strconv.ParseInt(strings.TrimSpace(req.Header.Get(WebhookTimestampHeader)), 10, 64)
+	if err != nil {
+		return provider, "invalid timestamp header"
+	}
+	age := m.now().Sub(time.Unix(timestamp, 0))
+	if age > m.tolerance || age < -m.tolerance {
+		return provider, "timestamp outside replay window"
+	}
+
+	body, err := io.ReadAll(io.LimitReader(req.Body, webhookMaxBodyBytes+1))
+	if err != nil {
+		return provider, "unreadable body"
+	}
+	if len(body) > webhookMaxBodyBytes {
+		return provider, "body too large"
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	if !webhooksig.Verify(secret, timestamp, body, strings.TrimSpace(req.Header.Get(WebhookSignatureHeader))) {
+		return provider, "signature mismatch"
+	}
+
+	return provider, ""
+}
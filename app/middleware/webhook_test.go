@@ -0,0 +1,102 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/webhooksig"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+const testWebhookBody = `{"subscription_id":1,"status":"success","transaction_id":"ch_1"}`
+
+func newWebhookMiddlewareForTest(now time.Time) *WebhookSignatureMiddleware {
+	m := NewWebhookSignatureMiddleware(config.PaymentConfig{
+		WebhookSecrets:   map[string]string{"acme": "secret"},
+		WebhookTolerance: 5 * time.Minute,
+	})
+	m.now = func() time.Time { return now }
+	return m
+}
+
+func serveWebhook(t *testing.T, m *WebhookSignatureMiddleware, headers map[string]string) (*httptest.ResponseRecorder, string, bool) {
+	t.Helper()
+
+	var seenBody, seenProvider string
+	fallbackCalled := false
+	fallback := func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			fallbackCalled = true
+			return c.NoContent(http.StatusUnauthorized)
+		}
+	}
+	handler := m.RequireSignatureOr(fallback)(func(c echo.Context) error {
+		body, _ := io.ReadAll(c.Request().Body)
+		seenBody = string(body)
+		seenProvider = WebhookProviderFromContext(c)
+		return c.NoContent(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/payment-callback", strings.NewReader(testWebhookBody))
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	rec := httptest.NewRecorder()
+	if err := handler(echo.New().NewContext(req, rec)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code == http.StatusOK && seenBody != testWebhookBody {
+		t.Fatalf("expected handler to read the original body, got %q", seenBody)
+	}
+	return rec, seenProvider, fallbackCalled
+}
+
+func signedHeaders(secret string, ts time.Time) map[string]string {
+	return map[string]string{
+		WebhookProviderHeader:  "Acme",
+		WebhookTimestampHeader: strconv.FormatInt(ts.Unix(), 10),
+		WebhookSignatureHeader: webhooksig.Sign(secret, ts.Unix(), []byte(testWebhookBody)),
+	}
+}
+
+func TestWebhookSignatureAcceptsValidSignature(t *testing.T) {
+	now := time.Now()
+	rec, provider, fallbackCalled := serveWebhook(t, newWebhookMiddlewareForTest(now), signedHeaders("secret", now.Add(-time.Minute)))
+	if rec.Code != http.StatusOK || provider != "acme" || fallbackCalled {
+		t.Fatalf("expected signed request to pass, got code=%d provider=%q fallback=%v", rec.Code, provider, fallbackCalled)
+	}
+}
+
+func TestWebhookSignatureFallsBackWhenUnsigned(t *testing.T) {
+	_, _, fallbackCalled := serveWebhook(t, newWebhookMiddlewareForTest(time.Now()), nil)
+	if !fallbackCalled {
+		t.Fatal("expected unsigned request to use the fallback middleware")
+	}
+}
+
+func TestWebhookSignatureRejectsInvalidRequests(t *testing.T) {
+	now := time.Now()
+	unknownProvider := signedHeaders("secret", now)
+	unknownProvider[WebhookProviderHeader] = "other"
+
+	cases := map[string]map[string]string{
+		"wrong secret":     signedHeaders("not-the-secret", now),
+		"stale timestamp":  signedHeaders("secret", now.Add(-10*time.Minute)),
+		"future timestamp": signedHeaders("secret", now.Add(10*time.Minute)),
+		"unknown provider": unknownProvider,
+	}
+	for name, headers := range cases {
+		t.Run(name, func(t *testing.T) {
+			rec, _, fallbackCalled := serveWebhook(t, newWebhookMiddlewareForTest(now), headers)
+			if rec.Code != http.StatusUnauthorized || fallbackCalled {
+				t.Fatalf("expected 401 without fallback, got code=%d fallback=%v", rec.Code, fallbackCalled)
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package payment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+const (
+	chargesPath          = "/v1/charges"
+	checkoutSessionsPath = "/v1/checkout-sessions"
+	creditsPath          = "/v1/credits"
+
+	idempotencyKeyHeader = "Idempotency-Key"
+)
+
+// Charge statuses reported by the provider.
+const (
+	ChargeStatusSucceeded             = "succeeded"
+	ChargeStatusFailed                = "failed"
+	ChargeStatusRequiresAction        = "requires_action"
+	ChargeStatusRequiresPaymentMethod = "requires_payment_method"
+)
+
+// Credit statuses reported by the provider.
+const (
+	CreditStatusSucceeded = "succeeded"
+	CreditStatusFailed    = "failed"
+)
+
+// ChargeRequest is the body sent to the provider when charging a subscription.
+// Plan payments carry the seat quantity and the discounted price of all seats,
+// adjustments the prorated amount.
+type ChargeRequest struct {
+	Reference      string `json:"reference"`
+	SubscriptionID uint64 `json:"subscription_id"`
+	PlanTypeID     uint64 `json:"plan_type_id"`
+	Quantity       int32  `json:"quantity,omitempty"`
+	AmountCents    int64  `json:"amount_cents,omitempty"`
+	Currency       string `json:"currency,omitempty"`
+	UserID         string `json:"user_id,omitempty"`
+	Email          string `json:"email,omitempty"`
+}
+
+// ChargeResponse is the provider answer to a charge request.
+type ChargeResponse struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	RedirectURL    string `json:"redirect_url,omitempty"`
+	FailureMessage string `json:"failure_message,omitempty"`
+}
+
+// CheckoutRequest asks the provider for a hosted checkout page.
+type CheckoutRequest struct {
+	ChargeRequest
+	ReturnURL string `json:"return_url,omitempty"`
+}
+
+// CheckoutResponse carries the hosted checkout page the customer is redirected to.
+type CheckoutResponse struct {
+	ID  string `json:"id"`
+	URL string `json:"url"`
+}
+
+// CreditRequest gives money back to the customer of a subscription.
+type CreditRequest struct {
+	Reference      string `json:"reference"`
+	SubscriptionID uint64 `json:"subscription_id"`
+	PlanTypeID     uint64 `json:"plan_type_id"`
+	AmountCents    int64  `json:"amount_cents"`
+	Currency       string `json:"currency"`
+	UserID         string `json:"user_id,omitempty"`
+	Email          string `json:"email,omitempty"`
+}
+
+// CreditResponse is the provider answer to a credit request.
+type CreditResponse struct {
+	ID             string `json:"id"`
+	Status         string `json:"status"`
+	FailureMessage string `json:"failure_message,omitempty"`
+}
+
+// ErrorResponse is the provider error payload.
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// HTTPService charges subscriptions through an external provider reachable over HTTP.
+// Charges are attempted against the customer's stored payment method first; when the
+// provider needs customer interaction a hosted checkout session is created instead.
+type HTTPService struct {
+	baseURL      string
+	apiKey       string
+	returnURL    string
+	maxRetries   int
+	retryBackoff time.Duration
+	client       *http.Client
+}
+
+func NewHTTPService(cfg config.PaymentConfig) *HTTPService {
+	return &HTTPService{
+		baseURL:      strings.TrimRight(cfg.HTTPBaseURL, "/"),
+		apiKey:       cfg.HTTPAPIKey,
+		returnURL:    cfg.CheckoutReturnURL,
+		maxRetries:   cfg.HTTPMaxRetries,
+		retryBackoff: cfg.HTTPRetryBackoff,
+		client:       &http.Client{Timeout: cfg.HTTPTimeout},
+	}
+}
+
+func (s *HTTPService) ProcessSubscriptionPayment(ctx context.Context, charge Charge) Result {
+	req := ChargeRequest{
+		Reference:      fmt.Sprintf("subscription-%d", charge.SubscriptionID),
+		SubscriptionID: charge.SubscriptionID,
+		PlanTypeID:     charge.PlanTypeID,
+		Quantity:       charge.Quantity,
+		AmountCents:    charge.AmountCents,
+		Currency:       charge.Currency,
+		UserID:         derefString(charge.UserID),
+		Email:          derefString(charge.Email),
+	}
+	return s.charge(ctx, charge.IdempotencyKey, req)
+}
+
+// ProcessSubscriptionAdjustment charges positive amounts like a plan payment and
+// credits negative amounts back to the customer.
+func (s *HTTPService) ProcessSubscriptionAdjustment(ctx context.Context, adjustment Adjustment) Result {
+	reference := fmt.Sprintf("subscription-%d-adjustment", adjustment.SubscriptionID)
+	if adjustment.AmountCents >= 0 {
+		return s.charge(ctx, adjustment.IdempotencyKey, ChargeRequest{
+			Reference:      reference,
+			SubscriptionID: adjustment.SubscriptionID,
+			PlanTypeID:     adjustment.PlanTypeID,
+			AmountCents:    adjustment.AmountCents,
+			Currency:       adjustment.Currency,
+			UserID:         derefString(adjustment.UserID),
+			Email:          derefString(adjustment.Email),
+		})
+	}
+
+	req := CreditRequest{
+		Reference:      reference,
+		SubscriptionID: adjustment.SubscriptionID,
+		PlanTypeID:     adjustment.PlanTypeID,
+		AmountCents:    -adjustment.AmountCents,
+		Currency:       adjustment.Currency,
+		UserID:         derefString(adjustment.UserID),
+		Email:          derefString(adjustment.Email),
+	}
+
+	var credit CreditResponse
+	if err := s.post(ctx, creditsPath, adjustment.IdempotencyKey, req, &credit); err != nil {
+		return Result{Type: ResultTypeFailure, Error: err.Error()}
+	}
+
+	switch credit.Status {
+	case CreditStatusSucceeded:
+		return Result{Type: ResultTypeSuccess, TransactionID: credit.ID}
+	case CreditStatusFailed:
+		message := credit.FailureMessage
+		if message == "" {
+			message = "credit failed"
+		}
+		return Result{Type: ResultTypeFailure, TransactionID: credit.ID, Error: message}
+	default:
+		return Result{Type: ResultTypeFailure, TransactionID: credit.ID, Error: fmt.Sprintf("unexpected credit status %q", credit.Status)}
+	}
+}
+
+func (s *HTTPService) charge(ctx context.Context, idempotencyKey string, req ChargeRequest) Result {
+	var charge ChargeResponse
+	if err := s.post(ctx, chargesPath, idempotencyKey, req, &charge); err != nil {
+		return Result{Type: ResultTypeFailure, Error: err.Error()}
+	}
+
+	switch charge.Status {
+	case ChargeStatusSucceeded:
+		return Result{Type: ResultTypeSuccess, TransactionID: charge.ID}
+	case ChargeStatusRequiresAction:
+		if charge.RedirectURL != "" {
+			return Result{Type: ResultTypeRedirect, TransactionID: charge.ID, PaymentURL: charge.RedirectURL}
+		}
+		return s.createCheckout(ctx, idempotencyKey, req)
+	case ChargeStatusRequiresPaymentMethod:
+		return s.createCheckout(ctx, idempotencyKey, req)
+	case ChargeStatusFailed:
+		message := charge.FailureMessage
+		if message == "" {
+			message = "payment failed"
+		}
+		return Result{Type: ResultTypeFailure, TransactionID: charge.ID, Error: message}
+	default:
+		return Result{Type: ResultTypeFailure, TransactionID: charge.ID, Error: fmt.Sprintf("unexpected charge status %q", charge.Status)}
+	}
+}
+
+// createCheckout opens a hosted checkout for a charge the provider could not take
+// on its own. The session gets a key derived from the charge's, so a retried
+// attempt is sent to the same checkout page.
+func (s *HTTPService) createCheckout(ctx context.Context, idempotencyKey string, charge ChargeRequest) Result {
+	if idempotencyKey != "" {
+		idempotencyKey += "-checkout"
+	}
+	var checkout CheckoutResponse
+	if err := s.post(ctx, checkoutSessionsPath, idempotencyKey, CheckoutRequest{ChargeRequest: charge, ReturnURL: s.returnURL}, &checkout); err != nil {
+		return Result{Type: ResultTypeFailure, Error: err.Error()}
+	}
+	if checkout.URL == "" {
+		return Result{Type: ResultTypeFailure, TransactionID: checkout.ID, Error: "checkout session has no url"}
+	}
+	return Result{Type: ResultTypeRedirect, TransactionID: checkout.ID, PaymentURL: checkout.URL}
+}
+
+// post sends body to path under idempotencyKey and decodes the answer into out.
+// Transport errors, 429 and 5xx answers are retried with exponential backoff;
+// every retry reuses the key, and so does every later call for the same payment
+// attempt, so the provider never charges twice for one attempt. Calls without a
+// key get a fresh one.
+func (s *HTTPService) post(ctx context.Context, path, idempotencyKey string, body, out interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	if idempotencyKey == "" {
+		idempotencyKey = uuid.NewString()
+	}
+	var lastErr error
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		if attempt > 0 {
+			if err := sleepContext(ctx, s.retryBackoff*time.Duration(1<<(attempt-1))); err != nil {
+				return err
+			}
+		}
+
+		retryable, err := s.do(ctx, path, payload, idempotencyKey, out)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retryable {
+			return err
+		}
+	}
+
+	return fmt.Errorf("payment provider unavailable after %d attempts: %w", s.maxRetries+1, lastErr)
+}
+
+func (s *HTTPService) do(ctx context.Context, path string, payload []byte, idempotencyKey string, out interface{}) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+path, bytes.NewReader(payload))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(idempotencyKeyHeader, idempotencyKey)
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return true, err
+	}
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		if err := json.Unmarshal(data, out); err != nil {
+			return false, fmt.Errorf("invalid payment provider response: %w", err)
+		}
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, providerError(resp.StatusCode, data)
+	default:
+		return false, providerError(resp.StatusCode, data)
+	}
+}
+
+func providerError(statusCode int, body []byte) error {
+	var payload ErrorResponse
+	if err := json.Unmarshal(body, &payload); err == nil && payload.Error != "" {
+		return fmt.Errorf("payment provider returned %d: %s", statusCode, payload.Error)
+	}
+	return fmt.Errorf("payment provider returned %d", statusCode)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func derefString(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
@@ -0,0 +1,162 @@
+package payment_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/payment/paymenttest"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+func newHTTPServiceForTest(t *testing.T, provider http.Handler) *payment.HTTPService {
+	t.Helper()
+	srv := httptest.NewServer(provider)
+	t.Cleanup(srv.Close)
+
+	return payment.NewHTTPService(config.PaymentConfig{
+		Provider:          config.PaymentProviderHTTP,
+		HTTPBaseURL:       srv.URL,
+		HTTPAPIKey:        "test-key",
+		HTTPTimeout:       time.Second,
+		HTTPMaxRetries:    2,
+		HTTPRetryBackoff:  time.Millisecond,
+		CheckoutReturnURL: "https://app.local/return",
+	})
+}
+
+func TestHTTPServiceChargeSuccess(t *testing.T) {
+	provider := paymenttest.NewProvider()
+	svc := newHTTPServiceForTest(t, provider)
+
+	userID := "u-1"
+	res := svc.ProcessSubscriptionPayment(context.Background(), payment.Charge{SubscriptionID: 7, PlanTypeID: 3, AmountCents: 1500, Currency: "EUR", UserID: &userID})
+	if res.Type != payment.ResultTypeSuccess || res.TransactionID == "" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	charges := provider.Charges()
+	if len(charges) != 1 || charges[0].SubscriptionID != 7 || charges[0].PlanTypeID != 3 || charges[0].AmountCents != 1500 || charges[0].Currency != "EUR" || charges[0].UserID != "u-1" {
+		t.Fatalf("unexpected charges: %+v", charges)
+	}
+}
+
+func TestHTTPServiceCreatesCheckoutWhenPaymentMethodMissing(t *testing.T) {
+	provider := paymenttest.NewProvider()
+	provider.Outcome = func(payment.ChargeRequest) string { return payment.ChargeStatusRequiresPaymentMethod }
+	svc := newHTTPServiceForTest(t, provider)
+
+	res := svc.ProcessSubscriptionPayment(context.Background(), payment.Charge{SubscriptionID: 8, PlanTypeID: 3})
+	if res.Type != payment.ResultTypeRedirect || res.PaymentURL == "" || res.TransactionID == "" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+
+	checkouts := provider.Checkouts()
+	if len(checkouts) != 1 || checkouts[0].ReturnURL != "https://app.local/return" {
+		t.Fatalf("unexpected checkouts: %+v", checkouts)
+	}
+}
+
+func TestHTTPServiceChargeDeclined(t *testing.T) {
+	provider := paymenttest.NewProvider()
+	provider.Outcome = func(payment.ChargeRequest) string { return payment.ChargeStatusFailed }
+	svc := newHTTPServiceForTest(t, provider)
+
+	res := svc.ProcessSubscriptionPayment(context.Background(), payment.Charge{SubscriptionID: 9, PlanTypeID: 3})
+	if res.Type != payment.ResultTypeFailure || res.Error != "card declined" {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestHTTPServiceRetriesUnavailableProvider(t *testing.T) {
+	provider := paymenttest.NewProvider()
+	provider.FailNext(2)
+	svc := newHTTPServiceForTest(t, provider)
+
+	res := svc.ProcessSubscriptionPayment(context.Background(), payment.Charge{SubscriptionID: 10, PlanTypeID: 3})
+	if res.Type != payment.ResultTypeSuccess {
+		t.Fatalf("expected success after retries, got %+v", res)
+	}
+	if got := len(provider.Charges()); got != 1 {
+		t.Fatalf("expected exactly one charge, got %d", got)
+	}
+}
+
+func TestHTTPServiceGivesUpAfterMaxRetries(t *testing.T) {
+	provider := paymenttest.NewProvider()
+	provider.FailNext(3)
+	svc := newHTTPServiceForTest(t, provider)
+
+	res := svc.ProcessSubscriptionPayment(context.Background(), payment.Charge{SubscriptionID: 11, PlanTypeID: 3})
+	if res.Type != payment.ResultTypeFailure || !strings.Contains(res.Error, "unavailable after 3 attempts") {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+}
+
+func TestHTTPServiceRepeatedAttemptIsChargedOnce(t *testing.T) {
+	provider := paymenttest.NewProvider()
+	svc := newHTTPServiceForTest(t, provider)
+
+	charge := payment.Charge{IdempotencyKey: "attempt-7", SubscriptionID: 11, PlanTypeID: 3, AmountCents: 1500, Currency: "EUR"}
+	first := svc.ProcessSubscriptionPayment(context.Background(), charge)
+	second := svc.ProcessSubscriptionPayment(context.Background(), charge)
+	if first.Type != payment.ResultTypeSuccess || second.TransactionID != first.TransactionID {
+		t.Fatalf("expected the repeated attempt to get the original charge, got %+v and %+v", first, second)
+	}
+	if got := len(provider.Charges()); got != 1 {
+		t.Fatalf("expected exactly one charge, got %d", got)
+	}
+
+	charge.IdempotencyKey = "attempt-8"
+	if res := svc.ProcessSubscriptionPayment(context.Background(), charge); res.TransactionID == first.TransactionID {
+		t.Fatalf("expected a new attempt to be charged anew, got %+v", res)
+	}
+}
+
+func TestHTTPServiceDoesNotRetryClientErrors(t *testing.T) {
+	calls := 0
+	svc := newHTTPServiceForTest(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("Authorization") != "Bearer test-key" || r.Header.Get("Idempotency-Key") == "" {
+			t.Errorf("missing auth or idempotency headers: %v", r.Header)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"error":"unknown plan"}`))
+	}))
+
+	res := svc.ProcessSubscriptionPayment(context.Background(), payment.Charge{SubscriptionID: 12, PlanTypeID: 99})
+	if res.Type != payment.ResultTypeFailure || !strings.Contains(res.Error, "unknown plan") {
+		t.Fatalf("unexpected result: %+v", res)
+	}
+	if calls != 1 {
+		t.Fatalf("expected a single call, got %d", calls)
+	}
+}
+
+func TestHTTPServiceAdjustmentChargesAndCredits(t *testing.T) {
+	provider := paymenttest.NewProvider()
+	svc := newHTTPServiceForTest(t, provider)
+
+	res := svc.ProcessSubscriptionAdjustment(context.Background(), payment.Adjustment{SubscriptionID: 13, PlanTypeID: 4, AmountCents: 250, Currency: "EUR"})
+	if res.Type != payment.ResultTypeSuccess {
+		t.Fatalf("unexpected charge result: %+v", res)
+	}
+	charges := provider.Charges()
+	if len(charges) != 1 || charges[0].AmountCents != 250 || charges[0].Currency != "EUR" {
+		t.Fatalf("unexpected charges: %+v", charges)
+	}
+
+	res = svc.ProcessSubscriptionAdjustment(context.Background(), payment.Adjustment{SubscriptionID: 13, PlanTypeID: 5, AmountCents: -120, Currency: "EUR"})
+	if res.Type != payment.ResultTypeSuccess || !strings.HasPrefix(res.TransactionID, "cr_") {
+		t.Fatalf("unexpected credit result: %+v", res)
+	}
+	credits := provider.Credits()
+	if len(credits) != 1 || credits[0].AmountCents != 120 || credits[0].PlanTypeID != 5 {
+		t.Fatalf("unexpected credits: %+v", credits)
+	}
+}
@@ -17,6 +17,37 @@ type Result struct {
 	Error         string
 }
 
+// Charge is a regular plan payment. AmountCents is the plan price times Quantity,
+// less any coupon discount of the subscription.
+type Charge struct {
+	// IdempotencyKey identifies the payment attempt the charge belongs to. The
+	// provider answers a repeated key with the original outcome instead of
+	// charging again, so a retried attempt is never billed twice.
+	IdempotencyKey string
+	SubscriptionID uint64
+	PlanTypeID     uint64
+	Quantity       int32
+	AmountCents    int64
+	Currency       string
+	UserID         *string
+	Email          *string
+}
+
+// Adjustment is a one-off amount billed outside the regular plan price, such as
+// the prorated difference of a plan change. Positive amounts are charged to the
+// customer, negative amounts are credited back.
+type Adjustment struct {
+	// IdempotencyKey identifies the payment attempt, as for Charge.
+	IdempotencyKey string
+	SubscriptionID uint64
+	PlanTypeID     uint64
+	AmountCents    int64
+	Currency       string
+	UserID         *string
+	Email          *string
+}
+
 type Service interface {
-	ProcessSubscriptionPayment(ctx context.Context, subscriptionID uint64, planTypeID uint64, userID *string, email *string) Result
+	ProcessSubscriptionPayment(ctx context.Context, charge Charge) Result
+	ProcessSubscriptionAdjustment(ctx context.Context, adjustment Adjustment) Result
 }
@@ -0,0 +1,158 @@
+// Package paymenttest provides a local fake payment provider that speaks the
+// protocol used by payment.HTTPService.
+package paymenttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
+)
+
+// Provider is an in-memory payment provider. It is safe for concurrent use and
+// can be mounted on any http server (httptest.NewServer in unit tests, a real
+// listener in the e2e suite).
+type Provider struct {
+	// Outcome picks the charge status for a request. Defaults to succeeded.
+	Outcome func(req payment.ChargeRequest) string
+
+	mu        sync.Mutex
+	seq       int
+	failNext  int
+	charges   []payment.ChargeRequest
+	checkouts []payment.CheckoutRequest
+	credits   []payment.CreditRequest
+	replies   map[string]interface{}
+}
+
+func NewProvider() *Provider {
+	return &Provider{replies: make(map[string]interface{})}
+}
+
+// FailNext makes the next n requests answer 503 Service Unavailable.
+func (p *Provider) FailNext(n int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failNext = n
+}
+
+// Charges returns the charge requests accepted so far.
+func (p *Provider) Charges() []payment.ChargeRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]payment.ChargeRequest(nil), p.charges...)
+}
+
+// Checkouts returns the checkout sessions created so far.
+func (p *Provider) Checkouts() []payment.CheckoutRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]payment.CheckoutRequest(nil), p.checkouts...)
+}
+
+// Credits returns the credit requests accepted so far.
+func (p *Provider) Credits() []payment.CreditRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]payment.CreditRequest(nil), p.credits...)
+}
+
+func (p *Provider) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, payment.ErrorResponse{Error: "method not allowed"})
+		return
+	}
+
+	p.mu.Lock()
+	if p.failNext > 0 {
+		p.failNext--
+		p.mu.Unlock()
+		writeJSON(w, http.StatusServiceUnavailable, payment.ErrorResponse{Error: "temporarily unavailable"})
+		return
+	}
+	key := r.URL.Path + ":" + r.Header.Get("Idempotency-Key")
+	if reply, ok := p.replies[key]; ok {
+		p.mu.Unlock()
+		writeJSON(w, http.StatusOK, reply)
+		return
+	}
+	p.mu.Unlock()
+
+	var reply interface{}
+	switch r.URL.Path {
+	case "/v1/charges":
+		var req payment.ChargeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, payment.ErrorResponse{Error: "invalid body"})
+			return
+		}
+		reply = p.charge(req)
+	case "/v1/checkout-sessions":
+		var req payment.CheckoutRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, payment.ErrorResponse{Error: "invalid body"})
+			return
+		}
+		reply = p.checkout(req)
+	case "/v1/credits":
+		var req payment.CreditRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeJSON(w, http.StatusBadRequest, payment.ErrorResponse{Error: "invalid body"})
+			return
+		}
+		reply = p.credit(req)
+	default:
+		writeJSON(w, http.StatusNotFound, payment.ErrorResponse{Error: "not found"})
+		return
+	}
+
+	p.mu.Lock()
+	p.replies[key] = reply
+	p.mu.Unlock()
+	writeJSON(w, http.StatusOK, reply)
+}
+
+func (p *Provider) charge(req payment.ChargeRequest) payment.ChargeResponse {
+	status := payment.ChargeStatusSucceeded
+	if p.Outcome != nil {
+		status = p.Outcome(req)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seq++
+	p.charges = append(p.charges, req)
+
+	resp := payment.ChargeResponse{ID: fmt.Sprintf("ch_%d", p.seq), Status: status}
+	if status == payment.ChargeStatusFailed {
+		resp.FailureMessage = "card declined"
+	}
+	return resp
+}
+
+func (p *Provider) checkout(req payment.CheckoutRequest) payment.CheckoutResponse {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seq++
+	p.checkouts = append(p.checkouts, req)
+
+	id := fmt.Sprintf("cs_%d", p.seq)
+	return payment.CheckoutResponse{ID: id, URL: "https://checkout.local/" + id}
+}
+
+func (p *Provider) credit(req payment.CreditRequest) payment.CreditResponse {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.seq++
+	p.credits = append(p.credits, req)
+
+	return payment.CreditResponse{ID: fmt.Sprintf("cr_%d", p.seq), Status: payment.CreditStatusSucceeded}
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
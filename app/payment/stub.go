@@ -8,6 +8,10 @@ func NewStubService() *StubService {
 	return &StubService{}
 }
 
-func (s *StubService) ProcessSubscriptionPayment(_ context.Context, _ uint64, _ uint64, _ *string, _ *string) Result {
+func (s *StubService) ProcessSubscriptionPayment(_ context.Context, _ Charge) Result {
 	panic("payments for renewals are not implemented")
 }
+
+func (s *StubService) ProcessSubscriptionAdjustment(_ context.Context, _ Adjustment) Result {
+	panic("payment adjustments are not implemented")
+}
@@ -19,5 +19,17 @@ func TestStubServicePanics(t *testing.T) {
 		}
 	}()
 
-	_ = svc.ProcessSubscriptionPayment(context.Background(), 1, 2, nil, nil)
+	_ = svc.ProcessSubscriptionPayment(context.Background(), Charge{SubscriptionID: 1, PlanTypeID: 2})
+}
+
+func TestStubServiceAdjustmentPanics(t *testing.T) {
+	svc := NewStubService()
+
+	defer func() {
+		if rec := recover(); rec == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+
+	_ = svc.ProcessSubscriptionAdjustment(context.Background(), Adjustment{SubscriptionID: 1, AmountCents: 100})
 }
@@ -0,0 +1,64 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+const (
+	EventIDHeader   = "X-Event-Id"
+	EventTypeHeader = "X-Event-Type"
+)
+
+// HTTPPublisher POSTs every event as JSON to a single webhook URL. Any 2xx answer
+// acknowledges the event; consumers should deduplicate on X-Event-Id.
+type HTTPPublisher struct {
+	url    string
+	apiKey string
+	client *http.Client
+}
+
+func NewHTTPPublisher(cfg config.OutboxConfig) *HTTPPublisher {
+	return &HTTPPublisher{
+		url:    cfg.HTTPURL,
+		apiKey: cfg.HTTPAPIKey,
+		client: &http.Client{Timeout: cfg.HTTPTimeout},
+	}
+}
+
+func (p *HTTPPublisher) Publish(ctx context.Context, message Message) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, strconv.FormatUint(message.ID, 10))
+	req.Header.Set(EventTypeHeader, message.Type)
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("event sink returned %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package publisher_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/publisher"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+func newHTTPPublisherForTest(t *testing.T, handler http.HandlerFunc) *publisher.HTTPPublisher {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	return publisher.NewHTTPPublisher(config.OutboxConfig{
+		Publisher:   config.OutboxPublisherHTTP,
+		HTTPURL:     srv.URL,
+		HTTPAPIKey:  "sink-key",
+		HTTPTimeout: time.Second,
+	})
+}
+
+func TestHTTPPublisherPostsMessage(t *testing.T) {
+	var received publisher.Message
+	var headers http.Header
+	pub := newHTTPPublisherForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	err := pub.Publish(context.Background(), publisher.Message{
+		ID:             9,
+		Type:           "subscription.activated",
+		SubscriptionID: 4,
+		OccurredAt:     time.Now().UTC(),
+		Data:           json.RawMessage(`{"reason":"payment_succeeded"}`),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if received.ID != 9 || received.SubscriptionID != 4 || string(received.Data) != `{"reason":"payment_succeeded"}` {
+		t.Fatalf("unexpected message: %+v", received)
+	}
+	if headers.Get(publisher.EventIDHeader) != "9" || headers.Get(publisher.EventTypeHeader) != "subscription.activated" {
+		t.Fatalf("unexpected event headers: %v", headers)
+	}
+	if headers.Get("Authorization") != "Bearer sink-key" {
+		t.Fatalf("expected api key header, got %q", headers.Get("Authorization"))
+	}
+}
+
+func TestHTTPPublisherFailsOnErrorStatus(t *testing.T) {
+	pub := newHTTPPublisherForTest(t, func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+
+	if err := pub.Publish(context.Background(), publisher.Message{ID: 1, Data: json.RawMessage(`{}`)}); err == nil {
+		t.Fatal("expected error for non-2xx answer")
+	}
+}
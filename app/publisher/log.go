@@ -0,0 +1,28 @@
+package publisher
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/factory"
+)
+
+// LogPublisher writes every event to the service log. It never fails.
+type LogPublisher struct {
+	logger logrus.FieldLogger
+}
+
+func NewLogPublisher() *LogPublisher {
+	return &LogPublisher{logger: factory.NewModuleLogger("event-publisher")}
+}
+
+func (p *LogPublisher) Publish(_ context.Context, message Message) error {
+	p.logger.WithFields(logrus.Fields{
+		"event_id":        message.ID,
+		"event_type":      message.Type,
+		"subscription_id": message.SubscriptionID,
+		"occurred_at":     message.OccurredAt,
+		"data":            string(message.Data),
+	}).Info("subscription_event")
+	return nil
+}
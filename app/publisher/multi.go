@@ -0,0 +1,21 @@
+package publisher
+
+import (
+	"context"
+	"errors"
+)
+
+// Multi hands every message to each of its publishers. The message counts as
+// published only when all of them succeed, so publishers must tolerate receiving
+// a message they already accepted.
+type Multi []Publisher
+
+func (m Multi) Publish(ctx context.Context, message Message) error {
+	var errs []error
+	for _, p := range m {
+		if err := p.Publish(ctx, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
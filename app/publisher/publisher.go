@@ -0,0 +1,22 @@
+package publisher
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Message is a subscription domain event handed to a Publisher.
+type Message struct {
+	ID             uint64          `json:"id"`
+	Type           string          `json:"type"`
+	SubscriptionID uint64          `json:"subscription_id"`
+	OccurredAt     time.Time       `json:"occurred_at"`
+	Data           json.RawMessage `json:"data"`
+}
+
+// Publisher delivers domain events to downstream consumers. Delivery is
+// at-least-once: a message whose publish failed is handed over again later.
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+}
@@ -0,0 +1,109 @@
+package publisher
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/webhooksig"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+const WebhookDeliveryIDHeader = "X-Webhook-Delivery-Id"
+
+// ErrWebhookAddressNotAllowed is returned for webhook endpoints on private,
+// loopback, link-local, multicast, unspecified or carrier-grade NAT addresses,
+// which would let a client make the service call its own network.
+var ErrWebhookAddressNotAllowed = errors.New("webhook address is not publicly routable")
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598, which
+// net.IP.IsPrivate does not cover.
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// CheckWebhookIP rejects the addresses webhook endpoints must not point at.
+func CheckWebhookIP(ip net.IP) error {
+	if ip.IsPrivate() || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsMulticast() ||
+		ip.IsUnspecified() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressNotAllowed, ip)
+	}
+	return nil
+}
+
+// WebhookSender POSTs queued deliveries to client webhook endpoints. Every request
+// is signed with the endpoint secret using the same scheme the service verifies
+// for incoming payment webhooks: X-Webhook-Signature is "sha256=" followed by the
+// hex HMAC-SHA256 of "<X-Webhook-Timestamp>.<raw body>".
+type WebhookSender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewWebhookSender creates a sender that checks every address it connects to,
+// redirects included, with CheckWebhookIP unless cfg.AllowPrivateURLs is set. The
+// check runs on the resolved address at dial time, so a host that resolved to a
+// public address when the endpoint was registered cannot be pointed at the
+// service's own network later. Proxies are not used, since the address checked
+// would be the proxy's.
+func NewWebhookSender(cfg config.WebhookConfig) *WebhookSender {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !cfg.AllowPrivateURLs {
+		dialer.Control = checkDialAddress
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &WebhookSender{
+		client: &http.Client{Timeout: cfg.Timeout, Transport: transport},
+		now:    time.Now,
+	}
+}
+
+func checkDialAddress(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return fmt.Errorf("%w: %s", ErrWebhookAddressNotAllowed, host)
+	}
+	return CheckWebhookIP(ip)
+}
+
+// Send delivers the payload and returns the HTTP status code of the answer, or 0
+// when no answer was received. Only 2xx answers count as delivered.
+func (s *WebhookSender) Send(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := s.now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventIDHeader, strconv.FormatUint(delivery.EventID, 10))
+	req.Header.Set(EventTypeHeader, delivery.EventType)
+	req.Header.Set(WebhookDeliveryIDHeader, strconv.FormatUint(delivery.ID, 10))
+	req.Header.Set(webhooksig.TimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhooksig.SignatureHeader, webhooksig.Sign(endpoint.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook endpoint returned %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,93 @@
+package publisher_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/publisher"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/webhooksig"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+func TestWebhookSenderSignsPayload(t *testing.T) {
+	payload := []byte(`{"id":9,"type":"subscription.renewed"}`)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp, err := strconv.ParseInt(r.Header.Get(webhooksig.TimestampHeader), 10, 64)
+		if err != nil {
+			t.Errorf("invalid timestamp header: %v", err)
+		}
+		if r.Header.Get(webhooksig.SignatureHeader) != webhooksig.Sign("whsec_test", timestamp, body) {
+			t.Errorf("unexpected signature %q", r.Header.Get(webhooksig.SignatureHeader))
+		}
+		if r.Header.Get(publisher.EventIDHeader) != "9" || r.Header.Get(publisher.WebhookDeliveryIDHeader) != "3" {
+			t.Errorf("unexpected headers: %v", r.Header)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	sender := publisher.NewWebhookSender(config.WebhookConfig{Timeout: time.Second, AllowPrivateURLs: true})
+	status, err := sender.Send(context.Background(),
+		&entity.WebhookEndpoint{ID: 1, URL: srv.URL, Secret: "whsec_test"},
+		&entity.WebhookDelivery{ID: 3, EventID: 9, EventType: "subscription.renewed", Payload: payload},
+	)
+	if err != nil || status != http.StatusNoContent {
+		t.Fatalf("expected delivery to succeed, got status=%d err=%v", status, err)
+	}
+}
+
+func TestWebhookSenderReportsErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer srv.Close()
+
+	sender := publisher.NewWebhookSender(config.WebhookConfig{Timeout: time.Second, AllowPrivateURLs: true})
+	status, err := sender.Send(context.Background(),
+		&entity.WebhookEndpoint{URL: srv.URL, Secret: "whsec_test"},
+		&entity.WebhookDelivery{Payload: []byte(`{}`)},
+	)
+	if err == nil || status != http.StatusGone {
+		t.Fatalf("expected error with status 410, got status=%d err=%v", status, err)
+	}
+}
+
+func TestWebhookSenderRefusesNonPublicAddressesAtDialTime(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	sender := publisher.NewWebhookSender(config.WebhookConfig{Timeout: time.Second})
+	status, err := sender.Send(context.Background(),
+		&entity.WebhookEndpoint{URL: srv.URL, Secret: "whsec_test"},
+		&entity.WebhookDelivery{Payload: []byte(`{}`)},
+	)
+	if !errors.Is(err, publisher.ErrWebhookAddressNotAllowed) || status != 0 || called {
+		t.Fatalf("expected the loopback endpoint to be refused, got status=%d err=%v", status, err)
+	}
+}
+
+func TestCheckWebhookIP(t *testing.T) {
+	for _, addr := range []string{"127.0.0.1", "10.0.0.1", "172.16.5.4", "192.168.0.1", "169.254.169.254", "0.0.0.0", "100.64.0.1", "100.127.255.254", "224.0.0.1", "239.1.2.3", "::1", "::", "fe80::1", "fc00::1", "ff02::1", "ff0e::1", "::ffff:127.0.0.1"} {
+		if err := publisher.CheckWebhookIP(net.ParseIP(addr)); !errors.Is(err, publisher.ErrWebhookAddressNotAllowed) {
+			t.Fatalf("expected %s to be refused, got %v", addr, err)
+		}
+	}
+	for _, addr := range []string{"203.0.113.10", "8.8.8.8", "100.128.0.1", "2001:4860:4860::8888"} {
+		if err := publisher.CheckWebhookIP(net.ParseIP(addr)); err != nil {
+			t.Fatalf("expected %s to be allowed, got %v", addr, err)
+		}
+	}
+}
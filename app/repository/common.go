@@ -18,3 +18,10 @@ func isDuplicateEntryError(err error) bool {
 	var mysqlErr *mysqlDriver.MySQLError
 	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
 }
+
+func nullableRawString(v string) interface{} {
+	if v == "" {
+		return nil
+	}
+	return v
+}
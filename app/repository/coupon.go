@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+var (
+	ErrCouponNotFound            = errors.New("coupon not found")
+	ErrCouponCodeAlreadyExists   = errors.New("coupon code already exists")
+	ErrCouponRedemptionsExceeded = errors.New("coupon redemption limit reached")
+)
+
+type CouponRepository struct {
+	db DBTX
+}
+
+func NewCouponRepository(db DBTX) *CouponRepository {
+	return &CouponRepository{db: db}
+}
+
+// Create stores a new coupon. Coupon codes are unique.
+func (r *CouponRepository) Create(ctx context.Context, coupon *entity.Coupon) error {
+	query := `
+		INSERT INTO coupons (
+			code, description, percent_off, amount_off_cents, currency, duration, duration_in_periods,
+			max_redemptions, times_redeemed, plan_type_ids, expires_at, status, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		coupon.Code,
+		coupon.Description,
+		coupon.PercentOff,
+		coupon.AmountOffCents,
+		coupon.Currency,
+		coupon.Duration,
+		coupon.DurationInPeriods,
+		coupon.MaxRedemptions,
+		coupon.TimesRedeemed,
+		joinPlanTypeIDs(coupon.PlanTypeIDs),
+		nullableTimeValue(coupon.ExpiresAt),
+		coupon.Status,
+		coupon.CreatedAt,
+		coupon.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateEntryError(err) {
+			return ErrCouponCodeAlreadyExists
+		}
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	coupon.ID = uint64(id)
+	return nil
+}
+
+// Update stores the fields of a coupon that may change after it was created.
+// The discount itself is fixed so that redemptions keep their terms.
+func (r *CouponRepository) Update(ctx context.Context, coupon *entity.Coupon) error {
+	query := `
+		UPDATE coupons
+		SET description = ?, max_redemptions = ?, expires_at = ?, status = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		coupon.Description,
+		coupon.MaxRedemptions,
+		nullableTimeValue(coupon.ExpiresAt),
+		coupon.Status,
+		coupon.UpdatedAt,
+		coupon.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrCouponNotFound
+	}
+
+	return nil
+}
+
+func (r *CouponRepository) FindByID(ctx context.Context, id uint64) (*entity.Coupon, error) {
+	query := `
+		SELECT id, code, description, percent_off, amount_off_cents, currency, duration, duration_in_periods,
+		       max_redemptions, times_redeemed, plan_type_ids, expires_at, status, created_at, updated_at
+		FROM coupons
+		WHERE id = ?
+	`
+
+	return r.find(ctx, query, id)
+}
+
+func (r *CouponRepository) FindByCode(ctx context.Context, code string) (*entity.Coupon, error) {
+	query := `
+		SELECT id, code, description, percent_off, amount_off_cents, currency, duration, duration_in_periods,
+		       max_redemptions, times_redeemed, plan_type_ids, expires_at, status, created_at, updated_at
+		FROM coupons
+		WHERE code = ?
+	`
+
+	return r.find(ctx, query, code)
+}
+
+func (r *CouponRepository) List(ctx context.Context) ([]*entity.Coupon, error) {
+	query := `
+		SELECT id, code, description, percent_off, amount_off_cents, currency, duration, duration_in_periods,
+		       max_redemptions, times_redeemed, plan_type_ids, expires_at, status, created_at, updated_at
+		FROM coupons
+		ORDER BY id ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*entity.Coupon, 0)
+	for rows.Next() {
+		item := &entity.Coupon{}
+		if err := scanCoupon(rows, item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// IncrementRedemptions counts one more redemption of the coupon. The limit is
+// checked in the same statement so concurrent sign-ups cannot exceed it.
+func (r *CouponRepository) IncrementRedemptions(ctx context.Context, id uint64, now time.Time) error {
+	query := `
+		UPDATE coupons
+		SET times_redeemed = times_redeemed + 1, updated_at = ?
+		WHERE id = ? AND (max_redemptions = 0 OR times_redeemed < max_redemptions)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, now, id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrCouponRedemptionsExceeded
+	}
+
+	return nil
+}
+
+// DecrementRedemptions gives back one redemption of the coupon, never going
+// below zero.
+func (r *CouponRepository) DecrementRedemptions(ctx context.Context, id uint64, now time.Time) error {
+	query := `
+		UPDATE coupons
+		SET times_redeemed = times_redeemed - 1, updated_at = ?
+		WHERE id = ? AND times_redeemed > 0
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, now, id)
+	return err
+}
+
+func (r *CouponRepository) find(ctx context.Context, query string, args ...interface{}) (*entity.Coupon, error) {
+	item := &entity.Coupon{}
+	err := scanCoupon(conn(ctx, r.db).QueryRowContext(ctx, query, args...), item)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func scanCoupon(scanner rowScanner, item *entity.Coupon) error {
+	var planTypeIDs string
+	var expiresAt sql.NullTime
+
+	err := scanner.Scan(
+		&item.ID,
+		&item.Code,
+		&item.Description,
+		&item.PercentOff,
+		&item.AmountOffCents,
+		&item.Currency,
+		&item.Duration,
+		&item.DurationInPeriods,
+		&item.MaxRedemptions,
+		&item.TimesRedeemed,
+		&planTypeIDs,
+		&expiresAt,
+		&item.Status,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	item.ExpiresAt = nil
+	if expiresAt.Valid {
+		item.ExpiresAt = &expiresAt.Time
+	}
+	item.PlanTypeIDs, err = splitPlanTypeIDs(planTypeIDs)
+	return err
+}
+
+// Plan restrictions are stored as a comma-separated list of plan ids; an empty
+// list means the coupon applies to every plan.
+func joinPlanTypeIDs(ids []uint64) string {
+	parts := make([]string, 0, len(ids))
+	for _, id := range ids {
+		parts = append(parts, strconv.FormatUint(id, 10))
+	}
+	return strings.Join(parts, ",")
+}
+
+func splitPlanTypeIDs(raw string) ([]uint64, error) {
+	if raw == "" {
+		return []uint64{}, nil
+	}
+
+	parts := strings.Split(raw, ",")
+	ids := make([]uint64, 0, len(parts))
+	for _, part := range parts {
+		id, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+var (
+	ErrCouponRedemptionNotFound = errors.New("coupon redemption not found")
+	ErrCouponAlreadyRedeemed    = errors.New("coupon already redeemed for subscription")
+)
+
+type CouponRedemptionRepository struct {
+	db DBTX
+}
+
+func NewCouponRedemptionRepository(db DBTX) *CouponRedemptionRepository {
+	return &CouponRedemptionRepository{db: db}
+}
+
+// Create stores a redemption. A coupon can be redeemed once per subscription.
+func (r *CouponRedemptionRepository) Create(ctx context.Context, redemption *entity.CouponRedemption) error {
+	query := `
+		INSERT INTO coupon_redemptions (
+			coupon_id, subscription_id, duration_periods, periods_applied, status, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		redemption.CouponID,
+		redemption.SubscriptionID,
+		redemption.DurationPeriods,
+		redemption.PeriodsApplied,
+		redemption.Status,
+		redemption.CreatedAt,
+		redemption.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateEntryError(err) {
+			return ErrCouponAlreadyRedeemed
+		}
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	redemption.ID = uint64(id)
+	return nil
+}
+
+func (r *CouponRedemptionRepository) Update(ctx context.Context, redemption *entity.CouponRedemption) error {
+	query := `
+		UPDATE coupon_redemptions
+		SET periods_applied = ?, status = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		redemption.PeriodsApplied,
+		redemption.Status,
+		redemption.UpdatedAt,
+		redemption.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrCouponRedemptionNotFound
+	}
+
+	return nil
+}
+
+// FindActiveBySubscriptionID returns the redemption currently discounting the
+// subscription, or nil when there is none.
+func (r *CouponRedemptionRepository) FindActiveBySubscriptionID(ctx context.Context, subscriptionID uint64) (*entity.CouponRedemption, error) {
+	query := `
+		SELECT id, coupon_id, subscription_id, duration_periods, periods_applied, status, created_at, updated_at
+		FROM coupon_redemptions
+		WHERE subscription_id = ? AND status = ?
+		ORDER BY id DESC
+		LIMIT 1
+	`
+
+	item := &entity.CouponRedemption{}
+	err := scanCouponRedemption(conn(ctx, r.db).QueryRowContext(ctx, query, subscriptionID, entity.CouponRedemptionStatusActive), item)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// EndActive ends every active redemption of the subscription.
+func (r *CouponRedemptionRepository) EndActive(ctx context.Context, subscriptionID uint64, now time.Time) error {
+	query := `
+		UPDATE coupon_redemptions
+		SET status = ?, updated_at = ?
+		WHERE subscription_id = ? AND status = ?
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		entity.CouponRedemptionStatusEnded,
+		now,
+		subscriptionID,
+		entity.CouponRedemptionStatusActive,
+	)
+	return err
+}
+
+// Delete removes a redemption, as if the coupon had never been redeemed for the
+// subscription.
+func (r *CouponRedemptionRepository) Delete(ctx context.Context, id uint64) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM coupon_redemptions WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrCouponRedemptionNotFound
+	}
+
+	return nil
+}
+
+func (r *CouponRedemptionRepository) ListByCouponID(ctx context.Context, couponID uint64) ([]*entity.CouponRedemption, error) {
+	query := `
+		SELECT id, coupon_id, subscription_id, duration_periods, periods_applied, status, created_at, updated_at
+		FROM coupon_redemptions
+		WHERE coupon_id = ?
+		ORDER BY id ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, couponID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*entity.CouponRedemption, 0)
+	for rows.Next() {
+		item := &entity.CouponRedemption{}
+		if err := scanCouponRedemption(rows, item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func scanCouponRedemption(scanner rowScanner, item *entity.CouponRedemption) error {
+	return scanner.Scan(
+		&item.ID,
+		&item.CouponID,
+		&item.SubscriptionID,
+		&item.DurationPeriods,
+		&item.PeriodsApplied,
+		&item.Status,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+)
+
+// LeaderLock is a MySQL named lock (GET_LOCK) that at most one process holds at
+// a time. The lock belongs to the connection that took it, so it is held on a
+// connection of its own; MySQL frees it when that connection drops, so a crashed
+// holder does not block the other processes. Its methods must not be called
+// concurrently.
+type LeaderLock struct {
+	db   *sql.DB
+	name string
+	conn *sql.Conn
+}
+
+func NewLeaderLock(db *sql.DB, name string) *LeaderLock {
+	return &LeaderLock{db: db, name: name}
+}
+
+// TryAcquire takes the lock without waiting. It reports false while another
+// connection holds it.
+func (l *LeaderLock) TryAcquire(ctx context.Context) (bool, error) {
+	if l.conn != nil {
+		return l.Held(ctx)
+	}
+
+	conn, err := l.db.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, 0)", l.name).Scan(&acquired); err != nil {
+		_ = conn.Close()
+		return false, err
+	}
+	if !acquired.Valid || acquired.Int64 != 1 {
+		_ = conn.Close()
+		return false, nil
+	}
+
+	l.conn = conn
+	return true, nil
+}
+
+// Held reports whether the lock is still held on its connection. A lost
+// connection loses the lock with it.
+func (l *LeaderLock) Held(ctx context.Context) (bool, error) {
+	if l.conn == nil {
+		return false, nil
+	}
+
+	var held sql.NullBool
+	err := l.conn.QueryRowContext(ctx, "SELECT IS_USED_LOCK(?) = CONNECTION_ID()", l.name).Scan(&held)
+	if err != nil || !held.Valid || !held.Bool {
+		l.closeConn()
+		return false, err
+	}
+	return true, nil
+}
+
+// Release frees the lock, so another process can take it right away.
+func (l *LeaderLock) Release(ctx context.Context) error {
+	if l.conn == nil {
+		return nil
+	}
+
+	var released sql.NullInt64
+	err := l.conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", l.name).Scan(&released)
+	l.closeConn()
+	return err
+}
+
+func (l *LeaderLock) closeConn() {
+	_ = l.conn.Close()
+	l.conn = nil
+}
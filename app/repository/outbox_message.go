@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+var ErrOutboxMessageNotFound = errors.New("outbox message not found")
+
+type OutboxMessageRepository struct {
+	db DBTX
+}
+
+func NewOutboxMessageRepository(db DBTX) *OutboxMessageRepository {
+	return &OutboxMessageRepository{db: db}
+}
+
+func (r *OutboxMessageRepository) Create(ctx context.Context, message *entity.OutboxMessage) error {
+	query := `
+		INSERT INTO outbox_messages (
+			subscription_id, event_type, payload, status, attempts,
+			last_error, next_attempt_at, published_at, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		message.SubscriptionID,
+		message.EventType,
+		message.Payload,
+		message.Status,
+		message.Attempts,
+		nullableRawString(message.LastError),
+		message.NextAttemptAt,
+		nullableTimeValue(message.PublishedAt),
+		message.CreatedAt,
+		message.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	message.ID = uint64(id)
+	return nil
+}
+
+func (r *OutboxMessageRepository) Update(ctx context.Context, message *entity.OutboxMessage) error {
+	query := `
+		UPDATE outbox_messages
+		SET status = ?, attempts = ?, last_error = ?, next_attempt_at = ?, published_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		message.Status,
+		message.Attempts,
+		nullableRawString(message.LastError),
+		message.NextAttemptAt,
+		nullableTimeValue(message.PublishedAt),
+		message.UpdatedAt,
+		message.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrOutboxMessageNotFound
+	}
+
+	return nil
+}
+
+// OutboxClaim is a lease the relay takes on the messages it publishes, so that
+// relays running side by side never publish the same message. As with
+// SubscriptionClaim, leases older than Now are free to take over.
+type OutboxClaim struct {
+	Owner string
+	Now   time.Time
+	Until time.Time
+	Limit int
+}
+
+// ClaimDue leases up to claim.Limit unleased pending messages that are due at
+// claim.Now, oldest first, and returns the messages the owner holds. Only the
+// oldest pending message of each subscription is leased, so a subscription's
+// events are published in the order they were recorded. The lease is checked
+// again when the rows are updated, so concurrent relays never lease the same
+// message.
+func (r *OutboxMessageRepository) ClaimDue(ctx context.Context, claim OutboxClaim) ([]*entity.OutboxMessage, error) {
+	query := `
+		UPDATE outbox_messages
+		SET claimed_by = ?, claimed_until = ?, updated_at = updated_at
+		WHERE status = ?
+		  AND (claimed_until IS NULL OR claimed_until <= ?)
+		  AND id IN (
+		      SELECT id FROM (
+		          SELECT o.id
+		          FROM outbox_messages o
+		          WHERE o.status = ?
+		            AND o.next_attempt_at <= ?
+		            AND (o.claimed_until IS NULL OR o.claimed_until <= ?)
+		            AND NOT EXISTS (
+		                SELECT 1
+		                FROM outbox_messages earlier
+		                WHERE earlier.subscription_id = o.subscription_id
+		                  AND earlier.status = ?
+		                  AND earlier.id < o.id
+		            )
+		          ORDER BY o.id ASC
+		          LIMIT ?
+		      ) due
+		  )
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		claim.Owner,
+		claim.Until,
+		entity.OutboxMessageStatusPending,
+		claim.Now,
+		entity.OutboxMessageStatusPending,
+		claim.Now,
+		claim.Now,
+		entity.OutboxMessageStatusPending,
+		claim.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		SELECT id, subscription_id, event_type, payload, status, attempts,
+		       last_error, next_attempt_at, published_at, created_at, updated_at
+		FROM outbox_messages
+		WHERE claimed_by = ?
+		ORDER BY id ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, claim.Owner)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*entity.OutboxMessage, 0)
+	for rows.Next() {
+		item := &entity.OutboxMessage{}
+		if err := scanOutboxMessage(rows, item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+// ReleaseClaims drops the leases taken by owner so other relays can pick the
+// messages up again.
+func (r *OutboxMessageRepository) ReleaseClaims(ctx context.Context, owner string) error {
+	query := `
+		UPDATE outbox_messages
+		SET claimed_by = NULL, claimed_until = NULL, updated_at = updated_at
+		WHERE claimed_by = ?
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, owner)
+	return err
+}
+
+func scanOutboxMessage(scanner rowScanner, item *entity.OutboxMessage) error {
+	var lastError sql.NullString
+	var publishedAt sql.NullTime
+
+	err := scanner.Scan(
+		&item.ID,
+		&item.SubscriptionID,
+		&item.EventType,
+		&item.Payload,
+		&item.Status,
+		&item.Attempts,
+		&lastError,
+		&item.NextAttemptAt,
+		&publishedAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	item.LastError = lastError.String
+	item.PublishedAt = nil
+	if publishedAt.Valid {
+		item.PublishedAt = &publishedAt.Time
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+func TestOutboxClaimDueSkipsLeasedMessages(t *testing.T) {
+	var gotQuery string
+	var gotArgs []interface{}
+	repo := NewOutboxMessageRepository(&fakeDB{execFn: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+		gotQuery = query
+		gotArgs = args
+		return fakeResult{rowsAffected: 2}, nil
+	}})
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	claim := OutboxClaim{Owner: "relay-1/ab", Now: now, Until: now.Add(15 * time.Minute), Limit: 100}
+	_, _ = repo.ClaimDue(context.Background(), claim)
+
+	if strings.Count(gotQuery, "claimed_until IS NULL OR") != 2 || !strings.Contains(gotQuery, "LIMIT ?") {
+		t.Fatalf("expected the update to skip messages under a live lease, got %s", gotQuery)
+	}
+	if !strings.Contains(gotQuery, "updated_at = updated_at") {
+		t.Fatalf("expected the lease to keep updated_at, got %s", gotQuery)
+	}
+	if gotArgs[0] != claim.Owner || gotArgs[1] != claim.Until {
+		t.Fatalf("expected the lease to be set first, got %#v", gotArgs)
+	}
+	if gotArgs[2] != entity.OutboxMessageStatusPending || gotArgs[3] != now {
+		t.Fatalf("expected the lease to be checked again on update, got %#v", gotArgs)
+	}
+	if gotArgs[len(gotArgs)-1] != 100 {
+		t.Fatalf("expected the batch size last, got %#v", gotArgs)
+	}
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+var (
+	ErrPaymentAttemptNotFound      = errors.New("payment attempt not found")
+	ErrPaymentAttemptAlreadyExists = errors.New("payment attempt already exists")
+	ErrPaymentAttemptNotPending    = errors.New("payment attempt is not pending")
+)
+
+type PaymentAttemptRepository struct {
+	db DBTX
+}
+
+func NewPaymentAttemptRepository(db DBTX) *PaymentAttemptRepository {
+	return &PaymentAttemptRepository{db: db}
+}
+
+func (r *PaymentAttemptRepository) Create(ctx context.Context, attempt *entity.PaymentAttempt) error {
+	query := `
+		INSERT INTO payment_attempts (
+			subscription_id, plan_type_id, kind, amount_cents, currency, discount_cents, quantity,
+			provider_transaction_id, result_type, status, error,
+			completed_at, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		attempt.SubscriptionID,
+		attempt.PlanTypeID,
+		attempt.Kind,
+		attempt.AmountCents,
+		attempt.Currency,
+		attempt.DiscountCents,
+		attempt.Quantity,
+		nullableStringValue(attempt.ProviderTransactionID),
+		nullableRawString(attempt.ResultType),
+		attempt.Status,
+		nullableRawString(attempt.Error),
+		nullableTimeValue(attempt.CompletedAt),
+		attempt.CreatedAt,
+		attempt.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateEntryError(err) {
+			return ErrPaymentAttemptAlreadyExists
+		}
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	attempt.ID = uint64(id)
+	return nil
+}
+
+func (r *PaymentAttemptRepository) Update(ctx context.Context, attempt *entity.PaymentAttempt) error {
+	query := `
+		UPDATE payment_attempts
+		SET provider_transaction_id = ?, result_type = ?, status = ?, error = ?, completed_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		nullableStringValue(attempt.ProviderTransactionID),
+		nullableRawString(attempt.ResultType),
+		attempt.Status,
+		nullableRawString(attempt.Error),
+		nullableTimeValue(attempt.CompletedAt),
+		attempt.UpdatedAt,
+		attempt.ID,
+	)
+	if err != nil {
+		if isDuplicateEntryError(err) {
+			return ErrPaymentAttemptAlreadyExists
+		}
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPaymentAttemptNotFound
+	}
+
+	return nil
+}
+
+// CompletePending stores the outcome of a pending attempt. It only matches rows that
+// are still pending, so concurrent deliveries of the same callback complete the
+// attempt once; the losers get ErrPaymentAttemptNotPending.
+func (r *PaymentAttemptRepository) CompletePending(ctx context.Context, attempt *entity.PaymentAttempt) error {
+	query := `
+		UPDATE payment_attempts
+		SET result_type = ?, status = ?, error = ?, completed_at = ?, updated_at = ?
+		WHERE id = ? AND status = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		nullableRawString(attempt.ResultType),
+		attempt.Status,
+		nullableRawString(attempt.Error),
+		nullableTimeValue(attempt.CompletedAt),
+		attempt.UpdatedAt,
+		attempt.ID,
+		entity.PaymentAttemptStatusPending,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPaymentAttemptNotPending
+	}
+
+	return nil
+}
+
+// SupersedePending marks every other pending attempt of the subscription of the
+// same kind as superseded. Callbacks for superseded attempts are rejected, so an
+// abandoned checkout cannot change the subscription once it was charged anew,
+// while attempts of other kinds, a refund next to an open renewal checkout for
+// instance, stay payable.
+func (r *PaymentAttemptRepository) SupersedePending(ctx context.Context, subscriptionID uint64, kind string, currentAttemptID uint64, now time.Time) error {
+	query := `
+		UPDATE payment_attempts
+		SET status = ?, updated_at = ?
+		WHERE subscription_id = ? AND kind = ? AND status = ? AND id <> ?
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		entity.PaymentAttemptStatusSuperseded,
+		now,
+		subscriptionID,
+		kind,
+		entity.PaymentAttemptStatusPending,
+		currentAttemptID,
+	)
+	return err
+}
+
+func (r *PaymentAttemptRepository) FindByTransactionID(ctx context.Context, transactionID string) (*entity.PaymentAttempt, error) {
+	query := `
+		SELECT id, subscription_id, plan_type_id, kind, amount_cents, currency, discount_cents, quantity,
+		       provider_transaction_id, result_type, status, error,
+		       completed_at, created_at, updated_at
+		FROM payment_attempts
+		WHERE provider_transaction_id = ?
+	`
+
+	item := &entity.PaymentAttempt{}
+	if err := scanPaymentAttempt(conn(ctx, r.db).QueryRowContext(ctx, query, transactionID), item); err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (r *PaymentAttemptRepository) ListBySubscriptionID(ctx context.Context, subscriptionID uint64) ([]*entity.PaymentAttempt, error) {
+	query := `
+		SELECT id, subscription_id, plan_type_id, kind, amount_cents, currency, discount_cents, quantity,
+		       provider_transaction_id, result_type, status, error,
+		       completed_at, created_at, updated_at
+		FROM payment_attempts
+		WHERE subscription_id = ?
+		ORDER BY id DESC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*entity.PaymentAttempt, 0)
+	for rows.Next() {
+		item := &entity.PaymentAttempt{}
+		if err := scanPaymentAttempt(rows, item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func scanPaymentAttempt(scanner rowScanner, item *entity.PaymentAttempt) error {
+	var transactionID sql.NullString
+	var resultType sql.NullString
+	var errorMessage sql.NullString
+	var completedAt sql.NullTime
+
+	err := scanner.Scan(
+		&item.ID,
+		&item.SubscriptionID,
+		&item.PlanTypeID,
+		&item.Kind,
+		&item.AmountCents,
+		&item.Currency,
+		&item.DiscountCents,
+		&item.Quantity,
+		&transactionID,
+		&resultType,
+		&item.Status,
+		&errorMessage,
+		&completedAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	item.ProviderTransactionID = nil
+	if transactionID.Valid {
+		item.ProviderTransactionID = &transactionID.String
+	}
+	item.ResultType = resultType.String
+	item.Error = errorMessage.String
+	item.CompletedAt = nil
+	if completedAt.Valid {
+		item.CompletedAt = &completedAt.Time
+	}
+
+	return nil
+}
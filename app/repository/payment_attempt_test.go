@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+func TestPaymentAttemptCreateStoresNullsForMissingValues(t *testing.T) {
+	var gotArgs []interface{}
+	repo := NewPaymentAttemptRepository(&fakeDB{execFn: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+		gotArgs = args
+		return fakeResult{lastInsertID: 7}, nil
+	}})
+
+	now := time.Now().UTC()
+	attempt := &entity.PaymentAttempt{
+		SubscriptionID: 3,
+		PlanTypeID:     2,
+		Kind:           entity.PaymentAttemptKindInitial,
+		AmountCents:    999,
+		Currency:       "EUR",
+		Status:         entity.PaymentAttemptStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if err := repo.Create(context.Background(), attempt); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if attempt.ID != 7 {
+		t.Fatalf("expected id=7, got %d", attempt.ID)
+	}
+	if gotArgs[2] != entity.PaymentAttemptKindInitial {
+		t.Fatalf("expected kind to be stored, got %#v", gotArgs[2])
+	}
+	if gotArgs[7] != nil || gotArgs[8] != nil || gotArgs[10] != nil || gotArgs[11] != nil {
+		t.Fatalf("expected NULL transaction id, result type, error and completed_at, got %#v", gotArgs)
+	}
+}
+
+func TestPaymentAttemptUpdateNoRowsAffected(t *testing.T) {
+	repo := NewPaymentAttemptRepository(&fakeDB{execFn: func(_ context.Context, _ string, _ ...interface{}) (sql.Result, error) {
+		return fakeResult{rowsAffected: 0}, nil
+	}})
+
+	err := repo.Update(context.Background(), &entity.PaymentAttempt{ID: 1})
+	if !errors.Is(err, ErrPaymentAttemptNotFound) {
+		t.Fatalf("expected ErrPaymentAttemptNotFound, got %v", err)
+	}
+}
+
+func TestPaymentAttemptCompletePendingOnlyMatchesPending(t *testing.T) {
+	var gotArgs []interface{}
+	repo := NewPaymentAttemptRepository(&fakeDB{execFn: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+		gotArgs = args
+		return fakeResult{rowsAffected: 0}, nil
+	}})
+
+	err := repo.CompletePending(context.Background(), &entity.PaymentAttempt{ID: 4, Status: entity.PaymentAttemptStatusSucceeded})
+	if !errors.Is(err, ErrPaymentAttemptNotPending) {
+		t.Fatalf("expected ErrPaymentAttemptNotPending, got %v", err)
+	}
+	if gotArgs[len(gotArgs)-1] != entity.PaymentAttemptStatusPending {
+		t.Fatalf("expected update to be guarded by pending status, got %#v", gotArgs)
+	}
+}
+
+func TestPaymentAttemptSupersedePendingOnlyMatchesSameKind(t *testing.T) {
+	var gotQuery string
+	var gotArgs []interface{}
+	repo := NewPaymentAttemptRepository(&fakeDB{execFn: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+		gotQuery, gotArgs = query, args
+		return fakeResult{rowsAffected: 1}, nil
+	}})
+
+	if err := repo.SupersedePending(context.Background(), 3, entity.PaymentAttemptKindRenewal, 9, time.Now().UTC()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !strings.Contains(gotQuery, "kind = ?") || gotArgs[3] != entity.PaymentAttemptKindRenewal || gotArgs[5] != uint64(9) {
+		t.Fatalf("expected only other pending renewal attempts to be superseded, got %s %#v", gotQuery, gotArgs)
+	}
+}
@@ -3,10 +3,16 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"errors"
 
 	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
 )
 
+var (
+	ErrPlanTypeNotFound      = errors.New("plan type not found")
+	ErrPlanCodeAlreadyExists = errors.New("plan code already exists")
+)
+
 type PlanTypeRepository struct {
 	db DBTX
 }
@@ -15,18 +21,147 @@ func NewPlanTypeRepository(db DBTX) *PlanTypeRepository {
 	return &PlanTypeRepository{db: db}
 }
 
-func (r *PlanTypeRepository) FindBySubscriptionTypeID(ctx context.Context, subscriptionTypeID uint64) (*entity.PlanType, error) {
+// Create stores a new plan. Plan codes are unique across all subscription types.
+func (r *PlanTypeRepository) Create(ctx context.Context, planType *entity.PlanType) error {
+	query := `
+		INSERT INTO plan_types (
+			subscription_type_id, plan_code, display_name, description, price_cents,
+			currency, interval_unit, interval_count, trial_days, features, status, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		planType.SubscriptionTypeID,
+		planType.PlanCode,
+		planType.DisplayName,
+		nullableRawString(planType.Description),
+		planType.PriceCents,
+		planType.Currency,
+		planType.IntervalUnit,
+		planType.IntervalCount,
+		planType.TrialDays,
+		nullableRawString(planType.Features),
+		planType.Status,
+		planType.CreatedAt,
+		planType.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateEntryError(err) {
+			return ErrPlanCodeAlreadyExists
+		}
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	planType.ID = uint64(id)
+	return nil
+}
+
+func (r *PlanTypeRepository) Update(ctx context.Context, planType *entity.PlanType) error {
+	query := `
+		UPDATE plan_types
+		SET display_name = ?, description = ?, price_cents = ?, currency = ?,
+		    interval_unit = ?, interval_count = ?, trial_days = ?, features = ?, status = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		planType.DisplayName,
+		nullableRawString(planType.Description),
+		planType.PriceCents,
+		planType.Currency,
+		planType.IntervalUnit,
+		planType.IntervalCount,
+		planType.TrialDays,
+		nullableRawString(planType.Features),
+		planType.Status,
+		planType.UpdatedAt,
+		planType.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrPlanTypeNotFound
+	}
+
+	return nil
+}
+
+// List returns the plans of a subscription type, or every plan when
+// subscriptionTypeID is 0, cheapest first. Archived plans are included.
+func (r *PlanTypeRepository) List(ctx context.Context, subscriptionTypeID uint64) ([]*entity.PlanType, error) {
 	query := `
 		SELECT id, subscription_type_id, plan_code, display_name, description,
-		       price_cents, currency, duration_days, features, created_at, updated_at
+		       price_cents, currency, interval_unit, interval_count, trial_days, features, status, created_at, updated_at
 		FROM plan_types
-		WHERE subscription_type_id = ?
 	`
 
+	args := make([]interface{}, 0, 1)
+	if subscriptionTypeID != 0 {
+		query += " WHERE subscription_type_id = ?"
+		args = append(args, subscriptionTypeID)
+	}
+	query += " ORDER BY subscription_type_id ASC, price_cents ASC, id ASC"
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*entity.PlanType, 0)
+	for rows.Next() {
+		item := &entity.PlanType{}
+		if err := scanPlanType(rows, item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (r *PlanTypeRepository) FindByID(ctx context.Context, id uint64) (*entity.PlanType, error) {
+	query := `
+		SELECT id, subscription_type_id, plan_code, display_name, description,
+		       price_cents, currency, interval_unit, interval_count, trial_days, features, status, created_at, updated_at
+		FROM plan_types
+		WHERE id = ?
+	`
+
+	return r.findOne(ctx, query, id)
+}
+
+func (r *PlanTypeRepository) findOne(ctx context.Context, query string, args ...interface{}) (*entity.PlanType, error) {
 	item := &entity.PlanType{}
+	err := scanPlanType(conn(ctx, r.db).QueryRowContext(ctx, query, args...), item)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func scanPlanType(scanner rowScanner, item *entity.PlanType) error {
 	var description sql.NullString
 	var features sql.NullString
-	err := r.db.QueryRowContext(ctx, query, subscriptionTypeID).Scan(
+	err := scanner.Scan(
 		&item.ID,
 		&item.SubscriptionTypeID,
 		&item.PlanCode,
@@ -34,24 +169,19 @@ func (r *PlanTypeRepository) FindBySubscriptionTypeID(ctx context.Context, subsc
 		&description,
 		&item.PriceCents,
 		&item.Currency,
-		&item.DurationDays,
+		&item.IntervalUnit,
+		&item.IntervalCount,
+		&item.TrialDays,
 		&features,
+		&item.Status,
 		&item.CreatedAt,
 		&item.UpdatedAt,
 	)
-	if err == sql.ErrNoRows {
-		return nil, nil
-	}
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	if description.Valid {
-		item.Description = description.String
-	}
-	if features.Valid {
-		item.Features = features.String
-	}
-
-	return item, nil
+	item.Description = description.String
+	item.Features = features.String
+	return nil
 }
@@ -13,8 +13,12 @@ import (
 var (
 	ErrSubscriptionNotFound      = errors.New("subscription not found")
 	ErrSubscriptionAlreadyExists = errors.New("subscription already exists")
+	ErrConcurrentModification    = errors.New("subscription was modified concurrently")
 )
 
+// initialSubscriptionVersion is the version of a newly created subscription.
+const initialSubscriptionVersion int64 = 1
+
 type SubscriptionRepository struct {
 	db DBTX
 }
@@ -26,22 +30,33 @@ func NewSubscriptionRepository(db DBTX) *SubscriptionRepository {
 func (r *SubscriptionRepository) Create(ctx context.Context, subscription *entity.Subscription) error {
 	query := `
 		INSERT INTO subscriptions (
-			subscription_type_id, user_id, email, status,
-			start_at, end_at, renew_at, auto_renew,
+			subscription_type_id, plan_type_id, pending_plan_type_id, user_id, email, status,
+			start_at, end_at, renew_at, trial_end_at, canceled_at, cancel_reason, paused_at, resume_at, renewal_retry_count, billing_anchor_day, quantity, auto_renew, version,
 			created_at, updated_at
 		)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
 		subscription.SubscriptionTypeID,
+		nullableUint64Value(subscription.PlanTypeID),
+		nullableUint64Value(subscription.PendingPlanTypeID),
 		nullableStringValue(subscription.UserID),
 		nullableStringValue(subscription.Email),
 		subscription.Status,
 		nullableTimeValue(subscription.StartAt),
 		nullableTimeValue(subscription.EndAt),
 		nullableTimeValue(subscription.RenewAt),
+		nullableTimeValue(subscription.TrialEndAt),
+		nullableTimeValue(subscription.CanceledAt),
+		nullableStringValue(subscription.CancelReason),
+		nullableTimeValue(subscription.PausedAt),
+		nullableTimeValue(subscription.ResumeAt),
+		subscription.RenewalRetryCount,
+		subscription.BillingAnchorDay,
+		subscription.Quantity,
 		subscription.AutoRenew,
+		initialSubscriptionVersion,
 		subscription.CreatedAt,
 		subscription.UpdatedAt,
 	)
@@ -57,24 +72,39 @@ func (r *SubscriptionRepository) Create(ctx context.Context, subscription *entit
 		return err
 	}
 	subscription.ID = uint64(id)
+	subscription.Version = initialSubscriptionVersion
 	return nil
 }
 
+// Update saves subscription if it is still at the version it was read at and
+// moves it to the next version. Subscriptions are never deleted, so an update
+// that matches no row lost the race against another writer.
 func (r *SubscriptionRepository) Update(ctx context.Context, subscription *entity.Subscription) error {
 	query := `
 		UPDATE subscriptions
-		SET status = ?, start_at = ?, end_at = ?, renew_at = ?, auto_renew = ?, updated_at = ?
-		WHERE id = ?
+		SET plan_type_id = ?, pending_plan_type_id = ?, status = ?, start_at = ?, end_at = ?, renew_at = ?, trial_end_at = ?, canceled_at = ?, cancel_reason = ?, paused_at = ?, resume_at = ?, renewal_retry_count = ?, billing_anchor_day = ?, quantity = ?, auto_renew = ?, version = version + 1, updated_at = ?
+		WHERE id = ? AND version = ?
 	`
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		nullableUint64Value(subscription.PlanTypeID),
+		nullableUint64Value(subscription.PendingPlanTypeID),
 		subscription.Status,
 		nullableTimeValue(subscription.StartAt),
 		nullableTimeValue(subscription.EndAt),
 		nullableTimeValue(subscription.RenewAt),
+		nullableTimeValue(subscription.TrialEndAt),
+		nullableTimeValue(subscription.CanceledAt),
+		nullableStringValue(subscription.CancelReason),
+		nullableTimeValue(subscription.PausedAt),
+		nullableTimeValue(subscription.ResumeAt),
+		subscription.RenewalRetryCount,
+		subscription.BillingAnchorDay,
+		subscription.Quantity,
 		subscription.AutoRenew,
 		subscription.UpdatedAt,
 		subscription.ID,
+		subscription.Version,
 	)
 	if err != nil {
 		return err
@@ -85,16 +115,17 @@ func (r *SubscriptionRepository) Update(ctx context.Context, subscription *entit
 		return err
 	}
 	if affected == 0 {
-		return ErrSubscriptionNotFound
+		return ErrConcurrentModification
 	}
 
+	subscription.Version++
 	return nil
 }
 
 func (r *SubscriptionRepository) FindByID(ctx context.Context, id uint64) (*entity.Subscription, error) {
 	query := `
-		SELECT id, subscription_type_id, user_id, email, status,
-		       start_at, end_at, renew_at, auto_renew,
+		SELECT id, subscription_type_id, plan_type_id, pending_plan_type_id, user_id, email, status,
+		       start_at, end_at, renew_at, trial_end_at, canceled_at, cancel_reason, paused_at, resume_at, renewal_retry_count, billing_anchor_day, quantity, auto_renew, version,
 		       created_at, updated_at
 		FROM subscriptions
 		WHERE id = ?
@@ -102,7 +133,7 @@ func (r *SubscriptionRepository) FindByID(ctx context.Context, id uint64) (*enti
 
 	item := &entity.Subscription{}
 	if err := scanSubscription(
-		r.db.QueryRowContext(ctx, query, id),
+		conn(ctx, r.db).QueryRowContext(ctx, query, id),
 		item,
 	); err == sql.ErrNoRows {
 		return nil, nil
@@ -115,8 +146,8 @@ func (r *SubscriptionRepository) FindByID(ctx context.Context, id uint64) (*enti
 
 func (r *SubscriptionRepository) FindByTypeAndIdentity(ctx context.Context, subscriptionTypeID uint64, userID, email *string) (*entity.Subscription, error) {
 	query := `
-		SELECT id, subscription_type_id, user_id, email, status,
-		       start_at, end_at, renew_at, auto_renew,
+		SELECT id, subscription_type_id, plan_type_id, pending_plan_type_id, user_id, email, status,
+		       start_at, end_at, renew_at, trial_end_at, canceled_at, cancel_reason, paused_at, resume_at, renewal_retry_count, billing_anchor_day, quantity, auto_renew, version,
 		       created_at, updated_at
 		FROM subscriptions
 		WHERE subscription_type_id = ?
@@ -127,7 +158,7 @@ func (r *SubscriptionRepository) FindByTypeAndIdentity(ctx context.Context, subs
 
 	item := &entity.Subscription{}
 	if err := scanSubscription(
-		r.db.QueryRowContext(ctx, query, subscriptionTypeID, nullableStringValue(userID), nullableStringValue(email)),
+		conn(ctx, r.db).QueryRowContext(ctx, query, subscriptionTypeID, nullableStringValue(userID), nullableStringValue(email)),
 		item,
 	); err == sql.ErrNoRows {
 		return nil, nil
@@ -138,96 +169,297 @@ func (r *SubscriptionRepository) FindByTypeAndIdentity(ctx context.Context, subs
 	return item, nil
 }
 
-func (r *SubscriptionRepository) List(ctx context.Context, userID, email string) ([]*entity.Subscription, error) {
+// Sort orders supported by List. Every order breaks ties by id, so a page
+// boundary is always a single row.
+const (
+	SubscriptionSortIDDesc        = "id_desc"
+	SubscriptionSortIDAsc         = "id_asc"
+	SubscriptionSortCreatedAtDesc = "created_at_desc"
+	SubscriptionSortCreatedAtAsc  = "created_at_asc"
+	SubscriptionSortEndAtDesc     = "end_at_desc"
+	SubscriptionSortEndAtAsc      = "end_at_asc"
+)
+
+// openEndAt stands in for a NULL end_at when sorting by end_at: subscriptions
+// without an end date sort after every dated one.
+var openEndAt = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+// SubscriptionFilter selects a page of subscriptions. Zero values mean "no
+// filter"; time ranges are inclusive.
+type SubscriptionFilter struct {
+	UserID             string
+	Email              string
+	Status             *int32
+	SubscriptionTypeID uint64
+	AutoRenew          *bool
+	EndAtFrom          *time.Time
+	EndAtTo            *time.Time
+	CreatedAtFrom      *time.Time
+	CreatedAtTo        *time.Time
+	Sort               string
+	// After resumes the listing right after the given row of a previous page.
+	After *SubscriptionCursor
+	Limit int
+}
+
+// SubscriptionCursor is the position of a row in a sorted listing: its sort key
+// (unused when sorting by id) and its id.
+type SubscriptionCursor struct {
+	Value time.Time
+	ID    uint64
+}
+
+// SubscriptionCursorOf returns the cursor of item for the given sort order.
+func SubscriptionCursorOf(item *entity.Subscription, sort string) SubscriptionCursor {
+	cursor := SubscriptionCursor{ID: item.ID}
+	switch sort {
+	case SubscriptionSortCreatedAtDesc, SubscriptionSortCreatedAtAsc:
+		cursor.Value = item.CreatedAt
+	case SubscriptionSortEndAtDesc, SubscriptionSortEndAtAsc:
+		cursor.Value = openEndAt
+		if item.EndAt != nil {
+			cursor.Value = *item.EndAt
+		}
+	}
+	return cursor
+}
+
+// SubscriptionClaim is a lease a batch job takes on the rows it processes, so
+// that workers running side by side handle disjoint rows. Leases older than Now
+// are free to take over: Until bounds how long a crashed worker holds its rows.
+// Only rows after AfterID are leased, so a job pages through its rows by id.
+type SubscriptionClaim struct {
+	Owner   string
+	Now     time.Time
+	Until   time.Time
+	AfterID uint64
+	Limit   int
+}
+
+func (r *SubscriptionRepository) List(ctx context.Context, filter SubscriptionFilter) ([]*entity.Subscription, error) {
 	query := `
-		SELECT id, subscription_type_id, user_id, email, status,
-		       start_at, end_at, renew_at, auto_renew,
+		SELECT id, subscription_type_id, plan_type_id, pending_plan_type_id, user_id, email, status,
+		       start_at, end_at, renew_at, trial_end_at, canceled_at, cancel_reason, paused_at, resume_at, renewal_retry_count, billing_anchor_day, quantity, auto_renew, version,
 		       created_at, updated_at
 		FROM subscriptions
 	`
 
-	conditions := make([]string, 0, 2)
-	args := make([]interface{}, 0, 2)
-	if strings.TrimSpace(userID) != "" {
+	conditions := make([]string, 0, 8)
+	args := make([]interface{}, 0, 12)
+	if strings.TrimSpace(filter.UserID) != "" {
 		conditions = append(conditions, "user_id = ?")
-		args = append(args, userID)
+		args = append(args, filter.UserID)
 	}
-	if strings.TrimSpace(email) != "" {
+	if strings.TrimSpace(filter.Email) != "" {
 		conditions = append(conditions, "email = ?")
-		args = append(args, email)
+		args = append(args, filter.Email)
 	}
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+	if filter.Status != nil {
+		conditions = append(conditions, "status = ?")
+		args = append(args, *filter.Status)
 	}
-	query += " ORDER BY id DESC"
-
-	rows, err := r.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, err
+	if filter.SubscriptionTypeID != 0 {
+		conditions = append(conditions, "subscription_type_id = ?")
+		args = append(args, filter.SubscriptionTypeID)
+	}
+	if filter.AutoRenew != nil {
+		conditions = append(conditions, "auto_renew = ?")
+		args = append(args, *filter.AutoRenew)
+	}
+	if filter.EndAtFrom != nil {
+		conditions = append(conditions, "end_at >= ?")
+		args = append(args, *filter.EndAtFrom)
+	}
+	if filter.EndAtTo != nil {
+		conditions = append(conditions, "end_at <= ?")
+		args = append(args, *filter.EndAtTo)
+	}
+	if filter.CreatedAtFrom != nil {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, *filter.CreatedAtFrom)
+	}
+	if filter.CreatedAtTo != nil {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, *filter.CreatedAtTo)
 	}
-	defer rows.Close()
 
-	items := make([]*entity.Subscription, 0)
-	for rows.Next() {
-		item, err := scanSubscriptionFromRows(rows)
-		if err != nil {
-			return nil, err
+	column, direction := subscriptionSortColumn(filter.Sort)
+	comparator := "<"
+	if direction == "ASC" {
+		comparator = ">"
+	}
+	if filter.After != nil {
+		if column == "" {
+			conditions = append(conditions, "id "+comparator+" ?")
+			args = append(args, filter.After.ID)
+		} else {
+			conditions = append(conditions, "("+column+" "+comparator+" ? OR ("+column+" = ? AND id "+comparator+" ?))")
+			args = append(args, filter.After.Value, filter.After.Value, filter.After.ID)
 		}
-		items = append(items, item)
 	}
-	if err := rows.Err(); err != nil {
-		return nil, err
+
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	if column == "" {
+		query += " ORDER BY id " + direction
+	} else {
+		query += " ORDER BY " + column + " " + direction + ", id " + direction
+	}
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
 	}
 
-	return items, nil
+	return r.listByQuery(ctx, query, args...)
 }
 
-func (r *SubscriptionRepository) ListDueAutoRenew(ctx context.Context, nowSQLTime time.Time) ([]*entity.Subscription, error) {
-	query := `
-		SELECT id, subscription_type_id, user_id, email, status,
-		       start_at, end_at, renew_at, auto_renew,
-		       created_at, updated_at
-		FROM subscriptions
-		WHERE auto_renew = 1
+// subscriptionSortColumn maps a sort order to its SQL sort expression (empty when
+// sorting by id alone) and direction. Unknown orders fall back to newest first.
+func subscriptionSortColumn(sort string) (string, string) {
+	switch sort {
+	case SubscriptionSortIDAsc:
+		return "", "ASC"
+	case SubscriptionSortCreatedAtDesc:
+		return "created_at", "DESC"
+	case SubscriptionSortCreatedAtAsc:
+		return "created_at", "ASC"
+	case SubscriptionSortEndAtDesc:
+		return "IFNULL(end_at, '9999-12-31 23:59:59')", "DESC"
+	case SubscriptionSortEndAtAsc:
+		return "IFNULL(end_at, '9999-12-31 23:59:59')", "ASC"
+	default:
+		return "", "DESC"
+	}
+}
+
+// ClaimDueAutoRenew leases the active, trialing and past-due subscriptions with
+// auto-renew on whose renew_at has come. A processing row whose last status
+// change started a renewal was left mid-renewal, by a worker that died or a
+// save that failed, and is leased again so the renewal is completed; rows in
+// processing for any other reason, such as an unpaid initial purchase, are
+// left alone.
+func (r *SubscriptionRepository) ClaimDueAutoRenew(ctx context.Context, nowSQLTime time.Time, claim SubscriptionClaim) ([]*entity.Subscription, error) {
+	condition := `auto_renew = 1
 		  AND renew_at <= ?
-		  AND status = ?
-		ORDER BY id ASC
+		  AND (status IN (?, ?, ?) OR (status = ? AND EXISTS (
+		      SELECT 1
+		      FROM subscription_events e
+		      WHERE e.subscription_id = subscriptions.id
+		        AND e.field = ?
+		        AND e.reason = ?
+		        AND e.id = (
+		            SELECT MAX(latest.id)
+		            FROM subscription_events latest
+		            WHERE latest.subscription_id = subscriptions.id
+		              AND latest.field = ?
+		        )
+		  )))`
+
+	return r.claim(ctx, claim, condition, nowSQLTime,
+		entity.SubscriptionStatusActive, entity.SubscriptionStatusTrialing, entity.SubscriptionStatusPastDue, entity.SubscriptionStatusProcessing,
+		entity.SubscriptionEventFieldStatus, entity.SubscriptionEventReasonRenewalStarted, entity.SubscriptionEventFieldStatus)
+}
+
+func (r *SubscriptionRepository) ClaimPendingPaymentStale(ctx context.Context, cutoffSQLTime time.Time, claim SubscriptionClaim) ([]*entity.Subscription, error) {
+	condition := `status = ?
+		  AND updated_at < ?`
+
+	return r.claim(ctx, claim, condition, entity.SubscriptionStatusPendingPayment, cutoffSQLTime)
+}
+
+// ClaimExpiredActive leases subscriptions in use whose period has ended. Past-due
+// subscriptions are left to dunning unless auto-renew was turned off.
+func (r *SubscriptionRepository) ClaimExpiredActive(ctx context.Context, nowSQLTime time.Time, claim SubscriptionClaim) ([]*entity.Subscription, error) {
+	condition := `(status IN (?, ?) OR (status = ? AND auto_renew = 0))
+		  AND end_at IS NOT NULL
+		  AND end_at < ?`
+
+	return r.claim(ctx, claim, condition, entity.SubscriptionStatusActive, entity.SubscriptionStatusTrialing, entity.SubscriptionStatusPastDue, nowSQLTime)
+}
+
+func (r *SubscriptionRepository) ClaimDueResume(ctx context.Context, nowSQLTime time.Time, claim SubscriptionClaim) ([]*entity.Subscription, error) {
+	condition := `status = ?
+		  AND resume_at IS NOT NULL
+		  AND resume_at <= ?`
+
+	return r.claim(ctx, claim, condition, entity.SubscriptionStatusPaused, nowSQLTime)
+}
+
+// ReleaseClaims drops the leases taken by owner so other workers can pick the
+// rows up again.
+func (r *SubscriptionRepository) ReleaseClaims(ctx context.Context, owner string) error {
+	query := `
+		UPDATE subscriptions
+		SET claimed_by = NULL, claimed_until = NULL, updated_at = updated_at
+		WHERE claimed_by = ?
 	`
 
-	return r.listByQuery(ctx, query, nowSQLTime, entity.SubscriptionStatusActive)
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, owner)
+	return err
 }
 
-func (r *SubscriptionRepository) ListPendingPaymentStale(ctx context.Context, cutoffSQLTime time.Time) ([]*entity.Subscription, error) {
+// claim leases up to claim.Limit unleased rows after claim.AfterID matching
+// condition to claim.Owner and returns the rows after claim.AfterID the owner
+// holds. The conditional UPDATE is atomic, so concurrent workers never lease the
+// same row. updated_at is kept as it is, since the pending-payment cleanup
+// selects on it.
+func (r *SubscriptionRepository) claim(ctx context.Context, claim SubscriptionClaim, condition string, args ...interface{}) ([]*entity.Subscription, error) {
 	query := `
-		SELECT id, subscription_type_id, user_id, email, status,
-		       start_at, end_at, renew_at, auto_renew,
+		UPDATE subscriptions
+		SET claimed_by = ?, claimed_until = ?, updated_at = updated_at
+		WHERE ` + condition + `
+		  AND id > ?
+		  AND (claimed_until IS NULL OR claimed_until <= ?)
+		ORDER BY id ASC
+		LIMIT ?
+	`
+
+	claimArgs := make([]interface{}, 0, len(args)+5)
+	claimArgs = append(claimArgs, claim.Owner, claim.Until)
+	claimArgs = append(claimArgs, args...)
+	claimArgs = append(claimArgs, claim.AfterID, claim.Now, claim.Limit)
+	if _, err := conn(ctx, r.db).ExecContext(ctx, query, claimArgs...); err != nil {
+		return nil, err
+	}
+
+	query = `
+		SELECT id, subscription_type_id, plan_type_id, pending_plan_type_id, user_id, email, status,
+		       start_at, end_at, renew_at, trial_end_at, canceled_at, cancel_reason, paused_at, resume_at, renewal_retry_count, billing_anchor_day, quantity, auto_renew, version,
 		       created_at, updated_at
 		FROM subscriptions
-		WHERE status = ?
-		  AND updated_at < ?
+		WHERE claimed_by = ? AND id > ?
 		ORDER BY id ASC
 	`
 
-	return r.listByQuery(ctx, query, entity.SubscriptionStatusPendingPayment, cutoffSQLTime)
+	return r.listByQuery(ctx, query, claim.Owner, claim.AfterID)
 }
 
-func (r *SubscriptionRepository) ListExpiredActive(ctx context.Context, nowSQLTime time.Time) ([]*entity.Subscription, error) {
+// HasUsedTrial reports whether a subscription of the type matching userID or
+// email has already been given a trial.
+func (r *SubscriptionRepository) HasUsedTrial(ctx context.Context, subscriptionTypeID uint64, userID, email *string) (bool, error) {
 	query := `
-		SELECT id, subscription_type_id, user_id, email, status,
-		       start_at, end_at, renew_at, auto_renew,
-		       created_at, updated_at
+		SELECT 1
 		FROM subscriptions
-		WHERE status = ?
-		  AND end_at IS NOT NULL
-		  AND end_at < ?
-		ORDER BY id ASC
+		WHERE subscription_type_id = ?
+		  AND trial_end_at IS NOT NULL
+		  AND (user_id = ? OR email = ?)
+		LIMIT 1
 	`
 
-	return r.listByQuery(ctx, query, entity.SubscriptionStatusActive, nowSQLTime)
+	var found int
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, subscriptionTypeID, nullableStringValue(userID), nullableStringValue(email)).Scan(&found)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
 }
 
 func (r *SubscriptionRepository) listByQuery(ctx context.Context, query string, args ...interface{}) ([]*entity.Subscription, error) {
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -253,22 +485,40 @@ type rowScanner interface {
 }
 
 func scanSubscription(scanner rowScanner, item *entity.Subscription) error {
+	var planTypeID sql.NullInt64
+	var pendingPlanTypeID sql.NullInt64
 	var userID sql.NullString
 	var email sql.NullString
 	var startAt sql.NullTime
 	var endAt sql.NullTime
 	var renewAt sql.NullTime
+	var trialEndAt sql.NullTime
+	var canceledAt sql.NullTime
+	var cancelReason sql.NullString
+	var pausedAt sql.NullTime
+	var resumeAt sql.NullTime
 
 	err := scanner.Scan(
 		&item.ID,
 		&item.SubscriptionTypeID,
+		&planTypeID,
+		&pendingPlanTypeID,
 		&userID,
 		&email,
 		&item.Status,
 		&startAt,
 		&endAt,
 		&renewAt,
+		&trialEndAt,
+		&canceledAt,
+		&cancelReason,
+		&pausedAt,
+		&resumeAt,
+		&item.RenewalRetryCount,
+		&item.BillingAnchorDay,
+		&item.Quantity,
 		&item.AutoRenew,
+		&item.Version,
 		&item.CreatedAt,
 		&item.UpdatedAt,
 	)
@@ -276,6 +526,18 @@ func scanSubscription(scanner rowScanner, item *entity.Subscription) error {
 		return err
 	}
 
+	if planTypeID.Valid {
+		value := uint64(planTypeID.Int64)
+		item.PlanTypeID = &value
+	} else {
+		item.PlanTypeID = nil
+	}
+	if pendingPlanTypeID.Valid {
+		value := uint64(pendingPlanTypeID.Int64)
+		item.PendingPlanTypeID = &value
+	} else {
+		item.PendingPlanTypeID = nil
+	}
 	if userID.Valid {
 		item.UserID = &userID.String
 	} else {
@@ -301,6 +563,31 @@ func scanSubscription(scanner rowScanner, item *entity.Subscription) error {
 	} else {
 		item.RenewAt = nil
 	}
+	if trialEndAt.Valid {
+		item.TrialEndAt = &trialEndAt.Time
+	} else {
+		item.TrialEndAt = nil
+	}
+	if canceledAt.Valid {
+		item.CanceledAt = &canceledAt.Time
+	} else {
+		item.CanceledAt = nil
+	}
+	if cancelReason.Valid {
+		item.CancelReason = &cancelReason.String
+	} else {
+		item.CancelReason = nil
+	}
+	if pausedAt.Valid {
+		item.PausedAt = &pausedAt.Time
+	} else {
+		item.PausedAt = nil
+	}
+	if resumeAt.Valid {
+		item.ResumeAt = &resumeAt.Time
+	} else {
+		item.ResumeAt = nil
+	}
 
 	return nil
 }
@@ -320,6 +607,13 @@ func nullableStringValue(v *string) interface{} {
 	return strings.TrimSpace(*v)
 }
 
+func nullableUint64Value(v *uint64) interface{} {
+	if v == nil {
+		return nil
+	}
+	return *v
+}
+
 func nullableTimeValue(v *time.Time) interface{} {
 	if v == nil {
 		return nil
@@ -0,0 +1,111 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+type SubscriptionEventRepository struct {
+	db DBTX
+}
+
+func NewSubscriptionEventRepository(db DBTX) *SubscriptionEventRepository {
+	return &SubscriptionEventRepository{db: db}
+}
+
+func (r *SubscriptionEventRepository) Create(ctx context.Context, event *entity.SubscriptionEvent) error {
+	query := `
+		INSERT INTO subscription_events (
+			subscription_id, field, old_value, new_value, actor, request_id, reason, created_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		event.SubscriptionID,
+		event.Field,
+		nullableStringValue(event.OldValue),
+		nullableStringValue(event.NewValue),
+		event.Actor,
+		nullableStringValue(event.RequestID),
+		event.Reason,
+		event.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	event.ID = uint64(id)
+	return nil
+}
+
+func (r *SubscriptionEventRepository) ListBySubscriptionID(ctx context.Context, subscriptionID uint64) ([]*entity.SubscriptionEvent, error) {
+	query := `
+		SELECT id, subscription_id, field, old_value, new_value, actor, request_id, reason, created_at
+		FROM subscription_events
+		WHERE subscription_id = ?
+		ORDER BY id DESC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*entity.SubscriptionEvent, 0)
+	for rows.Next() {
+		item := &entity.SubscriptionEvent{}
+		if err := scanSubscriptionEvent(rows, item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func scanSubscriptionEvent(scanner rowScanner, item *entity.SubscriptionEvent) error {
+	var oldValue sql.NullString
+	var newValue sql.NullString
+	var requestID sql.NullString
+
+	err := scanner.Scan(
+		&item.ID,
+		&item.SubscriptionID,
+		&item.Field,
+		&oldValue,
+		&newValue,
+		&item.Actor,
+		&requestID,
+		&item.Reason,
+		&item.CreatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	item.OldValue = nil
+	if oldValue.Valid {
+		item.OldValue = &oldValue.String
+	}
+	item.NewValue = nil
+	if newValue.Valid {
+		item.NewValue = &newValue.String
+	}
+	item.RequestID = nil
+	if requestID.Valid {
+		item.RequestID = &requestID.String
+	}
+
+	return nil
+}
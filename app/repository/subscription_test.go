@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"errors"
+	"strings"
 	"testing"
 	"time"
 
@@ -61,8 +62,8 @@ func TestCreateSuccess(t *testing.T) {
 	if err := repo.Create(context.Background(), s); err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if s.ID != 22 {
-		t.Fatalf("expected id=22, got %d", s.ID)
+	if s.ID != 22 || s.Version != 1 {
+		t.Fatalf("expected id=22 at version 1, got %d at %d", s.ID, s.Version)
 	}
 }
 
@@ -78,13 +79,70 @@ func TestCreateMapsDuplicate(t *testing.T) {
 }
 
 func TestUpdateNoRowsAffected(t *testing.T) {
-	repo := NewSubscriptionRepository(&fakeDB{execFn: func(_ context.Context, _ string, _ ...interface{}) (sql.Result, error) {
+	var gotArgs []interface{}
+	repo := NewSubscriptionRepository(&fakeDB{execFn: func(_ context.Context, _ string, args ...interface{}) (sql.Result, error) {
+		gotArgs = args
 		return fakeResult{rowsAffected: 0}, nil
 	}})
 
-	err := repo.Update(context.Background(), &entity.Subscription{ID: 1})
-	if !errors.Is(err, ErrSubscriptionNotFound) {
-		t.Fatalf("expected ErrSubscriptionNotFound, got %v", err)
+	subscription := &entity.Subscription{ID: 1, Version: 3}
+	err := repo.Update(context.Background(), subscription)
+	if !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification, got %v", err)
+	}
+	if gotArgs[len(gotArgs)-1] != int64(3) {
+		t.Fatalf("expected update to be guarded by the read version, got %#v", gotArgs)
+	}
+	if subscription.Version != 3 {
+		t.Fatalf("expected version to stay at 3, got %d", subscription.Version)
+	}
+}
+
+func TestUpdateMovesToNextVersion(t *testing.T) {
+	repo := NewSubscriptionRepository(&fakeDB{})
+
+	subscription := &entity.Subscription{ID: 1, Version: 3}
+	if err := repo.Update(context.Background(), subscription); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if subscription.Version != 4 {
+		t.Fatalf("expected version 4, got %d", subscription.Version)
+	}
+}
+
+func TestClaimDueAutoRenewSkipsLeasedRows(t *testing.T) {
+	var gotQuery string
+	var gotArgs []interface{}
+	repo := NewSubscriptionRepository(&fakeDB{execFn: func(_ context.Context, query string, args ...interface{}) (sql.Result, error) {
+		gotQuery = query
+		gotArgs = args
+		return fakeResult{rowsAffected: 2}, nil
+	}})
+
+	now := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	claim := SubscriptionClaim{Owner: "worker-1/ab", Now: now, Until: now.Add(15 * time.Minute), AfterID: 40, Limit: 50}
+	_, _ = repo.ClaimDueAutoRenew(context.Background(), now, claim)
+
+	if !strings.Contains(gotQuery, "(claimed_until IS NULL OR claimed_until <= ?)") || !strings.Contains(gotQuery, "LIMIT ?") {
+		t.Fatalf("expected the update to skip rows under a live lease, got %s", gotQuery)
+	}
+	if !strings.Contains(gotQuery, "updated_at = updated_at") {
+		t.Fatalf("expected the lease to keep updated_at, got %s", gotQuery)
+	}
+	if !strings.Contains(gotQuery, "OR (status = ? AND EXISTS (") || gotArgs[6] != entity.SubscriptionStatusProcessing {
+		t.Fatalf("expected renewals abandoned mid-run to be claimed again, got %s %#v", gotQuery, gotArgs)
+	}
+	if !strings.Contains(gotQuery, "SELECT MAX(latest.id)") || gotArgs[8] != entity.SubscriptionEventReasonRenewalStarted {
+		t.Fatalf("expected only processing rows whose last status change started a renewal, got %s %#v", gotQuery, gotArgs)
+	}
+	if gotArgs[0] != claim.Owner || gotArgs[1] != claim.Until {
+		t.Fatalf("expected the lease to be set first, got %#v", gotArgs)
+	}
+	if !strings.Contains(gotQuery, "AND id > ?") || gotArgs[len(gotArgs)-3] != uint64(40) {
+		t.Fatalf("expected the update to continue after the last id, got %s %#v", gotQuery, gotArgs)
+	}
+	if gotArgs[len(gotArgs)-2] != now || gotArgs[len(gotArgs)-1] != 50 {
+		t.Fatalf("expected lapsed leases and the batch size last, got %#v", gotArgs)
 	}
 }
 
@@ -117,13 +175,24 @@ func TestNullableHelpers(t *testing.T) {
 type fakeRowScanner struct {
 	id                 uint64
 	subscriptionTypeID uint64
+	planTypeID         sql.NullInt64
+	pendingPlanTypeID  sql.NullInt64
 	userID             sql.NullString
 	email              sql.NullString
 	status             int32
 	startAt            sql.NullTime
 	endAt              sql.NullTime
 	renewAt            sql.NullTime
+	trialEndAt         sql.NullTime
+	canceledAt         sql.NullTime
+	cancelReason       sql.NullString
+	pausedAt           sql.NullTime
+	resumeAt           sql.NullTime
+	renewalRetryCount  int32
+	billingAnchorDay   int32
+	quantity           int32
 	autoRenew          bool
+	version            int64
 	createdAt          time.Time
 	updatedAt          time.Time
 	err                error
@@ -135,15 +204,26 @@ func (f fakeRowScanner) Scan(dest ...interface{}) error {
 	}
 	*(dest[0].(*uint64)) = f.id
 	*(dest[1].(*uint64)) = f.subscriptionTypeID
-	*(dest[2].(*sql.NullString)) = f.userID
-	*(dest[3].(*sql.NullString)) = f.email
-	*(dest[4].(*int32)) = f.status
-	*(dest[5].(*sql.NullTime)) = f.startAt
-	*(dest[6].(*sql.NullTime)) = f.endAt
-	*(dest[7].(*sql.NullTime)) = f.renewAt
-	*(dest[8].(*bool)) = f.autoRenew
-	*(dest[9].(*time.Time)) = f.createdAt
-	*(dest[10].(*time.Time)) = f.updatedAt
+	*(dest[2].(*sql.NullInt64)) = f.planTypeID
+	*(dest[3].(*sql.NullInt64)) = f.pendingPlanTypeID
+	*(dest[4].(*sql.NullString)) = f.userID
+	*(dest[5].(*sql.NullString)) = f.email
+	*(dest[6].(*int32)) = f.status
+	*(dest[7].(*sql.NullTime)) = f.startAt
+	*(dest[8].(*sql.NullTime)) = f.endAt
+	*(dest[9].(*sql.NullTime)) = f.renewAt
+	*(dest[10].(*sql.NullTime)) = f.trialEndAt
+	*(dest[11].(*sql.NullTime)) = f.canceledAt
+	*(dest[12].(*sql.NullString)) = f.cancelReason
+	*(dest[13].(*sql.NullTime)) = f.pausedAt
+	*(dest[14].(*sql.NullTime)) = f.resumeAt
+	*(dest[15].(*int32)) = f.renewalRetryCount
+	*(dest[16].(*int32)) = f.billingAnchorDay
+	*(dest[17].(*int32)) = f.quantity
+	*(dest[18].(*bool)) = f.autoRenew
+	*(dest[19].(*int64)) = f.version
+	*(dest[20].(*time.Time)) = f.createdAt
+	*(dest[21].(*time.Time)) = f.updatedAt
 	return nil
 }
 
@@ -157,23 +237,41 @@ func TestScanSubscription(t *testing.T) {
 	err := scanSubscription(fakeRowScanner{
 		id:                 9,
 		subscriptionTypeID: 2,
+		planTypeID:         sql.NullInt64{Int64: 20, Valid: true},
+		pendingPlanTypeID:  sql.NullInt64{Int64: 21, Valid: true},
 		userID:             sql.NullString{String: "u-1", Valid: true},
 		email:              sql.NullString{String: "u-1@example.com", Valid: true},
 		status:             entity.SubscriptionStatusActive,
 		startAt:            sql.NullTime{Time: start, Valid: true},
 		endAt:              sql.NullTime{Time: end, Valid: true},
 		renewAt:            sql.NullTime{Time: renew, Valid: true},
+		trialEndAt:         sql.NullTime{Time: start, Valid: true},
+		canceledAt:         sql.NullTime{Time: now, Valid: true},
+		cancelReason:       sql.NullString{String: "too expensive", Valid: true},
+		pausedAt:           sql.NullTime{Time: now, Valid: true},
+		resumeAt:           sql.NullTime{Time: end, Valid: true},
+		renewalRetryCount:  2,
+		billingAnchorDay:   31,
+		quantity:           5,
 		autoRenew:          true,
+		version:            4,
 		createdAt:          now,
 		updatedAt:          now,
 	}, item)
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if item.ID != 9 || item.SubscriptionTypeID != 2 || item.UserID == nil || item.Email == nil {
+	if item.ID != 9 || item.SubscriptionTypeID != 2 || item.PlanTypeID == nil || *item.PlanTypeID != 20 || item.PendingPlanTypeID == nil || *item.PendingPlanTypeID != 21 || item.UserID == nil || item.Email == nil {
 		t.Fatalf("unexpected scan result: %+v", item)
 	}
-	if item.StartAt == nil || item.EndAt == nil || item.RenewAt == nil {
+	if item.StartAt == nil || item.EndAt == nil || item.RenewAt == nil || item.TrialEndAt == nil || item.CanceledAt == nil ||
+		item.PausedAt == nil || item.ResumeAt == nil {
 		t.Fatalf("expected all time pointers to be populated: %+v", item)
 	}
+	if item.CancelReason == nil || *item.CancelReason != "too expensive" {
+		t.Fatalf("expected cancel reason to be populated: %+v", item)
+	}
+	if item.RenewalRetryCount != 2 || item.BillingAnchorDay != 31 || item.Quantity != 5 || !item.AutoRenew || item.Version != 4 {
+		t.Fatalf("expected retry count, quantity, auto renew and version to be populated: %+v", item)
+	}
 }
@@ -19,6 +19,59 @@ func NewSubscriptionTypeRepository(db DBTX) *SubscriptionTypeRepository {
 	return &SubscriptionTypeRepository{db: db}
 }
 
+func (r *SubscriptionTypeRepository) Create(ctx context.Context, subscriptionType *entity.SubscriptionType) error {
+	query := `
+		INSERT INTO subscription_types (type, display_name, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		subscriptionType.Type,
+		subscriptionType.DisplayName,
+		subscriptionType.Status,
+		subscriptionType.CreatedAt,
+		subscriptionType.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	subscriptionType.ID = uint64(id)
+	return nil
+}
+
+func (r *SubscriptionTypeRepository) Update(ctx context.Context, subscriptionType *entity.SubscriptionType) error {
+	query := `
+		UPDATE subscription_types
+		SET display_name = ?, status = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		subscriptionType.DisplayName,
+		subscriptionType.Status,
+		subscriptionType.UpdatedAt,
+		subscriptionType.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSubscriptionTypeNotFound
+	}
+
+	return nil
+}
+
 func (r *SubscriptionTypeRepository) List(ctx context.Context, typeFilter string, hasStatus bool, status int32) ([]*entity.SubscriptionType, error) {
 	query := `
 		SELECT id, type, display_name, status, created_at, updated_at
@@ -40,7 +93,7 @@ func (r *SubscriptionTypeRepository) List(ctx context.Context, typeFilter string
 	}
 	query += " ORDER BY id ASC"
 
-	rows, err := r.db.QueryContext(ctx, query, args...)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -69,7 +122,7 @@ func (r *SubscriptionTypeRepository) FindByID(ctx context.Context, id uint64) (*
 	`
 
 	item := &entity.SubscriptionType{}
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id).Scan(
 		&item.ID,
 		&item.Type,
 		&item.DisplayName,
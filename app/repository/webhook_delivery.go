@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+var (
+	ErrWebhookDeliveryNotFound      = errors.New("webhook delivery not found")
+	ErrWebhookDeliveryAlreadyExists = errors.New("webhook delivery already exists")
+)
+
+type WebhookDeliveryRepository struct {
+	db DBTX
+}
+
+func NewWebhookDeliveryRepository(db DBTX) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+// Create queues a delivery. Each event is queued at most once per endpoint, so a
+// repeated fan-out of the same event returns ErrWebhookDeliveryAlreadyExists.
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (
+			webhook_endpoint_id, event_id, event_type, subscription_id, payload, status, attempts,
+			response_status, last_error, next_attempt_at, delivered_at, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		delivery.WebhookEndpointID,
+		delivery.EventID,
+		delivery.EventType,
+		delivery.SubscriptionID,
+		delivery.Payload,
+		delivery.Status,
+		delivery.Attempts,
+		nullableResponseStatus(delivery.ResponseStatus),
+		nullableRawString(delivery.LastError),
+		delivery.NextAttemptAt,
+		nullableTimeValue(delivery.DeliveredAt),
+		delivery.CreatedAt,
+		delivery.UpdatedAt,
+	)
+	if err != nil {
+		if isDuplicateEntryError(err) {
+			return ErrWebhookDeliveryAlreadyExists
+		}
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	delivery.ID = uint64(id)
+	return nil
+}
+
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, response_status = ?, last_error = ?, next_attempt_at = ?, delivered_at = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		delivery.Status,
+		delivery.Attempts,
+		nullableResponseStatus(delivery.ResponseStatus),
+		nullableRawString(delivery.LastError),
+		delivery.NextAttemptAt,
+		nullableTimeValue(delivery.DeliveredAt),
+		delivery.UpdatedAt,
+		delivery.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrWebhookDeliveryNotFound
+	}
+
+	return nil
+}
+
+// WebhookDeliveryClaim is a lease a delivery worker takes on the deliveries it
+// sends, so that workers running side by side never send the same delivery. As
+// with OutboxClaim, leases older than Now are free to take over.
+type WebhookDeliveryClaim struct {
+	Owner string
+	Now   time.Time
+	Until time.Time
+	Limit int
+}
+
+// ClaimDue leases up to claim.Limit unleased pending deliveries that are due at
+// claim.Now, oldest first, and returns the deliveries the owner holds. The lease
+// is checked again when the rows are updated, so concurrent workers never lease
+// the same delivery.
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, claim WebhookDeliveryClaim) ([]*entity.WebhookDelivery, error) {
+	query := `
+		UPDATE webhook_deliveries
+		SET claimed_by = ?, claimed_until = ?, updated_at = updated_at
+		WHERE status = ?
+		  AND (claimed_until IS NULL OR claimed_until <= ?)
+		  AND id IN (
+		      SELECT id FROM (
+		          SELECT d.id
+		          FROM webhook_deliveries d
+		          WHERE d.status = ?
+		            AND d.next_attempt_at <= ?
+		            AND (d.claimed_until IS NULL OR d.claimed_until <= ?)
+		          ORDER BY d.id ASC
+		          LIMIT ?
+		      ) due
+		  )
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query,
+		claim.Owner,
+		claim.Until,
+		entity.WebhookDeliveryStatusPending,
+		claim.Now,
+		entity.WebhookDeliveryStatusPending,
+		claim.Now,
+		claim.Now,
+		claim.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	query = `
+		SELECT id, webhook_endpoint_id, event_id, event_type, subscription_id, payload, status, attempts,
+		       response_status, last_error, next_attempt_at, delivered_at, created_at, updated_at
+		FROM webhook_deliveries
+		WHERE claimed_by = ?
+		ORDER BY id ASC
+	`
+
+	return r.list(ctx, query, claim.Owner)
+}
+
+// ReleaseClaims drops the leases taken by owner so other workers can pick the
+// deliveries up again.
+func (r *WebhookDeliveryRepository) ReleaseClaims(ctx context.Context, owner string) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET claimed_by = NULL, claimed_until = NULL, updated_at = updated_at
+		WHERE claimed_by = ?
+	`
+
+	_, err := conn(ctx, r.db).ExecContext(ctx, query, owner)
+	return err
+}
+
+// ListByEndpointID returns the most recent deliveries of an endpoint, newest first.
+func (r *WebhookDeliveryRepository) ListByEndpointID(ctx context.Context, endpointID uint64, limit int) ([]*entity.WebhookDelivery, error) {
+	query := `
+		SELECT id, webhook_endpoint_id, event_id, event_type, subscription_id, payload, status, attempts,
+		       response_status, last_error, next_attempt_at, delivered_at, created_at, updated_at
+		FROM webhook_deliveries
+		WHERE webhook_endpoint_id = ?
+		ORDER BY id DESC
+		LIMIT ?
+	`
+
+	return r.list(ctx, query, endpointID, limit)
+}
+
+func (r *WebhookDeliveryRepository) list(ctx context.Context, query string, args ...interface{}) ([]*entity.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*entity.WebhookDelivery, 0)
+	for rows.Next() {
+		item := &entity.WebhookDelivery{}
+		if err := scanWebhookDelivery(rows, item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func scanWebhookDelivery(scanner rowScanner, item *entity.WebhookDelivery) error {
+	var responseStatus sql.NullInt64
+	var lastError sql.NullString
+	var deliveredAt sql.NullTime
+
+	err := scanner.Scan(
+		&item.ID,
+		&item.WebhookEndpointID,
+		&item.EventID,
+		&item.EventType,
+		&item.SubscriptionID,
+		&item.Payload,
+		&item.Status,
+		&item.Attempts,
+		&responseStatus,
+		&lastError,
+		&item.NextAttemptAt,
+		&deliveredAt,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	item.ResponseStatus = int(responseStatus.Int64)
+	item.LastError = lastError.String
+	item.DeliveredAt = nil
+	if deliveredAt.Valid {
+		item.DeliveredAt = &deliveredAt.Time
+	}
+
+	return nil
+}
+
+func nullableResponseStatus(v int) interface{} {
+	if v == 0 {
+		return nil
+	}
+	return v
+}
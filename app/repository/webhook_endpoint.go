@@ -0,0 +1,194 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+var ErrWebhookEndpointNotFound = errors.New("webhook endpoint not found")
+
+type WebhookEndpointRepository struct {
+	db DBTX
+}
+
+func NewWebhookEndpointRepository(db DBTX) *WebhookEndpointRepository {
+	return &WebhookEndpointRepository{db: db}
+}
+
+func (r *WebhookEndpointRepository) Create(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	query := `
+		INSERT INTO webhook_endpoints (
+			url, description, event_types, secret, status, created_by, created_at, updated_at
+		)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		endpoint.URL,
+		endpoint.Description,
+		joinEventTypes(endpoint.EventTypes),
+		endpoint.Secret,
+		endpoint.Status,
+		endpoint.CreatedBy,
+		endpoint.CreatedAt,
+		endpoint.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	endpoint.ID = uint64(id)
+	return nil
+}
+
+func (r *WebhookEndpointRepository) Update(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	query := `
+		UPDATE webhook_endpoints
+		SET url = ?, description = ?, event_types = ?, status = ?, updated_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		endpoint.URL,
+		endpoint.Description,
+		joinEventTypes(endpoint.EventTypes),
+		endpoint.Status,
+		endpoint.UpdatedAt,
+		endpoint.ID,
+	)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrWebhookEndpointNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookEndpointRepository) FindByID(ctx context.Context, id uint64) (*entity.WebhookEndpoint, error) {
+	query := `
+		SELECT id, url, description, event_types, secret, status, created_by, created_at, updated_at
+		FROM webhook_endpoints
+		WHERE id = ?
+	`
+
+	item := &entity.WebhookEndpoint{}
+	err := scanWebhookEndpoint(conn(ctx, r.db).QueryRowContext(ctx, query, id), item)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (r *WebhookEndpointRepository) List(ctx context.Context) ([]*entity.WebhookEndpoint, error) {
+	query := `
+		SELECT id, url, description, event_types, secret, status, created_by, created_at, updated_at
+		FROM webhook_endpoints
+		ORDER BY id ASC
+	`
+
+	return r.list(ctx, query)
+}
+
+func (r *WebhookEndpointRepository) ListActive(ctx context.Context) ([]*entity.WebhookEndpoint, error) {
+	query := `
+		SELECT id, url, description, event_types, secret, status, created_by, created_at, updated_at
+		FROM webhook_endpoints
+		WHERE status = ?
+		ORDER BY id ASC
+	`
+
+	return r.list(ctx, query, entity.WebhookEndpointStatusActive)
+}
+
+func (r *WebhookEndpointRepository) Delete(ctx context.Context, id uint64) error {
+	result, err := conn(ctx, r.db).ExecContext(ctx, "DELETE FROM webhook_endpoints WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrWebhookEndpointNotFound
+	}
+
+	return nil
+}
+
+func (r *WebhookEndpointRepository) list(ctx context.Context, query string, args ...interface{}) ([]*entity.WebhookEndpoint, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := make([]*entity.WebhookEndpoint, 0)
+	for rows.Next() {
+		item := &entity.WebhookEndpoint{}
+		if err := scanWebhookEndpoint(rows, item); err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func scanWebhookEndpoint(scanner rowScanner, item *entity.WebhookEndpoint) error {
+	var eventTypes string
+
+	err := scanner.Scan(
+		&item.ID,
+		&item.URL,
+		&item.Description,
+		&eventTypes,
+		&item.Secret,
+		&item.Status,
+		&item.CreatedBy,
+		&item.CreatedAt,
+		&item.UpdatedAt,
+	)
+	if err != nil {
+		return err
+	}
+
+	item.EventTypes = splitEventTypes(eventTypes)
+	return nil
+}
+
+// Event type filters are stored as a comma-separated list; an empty list means
+// the endpoint receives every event.
+func joinEventTypes(items []string) string {
+	return strings.Join(items, ",")
+}
+
+func splitEventTypes(raw string) []string {
+	if raw == "" {
+		return []string{}
+	}
+	return strings.Split(raw, ",")
+}
@@ -0,0 +1,72 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCronNext(t *testing.T) {
+	from := time.Date(2026, 3, 14, 10, 7, 30, 0, time.UTC) // a Saturday
+
+	cases := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 14, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 14, 10, 15, 0, 0, time.UTC)},
+		{"5,50 * * * *", time.Date(2026, 3, 14, 10, 50, 0, 0, time.UTC)},
+		{"0 2 * * *", time.Date(2026, 3, 15, 2, 0, 0, 0, time.UTC)},
+		{"30 9-17/4 * * *", time.Date(2026, 3, 14, 13, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 6 * * 1-5", time.Date(2026, 3, 16, 6, 0, 0, 0, time.UTC)},
+		{"0 6 * * 7", time.Date(2026, 3, 15, 6, 0, 0, 0, time.UTC)},
+		// With both day fields restricted either one matches.
+		{"0 0 20 * 0", time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		schedule, err := ParseCron(tc.expr)
+		if err != nil {
+			t.Fatalf("%q: expected no error, got %v", tc.expr, err)
+		}
+		if got := schedule.Next(from); !got.Equal(tc.want) {
+			t.Fatalf("%q: expected %v, got %v", tc.expr, tc.want, got)
+		}
+	}
+}
+
+func TestParseCronRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"10-5 * * * *",
+		"a * * * *",
+		"0 0 31 2 *",
+	} {
+		if _, err := ParseCron(expr); err == nil {
+			t.Fatalf("expected error for %q", expr)
+		}
+	}
+}
+
+func TestScheduleFor(t *testing.T) {
+	from := time.Date(2026, 3, 14, 10, 7, 30, 0, time.UTC)
+
+	schedule, err := ScheduleFor(time.Minute, "")
+	if err != nil || !schedule.Next(from).Equal(from.Add(time.Minute)) {
+		t.Fatalf("expected an interval schedule, got %v", err)
+	}
+	schedule, err = ScheduleFor(time.Minute, "0 * * * *")
+	if err != nil || !schedule.Next(from).Equal(time.Date(2026, 3, 14, 11, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the cron expression to replace the interval, got %v", err)
+	}
+	if _, err := ScheduleFor(0, ""); err == nil {
+		t.Fatal("expected error without interval or cron expression")
+	}
+}
@@ -0,0 +1,143 @@
+package scheduler
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/factory"
+)
+
+// Job is a task the scheduler runs on its schedule.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	// Run does one run of the job. Its context is canceled when the scheduler
+	// stops or loses leadership.
+	Run func(ctx context.Context)
+}
+
+// Locker elects the replica that schedules. Its methods are called from one
+// goroutine at a time.
+type Locker interface {
+	// TryAcquire takes the lock without waiting and reports whether it did.
+	TryAcquire(ctx context.Context) (bool, error)
+	// Held reports whether the lock taken is still held.
+	Held(ctx context.Context) (bool, error)
+	Release(ctx context.Context) error
+}
+
+// Scheduler runs jobs on their schedules while it holds the leader lock, so
+// that of several replicas only one schedules at a time. Each job runs on its
+// own: its next run is planned once the previous one is done, so runs of a job
+// never overlap, and runs missed meanwhile are skipped.
+type Scheduler struct {
+	jobs        []Job
+	locker      Locker
+	jitter      time.Duration
+	leaderCheck time.Duration
+	logger      logrus.FieldLogger
+}
+
+// NewScheduler creates a scheduler that delays every run by up to jitter, so
+// jobs due at the same moment do not all start at once, and tries to take or
+// confirm the leader lock every leaderCheck.
+func NewScheduler(locker Locker, jobs []Job, jitter, leaderCheck time.Duration) *Scheduler {
+	return &Scheduler{
+		jobs:        jobs,
+		locker:      locker,
+		jitter:      jitter,
+		leaderCheck: leaderCheck,
+		logger:      factory.NewModuleLogger("scheduler"),
+	}
+}
+
+// Run schedules the jobs until ctx is canceled. It returns once the runs under
+// way are done and the leader lock is released.
+func (s *Scheduler) Run(ctx context.Context) {
+	for {
+		leader, err := s.locker.TryAcquire(ctx)
+		if err != nil && ctx.Err() == nil {
+			s.logger.WithError(err).Warn("Scheduler leader lock unavailable")
+		}
+		if leader {
+			s.logger.Info("Scheduler leadership acquired")
+			s.lead(ctx)
+		}
+		if !sleep(ctx, s.leaderCheck) {
+			return
+		}
+	}
+}
+
+// lead runs the jobs until ctx is canceled or the leader lock is lost.
+func (s *Scheduler) lead(ctx context.Context) {
+	leaderCtx, cancel := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.loop(leaderCtx, job)
+		}()
+	}
+
+	ticker := time.NewTicker(s.leaderCheck)
+	defer ticker.Stop()
+	for held := true; held; {
+		select {
+		case <-ctx.Done():
+			held = false
+		case <-ticker.C:
+			var err error
+			if held, err = s.locker.Held(ctx); err != nil && ctx.Err() == nil {
+				s.logger.WithError(err).Warn("Scheduler leader lock check failed")
+			}
+			if !held && ctx.Err() == nil {
+				s.logger.Warn("Scheduler leadership lost")
+			}
+		}
+	}
+
+	cancel()
+	wg.Wait()
+	if err := s.locker.Release(context.WithoutCancel(ctx)); err != nil {
+		s.logger.WithError(err).Warn("Scheduler leader lock release failed")
+	}
+}
+
+// loop runs job on its schedule until ctx is canceled.
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	for {
+		next := job.Schedule.Next(time.Now())
+		if next.IsZero() {
+			s.logger.WithField("job", job.Name).Error("Job has no next run")
+			return
+		}
+		if !sleep(ctx, time.Until(next)+s.randomJitter()) {
+			return
+		}
+		job.Run(ctx)
+	}
+}
+
+func (s *Scheduler) randomJitter() time.Duration {
+	if s.jitter <= 0 {
+		return 0
+	}
+	return rand.N(s.jitter)
+}
+
+// sleep waits for d and reports false when ctx was canceled first.
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
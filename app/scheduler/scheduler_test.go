@@ -0,0 +1,158 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type fakeLocker struct {
+	mu        sync.Mutex
+	available bool
+	held      bool
+	releases  int
+}
+
+func (l *fakeLocker) TryAcquire(_ context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.available {
+		l.held = true
+	}
+	return l.held, nil
+}
+
+func (l *fakeLocker) Held(_ context.Context) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held, nil
+}
+
+func (l *fakeLocker) Release(_ context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.held = false
+	l.releases++
+	return nil
+}
+
+func (l *fakeLocker) set(available, held bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.available = available
+	l.held = held
+}
+
+func runScheduler(t *testing.T, s *Scheduler) (stop func()) {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.Run(ctx)
+	}()
+	return func() {
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("expected the scheduler to stop")
+		}
+	}
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSchedulerRunsJobsOnlyWhileLeader(t *testing.T) {
+	var runs atomic.Int32
+	locker := &fakeLocker{}
+	s := NewScheduler(locker, []Job{{
+		Name:     "renew",
+		Schedule: Every(5 * time.Millisecond),
+		Run:      func(context.Context) { runs.Add(1) },
+	}}, 0, 5*time.Millisecond)
+	stop := runScheduler(t, s)
+	defer stop()
+
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() != 0 {
+		t.Fatalf("expected no runs without the lock, got %d", runs.Load())
+	}
+
+	locker.set(true, false)
+	waitFor(t, "runs as leader", func() bool { return runs.Load() >= 2 })
+
+	// Another replica took the lock over.
+	locker.set(false, false)
+	time.Sleep(20 * time.Millisecond)
+	before := runs.Load()
+	time.Sleep(30 * time.Millisecond)
+	if runs.Load() != before {
+		t.Fatalf("expected no runs after losing leadership, got %d more", runs.Load()-before)
+	}
+}
+
+func TestSchedulerNeverOverlapsRunsOfAJob(t *testing.T) {
+	var inFlight, overlaps, runs atomic.Int32
+	locker := &fakeLocker{available: true}
+	s := NewScheduler(locker, []Job{{
+		Name:     "renew",
+		Schedule: Every(time.Millisecond),
+		Run: func(context.Context) {
+			if inFlight.Add(1) > 1 {
+				overlaps.Add(1)
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+			runs.Add(1)
+		},
+	}}, time.Millisecond, 5*time.Millisecond)
+	stop := runScheduler(t, s)
+
+	waitFor(t, "several runs", func() bool { return runs.Load() >= 3 })
+	stop()
+	if overlaps.Load() != 0 {
+		t.Fatalf("expected runs not to overlap, got %d overlaps", overlaps.Load())
+	}
+}
+
+func TestSchedulerCancelsRunsAndReleasesLockOnStop(t *testing.T) {
+	started := make(chan struct{})
+	var canceled atomic.Bool
+	locker := &fakeLocker{available: true}
+	s := NewScheduler(locker, []Job{{
+		Name:     "renew",
+		Schedule: Every(time.Millisecond),
+		Run: func(ctx context.Context) {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			<-ctx.Done()
+			canceled.Store(true)
+		},
+	}}, 0, 5*time.Millisecond)
+	stop := runScheduler(t, s)
+
+	<-started
+	stop()
+	if !canceled.Load() {
+		t.Fatal("expected the run under way to see the cancellation")
+	}
+	locker.mu.Lock()
+	defer locker.mu.Unlock()
+	if locker.held || locker.releases == 0 {
+		t.Fatalf("expected the lock to be released, held=%v releases=%d", locker.held, locker.releases)
+	}
+}
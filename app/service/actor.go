@@ -0,0 +1,27 @@
+package service
+
+import "context"
+
+const systemActor = "system"
+
+// Actor identifies who triggered a subscription change: the calling service, a
+// signed payment webhook or a background job.
+type Actor struct {
+	Name      string
+	RequestID string
+}
+
+type actorContextKey struct{}
+
+// WithActor returns a context whose subscription changes are attributed to actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorContextKey{}, actor)
+}
+
+func actorFromContext(ctx context.Context) Actor {
+	actor, _ := ctx.Value(actorContextKey{}).(Actor)
+	if actor.Name == "" {
+		actor.Name = systemActor
+	}
+	return actor
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+// planPeriodEnd returns the end of one billing period of planType starting at
+// start. Periods are counted in calendar units of loc, so the local time of day
+// survives DST changes. Month and year periods land on anchorDay of the target
+// month, clamped to its last day: a subscription billed on the 31st renews on
+// Feb 28 and on Mar 31 again. An anchorDay of 0 uses the day of start.
+func planPeriodEnd(start time.Time, planType *entity.PlanType, anchorDay int32, loc *time.Location) time.Time {
+	local := start.In(billingLocation(loc))
+	if anchorDay <= 0 {
+		anchorDay = int32(local.Day())
+	}
+	count := int(planType.IntervalCount)
+	switch planType.IntervalUnit {
+	case entity.PlanIntervalWeek:
+		return local.AddDate(0, 0, 7*count).UTC()
+	case entity.PlanIntervalMonth:
+		return addMonthsClamped(local, count, int(anchorDay)).UTC()
+	case entity.PlanIntervalYear:
+		return addMonthsClamped(local, 12*count, int(anchorDay)).UTC()
+	default:
+		return local.AddDate(0, 0, count).UTC()
+	}
+}
+
+// planPeriodLength is the length of the billing period of planType starting at
+// from. Prices are spread over it when prorating.
+func planPeriodLength(planType *entity.PlanType, from time.Time, loc *time.Location) time.Duration {
+	if planType.IntervalCount <= 0 {
+		return 0
+	}
+	return planPeriodEnd(from, planType, 0, loc).Sub(from)
+}
+
+// addDays adds calendar days in loc.
+func addDays(start time.Time, days int32, loc *time.Location) time.Time {
+	return start.In(billingLocation(loc)).AddDate(0, 0, int(days)).UTC()
+}
+
+// billingAnchorFor returns the anchor day for a period starting at start. The
+// current anchorDay is kept while start falls on it, or on the last day of a
+// month too short for it; otherwise, after a pause or a switch from a day or
+// week plan, periods are re-anchored on the day of start.
+func billingAnchorFor(start time.Time, anchorDay int32, loc *time.Location) int32 {
+	local := start.In(billingLocation(loc))
+	day := int32(local.Day())
+	if day == anchorDay {
+		return anchorDay
+	}
+	if last := int32(daysInMonth(local.Year(), local.Month())); day == last && anchorDay > last {
+		return anchorDay
+	}
+	return day
+}
+
+func addMonthsClamped(t time.Time, months, day int) time.Time {
+	year, month, _ := t.Date()
+	// Normalising through the first of the month keeps time.Date from rolling
+	// Jan 31 + 1 month over into March.
+	target := time.Date(year, month+time.Month(months), 1, 0, 0, 0, 0, t.Location())
+	if last := daysInMonth(target.Year(), target.Month()); day > last {
+		day = last
+	}
+	return time.Date(target.Year(), target.Month(), day, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func daysInMonth(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func billingLocation(loc *time.Location) *time.Location {
+	if loc == nil {
+		return time.UTC
+	}
+	return loc
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+)
+
+const (
+	cancelModePeriodEnd = "period_end"
+	cancelModeImmediate = "immediate"
+)
+
+type cancelSubscriptionRequest interface {
+	GetId() uint64
+	GetMode() string
+	GetReason() string
+	GetRefund() bool
+}
+
+type CancelResult struct {
+	Subscription *entity.Subscription
+	// RefundedAmountCents is what an immediate cancellation credited back for the
+	// unused part of the period. It is zero when no refund was asked for.
+	RefundedAmountCents int64
+}
+
+// CancelSubscription cancels a subscription and records when and why.
+//
+// Period-end cancellations turn auto-renew off and keep the subscription in use
+// until end_at, when the expiration job deactivates it; they can be undone with
+// UndoCancellation until then. Immediate cancellations deactivate the
+// subscription now and end its period; with refund set, the unused part of the
+// current plan price is credited through the payment provider. The cancellation
+// and a pending refund attempt are saved together before the provider is called,
+// so the credit is sent once, under that attempt's idempotency key, and a
+// repeated request finds the subscription cancelled rather than refunding again.
+// A failed credit is reported as ErrPaymentDeclined; the cancellation stands.
+func (s *SubscriptionService) CancelSubscription(ctx context.Context, req cancelSubscriptionRequest) (*CancelResult, error) {
+	subscription, err := s.subscriptionRepo.FindByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+
+	mode := req.GetMode()
+	if mode == "" {
+		mode = cancelModePeriodEnd
+	}
+	if mode != cancelModePeriodEnd && mode != cancelModeImmediate {
+		return nil, fmt.Errorf("%w: mode must be period_end or immediate", ErrInvalidRequest)
+	}
+	if req.GetRefund() && mode != cancelModeImmediate {
+		return nil, fmt.Errorf("%w: refund is only allowed for immediate cancellations", ErrInvalidRequest)
+	}
+
+	before := *subscription
+	result := &CancelResult{Subscription: subscription}
+	now := time.Now().UTC()
+	reason := eventReasonCancelled
+	var refund *entity.PaymentAttempt
+
+	if mode == cancelModeImmediate {
+		if err := transitionSubscriptionStatus(subscription, entity.SubscriptionStatusInactive); err != nil {
+			return nil, err
+		}
+		if req.GetRefund() && (before.Status == entity.SubscriptionStatusActive || before.Status == entity.SubscriptionStatusPaused) {
+			if refund, err = s.unusedPeriodRefund(ctx, &before, now); err != nil {
+				return nil, err
+			}
+		}
+		if subscription.EndAt != nil && subscription.EndAt.After(now) {
+			subscription.EndAt = &now
+		}
+		subscription.PausedAt = nil
+		subscription.ResumeAt = nil
+		reason = eventReasonCancelledImmediately
+	}
+
+	subscription.AutoRenew = false
+	subscription.RenewAt = nil
+	subscription.PendingPlanTypeID = nil
+	subscription.CanceledAt = &now
+	subscription.CancelReason = normalizeOptionalString(req.GetReason())
+	subscription.UpdatedAt = now
+
+	if err := s.saveCancellation(ctx, before, subscription, reason, refund); err != nil {
+		if errors.Is(err, repository.ErrSubscriptionNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+
+	if refund != nil {
+		if err := s.sendRefund(ctx, subscription, refund); err != nil {
+			return nil, err
+		}
+		result.RefundedAmountCents = -refund.AmountCents
+	}
+
+	return result, nil
+}
+
+// saveCancellation saves the cancelled subscription and, in the same
+// transaction, the pending refund attempt when there is one.
+func (s *SubscriptionService) saveCancellation(ctx context.Context, before entity.Subscription, subscription *entity.Subscription, reason string, refund *entity.PaymentAttempt) error {
+	if refund == nil {
+		return s.writer.update(ctx, before, subscription, reason)
+	}
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.writer.update(ctx, before, subscription, reason); err != nil {
+			return err
+		}
+		return s.createPaymentAttempt(ctx, refund)
+	})
+}
+
+// UndoCancellation restores auto-renew on a subscription cancelled at period end,
+// as long as its period has not ended yet. Paused subscriptions get their renewal
+// scheduled when they resume.
+func (s *SubscriptionService) UndoCancellation(ctx context.Context, id uint64) (*entity.Subscription, error) {
+	subscription, err := s.subscriptionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	if subscription.CanceledAt == nil {
+		return nil, fmt.Errorf("%w: subscription is not cancelled", ErrInvalidRequest)
+	}
+	if subscription.PlanTypeID == nil {
+		return nil, fmt.Errorf("%w: subscription has no plan to renew", ErrInvalidRequest)
+	}
+	now := time.Now().UTC()
+	if subscription.Status == entity.SubscriptionStatusInactive || subscription.EndAt == nil || !subscription.EndAt.After(now) {
+		return nil, fmt.Errorf("%w: the subscription period has already ended", ErrInvalidTransition)
+	}
+
+	before := *subscription
+	subscription.AutoRenew = true
+	if subscription.Status != entity.SubscriptionStatusPaused {
+		renewAt := subscription.EndAt.Add(-s.cfg.RenewBeforeEndMinutes)
+		subscription.RenewAt = &renewAt
+	}
+	subscription.CanceledAt = nil
+	subscription.CancelReason = nil
+	subscription.UpdatedAt = now
+
+	if err := s.writer.update(ctx, before, subscription, eventReasonCancellationUndone); err != nil {
+		if errors.Is(err, repository.ErrSubscriptionNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// unusedPeriodRefund returns the refund attempt crediting the part of the
+// current price of all seats that covers the time left until end_at, counted from
+// the pause for paused subscriptions, or nil when nothing is left to credit. Only
+// what was paid is credited: a coupon discount on the period is taken off.
+func (s *SubscriptionService) unusedPeriodRefund(ctx context.Context, subscription *entity.Subscription, now time.Time) (*entity.PaymentAttempt, error) {
+	planType, err := s.subscriptionPlanType(ctx, subscription)
+	if err != nil {
+		return nil, err
+	}
+	from := now
+	if subscription.Status == entity.SubscriptionStatusPaused && subscription.PausedAt != nil {
+		from = *subscription.PausedAt
+	}
+	share, err := s.periodPaidShare(ctx, subscription.ID)
+	if err != nil {
+		return nil, err
+	}
+	quantity := subscriptionQuantity(subscription)
+	amountCents := applyPaidShare(unusedPeriodAmount(planType, subscription.EndAt, from, s.cfg.BillingLocation)*int64(quantity), share)
+	if amountCents == 0 {
+		return nil, nil
+	}
+
+	attempt := newPaymentAttempt(subscription.ID, planType, quantity, entity.PaymentAttemptKindRefund, now)
+	attempt.AmountCents = -amountCents
+	return attempt, nil
+}
+
+// sendRefund credits the stored refund attempt through the payment provider.
+func (s *SubscriptionService) sendRefund(ctx context.Context, subscription *entity.Subscription, attempt *entity.PaymentAttempt) error {
+	payResult, err := s.processPaymentAttempt(ctx, attempt, func() payment.Result {
+		return s.paymentService.ProcessSubscriptionAdjustment(ctx, payment.Adjustment{
+			IdempotencyKey: paymentIdempotencyKey(attempt),
+			SubscriptionID: subscription.ID,
+			PlanTypeID:     attempt.PlanTypeID,
+			AmountCents:    attempt.AmountCents,
+			Currency:       attempt.Currency,
+			UserID:         subscription.UserID,
+			Email:          subscription.Email,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("%w: subscription was cancelled but the refund failed: %v", ErrPaymentDeclined, err)
+	}
+	if payResult.Type != payment.ResultTypeSuccess {
+		message := payResult.Error
+		if message == "" {
+			message = "refund failed"
+		}
+		return fmt.Errorf("%w: subscription was cancelled but the refund failed: %s", ErrPaymentDeclined, message)
+	}
+	return nil
+}
+
+// unusedPeriodAmount is the part of the plan price covering the time left until
+// periodEnd, never more than one full price.
+func unusedPeriodAmount(planType *entity.PlanType, periodEnd *time.Time, now time.Time, loc *time.Location) int64 {
+	if planType == nil || periodEnd == nil || !periodEnd.After(now) {
+		return 0
+	}
+	amountCents := proratePrice(planType, periodEnd.Sub(now), now, loc)
+	if amountCents > planType.PriceCents {
+		return planType.PriceCents
+	}
+	return amountCents
+}
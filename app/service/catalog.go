@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
+	"golang.org/x/text/currency"
+)
+
+const maxPlanFeatures = 64
+
+type catalogSubscriptionTypeRepository interface {
+	Create(ctx context.Context, subscriptionType *entity.SubscriptionType) error
+	Update(ctx context.Context, subscriptionType *entity.SubscriptionType) error
+	FindByID(ctx context.Context, id uint64) (*entity.SubscriptionType, error)
+}
+
+type catalogPlanTypeRepository interface {
+	Create(ctx context.Context, planType *entity.PlanType) error
+	Update(ctx context.Context, planType *entity.PlanType) error
+	FindByID(ctx context.Context, id uint64) (*entity.PlanType, error)
+}
+
+// CatalogService manages the subscription types, plans and coupons offered to
+// customers. It backs the admin API; archiving never touches existing
+// subscriptions.
+type CatalogService struct {
+	subscriptionTypeRepo catalogSubscriptionTypeRepository
+	planTypeRepo         catalogPlanTypeRepository
+	couponRepo           catalogCouponRepository
+	redemptionRepo       catalogCouponRedemptionRepository
+}
+
+func NewCatalogService(
+	subscriptionTypeRepo catalogSubscriptionTypeRepository,
+	planTypeRepo catalogPlanTypeRepository,
+	couponRepo catalogCouponRepository,
+	redemptionRepo catalogCouponRedemptionRepository,
+) *CatalogService {
+	return &CatalogService{
+		subscriptionTypeRepo: subscriptionTypeRepo,
+		planTypeRepo:         planTypeRepo,
+		couponRepo:           couponRepo,
+		redemptionRepo:       redemptionRepo,
+	}
+}
+
+func (s *CatalogService) CreateSubscriptionType(ctx context.Context, req *types.CreateSubscriptionTypeRequest) (*entity.SubscriptionType, error) {
+	kind := strings.TrimSpace(req.GetType())
+	if kind != entity.SubscriptionTypeEmail && kind != entity.SubscriptionTypePlan {
+		return nil, fmt.Errorf("%w: type must be %q or %q", ErrInvalidRequest, entity.SubscriptionTypeEmail, entity.SubscriptionTypePlan)
+	}
+
+	now := time.Now().UTC()
+	subscriptionType := &entity.SubscriptionType{
+		Type:        kind,
+		DisplayName: strings.TrimSpace(req.GetDisplayName()),
+		Status:      entity.SubscriptionTypeStatusActive,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.subscriptionTypeRepo.Create(ctx, subscriptionType); err != nil {
+		return nil, err
+	}
+
+	return subscriptionType, nil
+}
+
+func (s *CatalogService) UpdateSubscriptionType(ctx context.Context, req *types.UpdateSubscriptionTypeRequest) (*entity.SubscriptionType, error) {
+	if !req.GetHasDisplayName() && !req.GetHasStatus() {
+		return nil, ErrNoFieldsToUpdate
+	}
+	if req.GetHasStatus() && !isSubscriptionTypeStatusAllowed(req.GetStatus()) {
+		return nil, ErrInvalidStatus
+	}
+
+	subscriptionType, err := s.getSubscriptionType(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetHasDisplayName() {
+		subscriptionType.DisplayName = strings.TrimSpace(req.GetDisplayName())
+	}
+	if req.GetHasStatus() {
+		subscriptionType.Status = req.GetStatus()
+	}
+
+	return subscriptionType, s.updateSubscriptionType(ctx, subscriptionType)
+}
+
+// ArchiveSubscriptionType deactivates the type so no new subscriptions can be
+// created for it. Existing subscriptions keep renewing.
+func (s *CatalogService) ArchiveSubscriptionType(ctx context.Context, id uint64) (*entity.SubscriptionType, error) {
+	subscriptionType, err := s.getSubscriptionType(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscriptionType.Status == entity.SubscriptionTypeStatusInactive {
+		return subscriptionType, nil
+	}
+
+	subscriptionType.Status = entity.SubscriptionTypeStatusInactive
+	return subscriptionType, s.updateSubscriptionType(ctx, subscriptionType)
+}
+
+func (s *CatalogService) CreatePlanType(ctx context.Context, req *types.CreatePlanTypeRequest) (*entity.PlanType, error) {
+	subscriptionType, err := s.getSubscriptionType(ctx, req.GetSubscriptionTypeId())
+	if err != nil {
+		return nil, err
+	}
+	if subscriptionType.Type != entity.SubscriptionTypePlan {
+		return nil, fmt.Errorf("%w: plans can only be added to %q subscription types", ErrInvalidRequest, entity.SubscriptionTypePlan)
+	}
+
+	code, err := normalizeCurrency(req.GetCurrency())
+	if err != nil {
+		return nil, err
+	}
+	features, err := normalizeFeatures(req.GetFeatures())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	planType := &entity.PlanType{
+		SubscriptionTypeID: subscriptionType.ID,
+		PlanCode:           strings.TrimSpace(req.GetPlanCode()),
+		DisplayName:        strings.TrimSpace(req.GetDisplayName()),
+		Description:        strings.TrimSpace(req.GetDescription()),
+		PriceCents:         req.GetPriceCents(),
+		Currency:           code,
+		IntervalUnit:       req.GetIntervalUnit(),
+		IntervalCount:      req.GetIntervalCount(),
+		TrialDays:          req.GetTrialDays(),
+		Features:           features,
+		Status:             entity.PlanTypeStatusActive,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if err := s.planTypeRepo.Create(ctx, planType); err != nil {
+		if errors.Is(err, repository.ErrPlanCodeAlreadyExists) {
+			return nil, ErrPlanCodeAlreadyExists
+		}
+		return nil, err
+	}
+
+	return planType, nil
+}
+
+// UpdatePlanType changes a plan in place. Price and interval changes apply from
+// the next charge of every subscription on the plan; trial changes only affect
+// new subscribers.
+func (s *CatalogService) UpdatePlanType(ctx context.Context, req *types.UpdatePlanTypeRequest) (*entity.PlanType, error) {
+	if !req.GetHasDisplayName() && !req.GetHasDescription() && !req.GetHasPriceCents() &&
+		!req.GetHasCurrency() && !req.GetHasIntervalUnit() && !req.GetHasIntervalCount() && !req.GetHasTrialDays() && !req.GetHasFeatures() {
+		return nil, ErrNoFieldsToUpdate
+	}
+
+	planType, err := s.getPlanType(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetHasDisplayName() {
+		planType.DisplayName = strings.TrimSpace(req.GetDisplayName())
+	}
+	if req.GetHasDescription() {
+		planType.Description = strings.TrimSpace(req.GetDescription())
+	}
+	if req.GetHasPriceCents() {
+		planType.PriceCents = req.GetPriceCents()
+	}
+	if req.GetHasCurrency() {
+		if planType.Currency, err = normalizeCurrency(req.GetCurrency()); err != nil {
+			return nil, err
+		}
+	}
+	if req.GetHasIntervalUnit() {
+		planType.IntervalUnit = req.GetIntervalUnit()
+	}
+	if req.GetHasIntervalCount() {
+		planType.IntervalCount = req.GetIntervalCount()
+	}
+	if req.GetHasTrialDays() {
+		planType.TrialDays = req.GetTrialDays()
+	}
+	if req.GetHasFeatures() {
+		if planType.Features, err = normalizeFeatures(req.GetFeatures()); err != nil {
+			return nil, err
+		}
+	}
+
+	return planType, s.updatePlanType(ctx, planType)
+}
+
+// ArchivePlanType withdraws the plan from sale. Subscriptions already on the
+// plan keep renewing at its price.
+func (s *CatalogService) ArchivePlanType(ctx context.Context, id uint64) (*entity.PlanType, error) {
+	planType, err := s.getPlanType(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if planType.Status == entity.PlanTypeStatusArchived {
+		return planType, nil
+	}
+
+	planType.Status = entity.PlanTypeStatusArchived
+	return planType, s.updatePlanType(ctx, planType)
+}
+
+func (s *CatalogService) getSubscriptionType(ctx context.Context, id uint64) (*entity.SubscriptionType, error) {
+	subscriptionType, err := s.subscriptionTypeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscriptionType == nil {
+		return nil, ErrSubscriptionTypeNotFound
+	}
+	return subscriptionType, nil
+}
+
+func (s *CatalogService) updateSubscriptionType(ctx context.Context, subscriptionType *entity.SubscriptionType) error {
+	subscriptionType.UpdatedAt = time.Now().UTC()
+	if err := s.subscriptionTypeRepo.Update(ctx, subscriptionType); err != nil {
+		if errors.Is(err, repository.ErrSubscriptionTypeNotFound) {
+			return ErrSubscriptionTypeNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *CatalogService) getPlanType(ctx context.Context, id uint64) (*entity.PlanType, error) {
+	planType, err := s.planTypeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if planType == nil {
+		return nil, ErrPlanTypeNotFound
+	}
+	return planType, nil
+}
+
+func (s *CatalogService) updatePlanType(ctx context.Context, planType *entity.PlanType) error {
+	planType.UpdatedAt = time.Now().UTC()
+	if err := s.planTypeRepo.Update(ctx, planType); err != nil {
+		if errors.Is(err, repository.ErrPlanTypeNotFound) {
+			return ErrPlanTypeNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+func normalizeCurrency(raw string) (string, error) {
+	code := strings.ToUpper(strings.TrimSpace(raw))
+	unit, err := currency.ParseISO(code)
+	if err != nil || len(code) != 3 {
+		return "", fmt.Errorf("%w: currency must be an ISO 4217 code", ErrInvalidRequest)
+	}
+	return unit.String(), nil
+}
+
+// normalizeFeatures checks that features is a flat JSON object whose values are
+// strings, numbers or booleans, and returns it in compact form.
+func normalizeFeatures(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", nil
+	}
+
+	var features map[string]interface{}
+	if err := json.Unmarshal([]byte(raw), &features); err != nil || features == nil {
+		return "", fmt.Errorf("%w: features must be a JSON object", ErrInvalidRequest)
+	}
+	if len(features) > maxPlanFeatures {
+		return "", fmt.Errorf("%w: at most %d features are allowed", ErrInvalidRequest, maxPlanFeatures)
+	}
+	for key, value := range features {
+		if strings.TrimSpace(key) == "" {
+			return "", fmt.Errorf("%w: feature names must not be empty", ErrInvalidRequest)
+		}
+		switch value.(type) {
+		case string, float64, bool:
+		default:
+			return "", fmt.Errorf("%w: feature %q must be a string, number or boolean", ErrInvalidRequest, key)
+		}
+	}
+
+	normalized, err := json.Marshal(features)
+	if err != nil {
+		return "", err
+	}
+	return string(normalized), nil
+}
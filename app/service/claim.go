@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+)
+
+// errBatchSkipped marks a subscription that no longer needed the job by the time
+// the job got to it, because another writer changed it meanwhile.
+var errBatchSkipped = errors.New("subscription no longer qualifies")
+
+// BatchResult counts what one run of a batch job did.
+type BatchResult struct {
+	// Processed subscriptions were moved on by the job, declined renewals
+	// included.
+	Processed int
+	// Failed subscriptions could not be handled and are picked up again by a
+	// later run.
+	Failed int
+	// Skipped subscriptions no longer needed the job when it got to them.
+	Skipped int
+}
+
+func (r *BatchResult) record(err error) {
+	switch {
+	case err == nil:
+		r.Processed++
+	case errors.Is(err, errBatchSkipped):
+		r.Skipped++
+	default:
+		r.Failed++
+	}
+}
+
+// runClaimedBatch pages through the rows claimRows selects in id order, leasing
+// batchSize rows at a time and handing each to process on up to concurrency
+// goroutines, until no rows are left or BatchMaxPerRun rows were handled. Each
+// batch is released once it is done, so memory and leases stay bounded however
+// large the backlog is. Once ctx is canceled no further row is started.
+func (s *SubscriptionService) runClaimedBatch(
+	ctx context.Context,
+	batchSize int,
+	concurrency int,
+	claimRows func(ctx context.Context, claim repository.SubscriptionClaim) ([]*entity.Subscription, error),
+	process func(subscription *entity.Subscription) error,
+) (BatchResult, error) {
+	var result BatchResult
+	claim, err := s.newJobClaim(time.Now().UTC())
+	if err != nil {
+		return result, err
+	}
+
+	for remaining := s.cfg.BatchMaxPerRun; remaining > 0; {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		claim.Now = time.Now().UTC()
+		claim.Until = claim.Now.Add(s.cfg.ClaimTTL)
+		claim.Limit = min(batchSize, remaining)
+
+		items, err := claimRows(ctx, claim)
+		if err != nil {
+			s.releaseClaims(ctx, claim)
+			return result, err
+		}
+		processBatch(ctx, items, concurrency, process, &result)
+		s.releaseClaims(ctx, claim)
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		if len(items) == 0 || len(items) < claim.Limit {
+			break
+		}
+		claim.AfterID = items[len(items)-1].ID
+		remaining -= len(items)
+	}
+
+	return result, nil
+}
+
+// processBatch hands items to process on up to concurrency goroutines and
+// records the outcomes on result. It stops starting items once ctx is canceled
+// and returns when the ones under way are done.
+func processBatch(ctx context.Context, items []*entity.Subscription, concurrency int, process func(*entity.Subscription) error, result *BatchResult) {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, max(concurrency, 1))
+	for _, item := range items {
+		select {
+		case <-ctx.Done():
+		case slots <- struct{}{}:
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := process(item)
+			<-slots
+			mu.Lock()
+			result.record(err)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+}
+
+// newJobClaim starts the lease of one batch run. The owner is unique to the run,
+// so rows an earlier run of the same worker left leased are not taken for its own.
+func (s *SubscriptionService) newJobClaim(now time.Time) (repository.SubscriptionClaim, error) {
+	owner, err := newClaimOwner(s.cfg.WorkerID)
+	if err != nil {
+		return repository.SubscriptionClaim{}, err
+	}
+	return repository.SubscriptionClaim{
+		Owner: owner,
+		Now:   now,
+		Until: now.Add(s.cfg.ClaimTTL),
+		Limit: s.cfg.ClaimBatchSize,
+	}, nil
+}
+
+// releaseClaims frees the rows of a finished batch, also when the job was
+// canceled. Rows a failed release leaves behind wait for their lease to lapse.
+func (s *SubscriptionService) releaseClaims(ctx context.Context, claim repository.SubscriptionClaim) {
+	_ = s.subscriptionRepo.ReleaseClaims(context.WithoutCancel(ctx), claim.Owner)
+}
+
+// newClaimOwner names the leases of one run of workerID.
+func newClaimOwner(workerID string) (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return workerID + "/" + hex.EncodeToString(buf), nil
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+// maxConflictRetries bounds how often a batch job reloads a subscription that
+// another writer saved while the job was working on it.
+const maxConflictRetries = 3
+
+// checkExpectedVersion fails with ErrConcurrentModification when subscription is
+// no longer at the version the caller expects. Zero skips the check.
+func checkExpectedVersion(subscription *entity.Subscription, expected int64) error {
+	if expected != 0 && subscription.Version != expected {
+		return fmt.Errorf("%w: subscription is at version %d, not %d", ErrConcurrentModification, subscription.Version, expected)
+	}
+	return nil
+}
+
+// updateWithRetry applies mutate to subscription and saves it with the reason
+// mutate returns. When another writer saved the subscription first, it is
+// reloaded and mutate runs again on the fresh copy, so mutate checks whatever it
+// relies on rather than trusting the first read. It returns the saved copy.
+func (s *SubscriptionService) updateWithRetry(ctx context.Context, subscription *entity.Subscription, mutate func(*entity.Subscription) (string, error)) (*entity.Subscription, error) {
+	for attempt := 0; ; attempt++ {
+		before := *subscription
+		reason, err := mutate(subscription)
+		if err != nil {
+			return nil, err
+		}
+		err = s.writer.update(ctx, before, subscription, reason)
+		if err == nil {
+			return subscription, nil
+		}
+		if !errors.Is(err, ErrConcurrentModification) || attempt == maxConflictRetries {
+			return nil, err
+		}
+
+		if subscription, err = s.subscriptionRepo.FindByID(ctx, subscription.ID); err != nil {
+			return nil, err
+		}
+		if subscription == nil {
+			return nil, ErrSubscriptionNotFound
+		}
+	}
+}
@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
+)
+
+type catalogCouponRepository interface {
+	Create(ctx context.Context, coupon *entity.Coupon) error
+	Update(ctx context.Context, coupon *entity.Coupon) error
+	FindByID(ctx context.Context, id uint64) (*entity.Coupon, error)
+	List(ctx context.Context) ([]*entity.Coupon, error)
+}
+
+type catalogCouponRedemptionRepository interface {
+	ListByCouponID(ctx context.Context, couponID uint64) ([]*entity.CouponRedemption, error)
+}
+
+type couponRepository interface {
+	FindByID(ctx context.Context, id uint64) (*entity.Coupon, error)
+	FindByCode(ctx context.Context, code string) (*entity.Coupon, error)
+	IncrementRedemptions(ctx context.Context, id uint64, now time.Time) error
+	DecrementRedemptions(ctx context.Context, id uint64, now time.Time) error
+}
+
+type couponRedemptionRepository interface {
+	Create(ctx context.Context, redemption *entity.CouponRedemption) error
+	Update(ctx context.Context, redemption *entity.CouponRedemption) error
+	FindActiveBySubscriptionID(ctx context.Context, subscriptionID uint64) (*entity.CouponRedemption, error)
+	EndActive(ctx context.Context, subscriptionID uint64, now time.Time) error
+	Delete(ctx context.Context, id uint64) error
+}
+
+// CreateCoupon adds an active coupon. Plan restrictions must name existing
+// plans; the discount cannot be changed afterwards.
+func (s *CatalogService) CreateCoupon(ctx context.Context, req *types.CreateCouponRequest) (*entity.Coupon, error) {
+	var currencyCode string
+	if req.GetAmountOffCents() > 0 {
+		var err error
+		if currencyCode, err = normalizeCurrency(req.GetCurrency()); err != nil {
+			return nil, err
+		}
+	}
+
+	planTypeIDs := make([]uint64, 0, len(req.GetPlanTypeIds()))
+	seen := make(map[uint64]bool, len(req.GetPlanTypeIds()))
+	for _, id := range req.GetPlanTypeIds() {
+		if seen[id] {
+			continue
+		}
+		if _, err := s.getPlanType(ctx, id); err != nil {
+			return nil, err
+		}
+		seen[id] = true
+		planTypeIDs = append(planTypeIDs, id)
+	}
+
+	now := time.Now().UTC()
+	expiresAt, err := parseCouponExpiry(req.GetExpiresAt(), now)
+	if err != nil {
+		return nil, err
+	}
+
+	coupon := &entity.Coupon{
+		Code:              normalizeCouponCode(req.GetCode()),
+		Description:       strings.TrimSpace(req.GetDescription()),
+		PercentOff:        req.GetPercentOff(),
+		AmountOffCents:    req.GetAmountOffCents(),
+		Currency:          currencyCode,
+		Duration:          req.GetDuration(),
+		DurationInPeriods: req.GetDurationInPeriods(),
+		MaxRedemptions:    req.GetMaxRedemptions(),
+		PlanTypeIDs:       planTypeIDs,
+		ExpiresAt:         expiresAt,
+		Status:            entity.CouponStatusActive,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	if err := s.couponRepo.Create(ctx, coupon); err != nil {
+		if errors.Is(err, repository.ErrCouponCodeAlreadyExists) {
+			return nil, ErrCouponCodeAlreadyExists
+		}
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+func (s *CatalogService) GetCoupon(ctx context.Context, id uint64) (*entity.Coupon, error) {
+	coupon, err := s.couponRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if coupon == nil {
+		return nil, ErrCouponNotFound
+	}
+	return coupon, nil
+}
+
+func (s *CatalogService) ListCoupons(ctx context.Context) ([]*entity.Coupon, error) {
+	return s.couponRepo.List(ctx)
+}
+
+// UpdateCoupon changes the description, limits or status of a coupon. Lowering
+// max_redemptions or disabling the coupon only stops new redemptions; existing
+// ones keep their discount.
+func (s *CatalogService) UpdateCoupon(ctx context.Context, req *types.UpdateCouponRequest) (*entity.Coupon, error) {
+	if !req.GetHasDescription() && !req.GetHasMaxRedemptions() && !req.GetHasExpiresAt() && !req.GetHasStatus() {
+		return nil, ErrNoFieldsToUpdate
+	}
+	if req.GetHasStatus() && req.GetStatus() != entity.CouponStatusDisabled && req.GetStatus() != entity.CouponStatusActive {
+		return nil, ErrInvalidStatus
+	}
+
+	coupon, err := s.GetCoupon(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	if req.GetHasDescription() {
+		coupon.Description = strings.TrimSpace(req.GetDescription())
+	}
+	if req.GetHasMaxRedemptions() {
+		coupon.MaxRedemptions = req.GetMaxRedemptions()
+	}
+	if req.GetHasExpiresAt() {
+		if coupon.ExpiresAt, err = parseCouponExpiry(req.GetExpiresAt(), now); err != nil {
+			return nil, err
+		}
+	}
+	if req.GetHasStatus() {
+		coupon.Status = req.GetStatus()
+	}
+	coupon.UpdatedAt = now
+
+	if err := s.couponRepo.Update(ctx, coupon); err != nil {
+		if errors.Is(err, repository.ErrCouponNotFound) {
+			return nil, ErrCouponNotFound
+		}
+		return nil, err
+	}
+
+	return coupon, nil
+}
+
+func (s *CatalogService) ListCouponRedemptions(ctx context.Context, couponID uint64) ([]*entity.CouponRedemption, error) {
+	if _, err := s.GetCoupon(ctx, couponID); err != nil {
+		return nil, err
+	}
+	return s.redemptionRepo.ListByCouponID(ctx, couponID)
+}
+
+// redeemableCoupon looks up the coupon a new subscription on planType was given.
+// It returns nil when no code was given.
+func (s *SubscriptionService) redeemableCoupon(ctx context.Context, code string, planType *entity.PlanType, now time.Time) (*entity.Coupon, error) {
+	code = normalizeCouponCode(code)
+	if code == "" {
+		return nil, nil
+	}
+	if planType == nil {
+		return nil, fmt.Errorf("%w: coupons only apply to plan subscriptions", ErrInvalidRequest)
+	}
+
+	coupon, err := s.couponRepo.FindByCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+	if coupon == nil {
+		return nil, ErrCouponNotFound
+	}
+
+	switch {
+	case coupon.Status != entity.CouponStatusActive:
+		return nil, fmt.Errorf("%w: coupon is disabled", ErrCouponNotRedeemable)
+	case coupon.ExpiresAt != nil && !coupon.ExpiresAt.After(now):
+		return nil, fmt.Errorf("%w: coupon has expired", ErrCouponNotRedeemable)
+	case coupon.MaxRedemptions > 0 && coupon.TimesRedeemed >= coupon.MaxRedemptions:
+		return nil, fmt.Errorf("%w: coupon has reached its redemption limit", ErrCouponNotRedeemable)
+	case !coupon.AppliesTo(planType):
+		return nil, fmt.Errorf("%w: coupon does not apply to this plan", ErrCouponNotRedeemable)
+	}
+	return coupon, nil
+}
+
+// redeemCoupon records the redemption of coupon by the subscription and counts
+// it against the coupon's limit.
+func (s *SubscriptionService) redeemCoupon(ctx context.Context, subscriptionID uint64, coupon *entity.Coupon, now time.Time) error {
+	if err := s.couponRepo.IncrementRedemptions(ctx, coupon.ID, now); err != nil {
+		if errors.Is(err, repository.ErrCouponRedemptionsExceeded) {
+			return fmt.Errorf("%w: coupon has reached its redemption limit", ErrCouponNotRedeemable)
+		}
+		return err
+	}
+
+	redemption := &entity.CouponRedemption{
+		CouponID:        coupon.ID,
+		SubscriptionID:  subscriptionID,
+		DurationPeriods: coupon.Periods(),
+		Status:          entity.CouponRedemptionStatusActive,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if err := s.redemptionRepo.Create(ctx, redemption); err != nil {
+		if errors.Is(err, repository.ErrCouponAlreadyRedeemed) {
+			return fmt.Errorf("%w: coupon was already redeemed for this subscription", ErrCouponNotRedeemable)
+		}
+		return err
+	}
+	return nil
+}
+
+// releaseCoupon takes back the redemption of a sign-up whose initial charge did
+// not go through, so a declined payment does not use up one of the coupon's
+// redemptions and the customer can sign up with it again.
+func (s *SubscriptionService) releaseCoupon(ctx context.Context, subscriptionID uint64, now time.Time) error {
+	return s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		redemption, err := s.redemptionRepo.FindActiveBySubscriptionID(ctx, subscriptionID)
+		if err != nil || redemption == nil {
+			return err
+		}
+		if err := s.redemptionRepo.Delete(ctx, redemption.ID); err != nil {
+			return err
+		}
+		return s.couponRepo.DecrementRedemptions(ctx, redemption.CouponID, now)
+	})
+}
+
+// couponDiscountCents returns what the subscription's coupon takes off a charge
+// of priceCents for planType. A plan the coupon does not apply to, after a plan
+// change for instance, is charged in full without using up a discounted period.
+func (s *SubscriptionService) couponDiscountCents(ctx context.Context, subscriptionID uint64, planType *entity.PlanType, priceCents int64) (int64, error) {
+	redemption, err := s.redemptionRepo.FindActiveBySubscriptionID(ctx, subscriptionID)
+	if err != nil || redemption == nil || redemption.Exhausted() {
+		return 0, err
+	}
+	coupon, err := s.couponRepo.FindByID(ctx, redemption.CouponID)
+	if err != nil || coupon == nil || !coupon.AppliesTo(planType) {
+		return 0, err
+	}
+	return coupon.DiscountCents(priceCents), nil
+}
+
+// recordDiscountedPeriod counts a paid, discounted period against the active
+// redemption of the subscription and ends the redemption once its periods are
+// used up.
+func recordDiscountedPeriod(ctx context.Context, repo couponRedemptionRepository, subscriptionID uint64, now time.Time) error {
+	redemption, err := repo.FindActiveBySubscriptionID(ctx, subscriptionID)
+	if err != nil || redemption == nil {
+		return err
+	}
+	redemption.PeriodsApplied++
+	if redemption.Exhausted() {
+		redemption.Status = entity.CouponRedemptionStatusEnded
+	}
+	redemption.UpdatedAt = now
+	return repo.Update(ctx, redemption)
+}
+
+// periodPaidShare is the part of the list price the customer paid for the
+// current period: the last successful initial or renewal charge, net of its
+// coupon discount, over its full price. Refunds and prorations are scaled by it,
+// so a discounted period is never credited or billed at list price. It is 1 when
+// the subscription has no such charge.
+func (s *SubscriptionService) periodPaidShare(ctx context.Context, subscriptionID uint64) (float64, error) {
+	attempts, err := s.paymentAttemptRepo.ListBySubscriptionID(ctx, subscriptionID)
+	if err != nil {
+		return 0, err
+	}
+	for _, attempt := range attempts {
+		if attempt.Status != entity.PaymentAttemptStatusSucceeded ||
+			(attempt.Kind != entity.PaymentAttemptKindInitial && attempt.Kind != entity.PaymentAttemptKindRenewal) {
+			continue
+		}
+		listCents := attempt.AmountCents + attempt.DiscountCents
+		if listCents <= 0 {
+			return 1, nil
+		}
+		return float64(attempt.AmountCents) / float64(listCents), nil
+	}
+	return 1, nil
+}
+
+// applyPaidShare scales a list price amount by the share of it that was paid.
+func applyPaidShare(amountCents int64, share float64) int64 {
+	return int64(math.Round(float64(amountCents) * share))
+}
+
+func parseCouponExpiry(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	expiresAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: expires_at must be RFC3339", ErrInvalidRequest)
+	}
+	expiresAt = expiresAt.UTC()
+	if !expiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidRequest)
+	}
+	return &expiresAt, nil
+}
+
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+// scheduleDunningRetry counts a renewal charge that did not succeed and sets
+// renew_at to the matching retry of the dunning schedule. Retries are offsets
+// from end_at, so the grace window does not move while the subscription waits
+// for payment. renew_at is cleared once the schedule is exhausted.
+func scheduleDunningRetry(item *entity.Subscription, schedule []time.Duration) {
+	item.RenewalRetryCount++
+	if dunningExhausted(item, schedule) || item.EndAt == nil {
+		item.RenewAt = nil
+		return
+	}
+	renewAt := item.EndAt.Add(schedule[item.RenewalRetryCount-1])
+	item.RenewAt = &renewAt
+}
+
+// enterDunning moves a subscription whose renewal charge did not succeed to
+// past_due, where it keeps access until the retry at renew_at, or to inactive
+// once the dunning schedule is exhausted. It returns the reason to record.
+func enterDunning(item *entity.Subscription, schedule []time.Duration, reason string) (string, error) {
+	if !dunningExhausted(item, schedule) {
+		if err := transitionSubscriptionStatus(item, entity.SubscriptionStatusPastDue); err != nil {
+			return "", err
+		}
+		return reason, nil
+	}
+
+	if err := transitionSubscriptionStatus(item, entity.SubscriptionStatusInactive); err != nil {
+		return "", err
+	}
+	item.AutoRenew = false
+	item.RenewAt = nil
+	item.PendingPlanTypeID = nil
+	return eventReasonRenewalRetriesExhausted, nil
+}
+
+func dunningExhausted(item *entity.Subscription, schedule []time.Duration) bool {
+	return int(item.RenewalRetryCount) > len(schedule)
+}
@@ -5,9 +5,21 @@ import "errors"
 var (
 	ErrSubscriptionNotFound      = errors.New("subscription not found")
 	ErrSubscriptionTypeNotFound  = errors.New("subscription type not found")
+	ErrPlanTypeNotFound          = errors.New("plan type not found")
 	ErrSubscriptionAlreadyExists = errors.New("subscription already exists")
 	ErrInvalidRequest            = errors.New("invalid request")
 	ErrInvalidStatus             = errors.New("invalid status")
 	ErrStartAtRequired           = errors.New("start_at is required for plan subscriptions")
 	ErrNoFieldsToUpdate          = errors.New("no fields provided for update")
+	ErrPaymentAttemptNotFound    = errors.New("payment attempt not found")
+	ErrPaymentAttemptSuperseded  = errors.New("payment attempt was superseded by a newer attempt")
+	ErrInvalidTransition         = errors.New("invalid status transition")
+	ErrWebhookEndpointNotFound   = errors.New("webhook endpoint not found")
+	ErrInvalidPageToken          = errors.New("invalid page token")
+	ErrPlanCodeAlreadyExists     = errors.New("plan code already exists")
+	ErrPaymentDeclined           = errors.New("payment declined")
+	ErrCouponNotFound            = errors.New("coupon not found")
+	ErrCouponCodeAlreadyExists   = errors.New("coupon code already exists")
+	ErrCouponNotRedeemable       = errors.New("coupon cannot be redeemed")
+	ErrConcurrentModification    = errors.New("subscription was modified concurrently")
 )
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+)
+
+const (
+	eventReasonCreated                 = "subscription_created"
+	eventReasonUpdated                 = "subscription_updated"
+	eventReasonDeleted                 = "subscription_deleted"
+	eventReasonCancelled               = "subscription_cancelled"
+	eventReasonCancelledImmediately    = "subscription_cancelled_immediately"
+	eventReasonCancellationUndone      = "cancellation_undone"
+	eventReasonPaymentSucceeded        = "payment_succeeded"
+	eventReasonPaymentPending          = "payment_pending"
+	eventReasonPaymentFailed           = "payment_failed"
+	eventReasonPaymentCallbackSuccess  = "payment_callback_succeeded"
+	eventReasonPaymentCallbackFailed   = "payment_callback_failed"
+	eventReasonRenewalStarted          = entity.SubscriptionEventReasonRenewalStarted
+	eventReasonRenewalPlanMissing      = "renewal_plan_missing"
+	eventReasonRenewalRetriesExhausted = "renewal_retries_exhausted"
+	eventReasonPendingPaymentTimeout   = "pending_payment_timeout"
+	eventReasonExpired                 = "subscription_expired"
+	eventReasonPlanChanged             = "plan_changed"
+	eventReasonPlanChangeScheduled     = "plan_change_scheduled"
+	eventReasonPlanChangeCancelled     = "plan_change_cancelled"
+	eventReasonTrialStarted            = "trial_started"
+	eventReasonPaused                  = "subscription_paused"
+	eventReasonResumed                 = "subscription_resumed"
+	eventReasonQuantityChanged         = "quantity_changed"
+)
+
+var subscriptionEventFields = []string{
+	entity.SubscriptionEventFieldStatus,
+	entity.SubscriptionEventFieldPlanTypeID,
+	entity.SubscriptionEventFieldPendingPlanTypeID,
+	entity.SubscriptionEventFieldStartAt,
+	entity.SubscriptionEventFieldEndAt,
+	entity.SubscriptionEventFieldRenewAt,
+	entity.SubscriptionEventFieldAutoRenew,
+	entity.SubscriptionEventFieldCanceledAt,
+	entity.SubscriptionEventFieldQuantity,
+}
+
+type subscriptionEventRepository interface {
+	Create(ctx context.Context, event *entity.SubscriptionEvent) error
+	ListBySubscriptionID(ctx context.Context, subscriptionID uint64) ([]*entity.SubscriptionEvent, error)
+}
+
+type txManager interface {
+	WithinTx(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// subscriptionWriter persists subscriptions and records an event for every status,
+// period and auto-renew change, attributed to the actor found in the context.
+// Domain events for the change are queued in the outbox in the same transaction.
+type subscriptionWriter struct {
+	txManager        txManager
+	subscriptionRepo subscriptionRepository
+	eventRepo        subscriptionEventRepository
+	outboxRepo       outboxMessageRepository
+}
+
+func newSubscriptionWriter(
+	txManager txManager,
+	subscriptionRepo subscriptionRepository,
+	eventRepo subscriptionEventRepository,
+	outboxRepo outboxMessageRepository,
+) *subscriptionWriter {
+	return &subscriptionWriter{
+		txManager:        txManager,
+		subscriptionRepo: subscriptionRepo,
+		eventRepo:        eventRepo,
+		outboxRepo:       outboxRepo,
+	}
+}
+
+func (w *subscriptionWriter) create(ctx context.Context, subscription *entity.Subscription, reason string) error {
+	return w.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := w.subscriptionRepo.Create(ctx, subscription); err != nil {
+			return err
+		}
+		return w.record(ctx, nil, subscription, reason)
+	})
+}
+
+// update saves subscription, which must still be at the version it was read at,
+// and records the change. It fails with ErrConcurrentModification when another
+// writer saved the subscription in between.
+func (w *subscriptionWriter) update(ctx context.Context, before entity.Subscription, subscription *entity.Subscription, reason string) error {
+	err := w.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := w.subscriptionRepo.Update(ctx, subscription); err != nil {
+			return err
+		}
+		return w.record(ctx, &before, subscription, reason)
+	})
+	if errors.Is(err, repository.ErrConcurrentModification) {
+		return ErrConcurrentModification
+	}
+	return err
+}
+
+func (w *subscriptionWriter) record(ctx context.Context, before, after *entity.Subscription, reason string) error {
+	if err := w.enqueue(ctx, before, after, reason); err != nil {
+		return err
+	}
+
+	actor := actorFromContext(ctx)
+	var requestID *string
+	if actor.RequestID != "" {
+		requestID = &actor.RequestID
+	}
+
+	for _, event := range subscriptionChanges(before, after) {
+		event.Actor = actor.Name
+		event.RequestID = requestID
+		event.Reason = reason
+		event.CreatedAt = after.UpdatedAt
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now().UTC()
+		}
+		if err := w.eventRepo.Create(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscriptionChanges lists the audited fields that differ between before and after.
+// A nil before describes a newly created subscription.
+func subscriptionChanges(before, after *entity.Subscription) []*entity.SubscriptionEvent {
+	events := make([]*entity.SubscriptionEvent, 0)
+	for _, field := range subscriptionEventFields {
+		oldValue := subscriptionEventValue(before, field)
+		newValue := subscriptionEventValue(after, field)
+		if sameEventValue(oldValue, newValue) {
+			continue
+		}
+		events = append(events, &entity.SubscriptionEvent{
+			SubscriptionID: after.ID,
+			Field:          field,
+			OldValue:       oldValue,
+			NewValue:       newValue,
+		})
+	}
+	return events
+}
+
+func subscriptionEventValue(item *entity.Subscription, field string) *string {
+	if item == nil {
+		return nil
+	}
+
+	var value string
+	switch field {
+	case entity.SubscriptionEventFieldStatus:
+		value = subscriptionStatusName(item.Status)
+	case entity.SubscriptionEventFieldPlanTypeID:
+		if item.PlanTypeID == nil {
+			return nil
+		}
+		value = strconv.FormatUint(*item.PlanTypeID, 10)
+	case entity.SubscriptionEventFieldPendingPlanTypeID:
+		if item.PendingPlanTypeID == nil {
+			return nil
+		}
+		value = strconv.FormatUint(*item.PendingPlanTypeID, 10)
+	case entity.SubscriptionEventFieldStartAt:
+		return formatEventTime(item.StartAt)
+	case entity.SubscriptionEventFieldEndAt:
+		return formatEventTime(item.EndAt)
+	case entity.SubscriptionEventFieldRenewAt:
+		return formatEventTime(item.RenewAt)
+	case entity.SubscriptionEventFieldAutoRenew:
+		value = strconv.FormatBool(item.AutoRenew)
+	case entity.SubscriptionEventFieldCanceledAt:
+		return formatEventTime(item.CanceledAt)
+	case entity.SubscriptionEventFieldQuantity:
+		value = strconv.FormatInt(int64(subscriptionQuantity(item)), 10)
+	default:
+		return nil
+	}
+	return &value
+}
+
+func formatEventTime(v *time.Time) *string {
+	if v == nil {
+		return nil
+	}
+	value := v.UTC().Format(time.RFC3339)
+	return &value
+}
+
+func sameEventValue(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
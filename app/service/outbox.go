@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/mapper"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
+)
+
+type outboxMessageRepository interface {
+	Create(ctx context.Context, message *entity.OutboxMessage) error
+	Update(ctx context.Context, message *entity.OutboxMessage) error
+	ClaimDue(ctx context.Context, claim repository.OutboxClaim) ([]*entity.OutboxMessage, error)
+	ReleaseClaims(ctx context.Context, owner string) error
+}
+
+// outboxPayload is the data published with every subscription domain event.
+type outboxPayload struct {
+	Reason       string              `json:"reason"`
+	Subscription *types.Subscription `json:"subscription"`
+}
+
+// enqueue stores the domain events caused by the change from before to after.
+func (w *subscriptionWriter) enqueue(ctx context.Context, before, after *entity.Subscription, reason string) error {
+	eventTypes := subscriptionDomainEvents(before, after, reason)
+	if len(eventTypes) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(outboxPayload{Reason: reason, Subscription: mapper.SubscriptionToProto(after)})
+	if err != nil {
+		return err
+	}
+
+	now := after.UpdatedAt
+	if now.IsZero() {
+		now = time.Now().UTC()
+	}
+	for _, eventType := range eventTypes {
+		message := &entity.OutboxMessage{
+			SubscriptionID: after.ID,
+			EventType:      eventType,
+			Payload:        payload,
+			Status:         entity.OutboxMessageStatusPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+			UpdatedAt:      now,
+		}
+		if err := w.outboxRepo.Create(ctx, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// subscriptionDomainEvents lists the domain events downstream services are told
+// about for the change from before to after. A nil before describes a newly
+// created subscription.
+//
+//   - pausing publishes paused; becoming active again from paused publishes resumed
+//   - the first failed renewal charge moving the subscription to past_due publishes past_due
+//   - becoming active publishes renewed when the period was extended, activated
+//     otherwise or when the extended period was a trial
+//   - starting a trial publishes trial_started
+//   - becoming inactive publishes expired for the expiration job, deactivated otherwise
+//   - turning auto-renew off on a subscription that stays in use publishes cancelled
+//   - turning it back on for a cancelled subscription in use publishes uncancelled
+//   - an active subscription moving to another plan publishes plan_changed
+//   - an active or trialing subscription changing its seat quantity publishes
+//     quantity_changed
+func subscriptionDomainEvents(before, after *entity.Subscription, reason string) []string {
+	previousStatus := entity.SubscriptionStatusInactive
+	if before != nil {
+		previousStatus = before.Status
+	}
+
+	events := make([]string, 0, 1)
+	switch {
+	case after.Status == entity.SubscriptionStatusPaused && previousStatus != entity.SubscriptionStatusPaused:
+		events = append(events, entity.OutboxEventSubscriptionPaused)
+	case after.Status == entity.SubscriptionStatusActive && previousStatus == entity.SubscriptionStatusPaused:
+		events = append(events, entity.OutboxEventSubscriptionResumed)
+	case after.Status == entity.SubscriptionStatusPastDue && previousStatus != entity.SubscriptionStatusPastDue:
+		if after.RenewalRetryCount == 1 {
+			events = append(events, entity.OutboxEventSubscriptionPastDue)
+		}
+	case after.Status == entity.SubscriptionStatusActive && previousStatus != entity.SubscriptionStatusActive:
+		if before != nil && before.EndAt != nil && after.EndAt != nil && after.EndAt.After(*before.EndAt) && !endsTrial(before) {
+			events = append(events, entity.OutboxEventSubscriptionRenewed)
+		} else {
+			events = append(events, entity.OutboxEventSubscriptionActivated)
+		}
+	case after.Status == entity.SubscriptionStatusTrialing && previousStatus != entity.SubscriptionStatusTrialing:
+		events = append(events, entity.OutboxEventSubscriptionTrialStarted)
+	case after.Status == entity.SubscriptionStatusInactive && before != nil && previousStatus != entity.SubscriptionStatusInactive:
+		if reason == eventReasonExpired {
+			events = append(events, entity.OutboxEventSubscriptionExpired)
+		} else {
+			events = append(events, entity.OutboxEventSubscriptionDeactivated)
+		}
+	}
+
+	if before != nil && before.AutoRenew && !after.AutoRenew && after.Status != entity.SubscriptionStatusInactive {
+		events = append(events, entity.OutboxEventSubscriptionCancelled)
+	}
+	if before != nil && before.CanceledAt != nil && after.CanceledAt == nil && after.AutoRenew &&
+		after.Status == previousStatus && after.Status != entity.SubscriptionStatusInactive {
+		events = append(events, entity.OutboxEventSubscriptionUncancelled)
+	}
+	if before != nil && before.PlanTypeID != nil && after.PlanTypeID != nil && *before.PlanTypeID != *after.PlanTypeID &&
+		after.Status == entity.SubscriptionStatusActive {
+		events = append(events, entity.OutboxEventSubscriptionPlanChanged)
+	}
+	if before != nil && subscriptionQuantity(before) != subscriptionQuantity(after) && after.Status == previousStatus &&
+		(after.Status == entity.SubscriptionStatusActive || after.Status == entity.SubscriptionStatusTrialing) {
+		events = append(events, entity.OutboxEventSubscriptionQuantityChanged)
+	}
+	return events
+}
+
+// endsTrial reports whether the current period of item is its free trial.
+func endsTrial(item *entity.Subscription) bool {
+	return item.TrialEndAt != nil && item.EndAt != nil && item.EndAt.Equal(*item.TrialEndAt)
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// pageToken is the opaque continuation token handed to clients. It is bound to
+// the sort order it was issued for.
+type pageToken struct {
+	Sort  string    `json:"s"`
+	Value time.Time `json:"v,omitempty"`
+	ID    uint64    `json:"id"`
+}
+
+func encodePageToken(sort string, cursor repository.SubscriptionCursor) string {
+	payload, _ := json.Marshal(pageToken{Sort: sort, Value: cursor.Value, ID: cursor.ID})
+	return base64.RawURLEncoding.EncodeToString(payload)
+}
+
+func decodePageToken(raw, sort string) (*repository.SubscriptionCursor, error) {
+	payload, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidPageToken
+	}
+	var token pageToken
+	if err := json.Unmarshal(payload, &token); err != nil || token.ID == 0 || token.Sort != sort {
+		return nil, ErrInvalidPageToken
+	}
+	return &repository.SubscriptionCursor{Value: token.Value, ID: token.ID}, nil
+}
+
+func subscriptionFilterFromRequest(req listSubscriptionsRequest) (repository.SubscriptionFilter, error) {
+	filter := repository.SubscriptionFilter{
+		UserID:             strings.TrimSpace(req.GetUserId()),
+		Email:              strings.TrimSpace(req.GetEmail()),
+		SubscriptionTypeID: req.GetSubscriptionTypeId(),
+		Sort:               req.GetSort(),
+	}
+	switch filter.Sort {
+	case "":
+		filter.Sort = repository.SubscriptionSortIDDesc
+	case repository.SubscriptionSortIDDesc, repository.SubscriptionSortIDAsc,
+		repository.SubscriptionSortCreatedAtDesc, repository.SubscriptionSortCreatedAtAsc,
+		repository.SubscriptionSortEndAtDesc, repository.SubscriptionSortEndAtAsc:
+	default:
+		return filter, fmt.Errorf("%w: unsupported sort %q", ErrInvalidRequest, filter.Sort)
+	}
+
+	if req.GetHasStatus() {
+		status := req.GetStatus()
+		filter.Status = &status
+	}
+	if req.GetHasAutoRenew() {
+		autoRenew := req.GetAutoRenew()
+		filter.AutoRenew = &autoRenew
+	}
+
+	ranges := []struct {
+		name  string
+		raw   string
+		value **time.Time
+	}{
+		{"end_at_from", req.GetEndAtFrom(), &filter.EndAtFrom},
+		{"end_at_to", req.GetEndAtTo(), &filter.EndAtTo},
+		{"created_at_from", req.GetCreatedAtFrom(), &filter.CreatedAtFrom},
+		{"created_at_to", req.GetCreatedAtTo(), &filter.CreatedAtTo},
+	}
+	for _, item := range ranges {
+		if item.raw == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, item.raw)
+		if err != nil {
+			return filter, fmt.Errorf("%w: %s must be RFC3339", ErrInvalidRequest, item.name)
+		}
+		parsed = parsed.UTC()
+		*item.value = &parsed
+	}
+
+	if req.GetPageToken() != "" {
+		cursor, err := decodePageToken(req.GetPageToken(), filter.Sort)
+		if err != nil {
+			return filter, err
+		}
+		filter.After = cursor
+	}
+
+	return filter, nil
+}
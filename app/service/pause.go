@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+)
+
+type pauseSubscriptionRequest interface {
+	GetId() uint64
+	GetResumeAt() string
+}
+
+// PauseSubscription stops billing for an active plan subscription. The time left
+// until end_at is kept: it is added back when the subscription resumes, either
+// through ResumeSubscription or by the resume job at the optional resume_at.
+// Paused subscriptions are neither renewed nor expired.
+func (s *SubscriptionService) PauseSubscription(ctx context.Context, req pauseSubscriptionRequest) (*entity.Subscription, error) {
+	subscription, err := s.subscriptionRepo.FindByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	if subscription.PlanTypeID == nil || subscription.EndAt == nil {
+		return nil, fmt.Errorf("%w: only plan subscriptions can be paused", ErrInvalidRequest)
+	}
+
+	now := time.Now().UTC()
+	var resumeAt *time.Time
+	if value := req.GetResumeAt(); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: resume_at must be RFC3339", ErrInvalidRequest)
+		}
+		parsed = parsed.UTC()
+		if !parsed.After(now) {
+			return nil, fmt.Errorf("%w: resume_at must be in the future", ErrInvalidRequest)
+		}
+		resumeAt = &parsed
+	}
+
+	before := *subscription
+	if subscription.Status == entity.SubscriptionStatusActive && !subscription.EndAt.After(now) {
+		return nil, fmt.Errorf("%w: the subscription period has already ended", ErrInvalidTransition)
+	}
+	if err := transitionSubscriptionStatus(subscription, entity.SubscriptionStatusPaused); err != nil {
+		return nil, err
+	}
+	if before.Status != entity.SubscriptionStatusPaused {
+		subscription.PausedAt = &now
+	}
+	subscription.ResumeAt = resumeAt
+	subscription.RenewAt = nil
+	subscription.UpdatedAt = now
+
+	if err := s.writer.update(ctx, before, subscription, eventReasonPaused); err != nil {
+		if errors.Is(err, repository.ErrSubscriptionNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// ResumeSubscription reactivates a paused subscription and shifts end_at by the
+// time it spent paused.
+func (s *SubscriptionService) ResumeSubscription(ctx context.Context, id uint64) (*entity.Subscription, error) {
+	subscription, err := s.subscriptionRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	if subscription.Status != entity.SubscriptionStatusPaused {
+		return nil, fmt.Errorf("%w: only paused subscriptions can be resumed", ErrInvalidTransition)
+	}
+
+	before := *subscription
+	if err := s.resume(subscription, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+
+	if err := s.writer.update(ctx, before, subscription, eventReasonResumed); err != nil {
+		if errors.Is(err, repository.ErrSubscriptionNotFound) {
+			return nil, ErrSubscriptionNotFound
+		}
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// RunAutoResumeBatch resumes paused subscriptions whose resume_at has come.
+func (s *SubscriptionService) RunAutoResumeBatch(ctx context.Context) (BatchResult, error) {
+	now := time.Now().UTC()
+	return s.runClaimedBatch(ctx, s.cfg.ClaimBatchSize, 1,
+		func(ctx context.Context, claim repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+			return s.subscriptionRepo.ClaimDueResume(ctx, now, claim)
+		},
+		func(item *entity.Subscription) error {
+			_, err := s.updateWithRetry(ctx, item, func(item *entity.Subscription) (string, error) {
+				if item.Status != entity.SubscriptionStatusPaused || item.ResumeAt == nil || item.ResumeAt.After(now) {
+					return "", fmt.Errorf("%w: subscription is no longer due to resume", errBatchSkipped)
+				}
+				if err := s.resume(item, now); err != nil {
+					return "", err
+				}
+				return eventReasonResumed, nil
+			})
+			return err
+		},
+	)
+}
+
+// resume moves a paused subscription back to active, pushes end_at out by the
+// paused time and reschedules the renewal.
+func (s *SubscriptionService) resume(subscription *entity.Subscription, now time.Time) error {
+	if err := transitionSubscriptionStatus(subscription, entity.SubscriptionStatusActive); err != nil {
+		return err
+	}
+	if subscription.PausedAt != nil && subscription.EndAt != nil && now.After(*subscription.PausedAt) {
+		endAt := subscription.EndAt.Add(now.Sub(*subscription.PausedAt))
+		subscription.EndAt = &endAt
+	}
+	if subscription.AutoRenew && subscription.EndAt != nil {
+		renewAt := subscription.EndAt.Add(-s.cfg.RenewBeforeEndMinutes)
+		subscription.RenewAt = &renewAt
+	} else {
+		subscription.RenewAt = nil
+	}
+	subscription.PausedAt = nil
+	subscription.ResumeAt = nil
+	subscription.UpdatedAt = now
+	return nil
+}
@@ -4,55 +4,311 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
 	"github.com/vibast-solutions/ms-go-subscriptions/config"
 )
 
+type PaymentCallbackResult struct {
+	Subscription     *entity.Subscription
+	AlreadyProcessed bool
+}
+
 type PaymentCallbackService struct {
-	subscriptionRepo subscriptionRepository
-	cfg              config.SubscriptionConfig
+	subscriptionRepo   subscriptionRepository
+	planTypeRepo       planTypeRepository
+	paymentAttemptRepo paymentAttemptRepository
+	redemptionRepo     couponRedemptionRepository
+	txManager          txManager
+	writer             *subscriptionWriter
+	cfg                config.SubscriptionConfig
 }
 
-func NewPaymentCallbackService(subscriptionRepo subscriptionRepository, cfg config.SubscriptionConfig) *PaymentCallbackService {
+func NewPaymentCallbackService(
+	subscriptionRepo subscriptionRepository,
+	planTypeRepo planTypeRepository,
+	paymentAttemptRepo paymentAttemptRepository,
+	eventRepo subscriptionEventRepository,
+	outboxRepo outboxMessageRepository,
+	redemptionRepo couponRedemptionRepository,
+	txManager txManager,
+	cfg config.SubscriptionConfig,
+) *PaymentCallbackService {
 	return &PaymentCallbackService{
-		subscriptionRepo: subscriptionRepo,
-		cfg:              cfg,
+		subscriptionRepo:   subscriptionRepo,
+		planTypeRepo:       planTypeRepo,
+		paymentAttemptRepo: paymentAttemptRepo,
+		redemptionRepo:     redemptionRepo,
+		txManager:          txManager,
+		writer:             newSubscriptionWriter(txManager, subscriptionRepo, eventRepo, outboxRepo),
+		cfg:                cfg,
 	}
 }
 
-func (s *PaymentCallbackService) PaymentCallback(ctx context.Context, req *types.PaymentCallbackRequest) error {
+// PaymentCallback applies a provider callback to the attempt it reports on. Each
+// attempt is completed at most once: repeated or conflicting deliveries for a
+// completed attempt are acknowledged as already processed without touching the
+// subscription, and callbacks for superseded attempts are rejected. A successful
+// renewal attempt extends the subscription by one period of the charged plan. Plan
+// and quantity change attempts only switch the plan or the seat quantity on
+// success and leave the subscription untouched on failure; refund attempts never
+// change the subscription.
+func (s *PaymentCallbackService) PaymentCallback(ctx context.Context, req *types.PaymentCallbackRequest) (*PaymentCallbackResult, error) {
+	transactionID := strings.TrimSpace(req.GetTransactionId())
+	if transactionID == "" {
+		return nil, fmt.Errorf("%w: transaction_id is required", ErrInvalidRequest)
+	}
+
 	subscription, err := s.subscriptionRepo.FindByID(ctx, req.GetSubscriptionId())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if subscription == nil {
-		return ErrSubscriptionNotFound
+		return nil, ErrSubscriptionNotFound
+	}
+
+	attempt, err := s.paymentAttemptRepo.FindByTransactionID(ctx, transactionID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt == nil {
+		return nil, ErrPaymentAttemptNotFound
+	}
+	if attempt.SubscriptionID != subscription.ID {
+		return nil, fmt.Errorf("%w: transaction belongs to another subscription", ErrInvalidRequest)
+	}
+
+	switch attempt.Status {
+	case entity.PaymentAttemptStatusPending:
+	case entity.PaymentAttemptStatusSuperseded:
+		return nil, ErrPaymentAttemptSuperseded
+	default:
+		return &PaymentCallbackResult{Subscription: subscription, AlreadyProcessed: true}, nil
 	}
 
 	now := time.Now().UTC()
-	switch strings.ToLower(strings.TrimSpace(req.GetStatus())) {
-	case "success":
-		subscription.Status = entity.SubscriptionStatusActive
-	case "failed":
-		subscription.Status = entity.SubscriptionStatusProcessing
+	before := *subscription
+	var reason string
+	switch status := strings.ToLower(strings.TrimSpace(req.GetStatus())); {
+	case attempt.Kind == entity.PaymentAttemptKindPlanChange && status == "success":
+		reason = eventReasonPlanChanged
+		applyPlanChange(subscription, attempt.PlanTypeID)
+		attempt.Status = entity.PaymentAttemptStatusSucceeded
+	case attempt.Kind == entity.PaymentAttemptKindQuantityChange && status == "success":
+		reason = eventReasonQuantityChanged
+		subscription.Quantity = attempt.Quantity
+		attempt.Status = entity.PaymentAttemptStatusSucceeded
+	case (attempt.Kind == entity.PaymentAttemptKindRefund || attempt.Kind == entity.PaymentAttemptKindReversal) && status == "success":
+		reason = eventReasonPaymentCallbackSuccess
+		attempt.Status = entity.PaymentAttemptStatusSucceeded
+	case (attempt.Kind == entity.PaymentAttemptKindPlanChange || attempt.Kind == entity.PaymentAttemptKindQuantityChange ||
+		attempt.Kind == entity.PaymentAttemptKindRefund || attempt.Kind == entity.PaymentAttemptKindReversal) && status == "failed":
+		reason = eventReasonPaymentCallbackFailed
+		attempt.Status = entity.PaymentAttemptStatusFailed
+		attempt.Error = "payment failed"
+	case status == "success":
+		reason = eventReasonPaymentCallbackSuccess
+		if attempt.Kind == entity.PaymentAttemptKindRenewal {
+			planType, err := s.planTypeRepo.FindByID(ctx, attempt.PlanTypeID)
+			if err != nil {
+				return nil, err
+			}
+			if planType == nil {
+				return nil, fmt.Errorf("plan type %d not found for payment attempt %d", attempt.PlanTypeID, attempt.ID)
+			}
+			applyPlanChange(subscription, planType.ID)
+			extendSubscriptionPeriod(subscription, planType, now, s.cfg)
+			subscription.RenewalRetryCount = 0
+		}
+		if err := transitionSubscriptionStatus(subscription, entity.SubscriptionStatusActive); err != nil {
+			return nil, err
+		}
+		attempt.Status = entity.PaymentAttemptStatusSucceeded
+	case attempt.Kind == entity.PaymentAttemptKindRenewal && status == "failed":
+		// The retry was counted and scheduled when the checkout started.
+		if reason, err = enterDunning(subscription, s.cfg.DunningSchedule, eventReasonPaymentCallbackFailed); err != nil {
+			return nil, err
+		}
+		attempt.Status = entity.PaymentAttemptStatusFailed
+		attempt.Error = "payment failed"
+	case status == "failed":
+		reason = eventReasonPaymentCallbackFailed
+		if err := transitionSubscriptionStatus(subscription, entity.SubscriptionStatusProcessing); err != nil {
+			return nil, err
+		}
 		renewAt := now.Add(s.cfg.RenewalRetryIntervalMinutes)
 		subscription.RenewAt = &renewAt
+		attempt.Status = entity.PaymentAttemptStatusFailed
+		attempt.Error = "payment failed"
 	default:
-		return fmt.Errorf("%w: invalid callback status", ErrInvalidRequest)
+		return nil, fmt.Errorf("%w: invalid callback status", ErrInvalidRequest)
 	}
 	subscription.UpdatedAt = now
+	attempt.CompletedAt = &now
+	attempt.UpdatedAt = now
 
-	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+	// The attempt is completed together with the subscription change, so a
+	// callback whose change cannot be saved leaves the attempt pending and the
+	// provider's retry is applied.
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.paymentAttemptRepo.CompletePending(ctx, attempt); err != nil {
+			return err
+		}
+		if err := s.writer.update(ctx, before, subscription, reason); err != nil {
+			return err
+		}
+		if attempt.DiscountCents > 0 && attempt.Status == entity.PaymentAttemptStatusSucceeded {
+			return recordDiscountedPeriod(ctx, s.redemptionRepo, subscription.ID, now)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrPaymentAttemptNotPending) {
+			return s.alreadyProcessed(ctx, req.GetSubscriptionId())
+		}
 		if errors.Is(err, repository.ErrSubscriptionNotFound) {
-			return ErrSubscriptionNotFound
+			return nil, ErrSubscriptionNotFound
 		}
-		return err
+		return nil, err
 	}
+	return &PaymentCallbackResult{Subscription: subscription}, nil
+}
+
+func (s *PaymentCallbackService) alreadyProcessed(ctx context.Context, subscriptionID uint64) (*PaymentCallbackResult, error) {
+	subscription, err := s.subscriptionRepo.FindByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	return &PaymentCallbackResult{Subscription: subscription, AlreadyProcessed: true}, nil
+}
 
+func newPaymentAttempt(subscriptionID uint64, planType *entity.PlanType, quantity int32, kind string, now time.Time) *entity.PaymentAttempt {
+	return &entity.PaymentAttempt{
+		SubscriptionID: subscriptionID,
+		PlanTypeID:     planType.ID,
+		Kind:           kind,
+		AmountCents:    planType.PriceCents * int64(quantity),
+		Currency:       planType.Currency,
+		Quantity:       quantity,
+		Status:         entity.PaymentAttemptStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// openAdjustmentAttempt returns the pending attempt of kind that an earlier
+// request left for the same plan and quantity, or nil. Retrying a plan or
+// quantity change sends that attempt again, under the same idempotency key, so
+// the provider bills the adjustment once however often the request is repeated.
+// Attempts made before the subscription was last saved were priced on an older
+// state and are not reused.
+func (s *SubscriptionService) openAdjustmentAttempt(ctx context.Context, subscription *entity.Subscription, kind string, planTypeID uint64, quantity int32) (*entity.PaymentAttempt, error) {
+	attempts, err := s.paymentAttemptRepo.ListBySubscriptionID(ctx, subscription.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, attempt := range attempts {
+		if attempt.Kind != kind || attempt.Status != entity.PaymentAttemptStatusPending {
+			continue
+		}
+		// Older pending attempts of the kind were superseded by this one.
+		if attempt.PlanTypeID != planTypeID || attempt.Quantity != quantity || attempt.CreatedAt.Before(subscription.UpdatedAt) {
+			return nil, nil
+		}
+		return attempt, nil
+	}
+	return nil, nil
+}
+
+// reverseAdjustment gives back adjustment, which the provider accepted, when the
+// subscription change it paid for could not be saved. Leaving it open for a
+// retry is not enough: once another write moves the subscription on, the retry
+// prices a fresh adjustment and the first one would be billed for nothing. The
+// reversal is stored as an attempt of its own before the provider is called.
+func (s *SubscriptionService) reverseAdjustment(ctx context.Context, subscription *entity.Subscription, adjustment *entity.PaymentAttempt) error {
+	ctx = context.WithoutCancel(ctx)
+	now := time.Now().UTC()
+	reversal := &entity.PaymentAttempt{
+		SubscriptionID: adjustment.SubscriptionID,
+		PlanTypeID:     adjustment.PlanTypeID,
+		Kind:           entity.PaymentAttemptKindReversal,
+		AmountCents:    -adjustment.AmountCents,
+		Currency:       adjustment.Currency,
+		Quantity:       adjustment.Quantity,
+		Status:         entity.PaymentAttemptStatusPending,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	payResult, err := s.processPaymentAttempt(ctx, reversal, func() payment.Result {
+		return s.paymentService.ProcessSubscriptionAdjustment(ctx, payment.Adjustment{
+			IdempotencyKey: paymentIdempotencyKey(reversal),
+			SubscriptionID: subscription.ID,
+			PlanTypeID:     reversal.PlanTypeID,
+			AmountCents:    reversal.AmountCents,
+			Currency:       reversal.Currency,
+			UserID:         subscription.UserID,
+			Email:          subscription.Email,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("reversing payment attempt %d failed: %w", adjustment.ID, err)
+	}
+	if payResult.Type != payment.ResultTypeSuccess {
+		message := payResult.Error
+		if message == "" {
+			message = "reversal failed"
+		}
+		return fmt.Errorf("reversing payment attempt %d failed: %s", adjustment.ID, message)
+	}
 	return nil
 }
+
+// paymentIdempotencyKey is the key attempt is sent to the provider under. It is
+// derived from the stored attempt, so sending the same attempt again after a
+// timeout or a crash does not bill the customer twice.
+func paymentIdempotencyKey(attempt *entity.PaymentAttempt) string {
+	return "attempt-" + strconv.FormatUint(attempt.ID, 10)
+}
+
+// completePaymentAttempt copies the provider answer onto the attempt. Redirects stay
+// pending until the provider reports the outcome through the payment callback.
+func completePaymentAttempt(attempt *entity.PaymentAttempt, result payment.Result, payErr error, now time.Time) {
+	attempt.UpdatedAt = now
+	if result.TransactionID != "" {
+		transactionID := result.TransactionID
+		attempt.ProviderTransactionID = &transactionID
+	}
+
+	if payErr != nil {
+		attempt.ResultType = string(payment.ResultTypeFailure)
+		attempt.Status = entity.PaymentAttemptStatusFailed
+		attempt.Error = payErr.Error()
+		attempt.CompletedAt = &now
+		return
+	}
+
+	attempt.ResultType = string(result.Type)
+	switch result.Type {
+	case payment.ResultTypeSuccess:
+		attempt.Status = entity.PaymentAttemptStatusSucceeded
+		attempt.CompletedAt = &now
+	case payment.ResultTypeRedirect:
+		attempt.Status = entity.PaymentAttemptStatusPending
+	default:
+		attempt.Status = entity.PaymentAttemptStatusFailed
+		attempt.Error = result.Error
+		if attempt.Error == "" {
+			attempt.Error = "payment failed"
+		}
+		attempt.CompletedAt = &now
+	}
+}
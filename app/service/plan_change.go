@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+)
+
+const (
+	planChangeTimingImmediate = "immediate"
+	planChangeTimingPeriodEnd = "period_end"
+)
+
+type changePlanRequest interface {
+	GetId() uint64
+	GetPlanTypeId() uint64
+	GetTiming() string
+}
+
+type ChangePlanResult struct {
+	Subscription *entity.Subscription
+	// ProratedAmountCents is what an immediate change charged (positive) or
+	// credited (negative). It is zero for period-end changes.
+	ProratedAmountCents int64
+	// PaymentURL is set when the provider needs the customer to confirm the
+	// charge; the plan switches once the payment callback reports success.
+	PaymentURL string
+}
+
+// ChangePlan moves an active subscription to another active plan of the same
+// subscription type.
+//
+// Immediate changes keep the current period end and bill the difference between
+// both plans for the time left in it, for every seat and with the period's coupon
+// discount applied to both plans: upgrades are charged and downgrades are
+// credited through the payment provider, and the plan switches once the provider
+// confirms. The adjustment is stored as a payment attempt before the provider is
+// called, and an attempt an earlier request left pending is sent again rather
+// than billed twice. When the plan switch cannot be saved after the provider
+// accepted the adjustment, the adjustment is reversed. Period-end changes are stored on the subscription and applied by the
+// next renewal. Asking for the current plan drops a pending change, and so does
+// turning auto-renew off or deactivating the subscription.
+func (s *SubscriptionService) ChangePlan(ctx context.Context, req changePlanRequest) (*ChangePlanResult, error) {
+	subscription, err := s.subscriptionRepo.FindByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	if subscription.PlanTypeID == nil {
+		return nil, fmt.Errorf("%w: subscription has no plan", ErrInvalidRequest)
+	}
+	if subscription.Status != entity.SubscriptionStatusActive {
+		return nil, fmt.Errorf("%w: only active subscriptions can change plan", ErrInvalidTransition)
+	}
+
+	timing := req.GetTiming()
+	if timing == "" {
+		timing = planChangeTimingImmediate
+	}
+	if timing != planChangeTimingImmediate && timing != planChangeTimingPeriodEnd {
+		return nil, fmt.Errorf("%w: timing must be immediate or period_end", ErrInvalidRequest)
+	}
+
+	before := *subscription
+	result := &ChangePlanResult{Subscription: subscription}
+	if req.GetPlanTypeId() == *subscription.PlanTypeID {
+		if subscription.PendingPlanTypeID == nil {
+			return result, nil
+		}
+		subscription.PendingPlanTypeID = nil
+		subscription.UpdatedAt = time.Now().UTC()
+		return result, s.savePlanChange(ctx, before, subscription, eventReasonPlanChangeCancelled)
+	}
+
+	target, err := s.planTypeRepo.FindByID(ctx, req.GetPlanTypeId())
+	if err != nil {
+		return nil, err
+	}
+	if target == nil || target.Status != entity.PlanTypeStatusActive || target.SubscriptionTypeID != subscription.SubscriptionTypeID {
+		return nil, ErrPlanTypeNotFound
+	}
+
+	if timing == planChangeTimingPeriodEnd {
+		if !subscription.AutoRenew {
+			return nil, fmt.Errorf("%w: period_end plan changes need auto_renew", ErrInvalidRequest)
+		}
+		subscription.PendingPlanTypeID = &target.ID
+		subscription.UpdatedAt = time.Now().UTC()
+		return result, s.savePlanChange(ctx, before, subscription, eventReasonPlanChangeScheduled)
+	}
+
+	current, err := s.planTypeRepo.FindByID(ctx, *subscription.PlanTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, fmt.Errorf("plan type %d not found for subscription %d", *subscription.PlanTypeID, subscription.ID)
+	}
+	if current.Currency != target.Currency {
+		return nil, fmt.Errorf("%w: plans must use the same currency to change immediately", ErrInvalidRequest)
+	}
+
+	share, err := s.periodPaidShare(ctx, subscription.ID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+	result.ProratedAmountCents = applyPaidShare(proratedPlanChangeAmount(current, target, subscription.EndAt, now, s.cfg.BillingLocation)*int64(subscriptionQuantity(subscription)), share)
+	var attempt *entity.PaymentAttempt
+	if result.ProratedAmountCents != 0 {
+		attempt, err = s.openAdjustmentAttempt(ctx, subscription, entity.PaymentAttemptKindPlanChange, target.ID, subscriptionQuantity(subscription))
+		if err != nil {
+			return nil, err
+		}
+		if attempt == nil {
+			attempt = newPaymentAttempt(subscription.ID, target, subscriptionQuantity(subscription), entity.PaymentAttemptKindPlanChange, now)
+			attempt.AmountCents = result.ProratedAmountCents
+		}
+		result.ProratedAmountCents = attempt.AmountCents
+
+		payResult, err := s.chargePlanChange(ctx, subscription, attempt)
+		if err != nil {
+			return nil, err
+		}
+		switch payResult.Type {
+		case payment.ResultTypeSuccess:
+		case payment.ResultTypeRedirect:
+			result.PaymentURL = payResult.PaymentURL
+			return result, nil
+		default:
+			message := payResult.Error
+			if message == "" {
+				message = "payment failed"
+			}
+			return nil, fmt.Errorf("%w: %s", ErrPaymentDeclined, message)
+		}
+	}
+
+	saved, err := s.updateWithRetry(ctx, subscription, func(item *entity.Subscription) (string, error) {
+		if item.Status != entity.SubscriptionStatusActive || item.PlanTypeID == nil || *item.PlanTypeID != current.ID {
+			return "", fmt.Errorf("%w: subscription changed while its plan change was billed", ErrConcurrentModification)
+		}
+		applyPlanChange(item, target.ID)
+		item.UpdatedAt = time.Now().UTC()
+		return eventReasonPlanChanged, nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrSubscriptionNotFound) {
+			err = ErrSubscriptionNotFound
+		}
+		if attempt != nil {
+			if reverseErr := s.reverseAdjustment(ctx, subscription, attempt); reverseErr != nil {
+				return nil, fmt.Errorf("%w; %v", err, reverseErr)
+			}
+		}
+		return nil, err
+	}
+	result.Subscription = saved
+	return result, nil
+}
+
+func (s *SubscriptionService) savePlanChange(ctx context.Context, before entity.Subscription, subscription *entity.Subscription, reason string) error {
+	if err := s.writer.update(ctx, before, subscription, reason); err != nil {
+		if errors.Is(err, repository.ErrSubscriptionNotFound) {
+			return ErrSubscriptionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// chargePlanChange bills the prorated difference of an immediate plan change,
+// recorded in the payment ledger as attempt against the target plan.
+func (s *SubscriptionService) chargePlanChange(ctx context.Context, subscription *entity.Subscription, attempt *entity.PaymentAttempt) (payment.Result, error) {
+	return s.processPaymentAttempt(ctx, attempt, func() payment.Result {
+		return s.paymentService.ProcessSubscriptionAdjustment(ctx, payment.Adjustment{
+			IdempotencyKey: paymentIdempotencyKey(attempt),
+			SubscriptionID: subscription.ID,
+			PlanTypeID:     attempt.PlanTypeID,
+			AmountCents:    attempt.AmountCents,
+			Currency:       attempt.Currency,
+			UserID:         subscription.UserID,
+			Email:          subscription.Email,
+		})
+	})
+}
+
+// renewalPlanType returns the plan the next renewal is billed on: the pending
+// plan when a period-end change is scheduled, the current plan otherwise.
+func (s *SubscriptionService) renewalPlanType(ctx context.Context, subscription *entity.Subscription) (*entity.PlanType, error) {
+	if subscription.PendingPlanTypeID != nil {
+		planType, err := s.planTypeRepo.FindByID(ctx, *subscription.PendingPlanTypeID)
+		if err != nil || planType != nil {
+			return planType, err
+		}
+	}
+	return s.subscriptionPlanType(ctx, subscription)
+}
+
+// applyPlanChange switches the subscription to planTypeID and settles any
+// pending change.
+func applyPlanChange(subscription *entity.Subscription, planTypeID uint64) {
+	subscription.PlanTypeID = &planTypeID
+	subscription.PendingPlanTypeID = nil
+}
+
+// proratedPlanChangeAmount is what switching from current to target costs for the
+// time left until periodEnd: the target price for that time minus the unused part
+// of the current price. Each price is spread over one period of its own plan
+// starting now, so plans of different lengths are compared by their daily rate.
+func proratedPlanChangeAmount(current, target *entity.PlanType, periodEnd *time.Time, now time.Time, loc *time.Location) int64 {
+	if periodEnd == nil || !periodEnd.After(now) {
+		return 0
+	}
+	remaining := periodEnd.Sub(now)
+	return proratePrice(target, remaining, now, loc) - proratePrice(current, remaining, now, loc)
+}
+
+func proratePrice(planType *entity.PlanType, remaining time.Duration, now time.Time, loc *time.Location) int64 {
+	period := planPeriodLength(planType, now, loc)
+	if period <= 0 {
+		return 0
+	}
+	return int64(math.Round(float64(planType.PriceCents) * float64(remaining) / float64(period)))
+}
@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+)
+
+const (
+	quantityProrationProrate = "prorate"
+	quantityProrationNone    = "none"
+)
+
+type updateQuantityRequest interface {
+	GetId() uint64
+	GetQuantity() int32
+	GetProration() string
+}
+
+type UpdateQuantityResult struct {
+	Subscription *entity.Subscription
+	// ProratedAmountCents is what a prorated change charged (positive) or
+	// credited (negative). It is zero without proration and during a trial.
+	ProratedAmountCents int64
+	// PaymentURL is set when the provider needs the customer to confirm the
+	// charge; the quantity changes once the payment callback reports success.
+	PaymentURL string
+}
+
+// UpdateQuantity changes the number of seats of an active or trialing plan
+// subscription. The new quantity is billed from the next renewal on.
+//
+// With prorate, the default, the plan price of the added or removed seats for
+// the time left in the current period, less the period's coupon discount, is
+// charged or credited through the payment provider, and the quantity changes
+// once the provider confirms. As with plan changes, the adjustment is stored as
+// a payment attempt first, an attempt an earlier request left pending is sent
+// again, and an accepted adjustment is reversed when the new quantity cannot be
+// saved. With none the quantity changes right away and nothing is billed until
+// the next renewal.
+// Nothing is prorated during a trial.
+func (s *SubscriptionService) UpdateQuantity(ctx context.Context, req updateQuantityRequest) (*UpdateQuantityResult, error) {
+	if req.GetQuantity() < 1 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidRequest)
+	}
+	proration := req.GetProration()
+	if proration == "" {
+		proration = quantityProrationProrate
+	}
+	if proration != quantityProrationProrate && proration != quantityProrationNone {
+		return nil, fmt.Errorf("%w: proration must be prorate or none", ErrInvalidRequest)
+	}
+
+	subscription, err := s.subscriptionRepo.FindByID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, ErrSubscriptionNotFound
+	}
+	if subscription.PlanTypeID == nil {
+		return nil, fmt.Errorf("%w: subscription has no plan", ErrInvalidRequest)
+	}
+	if subscription.Status != entity.SubscriptionStatusActive && subscription.Status != entity.SubscriptionStatusTrialing {
+		return nil, fmt.Errorf("%w: only active or trialing subscriptions can change quantity", ErrInvalidTransition)
+	}
+
+	result := &UpdateQuantityResult{Subscription: subscription}
+	current := subscriptionQuantity(subscription)
+	if req.GetQuantity() == current {
+		return result, nil
+	}
+
+	var attempt *entity.PaymentAttempt
+	if proration == quantityProrationProrate && subscription.Status == entity.SubscriptionStatusActive {
+		planType, err := s.subscriptionPlanType(ctx, subscription)
+		if err != nil {
+			return nil, err
+		}
+		if planType == nil {
+			return nil, fmt.Errorf("plan type %d not found for subscription %d", *subscription.PlanTypeID, subscription.ID)
+		}
+
+		share, err := s.periodPaidShare(ctx, subscription.ID)
+		if err != nil {
+			return nil, err
+		}
+		now := time.Now().UTC()
+		seats := int64(req.GetQuantity() - current)
+		result.ProratedAmountCents = applyPaidShare(unusedPeriodAmount(planType, subscription.EndAt, now, s.cfg.BillingLocation)*seats, share)
+		if result.ProratedAmountCents != 0 {
+			attempt, err = s.openAdjustmentAttempt(ctx, subscription, entity.PaymentAttemptKindQuantityChange, planType.ID, req.GetQuantity())
+			if err != nil {
+				return nil, err
+			}
+			if attempt == nil {
+				attempt = newPaymentAttempt(subscription.ID, planType, req.GetQuantity(), entity.PaymentAttemptKindQuantityChange, now)
+				attempt.AmountCents = result.ProratedAmountCents
+			}
+			result.ProratedAmountCents = attempt.AmountCents
+
+			payResult, err := s.chargeQuantityChange(ctx, subscription, attempt)
+			if err != nil {
+				return nil, err
+			}
+			switch payResult.Type {
+			case payment.ResultTypeSuccess:
+			case payment.ResultTypeRedirect:
+				result.PaymentURL = payResult.PaymentURL
+				return result, nil
+			default:
+				message := payResult.Error
+				if message == "" {
+					message = "payment failed"
+				}
+				return nil, fmt.Errorf("%w: %s", ErrPaymentDeclined, message)
+			}
+		}
+	}
+
+	status, planTypeID := subscription.Status, *subscription.PlanTypeID
+	saved, err := s.updateWithRetry(ctx, subscription, func(item *entity.Subscription) (string, error) {
+		if item.Status != status || subscriptionQuantity(item) != current || item.PlanTypeID == nil || *item.PlanTypeID != planTypeID {
+			return "", fmt.Errorf("%w: subscription changed while its quantity change was billed", ErrConcurrentModification)
+		}
+		item.Quantity = req.GetQuantity()
+		item.UpdatedAt = time.Now().UTC()
+		return eventReasonQuantityChanged, nil
+	})
+	if err != nil {
+		if errors.Is(err, repository.ErrSubscriptionNotFound) {
+			err = ErrSubscriptionNotFound
+		}
+		if attempt != nil {
+			if reverseErr := s.reverseAdjustment(ctx, subscription, attempt); reverseErr != nil {
+				return nil, fmt.Errorf("%w; %v", err, reverseErr)
+			}
+		}
+		return nil, err
+	}
+	result.Subscription = saved
+
+	return result, nil
+}
+
+// chargeQuantityChange bills the prorated price of the added or removed seats,
+// recorded in the payment ledger as attempt with the new quantity.
+func (s *SubscriptionService) chargeQuantityChange(ctx context.Context, subscription *entity.Subscription, attempt *entity.PaymentAttempt) (payment.Result, error) {
+	return s.processPaymentAttempt(ctx, attempt, func() payment.Result {
+		return s.paymentService.ProcessSubscriptionAdjustment(ctx, payment.Adjustment{
+			IdempotencyKey: paymentIdempotencyKey(attempt),
+			SubscriptionID: subscription.ID,
+			PlanTypeID:     attempt.PlanTypeID,
+			AmountCents:    attempt.AmountCents,
+			Currency:       attempt.Currency,
+			UserID:         subscription.UserID,
+			Email:          subscription.Email,
+		})
+	})
+}
+
+// subscriptionQuantity is the number of seats billed for subscription. A zero
+// quantity counts as one seat.
+func subscriptionQuantity(subscription *entity.Subscription) int32 {
+	if subscription.Quantity < 1 {
+		return 1
+	}
+	return subscription.Quantity
+}
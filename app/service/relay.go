@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/publisher"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+const (
+	maxErrorLength  = 1024
+	maxBackoffShift = 10
+)
+
+// OutboxRelayService publishes the domain events queued in the outbox.
+type OutboxRelayService struct {
+	outboxRepo outboxMessageRepository
+	publisher  publisher.Publisher
+	cfg        config.OutboxConfig
+}
+
+func NewOutboxRelayService(outboxRepo outboxMessageRepository, publisher publisher.Publisher, cfg config.OutboxConfig) *OutboxRelayService {
+	return &OutboxRelayService{
+		outboxRepo: outboxRepo,
+		publisher:  publisher,
+		cfg:        cfg,
+	}
+}
+
+// RunRelayBatch publishes one batch of due messages. A failed publish is retried
+// with exponential backoff; after MaxAttempts the message is marked failed, which
+// releases the next events of the same subscription. The batch is leased while
+// it is published, so relays can run side by side without publishing a message
+// twice.
+func (s *OutboxRelayService) RunRelayBatch(ctx context.Context) error {
+	owner, err := newClaimOwner(s.cfg.WorkerID)
+	if err != nil {
+		return err
+	}
+	// Messages the batch did not get to wait for their lease to lapse when the
+	// release fails.
+	defer func() { _ = s.outboxRepo.ReleaseClaims(context.WithoutCancel(ctx), owner) }()
+
+	now := time.Now().UTC()
+	items, err := s.outboxRepo.ClaimDue(ctx, repository.OutboxClaim{
+		Owner: owner,
+		Now:   now,
+		Until: now.Add(s.cfg.ClaimTTL),
+		Limit: s.cfg.BatchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		publishErr := s.publisher.Publish(ctx, publisher.Message{
+			ID:             item.ID,
+			Type:           item.EventType,
+			SubscriptionID: item.SubscriptionID,
+			OccurredAt:     item.CreatedAt,
+			Data:           item.Payload,
+		})
+
+		now := time.Now().UTC()
+		item.Attempts++
+		item.UpdatedAt = now
+		if publishErr == nil {
+			item.Status = entity.OutboxMessageStatusPublished
+			item.LastError = ""
+			item.PublishedAt = &now
+		} else {
+			item.LastError = truncateString(publishErr.Error(), maxErrorLength)
+			if item.Attempts >= s.cfg.MaxAttempts {
+				item.Status = entity.OutboxMessageStatusFailed
+			} else {
+				item.NextAttemptAt = now.Add(retryBackoff(s.cfg.RetryBackoff, item.Attempts))
+			}
+		}
+
+		if err := s.outboxRepo.Update(ctx, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// retryBackoff doubles base with every failed attempt, up to 2^maxBackoffShift times base.
+func retryBackoff(base time.Duration, attempts int) time.Duration {
+	shift := attempts - 1
+	if shift > maxBackoffShift {
+		shift = maxBackoffShift
+	}
+	return base * time.Duration(1<<shift)
+}
+
+func truncateString(v string, max int) string {
+	if len(v) <= max {
+		return v
+	}
+	return v[:max]
+}
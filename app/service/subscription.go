@@ -11,6 +11,7 @@ import (
 	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
 	"github.com/vibast-solutions/ms-go-subscriptions/config"
+	"golang.org/x/time/rate"
 )
 
 type listSubscriptionTypesRequest interface {
@@ -19,12 +20,20 @@ type listSubscriptionTypesRequest interface {
 	GetStatus() int32
 }
 
+type listPlanTypesRequest interface {
+	GetSubscriptionTypeId() uint64
+	GetIncludeArchived() bool
+}
+
 type createSubscriptionRequest interface {
 	GetSubscriptionTypeId() uint64
 	GetUserId() string
 	GetEmail() string
 	GetStartAt() string
 	GetAutoRenew() bool
+	GetPlanTypeId() uint64
+	GetCouponCode() string
+	GetQuantity() int32
 }
 
 type updateSubscriptionRequest interface {
@@ -33,11 +42,34 @@ type updateSubscriptionRequest interface {
 	GetAutoRenew() bool
 	GetHasStatus() bool
 	GetStatus() int32
+	GetExpectedVersion() int64
+}
+
+type deleteSubscriptionRequest interface {
+	GetId() uint64
+	GetExpectedVersion() int64
 }
 
 type listSubscriptionsRequest interface {
 	GetUserId() string
 	GetEmail() string
+	GetPageSize() int32
+	GetPageToken() string
+	GetHasStatus() bool
+	GetStatus() int32
+	GetSubscriptionTypeId() uint64
+	GetHasAutoRenew() bool
+	GetAutoRenew() bool
+	GetEndAtFrom() string
+	GetEndAtTo() string
+	GetCreatedAtFrom() string
+	GetCreatedAtTo() string
+	GetSort() string
+}
+
+type ListSubscriptionsResult struct {
+	Subscriptions []*entity.Subscription
+	NextPageToken string
 }
 
 type CreateResult struct {
@@ -49,8 +81,16 @@ type SubscriptionService struct {
 	subscriptionRepo     subscriptionRepository
 	subscriptionTypeRepo subscriptionTypeRepository
 	planTypeRepo         planTypeRepository
+	paymentAttemptRepo   paymentAttemptRepository
+	eventRepo            subscriptionEventRepository
+	couponRepo           couponRepository
+	redemptionRepo       couponRedemptionRepository
+	txManager            txManager
+	writer               *subscriptionWriter
 	paymentService       payment.Service
-	cfg                  config.SubscriptionConfig
+	// paymentLimiter paces the renewal job's charges; nil leaves them unlimited.
+	paymentLimiter *rate.Limiter
+	cfg            config.SubscriptionConfig
 }
 
 type subscriptionRepository interface {
@@ -58,10 +98,13 @@ type subscriptionRepository interface {
 	Update(ctx context.Context, subscription *entity.Subscription) error
 	FindByID(ctx context.Context, id uint64) (*entity.Subscription, error)
 	FindByTypeAndIdentity(ctx context.Context, subscriptionTypeID uint64, userID, email *string) (*entity.Subscription, error)
-	List(ctx context.Context, userID, email string) ([]*entity.Subscription, error)
-	ListDueAutoRenew(ctx context.Context, now time.Time) ([]*entity.Subscription, error)
-	ListPendingPaymentStale(ctx context.Context, cutoff time.Time) ([]*entity.Subscription, error)
-	ListExpiredActive(ctx context.Context, now time.Time) ([]*entity.Subscription, error)
+	List(ctx context.Context, filter repository.SubscriptionFilter) ([]*entity.Subscription, error)
+	ClaimDueAutoRenew(ctx context.Context, now time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error)
+	ClaimPendingPaymentStale(ctx context.Context, cutoff time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error)
+	ClaimExpiredActive(ctx context.Context, now time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error)
+	ClaimDueResume(ctx context.Context, now time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error)
+	ReleaseClaims(ctx context.Context, owner string) error
+	HasUsedTrial(ctx context.Context, subscriptionTypeID uint64, userID, email *string) (bool, error)
 }
 
 type subscriptionTypeRepository interface {
@@ -70,13 +113,29 @@ type subscriptionTypeRepository interface {
 }
 
 type planTypeRepository interface {
-	FindBySubscriptionTypeID(ctx context.Context, subscriptionTypeID uint64) (*entity.PlanType, error)
+	List(ctx context.Context, subscriptionTypeID uint64) ([]*entity.PlanType, error)
+	FindByID(ctx context.Context, id uint64) (*entity.PlanType, error)
+}
+
+type paymentAttemptRepository interface {
+	Create(ctx context.Context, attempt *entity.PaymentAttempt) error
+	Update(ctx context.Context, attempt *entity.PaymentAttempt) error
+	CompletePending(ctx context.Context, attempt *entity.PaymentAttempt) error
+	SupersedePending(ctx context.Context, subscriptionID uint64, kind string, currentAttemptID uint64, now time.Time) error
+	FindByTransactionID(ctx context.Context, transactionID string) (*entity.PaymentAttempt, error)
+	ListBySubscriptionID(ctx context.Context, subscriptionID uint64) ([]*entity.PaymentAttempt, error)
 }
 
 func NewSubscriptionService(
 	subscriptionRepo subscriptionRepository,
 	subscriptionTypeRepo subscriptionTypeRepository,
 	planTypeRepo planTypeRepository,
+	paymentAttemptRepo paymentAttemptRepository,
+	eventRepo subscriptionEventRepository,
+	outboxRepo outboxMessageRepository,
+	couponRepo couponRepository,
+	redemptionRepo couponRedemptionRepository,
+	txManager txManager,
 	paymentService payment.Service,
 	cfg config.SubscriptionConfig,
 ) *SubscriptionService {
@@ -84,7 +143,14 @@ func NewSubscriptionService(
 		subscriptionRepo:     subscriptionRepo,
 		subscriptionTypeRepo: subscriptionTypeRepo,
 		planTypeRepo:         planTypeRepo,
+		paymentAttemptRepo:   paymentAttemptRepo,
+		eventRepo:            eventRepo,
+		couponRepo:           couponRepo,
+		redemptionRepo:       redemptionRepo,
+		txManager:            txManager,
+		writer:               newSubscriptionWriter(txManager, subscriptionRepo, eventRepo, outboxRepo),
 		paymentService:       paymentService,
+		paymentLimiter:       newPaymentLimiter(cfg.PaymentRateLimit),
 		cfg:                  cfg,
 	}
 }
@@ -101,6 +167,35 @@ func (s *SubscriptionService) ListSubscriptionTypes(ctx context.Context, req lis
 	return items, nil
 }
 
+func (s *SubscriptionService) ListPlanTypes(ctx context.Context, req listPlanTypesRequest) ([]*entity.PlanType, error) {
+	items, err := s.planTypeRepo.List(ctx, req.GetSubscriptionTypeId())
+	if err != nil {
+		return nil, err
+	}
+	if req.GetIncludeArchived() {
+		return items, nil
+	}
+
+	active := make([]*entity.PlanType, 0, len(items))
+	for _, item := range items {
+		if item.Status == entity.PlanTypeStatusActive {
+			active = append(active, item)
+		}
+	}
+	return active, nil
+}
+
+func (s *SubscriptionService) GetPlanType(ctx context.Context, id uint64) (*entity.PlanType, error) {
+	planType, err := s.planTypeRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if planType == nil {
+		return nil, ErrPlanTypeNotFound
+	}
+	return planType, nil
+}
+
 func (s *SubscriptionService) CreateSubscription(ctx context.Context, req createSubscriptionRequest) (*CreateResult, error) {
 	userID := normalizeOptionalString(req.GetUserId())
 	email := normalizeOptionalString(req.GetEmail())
@@ -112,22 +207,41 @@ func (s *SubscriptionService) CreateSubscription(ctx context.Context, req create
 	if err != nil {
 		return nil, err
 	}
-	if subscriptionType == nil || subscriptionType.Status != 10 {
+	if subscriptionType == nil || subscriptionType.Status != entity.SubscriptionTypeStatusActive {
 		return nil, ErrSubscriptionTypeNotFound
 	}
 
-	planType, err := s.planTypeRepo.FindBySubscriptionTypeID(ctx, req.GetSubscriptionTypeId())
+	planType, err := s.resolvePlanType(ctx, req.GetSubscriptionTypeId(), req.GetPlanTypeId())
+	if err != nil {
+		return nil, err
+	}
+	quantity := req.GetQuantity()
+	if quantity == 0 {
+		quantity = 1
+	}
+	if quantity < 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidRequest)
+	}
+	if planType == nil && quantity > 1 {
+		return nil, fmt.Errorf("%w: quantity only applies to plan subscriptions", ErrInvalidRequest)
+	}
+	trial, err := s.trialAvailable(ctx, planType, userID, email)
 	if err != nil {
 		return nil, err
 	}
 
 	now := time.Now().UTC()
+	coupon, err := s.redeemableCoupon(ctx, req.GetCouponCode(), planType, now)
+	if err != nil {
+		return nil, err
+	}
 	subscription, err := s.subscriptionRepo.FindByTypeAndIdentity(ctx, req.GetSubscriptionTypeId(), userID, email)
 	if err != nil {
 		return nil, err
 	}
 
 	isNew := subscription == nil
+	var before entity.Subscription
 	if isNew {
 		subscription = &entity.Subscription{
 			SubscriptionTypeID: req.GetSubscriptionTypeId(),
@@ -135,12 +249,29 @@ func (s *SubscriptionService) CreateSubscription(ctx context.Context, req create
 			Email:              email,
 			CreatedAt:          now,
 		}
+	} else {
+		before = *subscription
 	}
 
 	subscription.SubscriptionTypeID = req.GetSubscriptionTypeId()
+	subscription.PlanTypeID = nil
+	subscription.PendingPlanTypeID = nil
+	subscription.CanceledAt = nil
+	subscription.CancelReason = nil
+	subscription.PausedAt = nil
+	subscription.ResumeAt = nil
+	subscription.RenewalRetryCount = 0
+	subscription.BillingAnchorDay = 0
+	subscription.Quantity = quantity
+	if planType != nil {
+		subscription.PlanTypeID = &planType.ID
+	}
 	subscription.UserID = userID
 	subscription.Email = email
 	subscription.AutoRenew = req.GetAutoRenew()
+	if err := transitionSubscriptionStatus(subscription, entity.SubscriptionStatusProcessing); err != nil {
+		return nil, err
+	}
 
 	if planType != nil {
 		startAt, err := parseStartAt(req.GetStartAt())
@@ -148,7 +279,12 @@ func (s *SubscriptionService) CreateSubscription(ctx context.Context, req create
 			return nil, err
 		}
 		subscription.StartAt = &startAt
-		endAt := startAt.Add(time.Duration(planType.DurationDays) * 24 * time.Hour)
+		subscription.BillingAnchorDay = billingAnchorFor(startAt, 0, s.cfg.BillingLocation)
+		endAt := planPeriodEnd(startAt, planType, subscription.BillingAnchorDay, s.cfg.BillingLocation)
+		if trial {
+			endAt = addDays(startAt, planType.TrialDays, s.cfg.BillingLocation)
+			subscription.BillingAnchorDay = billingAnchorFor(endAt, 0, s.cfg.BillingLocation)
+		}
 		subscription.EndAt = &endAt
 		if subscription.AutoRenew {
 			renewAt := endAt.Add(-s.cfg.RenewBeforeEndMinutes)
@@ -156,61 +292,105 @@ func (s *SubscriptionService) CreateSubscription(ctx context.Context, req create
 		} else {
 			subscription.RenewAt = nil
 		}
-		subscription.Status = entity.SubscriptionStatusProcessing
+		if trial {
+			subscription.TrialEndAt = &endAt
+			if err := transitionSubscriptionStatus(subscription, entity.SubscriptionStatusTrialing); err != nil {
+				return nil, err
+			}
+		}
 	} else {
 		subscription.StartAt = nil
 		subscription.EndAt = nil
 		subscription.RenewAt = nil
 		subscription.AutoRenew = false
-		subscription.Status = entity.SubscriptionStatusActive
+		if err := transitionSubscriptionStatus(subscription, entity.SubscriptionStatusActive); err != nil {
+			return nil, err
+		}
 	}
 	subscription.UpdatedAt = now
 
-	if isNew {
-		if err := s.subscriptionRepo.Create(ctx, subscription); err != nil {
-			if errors.Is(err, repository.ErrSubscriptionAlreadyExists) {
-				return nil, ErrSubscriptionAlreadyExists
+	reason := eventReasonCreated
+	if trial {
+		reason = eventReasonTrialStarted
+	}
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if isNew {
+			if err := s.writer.create(ctx, subscription, reason); err != nil {
+				return err
 			}
-			return nil, err
-		}
-	} else {
-		if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
-			if errors.Is(err, repository.ErrSubscriptionNotFound) {
-				return nil, ErrSubscriptionNotFound
+		} else {
+			if err := s.writer.update(ctx, before, subscription, reason); err != nil {
+				return err
 			}
-			return nil, err
+			// A new sign-up does not carry over the discount of an earlier one.
+			if err := s.redemptionRepo.EndActive(ctx, subscription.ID, now); err != nil {
+				return err
+			}
+		}
+		if coupon == nil {
+			return nil
+		}
+		return s.redeemCoupon(ctx, subscription.ID, coupon, now)
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrSubscriptionAlreadyExists):
+			return nil, ErrSubscriptionAlreadyExists
+		case errors.Is(err, repository.ErrSubscriptionNotFound):
+			return nil, ErrSubscriptionNotFound
 		}
+		return nil, err
 	}
 
 	result := &CreateResult{Subscription: subscription}
-	if planType == nil {
+	if planType == nil || trial {
 		return result, nil
 	}
 
-	payResult, err := s.processPaymentSafely(ctx, subscription.ID, planType.ID, subscription.UserID, subscription.Email)
+	attempt, payResult, err := s.chargeSubscription(ctx, subscription, planType, entity.PaymentAttemptKindInitial)
 	if err != nil {
+		if coupon != nil {
+			if releaseErr := s.releaseCoupon(ctx, subscription.ID, time.Now().UTC()); releaseErr != nil {
+				return nil, fmt.Errorf("%w; %v", err, releaseErr)
+			}
+		}
 		return nil, err
 	}
 
 	now = time.Now().UTC()
+	before = *subscription
+	next := entity.SubscriptionStatusProcessing
+	reason = eventReasonPaymentFailed
 	switch payResult.Type {
 	case payment.ResultTypeSuccess:
-		subscription.Status = entity.SubscriptionStatusActive
+		next = entity.SubscriptionStatusActive
+		reason = eventReasonPaymentSucceeded
 	case payment.ResultTypeRedirect:
-		subscription.Status = entity.SubscriptionStatusPendingPayment
+		next = entity.SubscriptionStatusPendingPayment
+		reason = eventReasonPaymentPending
 		result.PaymentURL = payResult.PaymentURL
-	case payment.ResultTypeFailure:
-		subscription.Status = entity.SubscriptionStatusProcessing
-		renewAt := now.Add(s.cfg.RenewalRetryIntervalMinutes)
-		subscription.RenewAt = &renewAt
 	default:
-		subscription.Status = entity.SubscriptionStatusProcessing
 		renewAt := now.Add(s.cfg.RenewalRetryIntervalMinutes)
 		subscription.RenewAt = &renewAt
 	}
+	if err := transitionSubscriptionStatus(subscription, next); err != nil {
+		return nil, err
+	}
 	subscription.UpdatedAt = now
 
-	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+	err = s.txManager.WithinTx(ctx, func(ctx context.Context) error {
+		if err := s.writer.update(ctx, before, subscription, reason); err != nil {
+			return err
+		}
+		switch {
+		case paidDiscountedPeriod(attempt, payResult, nil):
+			return recordDiscountedPeriod(ctx, s.redemptionRepo, subscription.ID, now)
+		case coupon != nil && payResult.Type != payment.ResultTypeSuccess && payResult.Type != payment.ResultTypeRedirect:
+			return s.releaseCoupon(ctx, subscription.ID, now)
+		}
+		return nil
+	})
+	if err != nil {
 		if errors.Is(err, repository.ErrSubscriptionNotFound) {
 			return nil, ErrSubscriptionNotFound
 		}
@@ -231,12 +411,35 @@ func (s *SubscriptionService) GetSubscription(ctx context.Context, id uint64) (*
 	return subscription, nil
 }
 
-func (s *SubscriptionService) ListSubscriptions(ctx context.Context, req listSubscriptionsRequest) ([]*entity.Subscription, error) {
-	items, err := s.subscriptionRepo.List(ctx, strings.TrimSpace(req.GetUserId()), strings.TrimSpace(req.GetEmail()))
+// ListSubscriptions returns one page of the subscriptions matching req. The
+// returned NextPageToken is empty on the last page.
+func (s *SubscriptionService) ListSubscriptions(ctx context.Context, req listSubscriptionsRequest) (*ListSubscriptionsResult, error) {
+	filter, err := subscriptionFilterFromRequest(req)
 	if err != nil {
 		return nil, err
 	}
-	return items, nil
+
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	filter.Limit = pageSize + 1
+
+	items, err := s.subscriptionRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ListSubscriptionsResult{Subscriptions: items}
+	if len(items) > pageSize {
+		result.Subscriptions = items[:pageSize]
+		cursor := repository.SubscriptionCursorOf(items[pageSize-1], filter.Sort)
+		result.NextPageToken = encodePageToken(filter.Sort, cursor)
+	}
+	return result, nil
 }
 
 func (s *SubscriptionService) UpdateSubscription(ctx context.Context, req updateSubscriptionRequest) (*entity.Subscription, error) {
@@ -250,29 +453,46 @@ func (s *SubscriptionService) UpdateSubscription(ctx context.Context, req update
 	if !req.GetHasAutoRenew() && !req.GetHasStatus() {
 		return nil, ErrNoFieldsToUpdate
 	}
+	if err := checkExpectedVersion(subscription, req.GetExpectedVersion()); err != nil {
+		return nil, err
+	}
+	before := *subscription
 
 	if req.GetHasStatus() {
 		if !isSubscriptionStatusAllowed(req.GetStatus()) {
 			return nil, ErrInvalidStatus
 		}
-		subscription.Status = req.GetStatus()
+		if req.GetStatus() != subscription.Status && req.GetStatus() != entity.SubscriptionStatusInactive {
+			return nil, fmt.Errorf("%w: %s is only reached through payment processing", ErrInvalidTransition, subscriptionStatusName(req.GetStatus()))
+		}
+		if err := transitionSubscriptionStatus(subscription, req.GetStatus()); err != nil {
+			return nil, err
+		}
 	}
 	if req.GetHasAutoRenew() {
 		subscription.AutoRenew = req.GetAutoRenew()
 		if !subscription.AutoRenew {
 			subscription.RenewAt = nil
-		} else if subscription.EndAt != nil {
-			renewAt := subscription.EndAt.Add(-s.cfg.RenewBeforeEndMinutes)
-			subscription.RenewAt = &renewAt
+			subscription.PendingPlanTypeID = nil
+		} else {
+			subscription.CanceledAt = nil
+			subscription.CancelReason = nil
+			if subscription.EndAt != nil {
+				renewAt := subscription.EndAt.Add(-s.cfg.RenewBeforeEndMinutes)
+				subscription.RenewAt = &renewAt
+			}
 		}
 	}
 	if subscription.Status == entity.SubscriptionStatusInactive {
 		subscription.AutoRenew = false
 		subscription.RenewAt = nil
+		subscription.PendingPlanTypeID = nil
+		subscription.PausedAt = nil
+		subscription.ResumeAt = nil
 	}
 
 	subscription.UpdatedAt = time.Now().UTC()
-	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+	if err := s.writer.update(ctx, before, subscription, eventReasonUpdated); err != nil {
 		if errors.Is(err, repository.ErrSubscriptionNotFound) {
 			return nil, ErrSubscriptionNotFound
 		}
@@ -282,21 +502,30 @@ func (s *SubscriptionService) UpdateSubscription(ctx context.Context, req update
 	return subscription, nil
 }
 
-func (s *SubscriptionService) DeleteSubscription(ctx context.Context, id uint64) (*entity.Subscription, error) {
-	subscription, err := s.subscriptionRepo.FindByID(ctx, id)
+func (s *SubscriptionService) DeleteSubscription(ctx context.Context, req deleteSubscriptionRequest) (*entity.Subscription, error) {
+	subscription, err := s.subscriptionRepo.FindByID(ctx, req.GetId())
 	if err != nil {
 		return nil, err
 	}
 	if subscription == nil {
 		return nil, ErrSubscriptionNotFound
 	}
+	if err := checkExpectedVersion(subscription, req.GetExpectedVersion()); err != nil {
+		return nil, err
+	}
 
-	subscription.Status = entity.SubscriptionStatusInactive
+	before := *subscription
+	if err := transitionSubscriptionStatus(subscription, entity.SubscriptionStatusInactive); err != nil {
+		return nil, err
+	}
 	subscription.AutoRenew = false
 	subscription.RenewAt = nil
+	subscription.PendingPlanTypeID = nil
+	subscription.PausedAt = nil
+	subscription.ResumeAt = nil
 	subscription.UpdatedAt = time.Now().UTC()
 
-	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+	if err := s.writer.update(ctx, before, subscription, eventReasonDeleted); err != nil {
 		if errors.Is(err, repository.ErrSubscriptionNotFound) {
 			return nil, ErrSubscriptionNotFound
 		}
@@ -306,8 +535,8 @@ func (s *SubscriptionService) DeleteSubscription(ctx context.Context, id uint64)
 	return subscription, nil
 }
 
-func (s *SubscriptionService) CancelSubscription(ctx context.Context, id uint64) (*entity.Subscription, error) {
-	subscription, err := s.subscriptionRepo.FindByID(ctx, id)
+func (s *SubscriptionService) ListPaymentAttempts(ctx context.Context, subscriptionID uint64) ([]*entity.PaymentAttempt, error) {
+	subscription, err := s.subscriptionRepo.FindByID(ctx, subscriptionID)
 	if err != nil {
 		return nil, err
 	}
@@ -315,133 +544,292 @@ func (s *SubscriptionService) CancelSubscription(ctx context.Context, id uint64)
 		return nil, ErrSubscriptionNotFound
 	}
 
-	subscription.AutoRenew = false
-	subscription.RenewAt = nil
-	subscription.UpdatedAt = time.Now().UTC()
+	return s.paymentAttemptRepo.ListBySubscriptionID(ctx, subscriptionID)
+}
 
-	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
-		if errors.Is(err, repository.ErrSubscriptionNotFound) {
-			return nil, ErrSubscriptionNotFound
-		}
+func (s *SubscriptionService) ListSubscriptionEvents(ctx context.Context, subscriptionID uint64) ([]*entity.SubscriptionEvent, error) {
+	subscription, err := s.subscriptionRepo.FindByID(ctx, subscriptionID)
+	if err != nil {
 		return nil, err
 	}
+	if subscription == nil {
+		return nil, ErrSubscriptionNotFound
+	}
 
-	return subscription, nil
+	return s.eventRepo.ListBySubscriptionID(ctx, subscriptionID)
 }
 
-func (s *SubscriptionService) RunAutoRenewalBatch(ctx context.Context) error {
+// RunAutoRenewalBatch charges the subscriptions whose renew_at has come, past-due
+// ones included. Charges that do not go through follow the dunning schedule.
+// Each batch of rows is leased while it is charged, so renewal workers can run
+// side by side without charging a subscription twice. Up to RenewalConcurrency
+// subscriptions are charged at once.
+//
+// Canceling ctx stops the job from starting further charges. A charge that is
+// under way runs to its end, bounded by RenewalChargeTimeout, and its outcome is
+// saved, so no subscription is left half renewed.
+func (s *SubscriptionService) RunAutoRenewalBatch(ctx context.Context) (BatchResult, error) {
 	now := time.Now().UTC()
-	items, err := s.subscriptionRepo.ListDueAutoRenew(ctx, now)
-	if err != nil {
-		return err
+	return s.runClaimedBatch(ctx, s.renewalBatchSize(), s.cfg.RenewalConcurrency,
+		func(ctx context.Context, claim repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+			return s.subscriptionRepo.ClaimDueAutoRenew(ctx, now, claim)
+		},
+		func(item *entity.Subscription) error {
+			return s.renew(ctx, item, now)
+		},
+	)
+}
+
+// renew charges one subscription the renewal job leased.
+func (s *SubscriptionService) renew(ctx context.Context, claimed *entity.Subscription, now time.Time) error {
+	if s.paymentLimiter != nil {
+		if err := s.paymentLimiter.Wait(ctx); err != nil {
+			return err
+		}
 	}
+	// From here on the renewal is completed even when ctx is canceled.
+	ctx = context.WithoutCancel(ctx)
 
-	for _, item := range items {
-		item.Status = entity.SubscriptionStatusProcessing
-		item.UpdatedAt = now
-		if err := s.subscriptionRepo.Update(ctx, item); err != nil {
-			continue
+	// A processing row was left mid-renewal by a worker that died; it is
+	// finished from where that worker stopped.
+	resumed := interruptedRenewal(claimed, now)
+	item := claimed
+	if !resumed {
+		var err error
+		item, err = s.updateWithRetry(ctx, claimed, func(item *entity.Subscription) (string, error) {
+			if !dueForRenewal(item, now) {
+				return "", fmt.Errorf("%w: subscription is no longer due for renewal", errBatchSkipped)
+			}
+			if err := transitionSubscriptionStatus(item, entity.SubscriptionStatusProcessing); err != nil {
+				return "", err
+			}
+			item.UpdatedAt = now
+			return eventReasonRenewalStarted, nil
+		})
+		if err != nil {
+			return err
 		}
+	}
 
-		planType, err := s.planTypeRepo.FindBySubscriptionTypeID(ctx, item.SubscriptionTypeID)
-		if err != nil || planType == nil {
-			item.Status = entity.SubscriptionStatusInactive
+	planType, err := s.renewalPlanType(ctx, item)
+	if err != nil || planType == nil {
+		_, err = s.updateWithRetry(ctx, item, func(item *entity.Subscription) (string, error) {
+			_ = transitionSubscriptionStatus(item, entity.SubscriptionStatusInactive)
 			item.AutoRenew = false
 			item.RenewAt = nil
+			item.PendingPlanTypeID = nil
 			item.UpdatedAt = time.Now().UTC()
-			_ = s.subscriptionRepo.Update(ctx, item)
-			continue
-		}
+			return eventReasonRenewalPlanMissing, nil
+		})
+		return err
+	}
 
-		payResult, err := s.processPaymentSafely(ctx, item.ID, planType.ID, item.UserID, item.Email)
-		now = time.Now().UTC()
-		if err != nil {
-			renewAt := now.Add(s.cfg.RenewalRetryIntervalMinutes)
-			item.RenewAt = &renewAt
-			item.Status = entity.SubscriptionStatusProcessing
-			item.UpdatedAt = now
-			if shouldDeactivateForRetryAge(item, s.cfg.MaxRenewalRetryAgeMinutes) {
-				item.Status = entity.SubscriptionStatusInactive
-				item.AutoRenew = false
-				item.RenewAt = nil
+	chargeCtx, cancel := s.renewalChargeContext(ctx)
+	var attempt *entity.PaymentAttempt
+	var payResult payment.Result
+	var chargeErr error
+	if resumed {
+		attempt, payResult, chargeErr = s.resumeRenewalCharge(chargeCtx, item)
+	}
+	if attempt == nil && chargeErr == nil {
+		attempt, payResult, chargeErr = s.chargeSubscription(chargeCtx, item, planType, entity.PaymentAttemptKindRenewal)
+	}
+	cancel()
+	now = time.Now().UTC()
+	// The charge is not repeated when the subscription changed meanwhile;
+	// its outcome is applied to the fresh copy instead.
+	_, err = s.updateWithRetry(ctx, item, func(item *entity.Subscription) (string, error) {
+		var reason string
+		var err error
+		switch {
+		case chargeErr == nil && payResult.Type == payment.ResultTypeSuccess:
+			reason = eventReasonPaymentSucceeded
+			if err = transitionSubscriptionStatus(item, entity.SubscriptionStatusActive); err != nil {
+				return "", err
 			}
-			_ = s.subscriptionRepo.Update(ctx, item)
-			continue
-		}
-
-		switch payResult.Type {
-		case payment.ResultTypeSuccess:
-			item.Status = entity.SubscriptionStatusActive
-			base := now
-			if item.EndAt != nil {
-				base = *item.EndAt
+			item.RenewalRetryCount = 0
+			applyPlanChange(item, planType.ID)
+			extendSubscriptionPeriod(item, planType, now, s.cfg)
+		case chargeErr == nil && payResult.Type == payment.ResultTypeRedirect:
+			// The checkout counts as a retry; the pending-payment cleanup moves
+			// the subscription into dunning if the customer never finishes it.
+			reason = eventReasonPaymentPending
+			if err = transitionSubscriptionStatus(item, entity.SubscriptionStatusPendingPayment); err != nil {
+				return "", err
 			}
-			newEnd := base.Add(time.Duration(planType.DurationDays) * 24 * time.Hour)
-			item.EndAt = &newEnd
-			if item.AutoRenew {
-				renewAt := newEnd.Add(-s.cfg.RenewBeforeEndMinutes)
-				item.RenewAt = &renewAt
+			scheduleDunningRetry(item, s.cfg.DunningSchedule)
+		default:
+			scheduleDunningRetry(item, s.cfg.DunningSchedule)
+			if reason, err = enterDunning(item, s.cfg.DunningSchedule, eventReasonPaymentFailed); err != nil {
+				return "", err
 			}
-		case payment.ResultTypeRedirect:
-			item.Status = entity.SubscriptionStatusPendingPayment
-			renewAt := now.Add(s.cfg.RenewalRetryIntervalMinutes)
-			item.RenewAt = &renewAt
-		case payment.ResultTypeFailure:
-			item.Status = entity.SubscriptionStatusProcessing
-			renewAt := now.Add(s.cfg.RenewalRetryIntervalMinutes)
-			item.RenewAt = &renewAt
-		}
-
-		if shouldDeactivateForRetryAge(item, s.cfg.MaxRenewalRetryAgeMinutes) {
-			item.Status = entity.SubscriptionStatusInactive
-			item.AutoRenew = false
-			item.RenewAt = nil
 		}
-
 		item.UpdatedAt = now
-		_ = s.subscriptionRepo.Update(ctx, item)
+		return reason, nil
+	})
+	if err != nil {
+		return err
+	}
+	// The period is counted once the renewal is saved, so a renewal finished
+	// by a later run after a crash counts it too.
+	if paidDiscountedPeriod(attempt, payResult, chargeErr) {
+		return recordDiscountedPeriod(ctx, s.redemptionRepo, item.ID, now)
 	}
-
 	return nil
 }
 
-func (s *SubscriptionService) RunPendingPaymentCleanupBatch(ctx context.Context) error {
+func (s *SubscriptionService) RunPendingPaymentCleanupBatch(ctx context.Context) (BatchResult, error) {
 	now := time.Now().UTC()
 	cutoff := now.Add(-s.cfg.PendingPaymentTimeout)
-	items, err := s.subscriptionRepo.ListPendingPaymentStale(ctx, cutoff)
-	if err != nil {
-		return err
+	return s.runClaimedBatch(ctx, s.cfg.ClaimBatchSize, 1,
+		func(ctx context.Context, claim repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+			return s.subscriptionRepo.ClaimPendingPaymentStale(ctx, cutoff, claim)
+		},
+		func(item *entity.Subscription) error {
+			_, err := s.updateWithRetry(ctx, item, func(item *entity.Subscription) (string, error) {
+				if item.Status != entity.SubscriptionStatusPendingPayment || !item.UpdatedAt.Before(cutoff) {
+					return "", fmt.Errorf("%w: payment is no longer pending", errBatchSkipped)
+				}
+				reason := eventReasonPendingPaymentTimeout
+				if item.RenewalRetryCount > 0 {
+					// An abandoned renewal checkout: the retry is already scheduled.
+					var err error
+					if reason, err = enterDunning(item, s.cfg.DunningSchedule, reason); err != nil {
+						return "", err
+					}
+				} else {
+					if err := transitionSubscriptionStatus(item, entity.SubscriptionStatusProcessing); err != nil {
+						return "", err
+					}
+					if item.RenewAt == nil || item.RenewAt.Before(now) {
+						renewAt := now.Add(s.cfg.RenewalRetryIntervalMinutes)
+						item.RenewAt = &renewAt
+					}
+				}
+				item.UpdatedAt = now
+				return reason, nil
+			})
+			return err
+		},
+	)
+}
+
+func (s *SubscriptionService) RunExpirationBatch(ctx context.Context) (BatchResult, error) {
+	now := time.Now().UTC()
+	return s.runClaimedBatch(ctx, s.cfg.ClaimBatchSize, 1,
+		func(ctx context.Context, claim repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+			return s.subscriptionRepo.ClaimExpiredActive(ctx, now, claim)
+		},
+		func(item *entity.Subscription) error {
+			_, err := s.updateWithRetry(ctx, item, func(item *entity.Subscription) (string, error) {
+				if !expiredInUse(item, now) {
+					return "", fmt.Errorf("%w: subscription is no longer expired", errBatchSkipped)
+				}
+				if err := transitionSubscriptionStatus(item, entity.SubscriptionStatusInactive); err != nil {
+					return "", err
+				}
+				item.AutoRenew = false
+				item.RenewAt = nil
+				item.PendingPlanTypeID = nil
+				item.UpdatedAt = now
+				return eventReasonExpired, nil
+			})
+			return err
+		},
+	)
+}
+
+// renewalBatchSize caps the rows the renewal job leases at once to what it can
+// charge before their lease lapses, since a lease is not extended while the
+// rows wait their turn. RenewalConcurrency charges of up to RenewalChargeTimeout
+// run at once, and at most PaymentRateLimit start a second; half of ClaimTTL is
+// spent on charging, which leaves the rest for saving their outcomes.
+func (s *SubscriptionService) renewalBatchSize() int {
+	size := s.cfg.ClaimBatchSize
+	budget := s.cfg.ClaimTTL / 2
+	if s.cfg.RenewalChargeTimeout > 0 {
+		rounds := max(int(budget/s.cfg.RenewalChargeTimeout), 1)
+		size = min(size, rounds*max(s.cfg.RenewalConcurrency, 1))
+	}
+	if s.cfg.PaymentRateLimit > 0 {
+		size = min(size, max(int(budget.Seconds())*s.cfg.PaymentRateLimit, 1))
 	}
+	return size
+}
 
-	for _, item := range items {
-		item.Status = entity.SubscriptionStatusProcessing
-		if item.RenewAt == nil || item.RenewAt.Before(now) {
-			renewAt := now.Add(s.cfg.RenewalRetryIntervalMinutes)
-			item.RenewAt = &renewAt
-		}
-		item.UpdatedAt = now
-		_ = s.subscriptionRepo.Update(ctx, item)
+// renewalChargeContext bounds one renewal charge by RenewalChargeTimeout.
+func (s *SubscriptionService) renewalChargeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.cfg.RenewalChargeTimeout <= 0 {
+		return context.WithCancel(ctx)
 	}
+	return context.WithTimeout(ctx, s.cfg.RenewalChargeTimeout)
+}
 
-	return nil
+// newPaymentLimiter allows perSecond renewal charges a second, one at a time.
+func newPaymentLimiter(perSecond int) *rate.Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(perSecond), 1)
 }
 
-func (s *SubscriptionService) RunExpirationBatch(ctx context.Context) error {
-	now := time.Now().UTC()
-	items, err := s.subscriptionRepo.ListExpiredActive(ctx, now)
-	if err != nil {
-		return err
+// dueForRenewal reports whether the renewal job still has to charge
+// subscription, as ClaimDueAutoRenew selects it.
+func dueForRenewal(subscription *entity.Subscription, now time.Time) bool {
+	switch subscription.Status {
+	case entity.SubscriptionStatusActive, entity.SubscriptionStatusTrialing, entity.SubscriptionStatusPastDue:
+	default:
+		return false
 	}
+	return subscription.AutoRenew && subscription.RenewAt != nil && !subscription.RenewAt.After(now)
+}
 
-	for _, item := range items {
-		item.Status = entity.SubscriptionStatusInactive
-		item.AutoRenew = false
-		item.RenewAt = nil
-		item.UpdatedAt = now
-		_ = s.subscriptionRepo.Update(ctx, item)
+// interruptedRenewal reports whether subscription was left in processing by a
+// renewal that did not finish. ClaimDueAutoRenew only leases processing rows
+// whose last status change started a renewal, once no worker holds them.
+func interruptedRenewal(subscription *entity.Subscription, now time.Time) bool {
+	return subscription.Status == entity.SubscriptionStatusProcessing &&
+		subscription.AutoRenew && subscription.RenewAt != nil && !subscription.RenewAt.After(now)
+}
+
+// resumeRenewalCharge picks up the renewal attempt an interrupted renewal made
+// after it moved the subscription to processing. A pending attempt is sent again
+// under its own idempotency key, and a completed one gives its recorded outcome,
+// so the customer is not charged twice. It returns no attempt when the renewal
+// stopped before it stored one.
+func (s *SubscriptionService) resumeRenewalCharge(ctx context.Context, subscription *entity.Subscription) (*entity.PaymentAttempt, payment.Result, error) {
+	attempts, err := s.paymentAttemptRepo.ListBySubscriptionID(ctx, subscription.ID)
+	if err != nil {
+		return nil, payment.Result{}, err
+	}
+	for _, attempt := range attempts {
+		if attempt.Kind != entity.PaymentAttemptKindRenewal || attempt.CreatedAt.Before(subscription.UpdatedAt) {
+			continue
+		}
+		switch attempt.Status {
+		case entity.PaymentAttemptStatusPending:
+			payResult, err := s.sendCharge(ctx, subscription, attempt)
+			return attempt, payResult, err
+		case entity.PaymentAttemptStatusSucceeded, entity.PaymentAttemptStatusFailed:
+			return attempt, payment.Result{Type: payment.ResultType(attempt.ResultType), Error: attempt.Error}, nil
+		}
 	}
+	return nil, payment.Result{}, nil
+}
 
-	return nil
+// expiredInUse reports whether the expiration job still has to end subscription,
+// as ClaimExpiredActive selects it.
+func expiredInUse(subscription *entity.Subscription, now time.Time) bool {
+	switch subscription.Status {
+	case entity.SubscriptionStatusActive, entity.SubscriptionStatusTrialing:
+	case entity.SubscriptionStatusPastDue:
+		if subscription.AutoRenew {
+			return false
+		}
+	default:
+		return false
+	}
+	return subscription.EndAt != nil && subscription.EndAt.Before(now)
 }
 
 func parseStartAt(value string) (time.Time, error) {
@@ -468,6 +856,9 @@ func isSubscriptionStatusAllowed(status int32) bool {
 	case entity.SubscriptionStatusInactive,
 		entity.SubscriptionStatusProcessing,
 		entity.SubscriptionStatusPendingPayment,
+		entity.SubscriptionStatusPaused,
+		entity.SubscriptionStatusPastDue,
+		entity.SubscriptionStatusTrialing,
 		entity.SubscriptionStatusActive:
 		return true
 	default:
@@ -476,22 +867,169 @@ func isSubscriptionStatusAllowed(status int32) bool {
 }
 
 func isSubscriptionTypeStatusAllowed(status int32) bool {
-	return status == 0 || status == 10
+	return status == entity.SubscriptionTypeStatusInactive || status == entity.SubscriptionTypeStatusActive
+}
+
+// resolvePlanType picks the plan a new subscription is billed on. Types without
+// plans are email subscriptions. Archived plans cannot be chosen, and planTypeID
+// may be omitted only when the type has a single active plan.
+func (s *SubscriptionService) resolvePlanType(ctx context.Context, subscriptionTypeID, planTypeID uint64) (*entity.PlanType, error) {
+	plans, err := s.planTypeRepo.List(ctx, subscriptionTypeID)
+	if err != nil {
+		return nil, err
+	}
+	if len(plans) == 0 {
+		return nil, nil
+	}
+
+	active := make([]*entity.PlanType, 0, len(plans))
+	for _, plan := range plans {
+		if plan.Status == entity.PlanTypeStatusActive {
+			active = append(active, plan)
+		}
+	}
+
+	if planTypeID == 0 {
+		switch len(active) {
+		case 0:
+			return nil, ErrPlanTypeNotFound
+		case 1:
+			return active[0], nil
+		default:
+			return nil, fmt.Errorf("%w: plan_type_id is required for subscription types with several plans", ErrInvalidRequest)
+		}
+	}
+
+	for _, plan := range active {
+		if plan.ID == planTypeID {
+			return plan, nil
+		}
+	}
+	return nil, ErrPlanTypeNotFound
+}
+
+// trialAvailable reports whether a new subscription on planType starts with a
+// free trial. Trials are given once per subscription type: an identity whose
+// user_id or email already had one is charged right away.
+func (s *SubscriptionService) trialAvailable(ctx context.Context, planType *entity.PlanType, userID, email *string) (bool, error) {
+	if planType == nil || planType.TrialDays <= 0 {
+		return false, nil
+	}
+	used, err := s.subscriptionRepo.HasUsedTrial(ctx, planType.SubscriptionTypeID, userID, email)
+	if err != nil {
+		return false, err
+	}
+	return !used, nil
 }
 
-func (s *SubscriptionService) processPaymentSafely(ctx context.Context, subscriptionID, planTypeID uint64, userID, email *string) (_ payment.Result, err error) {
+// subscriptionPlanType returns the plan the subscription is billed on, or nil
+// when it has none.
+func (s *SubscriptionService) subscriptionPlanType(ctx context.Context, subscription *entity.Subscription) (*entity.PlanType, error) {
+	if subscription.PlanTypeID == nil {
+		return nil, nil
+	}
+	return s.planTypeRepo.FindByID(ctx, *subscription.PlanTypeID)
+}
+
+// chargeSubscription charges the plan price for every seat, less the discount of
+// the subscription's coupon, and records the attempt in the payment ledger. A
+// period the coupon covers in full is not sent to the provider. The attempt is
+// returned so the caller can count a discounted period once it saved the
+// subscription.
+func (s *SubscriptionService) chargeSubscription(ctx context.Context, subscription *entity.Subscription, planType *entity.PlanType, kind string) (*entity.PaymentAttempt, payment.Result, error) {
+	attempt := newPaymentAttempt(subscription.ID, planType, subscriptionQuantity(subscription), kind, time.Now().UTC())
+	discount, err := s.couponDiscountCents(ctx, subscription.ID, planType, attempt.AmountCents)
+	if err != nil {
+		return nil, payment.Result{}, err
+	}
+
+	attempt.DiscountCents = discount
+	attempt.AmountCents -= discount
+	payResult, err := s.sendCharge(ctx, subscription, attempt)
+	return attempt, payResult, err
+}
+
+// paidDiscountedPeriod reports whether attempt paid for a period at the
+// coupon's discount, which counts against the subscription's redemption.
+func paidDiscountedPeriod(attempt *entity.PaymentAttempt, payResult payment.Result, err error) bool {
+	return err == nil && attempt != nil && attempt.DiscountCents > 0 && payResult.Type == payment.ResultTypeSuccess
+}
+
+// sendCharge charges attempt, stored or not yet, through the payment provider.
+func (s *SubscriptionService) sendCharge(ctx context.Context, subscription *entity.Subscription, attempt *entity.PaymentAttempt) (payment.Result, error) {
+	payResult, err := s.processPaymentAttempt(ctx, attempt, func() payment.Result {
+		if attempt.DiscountCents > 0 && attempt.AmountCents == 0 {
+			return payment.Result{Type: payment.ResultTypeSuccess}
+		}
+		return s.paymentService.ProcessSubscriptionPayment(ctx, payment.Charge{
+			IdempotencyKey: paymentIdempotencyKey(attempt),
+			SubscriptionID: subscription.ID,
+			PlanTypeID:     attempt.PlanTypeID,
+			Quantity:       attempt.Quantity,
+			AmountCents:    attempt.AmountCents,
+			Currency:       attempt.Currency,
+			UserID:         subscription.UserID,
+			Email:          subscription.Email,
+		})
+	})
+	return payResult, err
+}
+
+// processPaymentAttempt runs process and records its outcome on attempt. The
+// attempt is stored as pending before the provider is called so that a crash
+// mid-charge still leaves a trace, and older pending attempts of the same kind
+// are superseded so a late callback for an abandoned checkout cannot change the
+// subscription. An attempt stored earlier is sent again as it is, under the same
+// idempotency key. Failing
+// to record the outcome does not hide the payment result: the charge already
+// happened and the caller must persist it.
+func (s *SubscriptionService) processPaymentAttempt(ctx context.Context, attempt *entity.PaymentAttempt, process func() payment.Result) (payment.Result, error) {
+	if attempt.ID == 0 {
+		if err := s.createPaymentAttempt(ctx, attempt); err != nil {
+			return payment.Result{}, err
+		}
+	}
+
+	payResult, payErr := processPaymentSafely(process)
+	completePaymentAttempt(attempt, payResult, payErr, time.Now().UTC())
+	// The outcome is recorded even when the provider call ran out of time.
+	_ = s.paymentAttemptRepo.Update(context.WithoutCancel(ctx), attempt)
+
+	return payResult, payErr
+}
+
+// createPaymentAttempt stores attempt as pending and supersedes the older pending
+// attempts of the same kind.
+func (s *SubscriptionService) createPaymentAttempt(ctx context.Context, attempt *entity.PaymentAttempt) error {
+	if err := s.paymentAttemptRepo.Create(ctx, attempt); err != nil {
+		return err
+	}
+	return s.paymentAttemptRepo.SupersedePending(ctx, attempt.SubscriptionID, attempt.Kind, attempt.ID, attempt.CreatedAt)
+}
+
+func processPaymentSafely(process func() payment.Result) (_ payment.Result, err error) {
 	defer func() {
 		if rec := recover(); rec != nil {
 			err = fmt.Errorf("payment processing failed: %v", rec)
 		}
 	}()
 
-	return s.paymentService.ProcessSubscriptionPayment(ctx, subscriptionID, planTypeID, userID, email), nil
+	return process(), nil
 }
 
-func shouldDeactivateForRetryAge(item *entity.Subscription, maxRetryAge time.Duration) bool {
-	if item.EndAt == nil || item.RenewAt == nil {
-		return false
+// extendSubscriptionPeriod adds one plan period after a paid renewal. The period
+// continues from the current end so early renewals do not lose remaining time,
+// and stays on the billing anchor day.
+func extendSubscriptionPeriod(item *entity.Subscription, planType *entity.PlanType, now time.Time, cfg config.SubscriptionConfig) {
+	base := now
+	if item.EndAt != nil {
+		base = *item.EndAt
+	}
+	item.BillingAnchorDay = billingAnchorFor(base, item.BillingAnchorDay, cfg.BillingLocation)
+	newEnd := planPeriodEnd(base, planType, item.BillingAnchorDay, cfg.BillingLocation)
+	item.EndAt = &newEnd
+	if item.AutoRenew {
+		renewAt := newEnd.Add(-cfg.RenewBeforeEndMinutes)
+		item.RenewAt = &renewAt
 	}
-	return item.RenewAt.Sub(*item.EndAt) > maxRetryAge
 }
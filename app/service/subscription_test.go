@@ -3,12 +3,16 @@ package service
 import (
 	"context"
 	"errors"
+	"net"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/publisher"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
 	"github.com/vibast-solutions/ms-go-subscriptions/config"
@@ -19,10 +23,13 @@ type mockSubscriptionRepo struct {
 	updateFn                func(ctx context.Context, subscription *entity.Subscription) error
 	findByIDFn              func(ctx context.Context, id uint64) (*entity.Subscription, error)
 	findByTypeAndIdentityFn func(ctx context.Context, subscriptionTypeID uint64, userID, email *string) (*entity.Subscription, error)
-	listFn                  func(ctx context.Context, userID, email string) ([]*entity.Subscription, error)
-	listDueAutoRenewFn      func(ctx context.Context, now time.Time) ([]*entity.Subscription, error)
-	listPendingPaymentFn    func(ctx context.Context, cutoff time.Time) ([]*entity.Subscription, error)
-	listExpiredActiveFn     func(ctx context.Context, now time.Time) ([]*entity.Subscription, error)
+	listFn                  func(ctx context.Context, filter repository.SubscriptionFilter) ([]*entity.Subscription, error)
+	claimDueAutoRenewFn     func(ctx context.Context, now time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error)
+	claimPendingPaymentFn   func(ctx context.Context, cutoff time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error)
+	claimExpiredActiveFn    func(ctx context.Context, now time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error)
+	claimDueResumeFn        func(ctx context.Context, now time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error)
+	releaseClaimsFn         func(ctx context.Context, owner string) error
+	hasUsedTrialFn          func(ctx context.Context, subscriptionTypeID uint64, userID, email *string) (bool, error)
 }
 
 func (m *mockSubscriptionRepo) Create(ctx context.Context, subscription *entity.Subscription) error {
@@ -53,39 +60,76 @@ func (m *mockSubscriptionRepo) FindByTypeAndIdentity(ctx context.Context, subscr
 	return nil, nil
 }
 
-func (m *mockSubscriptionRepo) List(ctx context.Context, userID, email string) ([]*entity.Subscription, error) {
+func (m *mockSubscriptionRepo) List(ctx context.Context, filter repository.SubscriptionFilter) ([]*entity.Subscription, error) {
 	if m.listFn != nil {
-		return m.listFn(ctx, userID, email)
+		return m.listFn(ctx, filter)
 	}
 	return nil, nil
 }
 
-func (m *mockSubscriptionRepo) ListDueAutoRenew(ctx context.Context, now time.Time) ([]*entity.Subscription, error) {
-	if m.listDueAutoRenewFn != nil {
-		return m.listDueAutoRenewFn(ctx, now)
+func (m *mockSubscriptionRepo) ClaimDueAutoRenew(ctx context.Context, now time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+	if m.claimDueAutoRenewFn != nil {
+		return m.claimDueAutoRenewFn(ctx, now, claim)
 	}
 	return nil, nil
 }
 
-func (m *mockSubscriptionRepo) ListPendingPaymentStale(ctx context.Context, cutoff time.Time) ([]*entity.Subscription, error) {
-	if m.listPendingPaymentFn != nil {
-		return m.listPendingPaymentFn(ctx, cutoff)
+func (m *mockSubscriptionRepo) ClaimPendingPaymentStale(ctx context.Context, cutoff time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+	if m.claimPendingPaymentFn != nil {
+		return m.claimPendingPaymentFn(ctx, cutoff, claim)
 	}
 	return nil, nil
 }
 
-func (m *mockSubscriptionRepo) ListExpiredActive(ctx context.Context, now time.Time) ([]*entity.Subscription, error) {
-	if m.listExpiredActiveFn != nil {
-		return m.listExpiredActiveFn(ctx, now)
+func (m *mockSubscriptionRepo) ClaimExpiredActive(ctx context.Context, now time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+	if m.claimExpiredActiveFn != nil {
+		return m.claimExpiredActiveFn(ctx, now, claim)
 	}
 	return nil, nil
 }
 
+func (m *mockSubscriptionRepo) ClaimDueResume(ctx context.Context, now time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+	if m.claimDueResumeFn != nil {
+		return m.claimDueResumeFn(ctx, now, claim)
+	}
+	return nil, nil
+}
+
+func (m *mockSubscriptionRepo) ReleaseClaims(ctx context.Context, owner string) error {
+	if m.releaseClaimsFn != nil {
+		return m.releaseClaimsFn(ctx, owner)
+	}
+	return nil
+}
+
+func (m *mockSubscriptionRepo) HasUsedTrial(ctx context.Context, subscriptionTypeID uint64, userID, email *string) (bool, error) {
+	if m.hasUsedTrialFn != nil {
+		return m.hasUsedTrialFn(ctx, subscriptionTypeID, userID, email)
+	}
+	return false, nil
+}
+
 type mockSubscriptionTypeRepo struct {
+	createFn   func(ctx context.Context, subscriptionType *entity.SubscriptionType) error
+	updateFn   func(ctx context.Context, subscriptionType *entity.SubscriptionType) error
 	listFn     func(ctx context.Context, typeFilter string, hasStatus bool, status int32) ([]*entity.SubscriptionType, error)
 	findByIDFn func(ctx context.Context, id uint64) (*entity.SubscriptionType, error)
 }
 
+func (m *mockSubscriptionTypeRepo) Create(ctx context.Context, subscriptionType *entity.SubscriptionType) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, subscriptionType)
+	}
+	return nil
+}
+
+func (m *mockSubscriptionTypeRepo) Update(ctx context.Context, subscriptionType *entity.SubscriptionType) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, subscriptionType)
+	}
+	return nil
+}
+
 func (m *mockSubscriptionTypeRepo) List(ctx context.Context, typeFilter string, hasStatus bool, status int32) ([]*entity.SubscriptionType, error) {
 	if m.listFn != nil {
 		return m.listFn(ctx, typeFilter, hasStatus, status)
@@ -101,36 +145,422 @@ func (m *mockSubscriptionTypeRepo) FindByID(ctx context.Context, id uint64) (*en
 }
 
 type mockPlanTypeRepo struct {
-	findBySubscriptionTypeIDFn func(ctx context.Context, subscriptionTypeID uint64) (*entity.PlanType, error)
+	createFn   func(ctx context.Context, planType *entity.PlanType) error
+	updateFn   func(ctx context.Context, planType *entity.PlanType) error
+	listFn     func(ctx context.Context, subscriptionTypeID uint64) ([]*entity.PlanType, error)
+	findByIDFn func(ctx context.Context, id uint64) (*entity.PlanType, error)
+}
+
+func (m *mockPlanTypeRepo) Create(ctx context.Context, planType *entity.PlanType) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, planType)
+	}
+	return nil
+}
+
+func (m *mockPlanTypeRepo) Update(ctx context.Context, planType *entity.PlanType) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, planType)
+	}
+	return nil
+}
+
+func (m *mockPlanTypeRepo) List(ctx context.Context, subscriptionTypeID uint64) ([]*entity.PlanType, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, subscriptionTypeID)
+	}
+	return nil, nil
+}
+
+func (m *mockPlanTypeRepo) FindByID(ctx context.Context, id uint64) (*entity.PlanType, error) {
+	if m.findByIDFn != nil {
+		return m.findByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+type mockPaymentAttemptRepo struct {
+	createFn              func(ctx context.Context, attempt *entity.PaymentAttempt) error
+	updateFn              func(ctx context.Context, attempt *entity.PaymentAttempt) error
+	completePendingFn     func(ctx context.Context, attempt *entity.PaymentAttempt) error
+	supersedePendingFn    func(ctx context.Context, subscriptionID uint64, kind string, currentAttemptID uint64, now time.Time) error
+	findByTransactionIDFn func(ctx context.Context, transactionID string) (*entity.PaymentAttempt, error)
+	listFn                func(ctx context.Context, subscriptionID uint64) ([]*entity.PaymentAttempt, error)
+}
+
+func (m *mockPaymentAttemptRepo) Create(ctx context.Context, attempt *entity.PaymentAttempt) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, attempt)
+	}
+	return nil
+}
+
+func (m *mockPaymentAttemptRepo) Update(ctx context.Context, attempt *entity.PaymentAttempt) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, attempt)
+	}
+	return nil
+}
+
+func (m *mockPaymentAttemptRepo) CompletePending(ctx context.Context, attempt *entity.PaymentAttempt) error {
+	if m.completePendingFn != nil {
+		return m.completePendingFn(ctx, attempt)
+	}
+	return nil
+}
+
+func (m *mockPaymentAttemptRepo) SupersedePending(ctx context.Context, subscriptionID uint64, kind string, currentAttemptID uint64, now time.Time) error {
+	if m.supersedePendingFn != nil {
+		return m.supersedePendingFn(ctx, subscriptionID, kind, currentAttemptID, now)
+	}
+	return nil
+}
+
+func (m *mockPaymentAttemptRepo) FindByTransactionID(ctx context.Context, transactionID string) (*entity.PaymentAttempt, error) {
+	if m.findByTransactionIDFn != nil {
+		return m.findByTransactionIDFn(ctx, transactionID)
+	}
+	return nil, nil
+}
+
+func (m *mockPaymentAttemptRepo) ListBySubscriptionID(ctx context.Context, subscriptionID uint64) ([]*entity.PaymentAttempt, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, subscriptionID)
+	}
+	return nil, nil
+}
+
+type mockSubscriptionEventRepo struct {
+	createFn func(ctx context.Context, event *entity.SubscriptionEvent) error
+	listFn   func(ctx context.Context, subscriptionID uint64) ([]*entity.SubscriptionEvent, error)
+}
+
+func (m *mockSubscriptionEventRepo) Create(ctx context.Context, event *entity.SubscriptionEvent) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, event)
+	}
+	return nil
+}
+
+func (m *mockSubscriptionEventRepo) ListBySubscriptionID(ctx context.Context, subscriptionID uint64) ([]*entity.SubscriptionEvent, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, subscriptionID)
+	}
+	return nil, nil
+}
+
+type mockOutboxMessageRepo struct {
+	createFn        func(ctx context.Context, message *entity.OutboxMessage) error
+	updateFn        func(ctx context.Context, message *entity.OutboxMessage) error
+	claimDueFn      func(ctx context.Context, claim repository.OutboxClaim) ([]*entity.OutboxMessage, error)
+	releaseClaimsFn func(ctx context.Context, owner string) error
+}
+
+func (m *mockOutboxMessageRepo) Create(ctx context.Context, message *entity.OutboxMessage) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, message)
+	}
+	return nil
+}
+
+func (m *mockOutboxMessageRepo) Update(ctx context.Context, message *entity.OutboxMessage) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, message)
+	}
+	return nil
+}
+
+func (m *mockOutboxMessageRepo) ClaimDue(ctx context.Context, claim repository.OutboxClaim) ([]*entity.OutboxMessage, error) {
+	if m.claimDueFn != nil {
+		return m.claimDueFn(ctx, claim)
+	}
+	return nil, nil
+}
+
+func (m *mockOutboxMessageRepo) ReleaseClaims(ctx context.Context, owner string) error {
+	if m.releaseClaimsFn != nil {
+		return m.releaseClaimsFn(ctx, owner)
+	}
+	return nil
+}
+
+type mockCouponRepo struct {
+	findByIDFn             func(ctx context.Context, id uint64) (*entity.Coupon, error)
+	findByCodeFn           func(ctx context.Context, code string) (*entity.Coupon, error)
+	incrementRedemptionsFn func(ctx context.Context, id uint64, now time.Time) error
+	decrementRedemptionsFn func(ctx context.Context, id uint64, now time.Time) error
+}
+
+func (m *mockCouponRepo) FindByID(ctx context.Context, id uint64) (*entity.Coupon, error) {
+	if m.findByIDFn != nil {
+		return m.findByIDFn(ctx, id)
+	}
+	return nil, nil
+}
+
+func (m *mockCouponRepo) FindByCode(ctx context.Context, code string) (*entity.Coupon, error) {
+	if m.findByCodeFn != nil {
+		return m.findByCodeFn(ctx, code)
+	}
+	return nil, nil
+}
+
+func (m *mockCouponRepo) IncrementRedemptions(ctx context.Context, id uint64, now time.Time) error {
+	if m.incrementRedemptionsFn != nil {
+		return m.incrementRedemptionsFn(ctx, id, now)
+	}
+	return nil
+}
+
+func (m *mockCouponRepo) DecrementRedemptions(ctx context.Context, id uint64, now time.Time) error {
+	if m.decrementRedemptionsFn != nil {
+		return m.decrementRedemptionsFn(ctx, id, now)
+	}
+	return nil
+}
+
+func (m *mockCouponRepo) Create(context.Context, *entity.Coupon) error {
+	return nil
+}
+
+func (m *mockCouponRepo) Update(context.Context, *entity.Coupon) error {
+	return nil
+}
+
+func (m *mockCouponRepo) List(context.Context) ([]*entity.Coupon, error) {
+	return nil, nil
+}
+
+type mockCouponRedemptionRepo struct {
+	createFn                     func(ctx context.Context, redemption *entity.CouponRedemption) error
+	updateFn                     func(ctx context.Context, redemption *entity.CouponRedemption) error
+	findActiveBySubscriptionIDFn func(ctx context.Context, subscriptionID uint64) (*entity.CouponRedemption, error)
+	endActiveFn                  func(ctx context.Context, subscriptionID uint64, now time.Time) error
+	deleteFn                     func(ctx context.Context, id uint64) error
+}
+
+func (m *mockCouponRedemptionRepo) Create(ctx context.Context, redemption *entity.CouponRedemption) error {
+	if m.createFn != nil {
+		return m.createFn(ctx, redemption)
+	}
+	return nil
+}
+
+func (m *mockCouponRedemptionRepo) Update(ctx context.Context, redemption *entity.CouponRedemption) error {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, redemption)
+	}
+	return nil
+}
+
+func (m *mockCouponRedemptionRepo) FindActiveBySubscriptionID(ctx context.Context, subscriptionID uint64) (*entity.CouponRedemption, error) {
+	if m.findActiveBySubscriptionIDFn != nil {
+		return m.findActiveBySubscriptionIDFn(ctx, subscriptionID)
+	}
+	return nil, nil
+}
+
+func (m *mockCouponRedemptionRepo) EndActive(ctx context.Context, subscriptionID uint64, now time.Time) error {
+	if m.endActiveFn != nil {
+		return m.endActiveFn(ctx, subscriptionID, now)
+	}
+	return nil
+}
+
+func (m *mockCouponRedemptionRepo) Delete(ctx context.Context, id uint64) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, id)
+	}
+	return nil
+}
+
+func (m *mockCouponRedemptionRepo) ListByCouponID(context.Context, uint64) ([]*entity.CouponRedemption, error) {
+	return nil, nil
+}
+
+type mockWebhookEndpointRepo struct {
+	items   map[uint64]*entity.WebhookEndpoint
+	created []*entity.WebhookEndpoint
+}
+
+func (m *mockWebhookEndpointRepo) Create(_ context.Context, endpoint *entity.WebhookEndpoint) error {
+	endpoint.ID = uint64(len(m.created) + 1)
+	m.created = append(m.created, endpoint)
+	return nil
+}
+
+func (m *mockWebhookEndpointRepo) Update(context.Context, *entity.WebhookEndpoint) error {
+	return nil
+}
+
+func (m *mockWebhookEndpointRepo) FindByID(_ context.Context, id uint64) (*entity.WebhookEndpoint, error) {
+	return m.items[id], nil
+}
+
+func (m *mockWebhookEndpointRepo) List(context.Context) ([]*entity.WebhookEndpoint, error) {
+	return nil, nil
+}
+
+func (m *mockWebhookEndpointRepo) ListActive(context.Context) ([]*entity.WebhookEndpoint, error) {
+	items := make([]*entity.WebhookEndpoint, 0, len(m.items))
+	for id := uint64(1); id <= uint64(len(m.items)); id++ {
+		if item := m.items[id]; item != nil && item.Status == entity.WebhookEndpointStatusActive {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (m *mockWebhookEndpointRepo) Delete(context.Context, uint64) error {
+	return nil
+}
+
+type mockWebhookDeliveryRepo struct {
+	mu       sync.Mutex
+	created  []*entity.WebhookDelivery
+	due      []*entity.WebhookDelivery
+	updated  []entity.WebhookDelivery
+	claims   map[uint64]string
+	released []string
+}
+
+func (m *mockWebhookDeliveryRepo) Create(_ context.Context, delivery *entity.WebhookDelivery) error {
+	for _, item := range m.created {
+		if item.WebhookEndpointID == delivery.WebhookEndpointID && item.EventID == delivery.EventID {
+			return repository.ErrWebhookDeliveryAlreadyExists
+		}
+	}
+	m.created = append(m.created, delivery)
+	return nil
+}
+
+func (m *mockWebhookDeliveryRepo) Update(_ context.Context, delivery *entity.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updated = append(m.updated, *delivery)
+	for _, item := range m.due {
+		if item.ID == delivery.ID {
+			*item = *delivery
+		}
+	}
+	return nil
+}
+
+// ClaimDue leases pending deliveries nobody holds, as the repository does, and
+// hands out copies so concurrent workers never share one.
+func (m *mockWebhookDeliveryRepo) ClaimDue(_ context.Context, claim repository.WebhookDeliveryClaim) ([]*entity.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.claims == nil {
+		m.claims = make(map[uint64]string)
+	}
+	leased := 0
+	for _, item := range m.due {
+		if leased == claim.Limit {
+			break
+		}
+		if _, held := m.claims[item.ID]; held || item.Status != entity.WebhookDeliveryStatusPending {
+			continue
+		}
+		m.claims[item.ID] = claim.Owner
+		leased++
+	}
+	items := make([]*entity.WebhookDelivery, 0)
+	for _, item := range m.due {
+		if m.claims[item.ID] == claim.Owner {
+			copied := *item
+			items = append(items, &copied)
+		}
+	}
+	return items, nil
 }
 
-func (m *mockPlanTypeRepo) FindBySubscriptionTypeID(ctx context.Context, subscriptionTypeID uint64) (*entity.PlanType, error) {
-	if m.findBySubscriptionTypeIDFn != nil {
-		return m.findBySubscriptionTypeIDFn(ctx, subscriptionTypeID)
+func (m *mockWebhookDeliveryRepo) ReleaseClaims(_ context.Context, owner string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, held := range m.claims {
+		if held == owner {
+			delete(m.claims, id)
+		}
 	}
+	m.released = append(m.released, owner)
+	return nil
+}
+
+func (m *mockWebhookDeliveryRepo) ListByEndpointID(context.Context, uint64, int) ([]*entity.WebhookDelivery, error) {
 	return nil, nil
 }
 
+type fakeWebhookSender struct {
+	status int
+	err    error
+	sent   []uint64
+}
+
+func (f *fakeWebhookSender) Send(_ context.Context, _ *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (int, error) {
+	f.sent = append(f.sent, delivery.ID)
+	return f.status, f.err
+}
+
+type mockTxManager struct {
+	calls atomic.Int32
+}
+
+func (m *mockTxManager) WithinTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	m.calls.Add(1)
+	return fn(ctx)
+}
+
 type fakePaymentService struct {
 	result      payment.Result
 	panicWith   string
 	calledCount int
+	adjustments []payment.Adjustment
+	charges     []payment.Charge
+}
+
+func (f *fakePaymentService) ProcessSubscriptionPayment(_ context.Context, charge payment.Charge) payment.Result {
+	f.calledCount++
+	f.charges = append(f.charges, charge)
+	if f.panicWith != "" {
+		panic(f.panicWith)
+	}
+	return f.result
 }
 
-func (f *fakePaymentService) ProcessSubscriptionPayment(_ context.Context, _ uint64, _ uint64, _ *string, _ *string) payment.Result {
+func (f *fakePaymentService) ProcessSubscriptionAdjustment(_ context.Context, adjustment payment.Adjustment) payment.Result {
 	f.calledCount++
+	f.adjustments = append(f.adjustments, adjustment)
 	if f.panicWith != "" {
 		panic(f.panicWith)
 	}
 	return f.result
 }
 
+// chargeFuncPaymentService answers charges with chargeFn, which the renewal job
+// may call from several goroutines at once.
+type chargeFuncPaymentService struct {
+	chargeFn func(ctx context.Context, charge payment.Charge) payment.Result
+}
+
+func (f *chargeFuncPaymentService) ProcessSubscriptionPayment(ctx context.Context, charge payment.Charge) payment.Result {
+	return f.chargeFn(ctx, charge)
+}
+
+func (f *chargeFuncPaymentService) ProcessSubscriptionAdjustment(_ context.Context, _ payment.Adjustment) payment.Result {
+	return payment.Result{Type: payment.ResultTypeFailure}
+}
+
 func testConfig() config.SubscriptionConfig {
 	return config.SubscriptionConfig{
 		RenewBeforeEndMinutes:       2 * time.Hour,
 		RenewalRetryIntervalMinutes: 30 * time.Minute,
-		MaxRenewalRetryAgeMinutes:   2 * time.Hour,
+		DunningSchedule:             []time.Duration{time.Hour, 2 * time.Hour},
 		PendingPaymentTimeout:       10 * time.Minute,
+		WorkerID:                    "worker-1",
+		ClaimTTL:                    15 * time.Minute,
+		ClaimBatchSize:              50,
+		BatchMaxPerRun:              200,
 	}
 }
 
@@ -167,6 +597,12 @@ func TestListSubscriptionTypesRejectsInvalidStatus(t *testing.T) {
 		&mockSubscriptionRepo{},
 		&mockSubscriptionTypeRepo{},
 		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		&fakePaymentService{},
 		testConfig(),
 	)
@@ -182,6 +618,12 @@ func TestCreateSubscriptionRequiresIdentity(t *testing.T) {
 		&mockSubscriptionRepo{},
 		&mockSubscriptionTypeRepo{},
 		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		&fakePaymentService{},
 		testConfig(),
 	)
@@ -201,6 +643,12 @@ func TestCreateSubscriptionTypeNotFound(t *testing.T) {
 			},
 		},
 		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		&fakePaymentService{},
 		testConfig(),
 	)
@@ -239,6 +687,12 @@ func TestCreateEmailSubscriptionSuccess(t *testing.T) {
 			},
 		},
 		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		paymentSvc,
 		testConfig(),
 	)
@@ -284,10 +738,16 @@ func TestCreatePlanSubscriptionRequiresStartAt(t *testing.T) {
 			},
 		},
 		&mockPlanTypeRepo{
-			findBySubscriptionTypeIDFn: func(_ context.Context, _ uint64) (*entity.PlanType, error) {
-				return &entity.PlanType{ID: 10, SubscriptionTypeID: 2, DurationDays: 30}, nil
+			listFn: func(_ context.Context, _ uint64) ([]*entity.PlanType, error) {
+				return []*entity.PlanType{{ID: 10, SubscriptionTypeID: 2, Status: entity.PlanTypeStatusActive, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}}, nil
 			},
 		},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		&fakePaymentService{},
 		testConfig(),
 	)
@@ -319,9 +779,15 @@ func TestCreatePlanSubscriptionPaymentRedirect(t *testing.T) {
 		&mockSubscriptionTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.SubscriptionType, error) {
 			return &entity.SubscriptionType{ID: 2, Status: 10, Type: "plan"}, nil
 		}},
-		&mockPlanTypeRepo{findBySubscriptionTypeIDFn: func(_ context.Context, _ uint64) (*entity.PlanType, error) {
-			return &entity.PlanType{ID: 20, SubscriptionTypeID: 2, DurationDays: 30}, nil
+		&mockPlanTypeRepo{listFn: func(_ context.Context, _ uint64) ([]*entity.PlanType, error) {
+			return []*entity.PlanType{{ID: 20, SubscriptionTypeID: 2, Status: entity.PlanTypeStatusActive, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}}, nil
 		}},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		paymentSvc,
 		testConfig(),
 	)
@@ -350,35 +816,166 @@ func TestCreatePlanSubscriptionPaymentRedirect(t *testing.T) {
 	}
 }
 
-func TestCreateSubscriptionMapsDuplicateError(t *testing.T) {
-	svc := NewSubscriptionService(
-		&mockSubscriptionRepo{
-			findByTypeAndIdentityFn: func(_ context.Context, _ uint64, _ *string, _ *string) (*entity.Subscription, error) {
-				return nil, nil
-			},
-			createFn: func(_ context.Context, _ *entity.Subscription) error {
-				return repository.ErrSubscriptionAlreadyExists
-			},
-		},
-		&mockSubscriptionTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.SubscriptionType, error) {
-			return &entity.SubscriptionType{ID: 1, Status: 10, Type: "email"}, nil
-		}},
-		&mockPlanTypeRepo{},
-		&fakePaymentService{},
-		testConfig(),
-	)
+func TestCreatePlanSubscriptionResolvesPlanType(t *testing.T) {
+	plans := []*entity.PlanType{
+		{ID: 20, SubscriptionTypeID: 2, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30, Status: entity.PlanTypeStatusActive},
+		{ID: 21, SubscriptionTypeID: 2, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 365, Status: entity.PlanTypeStatusActive},
+		{ID: 22, SubscriptionTypeID: 2, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30, Status: entity.PlanTypeStatusArchived},
+	}
+	newService := func(created **entity.Subscription) *SubscriptionService {
+		return NewSubscriptionService(
+			&mockSubscriptionRepo{createFn: func(_ context.Context, subscription *entity.Subscription) error {
+				subscription.ID = 103
+				*created = copySubscription(subscription)
+				return nil
+			}},
+			&mockSubscriptionTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.SubscriptionType, error) {
+				return &entity.SubscriptionType{ID: 2, Status: 10, Type: "plan"}, nil
+			}},
+			&mockPlanTypeRepo{listFn: func(_ context.Context, _ uint64) ([]*entity.PlanType, error) {
+				return plans, nil
+			}},
+			&mockPaymentAttemptRepo{},
+			&mockSubscriptionEventRepo{},
+			&mockOutboxMessageRepo{},
+			&mockCouponRepo{},
+			&mockCouponRedemptionRepo{},
+			&mockTxManager{},
+			&fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}},
+			testConfig(),
+		)
+	}
+	start := time.Now().UTC().Add(2 * time.Hour).Format(time.RFC3339)
 
-	_, err := svc.CreateSubscription(context.Background(), &types.CreateSubscriptionRequest{SubscriptionTypeId: 1, UserId: "u-1"})
-	if !errors.Is(err, ErrSubscriptionAlreadyExists) {
-		t.Fatalf("expected ErrSubscriptionAlreadyExists, got %v", err)
+	var created *entity.Subscription
+	_, err := newService(&created).CreateSubscription(context.Background(), &types.CreateSubscriptionRequest{SubscriptionTypeId: 2, UserId: "u-1", StartAt: start})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest without plan_type_id, got %v", err)
+	}
+
+	_, err = newService(&created).CreateSubscription(context.Background(), &types.CreateSubscriptionRequest{SubscriptionTypeId: 2, PlanTypeId: 99, UserId: "u-1", StartAt: start})
+	if !errors.Is(err, ErrPlanTypeNotFound) {
+		t.Fatalf("expected ErrPlanTypeNotFound for a plan of another type, got %v", err)
+	}
+
+	_, err = newService(&created).CreateSubscription(context.Background(), &types.CreateSubscriptionRequest{SubscriptionTypeId: 2, PlanTypeId: 22, UserId: "u-1", StartAt: start})
+	if !errors.Is(err, ErrPlanTypeNotFound) {
+		t.Fatalf("expected ErrPlanTypeNotFound for an archived plan, got %v", err)
+	}
+
+	_, err = newService(&created).CreateSubscription(context.Background(), &types.CreateSubscriptionRequest{SubscriptionTypeId: 2, PlanTypeId: 21, UserId: "u-1", StartAt: start})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if created == nil || created.PlanTypeID == nil || *created.PlanTypeID != 21 {
+		t.Fatalf("expected subscription on plan 21, got %+v", created)
 	}
 }
 
-func TestCreateSubscriptionPaymentPanicIsHandled(t *testing.T) {
+func TestCreatePlanSubscriptionRecordsPaymentAttempt(t *testing.T) {
+	var created, updated *entity.PaymentAttempt
+	var supersededFor, supersededExcept uint64
+	var supersededKind string
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "ch_1"}}
 	svc := NewSubscriptionService(
 		&mockSubscriptionRepo{
-			findByTypeAndIdentityFn: func(_ context.Context, _ uint64, _ *string, _ *string) (*entity.Subscription, error) {
-				return nil, nil
+			createFn: func(_ context.Context, subscription *entity.Subscription) error {
+				subscription.ID = 102
+				return nil
+			},
+		},
+		&mockSubscriptionTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.SubscriptionType, error) {
+			return &entity.SubscriptionType{ID: 2, Status: 10, Type: "plan"}, nil
+		}},
+		&mockPlanTypeRepo{listFn: func(_ context.Context, _ uint64) ([]*entity.PlanType, error) {
+			return []*entity.PlanType{{ID: 20, SubscriptionTypeID: 2, Status: entity.PlanTypeStatusActive, PriceCents: 1500, Currency: "USD", IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}}, nil
+		}},
+		&mockPaymentAttemptRepo{
+			createFn: func(_ context.Context, attempt *entity.PaymentAttempt) error {
+				attempt.ID = 5
+				cp := *attempt
+				created = &cp
+				return nil
+			},
+			updateFn: func(_ context.Context, attempt *entity.PaymentAttempt) error {
+				cp := *attempt
+				updated = &cp
+				return nil
+			},
+			supersedePendingFn: func(_ context.Context, subscriptionID uint64, kind string, currentAttemptID uint64, _ time.Time) error {
+				supersededFor, supersededKind, supersededExcept = subscriptionID, kind, currentAttemptID
+				return nil
+			},
+		},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		paySvc,
+		testConfig(),
+	)
+
+	_, err := svc.CreateSubscription(context.Background(), &types.CreateSubscriptionRequest{
+		SubscriptionTypeId: 2,
+		UserId:             "u-1",
+		StartAt:            time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(paySvc.charges) != 1 || paySvc.charges[0].IdempotencyKey != "attempt-5" {
+		t.Fatalf("expected the charge to be keyed to the attempt, got %+v", paySvc.charges)
+	}
+	if created == nil || created.SubscriptionID != 102 || created.Status != entity.PaymentAttemptStatusPending ||
+		created.AmountCents != 1500 || created.Currency != "USD" {
+		t.Fatalf("unexpected created attempt: %+v", created)
+	}
+	if supersededFor != 102 || supersededKind != entity.PaymentAttemptKindInitial || supersededExcept != 5 {
+		t.Fatalf("expected older pending initial attempts of 102 to be superseded, got subscription=%d kind=%q except=%d", supersededFor, supersededKind, supersededExcept)
+	}
+	if updated == nil || updated.ID != 5 || updated.Status != entity.PaymentAttemptStatusSucceeded ||
+		updated.ResultType != string(payment.ResultTypeSuccess) || updated.ProviderTransactionID == nil ||
+		*updated.ProviderTransactionID != "ch_1" || updated.CompletedAt == nil {
+		t.Fatalf("unexpected completed attempt: %+v", updated)
+	}
+}
+
+func TestCreateSubscriptionMapsDuplicateError(t *testing.T) {
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{
+			findByTypeAndIdentityFn: func(_ context.Context, _ uint64, _ *string, _ *string) (*entity.Subscription, error) {
+				return nil, nil
+			},
+			createFn: func(_ context.Context, _ *entity.Subscription) error {
+				return repository.ErrSubscriptionAlreadyExists
+			},
+		},
+		&mockSubscriptionTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.SubscriptionType, error) {
+			return &entity.SubscriptionType{ID: 1, Status: 10, Type: "email"}, nil
+		}},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	_, err := svc.CreateSubscription(context.Background(), &types.CreateSubscriptionRequest{SubscriptionTypeId: 1, UserId: "u-1"})
+	if !errors.Is(err, ErrSubscriptionAlreadyExists) {
+		t.Fatalf("expected ErrSubscriptionAlreadyExists, got %v", err)
+	}
+}
+
+func TestCreateSubscriptionPaymentPanicIsHandled(t *testing.T) {
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{
+			findByTypeAndIdentityFn: func(_ context.Context, _ uint64, _ *string, _ *string) (*entity.Subscription, error) {
+				return nil, nil
 			},
 			createFn: func(_ context.Context, subscription *entity.Subscription) error {
 				subscription.ID = 9
@@ -388,9 +985,15 @@ func TestCreateSubscriptionPaymentPanicIsHandled(t *testing.T) {
 		&mockSubscriptionTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.SubscriptionType, error) {
 			return &entity.SubscriptionType{ID: 2, Status: 10, Type: "plan"}, nil
 		}},
-		&mockPlanTypeRepo{findBySubscriptionTypeIDFn: func(_ context.Context, _ uint64) (*entity.PlanType, error) {
-			return &entity.PlanType{ID: 4, SubscriptionTypeID: 2, DurationDays: 30}, nil
+		&mockPlanTypeRepo{listFn: func(_ context.Context, _ uint64) ([]*entity.PlanType, error) {
+			return []*entity.PlanType{{ID: 4, SubscriptionTypeID: 2, Status: entity.PlanTypeStatusActive, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}}, nil
 		}},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		&fakePaymentService{panicWith: "payments for renewals are not implemented"},
 		testConfig(),
 	)
@@ -412,6 +1015,12 @@ func TestUpdateSubscriptionRejectsInvalidStatus(t *testing.T) {
 		}},
 		&mockSubscriptionTypeRepo{},
 		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		&fakePaymentService{},
 		testConfig(),
 	)
@@ -422,6 +1031,61 @@ func TestUpdateSubscriptionRejectsInvalidStatus(t *testing.T) {
 	}
 }
 
+func TestUpdateSubscriptionRejectsPaymentDrivenStatus(t *testing.T) {
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{
+			findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+				return &entity.Subscription{ID: 1, Status: entity.SubscriptionStatusInactive}, nil
+			},
+			updateFn: func(context.Context, *entity.Subscription) error {
+				t.Fatal("expected the subscription to be left untouched")
+				return nil
+			},
+		},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	_, err := svc.UpdateSubscription(context.Background(), &types.UpdateSubscriptionRequest{Id: 1, HasStatus: true, Status: entity.SubscriptionStatusActive})
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+}
+
+func TestTransitionSubscriptionStatus(t *testing.T) {
+	cases := []struct {
+		from, to int32
+		allowed  bool
+	}{
+		{entity.SubscriptionStatusInactive, entity.SubscriptionStatusProcessing, true},
+		{entity.SubscriptionStatusInactive, entity.SubscriptionStatusActive, false},
+		{entity.SubscriptionStatusInactive, entity.SubscriptionStatusPendingPayment, false},
+		{entity.SubscriptionStatusProcessing, entity.SubscriptionStatusPendingPayment, true},
+		{entity.SubscriptionStatusPendingPayment, entity.SubscriptionStatusActive, true},
+		{entity.SubscriptionStatusActive, entity.SubscriptionStatusPendingPayment, false},
+		{entity.SubscriptionStatusActive, entity.SubscriptionStatusInactive, true},
+		{entity.SubscriptionStatusInactive, entity.SubscriptionStatusInactive, true},
+	}
+	for _, tc := range cases {
+		item := &entity.Subscription{Status: tc.from}
+		err := transitionSubscriptionStatus(item, tc.to)
+		if tc.allowed && (err != nil || item.Status != tc.to) {
+			t.Fatalf("expected %d -> %d to be allowed, got %v", tc.from, tc.to, err)
+		}
+		if !tc.allowed && (!errors.Is(err, ErrInvalidTransition) || item.Status != tc.from) {
+			t.Fatalf("expected %d -> %d to be rejected, got %v", tc.from, tc.to, err)
+		}
+	}
+}
+
 func TestDeleteSubscriptionSoftDeletes(t *testing.T) {
 	var updated *entity.Subscription
 	svc := NewSubscriptionService(
@@ -437,11 +1101,17 @@ func TestDeleteSubscriptionSoftDeletes(t *testing.T) {
 		},
 		&mockSubscriptionTypeRepo{},
 		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		&fakePaymentService{},
 		testConfig(),
 	)
 
-	item, err := svc.DeleteSubscription(context.Background(), 3)
+	item, err := svc.DeleteSubscription(context.Background(), &types.DeleteSubscriptionRequest{Id: 3})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
@@ -453,34 +1123,300 @@ func TestDeleteSubscriptionSoftDeletes(t *testing.T) {
 	}
 }
 
+func pendingAttemptRepo(subscriptionID uint64, status int32) *mockPaymentAttemptRepo {
+	return &mockPaymentAttemptRepo{
+		findByTransactionIDFn: func(_ context.Context, transactionID string) (*entity.PaymentAttempt, error) {
+			return &entity.PaymentAttempt{
+				ID:                    8,
+				SubscriptionID:        subscriptionID,
+				PlanTypeID:            20,
+				Kind:                  entity.PaymentAttemptKindInitial,
+				ProviderTransactionID: &transactionID,
+				ResultType:            string(payment.ResultTypeRedirect),
+				Status:                status,
+			}, nil
+		},
+	}
+}
+
 func TestPaymentCallbackFailedSetsRetry(t *testing.T) {
 	var updated *entity.Subscription
+	var completed *entity.PaymentAttempt
 	repo := &mockSubscriptionRepo{
 		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
-			return &entity.Subscription{ID: 4, Status: entity.SubscriptionStatusPendingPayment}, nil
+			return &entity.Subscription{ID: 4, SubscriptionTypeID: 2, Status: entity.SubscriptionStatusPendingPayment}, nil
 		},
 		updateFn: func(_ context.Context, subscription *entity.Subscription) error {
 			updated = copySubscription(subscription)
 			return nil
 		},
 	}
-	svc := NewPaymentCallbackService(repo, testConfig())
+	attemptRepo := pendingAttemptRepo(4, entity.PaymentAttemptStatusPending)
+	attemptRepo.completePendingFn = func(_ context.Context, attempt *entity.PaymentAttempt) error {
+		cp := *attempt
+		completed = &cp
+		return nil
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, attemptRepo, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
 
-	err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "failed", TransactionId: "tx-1"})
+	res, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "failed", TransactionId: "tx-1"})
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
+	if res.AlreadyProcessed {
+		t.Fatal("expected the callback to be applied")
+	}
 	if updated == nil || updated.Status != entity.SubscriptionStatusProcessing || updated.RenewAt == nil {
 		t.Fatalf("unexpected updated state: %+v", updated)
 	}
+	if completed == nil || completed.ID != 8 || completed.Status != entity.PaymentAttemptStatusFailed || completed.CompletedAt == nil {
+		t.Fatalf("unexpected completed attempt: %+v", completed)
+	}
+}
+
+func TestPaymentCallbackRenewalExtendsPeriod(t *testing.T) {
+	endAt := time.Now().UTC().Add(-time.Hour)
+	var updated *entity.Subscription
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4, SubscriptionTypeID: 2, Status: entity.SubscriptionStatusPendingPayment, AutoRenew: true, EndAt: &endAt}, nil
+		},
+		updateFn: func(_ context.Context, subscription *entity.Subscription) error {
+			updated = copySubscription(subscription)
+			return nil
+		},
+	}
+	planRepo := &mockPlanTypeRepo{findByIDFn: func(_ context.Context, id uint64) (*entity.PlanType, error) {
+		if id != 20 {
+			t.Fatalf("expected the charged plan to be loaded, got %d", id)
+		}
+		return &entity.PlanType{ID: 20, SubscriptionTypeID: 2, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}, nil
+	}}
+	attemptRepo := pendingAttemptRepo(4, entity.PaymentAttemptStatusPending)
+	findAttempt := attemptRepo.findByTransactionIDFn
+	attemptRepo.findByTransactionIDFn = func(ctx context.Context, transactionID string) (*entity.PaymentAttempt, error) {
+		attempt, err := findAttempt(ctx, transactionID)
+		attempt.Kind = entity.PaymentAttemptKindRenewal
+		return attempt, err
+	}
+	svc := NewPaymentCallbackService(repo, planRepo, attemptRepo, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
+
+	if _, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "tx-1"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expectedEnd := endAt.Add(30 * 24 * time.Hour)
+	if updated == nil || updated.Status != entity.SubscriptionStatusActive || updated.EndAt == nil || !updated.EndAt.Equal(expectedEnd) {
+		t.Fatalf("expected active subscription ending at %v, got %+v", expectedEnd, updated)
+	}
+	if updated.RenewAt == nil || !updated.RenewAt.Equal(expectedEnd.Add(-testConfig().RenewBeforeEndMinutes)) {
+		t.Fatalf("expected renew_at before the new end, got %v", updated.RenewAt)
+	}
+}
+
+func TestPaymentCallbackRenewalFailureEntersDunning(t *testing.T) {
+	endAt := time.Now().UTC().Add(-time.Hour)
+	renewAt := endAt.Add(2 * time.Hour)
+	var updated *entity.Subscription
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4, SubscriptionTypeID: 2, Status: entity.SubscriptionStatusPendingPayment, AutoRenew: true, EndAt: &endAt, RenewAt: &renewAt, RenewalRetryCount: 2}, nil
+		},
+		updateFn: func(_ context.Context, subscription *entity.Subscription) error {
+			updated = copySubscription(subscription)
+			return nil
+		},
+	}
+	attemptRepo := pendingAttemptRepo(4, entity.PaymentAttemptStatusPending)
+	findAttempt := attemptRepo.findByTransactionIDFn
+	attemptRepo.findByTransactionIDFn = func(ctx context.Context, transactionID string) (*entity.PaymentAttempt, error) {
+		attempt, err := findAttempt(ctx, transactionID)
+		attempt.Kind = entity.PaymentAttemptKindRenewal
+		return attempt, err
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, attemptRepo, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
+
+	if _, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "failed", TransactionId: "tx-1"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated == nil || updated.Status != entity.SubscriptionStatusPastDue || !updated.RenewAt.Equal(renewAt) {
+		t.Fatalf("expected the subscription to wait past due for the scheduled retry, got %+v", updated)
+	}
+}
+
+func TestPaymentCallbackInitialKeepsPeriod(t *testing.T) {
+	endAt := time.Now().UTC().Add(30 * 24 * time.Hour)
+	var updated *entity.Subscription
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4, SubscriptionTypeID: 2, Status: entity.SubscriptionStatusPendingPayment, EndAt: &endAt}, nil
+		},
+		updateFn: func(_ context.Context, subscription *entity.Subscription) error {
+			updated = copySubscription(subscription)
+			return nil
+		},
+	}
+	planRepo := &mockPlanTypeRepo{findByIDFn: func(context.Context, uint64) (*entity.PlanType, error) {
+		t.Fatal("expected no plan lookup for an initial purchase")
+		return nil, nil
+	}}
+	svc := NewPaymentCallbackService(repo, planRepo, pendingAttemptRepo(4, entity.PaymentAttemptStatusPending), &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
+
+	if _, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "tx-1"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated == nil || updated.Status != entity.SubscriptionStatusActive || updated.EndAt == nil || !updated.EndAt.Equal(endAt) {
+		t.Fatalf("expected activation without extending the period, got %+v", updated)
+	}
+}
+
+func TestPaymentCallbackRejectsInactiveSubscription(t *testing.T) {
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4, Status: entity.SubscriptionStatusInactive}, nil
+		},
+	}
+	attemptRepo := pendingAttemptRepo(4, entity.PaymentAttemptStatusPending)
+	attemptRepo.completePendingFn = func(context.Context, *entity.PaymentAttempt) error {
+		t.Fatal("expected the attempt to stay pending")
+		return nil
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, attemptRepo, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
+
+	_, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "tx-1"})
+	if !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition, got %v", err)
+	}
+}
+
+func TestPaymentCallbackDuplicateIsAlreadyProcessed(t *testing.T) {
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4, Status: entity.SubscriptionStatusActive}, nil
+		},
+		updateFn: func(context.Context, *entity.Subscription) error {
+			t.Fatal("expected the subscription to be left untouched")
+			return nil
+		},
+	}
+	attemptRepo := pendingAttemptRepo(4, entity.PaymentAttemptStatusSucceeded)
+	attemptRepo.completePendingFn = func(context.Context, *entity.PaymentAttempt) error {
+		t.Fatal("expected the completed attempt to be left untouched")
+		return nil
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, attemptRepo, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
+
+	res, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "failed", TransactionId: "ch_1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !res.AlreadyProcessed || res.Subscription.Status != entity.SubscriptionStatusActive {
+		t.Fatalf("expected already processed with unchanged subscription, got %+v", res)
+	}
+}
+
+func TestPaymentCallbackConcurrentDeliveryIsAlreadyProcessed(t *testing.T) {
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4, Status: entity.SubscriptionStatusPendingPayment}, nil
+		},
+		updateFn: func(context.Context, *entity.Subscription) error {
+			t.Fatal("expected the losing delivery not to update the subscription")
+			return nil
+		},
+	}
+	attemptRepo := pendingAttemptRepo(4, entity.PaymentAttemptStatusPending)
+	attemptRepo.completePendingFn = func(context.Context, *entity.PaymentAttempt) error {
+		return repository.ErrPaymentAttemptNotPending
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, attemptRepo, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
+
+	res, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "ch_1"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !res.AlreadyProcessed {
+		t.Fatalf("expected already processed, got %+v", res)
+	}
+}
+
+func TestPaymentCallbackCompletesAttemptWithSubscriptionChange(t *testing.T) {
+	tx := &mockTxManager{}
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4, Status: entity.SubscriptionStatusPendingPayment}, nil
+		},
+		updateFn: func(context.Context, *entity.Subscription) error {
+			return errors.New("database unavailable")
+		},
+	}
+	attemptRepo := pendingAttemptRepo(4, entity.PaymentAttemptStatusPending)
+	attemptRepo.completePendingFn = func(context.Context, *entity.PaymentAttempt) error {
+		if tx.calls.Load() == 0 {
+			t.Fatal("expected the attempt to be completed inside the transaction")
+		}
+		return nil
+	}
+	attemptRepo.updateFn = func(context.Context, *entity.PaymentAttempt) error {
+		t.Fatal("expected the transaction, not a compensating write, to keep the attempt pending")
+		return nil
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, attemptRepo, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, tx, testConfig())
+
+	if _, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "ch_1"}); err == nil {
+		t.Fatal("expected the failed write to be reported so the provider retries")
+	}
+}
+
+func TestPaymentCallbackRejectsSupersededAttempt(t *testing.T) {
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4, Status: entity.SubscriptionStatusPendingPayment}, nil
+		},
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, pendingAttemptRepo(4, entity.PaymentAttemptStatusSuperseded), &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
+
+	_, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "cs_1"})
+	if !errors.Is(err, ErrPaymentAttemptSuperseded) {
+		t.Fatalf("expected ErrPaymentAttemptSuperseded, got %v", err)
+	}
+}
+
+func TestPaymentCallbackUnknownTransaction(t *testing.T) {
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4}, nil
+		},
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, &mockPaymentAttemptRepo{}, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
+
+	_, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "ch_404"})
+	if !errors.Is(err, ErrPaymentAttemptNotFound) {
+		t.Fatalf("expected ErrPaymentAttemptNotFound, got %v", err)
+	}
+}
+
+func TestPaymentCallbackRejectsForeignTransaction(t *testing.T) {
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4, SubscriptionTypeID: 2}, nil
+		},
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, pendingAttemptRepo(5, entity.PaymentAttemptStatusPending), &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
+
+	_, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "ch_1"})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest, got %v", err)
+	}
 }
 
 func TestRunAutoRenewalBatchSuccess(t *testing.T) {
+	planTypeID := uint64(20)
 	endAt := time.Now().UTC().Add(24 * time.Hour)
 	renewAt := time.Now().UTC().Add(-2 * time.Minute)
 	item := &entity.Subscription{
 		ID:                 11,
 		SubscriptionTypeID: 2,
+		PlanTypeID:         &planTypeID,
 		Status:             entity.SubscriptionStatusActive,
 		AutoRenew:          true,
 		EndAt:              &endAt,
@@ -488,9 +1424,10 @@ func TestRunAutoRenewalBatchSuccess(t *testing.T) {
 	}
 
 	var updates []*entity.Subscription
+	var kind string
 	svc := NewSubscriptionService(
 		&mockSubscriptionRepo{
-			listDueAutoRenewFn: func(_ context.Context, _ time.Time) ([]*entity.Subscription, error) {
+			claimDueAutoRenewFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
 				return []*entity.Subscription{item}, nil
 			},
 			updateFn: func(_ context.Context, subscription *entity.Subscription) error {
@@ -499,17 +1436,29 @@ func TestRunAutoRenewalBatchSuccess(t *testing.T) {
 			},
 		},
 		&mockSubscriptionTypeRepo{},
-		&mockPlanTypeRepo{findBySubscriptionTypeIDFn: func(_ context.Context, _ uint64) (*entity.PlanType, error) {
-			return &entity.PlanType{ID: 20, SubscriptionTypeID: 2, DurationDays: 30}, nil
+		&mockPlanTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.PlanType, error) {
+			return &entity.PlanType{ID: 20, SubscriptionTypeID: 2, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}, nil
+		}},
+		&mockPaymentAttemptRepo{createFn: func(_ context.Context, attempt *entity.PaymentAttempt) error {
+			kind = attempt.Kind
+			return nil
 		}},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		&fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}},
 		testConfig(),
 	)
 
-	err := svc.RunAutoRenewalBatch(context.Background())
+	_, err := svc.RunAutoRenewalBatch(context.Background())
 	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
+	if kind != entity.PaymentAttemptKindRenewal {
+		t.Fatalf("expected a renewal attempt, got %q", kind)
+	}
 	if len(updates) < 2 {
 		t.Fatalf("expected at least two updates, got %d", len(updates))
 	}
@@ -525,25 +1474,25 @@ func TestRunAutoRenewalBatchSuccess(t *testing.T) {
 	}
 }
 
-func TestRunAutoRenewalBatchDisablesExpiredRetries(t *testing.T) {
-	endAt := time.Now().UTC().Add(-72 * time.Hour)
-	renewAt := time.Now().UTC().Add(-10 * time.Minute)
+func TestRunAutoRenewalBatchEntersDunning(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(time.Hour)
+	renewAt := time.Now().UTC().Add(-time.Minute)
 	item := &entity.Subscription{
 		ID:                 12,
 		SubscriptionTypeID: 2,
+		PlanTypeID:         &planTypeID,
 		Status:             entity.SubscriptionStatusActive,
 		AutoRenew:          true,
 		EndAt:              &endAt,
 		RenewAt:            &renewAt,
 	}
 
-	cfg := testConfig()
-	cfg.MaxRenewalRetryAgeMinutes = 30 * time.Minute
-
 	var final *entity.Subscription
+	var messages []*entity.OutboxMessage
 	svc := NewSubscriptionService(
 		&mockSubscriptionRepo{
-			listDueAutoRenewFn: func(_ context.Context, _ time.Time) ([]*entity.Subscription, error) {
+			claimDueAutoRenewFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
 				return []*entity.Subscription{item}, nil
 			},
 			updateFn: func(_ context.Context, subscription *entity.Subscription) error {
@@ -552,60 +1501,173 @@ func TestRunAutoRenewalBatchDisablesExpiredRetries(t *testing.T) {
 			},
 		},
 		&mockSubscriptionTypeRepo{},
-		&mockPlanTypeRepo{findBySubscriptionTypeIDFn: func(_ context.Context, _ uint64) (*entity.PlanType, error) {
-			return &entity.PlanType{ID: 20, SubscriptionTypeID: 2, DurationDays: 30}, nil
+		&mockPlanTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.PlanType, error) {
+			return &entity.PlanType{ID: 20, SubscriptionTypeID: 2, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}, nil
 		}},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{createFn: func(_ context.Context, message *entity.OutboxMessage) error {
+			messages = append(messages, message)
+			return nil
+		}},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		&fakePaymentService{result: payment.Result{Type: payment.ResultTypeFailure}},
-		cfg,
+		testConfig(),
 	)
 
-	err := svc.RunAutoRenewalBatch(context.Background())
-	if err != nil {
+	if _, err := svc.RunAutoRenewalBatch(context.Background()); err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if final == nil {
-		t.Fatal("expected final update")
+	if final == nil || final.Status != entity.SubscriptionStatusPastDue || final.RenewalRetryCount != 1 || !final.AutoRenew {
+		t.Fatalf("expected a past-due subscription with one retry, got %+v", final)
 	}
-	if final.Status != entity.SubscriptionStatusInactive || final.AutoRenew || final.RenewAt != nil {
-		t.Fatalf("expected subscription to be inactivated after max retry age, got %+v", final)
+	if !final.EndAt.Equal(endAt) || final.RenewAt == nil || !final.RenewAt.Equal(endAt.Add(time.Hour)) {
+		t.Fatalf("expected the first retry an hour after end_at, got %+v", final)
+	}
+	if len(messages) != 1 || messages[0].EventType != entity.OutboxEventSubscriptionPastDue {
+		t.Fatalf("expected a past_due event, got %+v", messages)
 	}
 }
 
-func TestRunPendingCleanupBatch(t *testing.T) {
-	item := &entity.Subscription{ID: 22, Status: entity.SubscriptionStatusPendingPayment}
-	var updated *entity.Subscription
+func TestRunAutoRenewalBatchExhaustsDunning(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(-3 * time.Hour)
+	renewAt := endAt.Add(2 * time.Hour)
+	item := &entity.Subscription{
+		ID:                 12,
+		SubscriptionTypeID: 2,
+		PlanTypeID:         &planTypeID,
+		Status:             entity.SubscriptionStatusPastDue,
+		AutoRenew:          true,
+		EndAt:              &endAt,
+		RenewAt:            &renewAt,
+		RenewalRetryCount:  2,
+	}
 
+	var final *entity.Subscription
+	var reason string
 	svc := NewSubscriptionService(
 		&mockSubscriptionRepo{
-			listPendingPaymentFn: func(_ context.Context, _ time.Time) ([]*entity.Subscription, error) {
+			claimDueAutoRenewFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
 				return []*entity.Subscription{item}, nil
 			},
 			updateFn: func(_ context.Context, subscription *entity.Subscription) error {
-				updated = copySubscription(subscription)
+				final = copySubscription(subscription)
 				return nil
 			},
 		},
 		&mockSubscriptionTypeRepo{},
-		&mockPlanTypeRepo{},
-		&fakePaymentService{},
+		&mockPlanTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.PlanType, error) {
+			return &entity.PlanType{ID: 20, SubscriptionTypeID: 2, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}, nil
+		}},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{createFn: func(_ context.Context, event *entity.SubscriptionEvent) error {
+			reason = event.Reason
+			return nil
+		}},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{result: payment.Result{Type: payment.ResultTypeFailure}},
 		testConfig(),
 	)
 
-	if err := svc.RunPendingPaymentCleanupBatch(context.Background()); err != nil {
+	_, err := svc.RunAutoRenewalBatch(context.Background())
+	if err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
-	if updated == nil || updated.Status != entity.SubscriptionStatusProcessing || updated.RenewAt == nil {
-		t.Fatalf("unexpected updated item: %+v", updated)
-	}
+	if final == nil {
+		t.Fatal("expected final update")
+	}
+	if final.Status != entity.SubscriptionStatusInactive || final.AutoRenew || final.RenewAt != nil {
+		t.Fatalf("expected subscription to be inactivated once dunning is exhausted, got %+v", final)
+	}
+	if reason != eventReasonRenewalRetriesExhausted {
+		t.Fatalf("expected %q reason, got %q", eventReasonRenewalRetriesExhausted, reason)
+	}
+}
+
+func TestRunPendingCleanupBatchResumesDunning(t *testing.T) {
+	endAt := time.Now().UTC().Add(-30 * time.Minute)
+	renewAt := endAt.Add(time.Hour)
+	item := &entity.Subscription{ID: 23, Status: entity.SubscriptionStatusPendingPayment, AutoRenew: true, EndAt: &endAt, RenewAt: &renewAt, RenewalRetryCount: 1}
+	var updated *entity.Subscription
+
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{
+			claimPendingPaymentFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+				return []*entity.Subscription{item}, nil
+			},
+			updateFn: func(_ context.Context, subscription *entity.Subscription) error {
+				updated = copySubscription(subscription)
+				return nil
+			},
+		},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	if _, err := svc.RunPendingPaymentCleanupBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated == nil || updated.Status != entity.SubscriptionStatusPastDue || !updated.RenewAt.Equal(renewAt) || updated.RenewalRetryCount != 1 {
+		t.Fatalf("expected the abandoned renewal checkout to go past due, got %+v", updated)
+	}
+}
+
+func TestRunPendingCleanupBatch(t *testing.T) {
+	item := &entity.Subscription{ID: 22, Status: entity.SubscriptionStatusPendingPayment}
+	var updated *entity.Subscription
+
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{
+			claimPendingPaymentFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+				return []*entity.Subscription{item}, nil
+			},
+			updateFn: func(_ context.Context, subscription *entity.Subscription) error {
+				updated = copySubscription(subscription)
+				return nil
+			},
+		},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	if _, err := svc.RunPendingPaymentCleanupBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated == nil || updated.Status != entity.SubscriptionStatusProcessing || updated.RenewAt == nil {
+		t.Fatalf("unexpected updated item: %+v", updated)
+	}
 }
 
 func TestRunExpirationBatch(t *testing.T) {
-	item := &entity.Subscription{ID: 30, Status: entity.SubscriptionStatusActive, AutoRenew: true}
+	endAt := time.Now().UTC().Add(-time.Hour)
+	item := &entity.Subscription{ID: 30, Status: entity.SubscriptionStatusActive, AutoRenew: true, EndAt: &endAt}
 	var updated *entity.Subscription
 
 	svc := NewSubscriptionService(
 		&mockSubscriptionRepo{
-			listExpiredActiveFn: func(_ context.Context, _ time.Time) ([]*entity.Subscription, error) {
+			claimExpiredActiveFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
 				return []*entity.Subscription{item}, nil
 			},
 			updateFn: func(_ context.Context, subscription *entity.Subscription) error {
@@ -615,11 +1677,17 @@ func TestRunExpirationBatch(t *testing.T) {
 		},
 		&mockSubscriptionTypeRepo{},
 		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
 		&fakePaymentService{},
 		testConfig(),
 	)
 
-	if err := svc.RunExpirationBatch(context.Background()); err != nil {
+	if _, err := svc.RunExpirationBatch(context.Background()); err != nil {
 		t.Fatalf("expected no error, got %v", err)
 	}
 	if updated == nil || updated.Status != entity.SubscriptionStatusInactive || updated.AutoRenew || updated.RenewAt != nil {
@@ -627,6 +1695,332 @@ func TestRunExpirationBatch(t *testing.T) {
 	}
 }
 
+func TestRunExpirationBatchLeasesAndReleasesRows(t *testing.T) {
+	var claims []repository.SubscriptionClaim
+	var released []string
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{
+			claimExpiredActiveFn: func(_ context.Context, _ time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+				claims = append(claims, claim)
+				return []*entity.Subscription{{ID: 1, Status: entity.SubscriptionStatusActive}}, nil
+			},
+			releaseClaimsFn: func(_ context.Context, owner string) error {
+				released = append(released, owner)
+				return nil
+			},
+		},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := svc.RunExpirationBatch(context.Background()); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if len(claims) != 2 || len(released) != 2 {
+		t.Fatalf("expected two claims and two releases, got %d and %d", len(claims), len(released))
+	}
+	claim := claims[0]
+	if !strings.HasPrefix(claim.Owner, "worker-1/") || claim.Limit != 50 || claim.Until.Sub(claim.Now) != 15*time.Minute {
+		t.Fatalf("unexpected claim: %+v", claim)
+	}
+	if released[0] != claim.Owner {
+		t.Fatalf("expected the claim of %q to be released, got %q", claim.Owner, released[0])
+	}
+	if claims[1].Owner == claim.Owner {
+		t.Fatal("expected every run to lease under its own owner")
+	}
+}
+
+func TestRunExpirationBatchPagesThroughRows(t *testing.T) {
+	past := time.Now().UTC().Add(-time.Hour)
+	future := time.Now().UTC().Add(time.Hour)
+	rows := make([]*entity.Subscription, 0, 7)
+	for id := uint64(1); id <= 7; id++ {
+		rows = append(rows, &entity.Subscription{ID: id, Status: entity.SubscriptionStatusActive, EndAt: &past})
+	}
+	// Row 2 was extended after it was leased, row 3 cannot be saved.
+	rows[1].EndAt = &future
+
+	var claims []repository.SubscriptionClaim
+	released := 0
+	cfg := testConfig()
+	cfg.ClaimBatchSize = 2
+	cfg.BatchMaxPerRun = 5
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{
+			claimExpiredActiveFn: func(_ context.Context, _ time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+				claims = append(claims, claim)
+				var page []*entity.Subscription
+				for _, row := range rows {
+					if row.ID > claim.AfterID && len(page) < claim.Limit {
+						page = append(page, copySubscription(row))
+					}
+				}
+				return page, nil
+			},
+			updateFn: func(_ context.Context, subscription *entity.Subscription) error {
+				if subscription.ID == 3 {
+					return errors.New("db down")
+				}
+				return nil
+			},
+			releaseClaimsFn: func(_ context.Context, _ string) error {
+				released++
+				return nil
+			},
+		},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		cfg,
+	)
+
+	result, err := svc.RunExpirationBatch(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result != (BatchResult{Processed: 3, Failed: 1, Skipped: 1}) {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+	if len(claims) != 3 || released != 3 {
+		t.Fatalf("expected three batches, each released, got %d claims and %d releases", len(claims), released)
+	}
+	for i, want := range []struct {
+		afterID uint64
+		limit   int
+	}{{0, 2}, {2, 2}, {4, 1}} {
+		if claims[i].AfterID != want.afterID || claims[i].Limit != want.limit {
+			t.Fatalf("unexpected claim %d: %+v", i, claims[i])
+		}
+		if claims[i].Owner != claims[0].Owner {
+			t.Fatal("expected one owner for the whole run")
+		}
+	}
+}
+
+func dueRenewals(count int) []*entity.Subscription {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(time.Hour)
+	renewAt := time.Now().UTC().Add(-time.Minute)
+	items := make([]*entity.Subscription, 0, count)
+	for id := 1; id <= count; id++ {
+		items = append(items, &entity.Subscription{
+			ID:                 uint64(id),
+			SubscriptionTypeID: 2,
+			PlanTypeID:         &planTypeID,
+			Status:             entity.SubscriptionStatusActive,
+			AutoRenew:          true,
+			EndAt:              &endAt,
+			RenewAt:            &renewAt,
+		})
+	}
+	return items
+}
+
+func newRenewalService(items []*entity.Subscription, paymentService payment.Service, cfg config.SubscriptionConfig) *SubscriptionService {
+	return NewSubscriptionService(
+		&mockSubscriptionRepo{
+			claimDueAutoRenewFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+				return items, nil
+			},
+		},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.PlanType, error) {
+			return &entity.PlanType{ID: 20, SubscriptionTypeID: 2, PriceCents: 999, Currency: "USD", IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}, nil
+		}},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		paymentService,
+		cfg,
+	)
+}
+
+func TestRunAutoRenewalBatchLeasesWhatItCanChargeWithinClaimTTL(t *testing.T) {
+	var limits []int
+	cfg := testConfig()
+	cfg.ClaimBatchSize = 500
+	cfg.RenewalConcurrency = 4
+	cfg.RenewalChargeTimeout = 30 * time.Second
+	cfg.PaymentRateLimit = 10
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{
+			claimDueAutoRenewFn: func(_ context.Context, _ time.Time, claim repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+				limits = append(limits, claim.Limit)
+				return nil, nil
+			},
+		},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		cfg,
+	)
+
+	if _, err := svc.RunAutoRenewalBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// Half of the 15 minute lease fits 15 rounds of 4 charges of 30 seconds.
+	if len(limits) != 1 || limits[0] != 60 {
+		t.Fatalf("expected a batch of 60 renewals, got %v", limits)
+	}
+}
+
+func TestRunAutoRenewalBatchChargesConcurrently(t *testing.T) {
+	var started sync.WaitGroup
+	started.Add(3)
+	allStarted := make(chan struct{})
+	go func() {
+		started.Wait()
+		close(allStarted)
+	}()
+
+	var sequential atomic.Bool
+	cfg := testConfig()
+	cfg.RenewalConcurrency = 3
+	svc := newRenewalService(dueRenewals(3), &chargeFuncPaymentService{chargeFn: func(_ context.Context, _ payment.Charge) payment.Result {
+		// Every charge waits for the other two to start.
+		started.Done()
+		select {
+		case <-allStarted:
+		case <-time.After(time.Second):
+			sequential.Store(true)
+		}
+		return payment.Result{Type: payment.ResultTypeSuccess}
+	}}, cfg)
+
+	result, err := svc.RunAutoRenewalBatch(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if result.Processed != 3 {
+		t.Fatalf("expected three renewals, got %+v", result)
+	}
+	if sequential.Load() {
+		t.Fatal("expected the three charges to run at once")
+	}
+}
+
+func TestRunAutoRenewalBatchFinishesChargeOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var charges int
+	var chargeErr error
+	var hasDeadline bool
+	cfg := testConfig()
+	cfg.RenewalConcurrency = 1
+	cfg.RenewalChargeTimeout = time.Minute
+	items := dueRenewals(3)
+	svc := newRenewalService(items, &chargeFuncPaymentService{chargeFn: func(chargeCtx context.Context, _ payment.Charge) payment.Result {
+		charges++
+		// The worker is told to shut down while the first charge is under way.
+		cancel()
+		chargeErr = chargeCtx.Err()
+		_, hasDeadline = chargeCtx.Deadline()
+		return payment.Result{Type: payment.ResultTypeSuccess}
+	}}, cfg)
+
+	var saved []*entity.Subscription
+	svc.subscriptionRepo.(*mockSubscriptionRepo).updateFn = func(_ context.Context, subscription *entity.Subscription) error {
+		saved = append(saved, copySubscription(subscription))
+		return nil
+	}
+
+	result, err := svc.RunAutoRenewalBatch(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the batch to stop on cancel, got %v", err)
+	}
+	if charges != 1 || result != (BatchResult{Processed: 1}) {
+		t.Fatalf("expected only the charge under way to finish, got %d charges and %+v", charges, result)
+	}
+	if chargeErr != nil || !hasDeadline {
+		t.Fatalf("expected the charge to keep its own timeout, got err %v, deadline %v", chargeErr, hasDeadline)
+	}
+	if len(saved) != 2 || saved[1].ID != 1 || saved[1].Status != entity.SubscriptionStatusActive {
+		t.Fatalf("expected the renewal to be saved, got %+v", saved)
+	}
+}
+
+func TestRunAutoRenewalBatchRateLimitsCharges(t *testing.T) {
+	cfg := testConfig()
+	cfg.RenewalConcurrency = 3
+	cfg.PaymentRateLimit = 20
+	var mu sync.Mutex
+	var times []time.Time
+	svc := newRenewalService(dueRenewals(3), &chargeFuncPaymentService{chargeFn: func(_ context.Context, _ payment.Charge) payment.Result {
+		mu.Lock()
+		times = append(times, time.Now())
+		mu.Unlock()
+		return payment.Result{Type: payment.ResultTypeSuccess}
+	}}, cfg)
+
+	start := time.Now()
+	if _, err := svc.RunAutoRenewalBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	// At 20 a second with no burst, the third charge starts 100ms in at the earliest.
+	if len(times) != 3 || time.Since(start) < 90*time.Millisecond {
+		t.Fatalf("expected three paced charges, got %d in %v", len(times), time.Since(start))
+	}
+}
+
+func TestRunAutoRenewalBatchReleasesClaimOnError(t *testing.T) {
+	released := false
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{
+			claimDueAutoRenewFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+				return nil, errors.New("db down")
+			},
+			releaseClaimsFn: func(_ context.Context, _ string) error {
+				released = true
+				return nil
+			},
+		},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	if _, err := svc.RunAutoRenewalBatch(context.Background()); err == nil {
+		t.Fatal("expected the claim error")
+	}
+	if !released {
+		t.Fatal("expected rows leased before the error to be released")
+	}
+}
+
 func TestParseStartAtValidation(t *testing.T) {
 	if _, err := parseStartAt(""); !errors.Is(err, ErrStartAtRequired) {
 		t.Fatalf("expected ErrStartAtRequired, got %v", err)
@@ -638,3 +2032,2088 @@ func TestParseStartAtValidation(t *testing.T) {
 		t.Fatalf("expected parsed time, got %v err=%v", v, err)
 	}
 }
+
+func TestUpdateSubscriptionChecksVersion(t *testing.T) {
+	updated := 0
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, id uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: id, Status: entity.SubscriptionStatusActive, Version: 3}, nil
+		},
+		updateFn: func(_ context.Context, _ *entity.Subscription) error {
+			updated++
+			return repository.ErrConcurrentModification
+		},
+	}
+	svc := NewSubscriptionService(
+		repo,
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	_, err := svc.UpdateSubscription(context.Background(), &types.UpdateSubscriptionRequest{Id: 1, HasAutoRenew: true, ExpectedVersion: 2})
+	if !errors.Is(err, ErrConcurrentModification) || updated != 0 {
+		t.Fatalf("expected a stale If-Match to fail before writing, got %v after %d updates", err, updated)
+	}
+
+	_, err = svc.UpdateSubscription(context.Background(), &types.UpdateSubscriptionRequest{Id: 1, HasAutoRenew: true, ExpectedVersion: 3})
+	if !errors.Is(err, ErrConcurrentModification) || updated != 1 {
+		t.Fatalf("expected a lost race to surface as ErrConcurrentModification, got %v after %d updates", err, updated)
+	}
+}
+
+func TestRunAutoRenewalBatchRetriesOnConflict(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(time.Hour)
+	renewAt := time.Now().UTC().Add(-time.Minute)
+	stored := entity.Subscription{
+		ID:                 12,
+		SubscriptionTypeID: 2,
+		PlanTypeID:         &planTypeID,
+		Status:             entity.SubscriptionStatusActive,
+		AutoRenew:          true,
+		EndAt:              &endAt,
+		RenewAt:            &renewAt,
+		Version:            1,
+	}
+
+	tests := []struct {
+		name          string
+		concurrent    func(*entity.Subscription)
+		expectCharges int
+	}{
+		{
+			name:          "unrelated change",
+			concurrent:    func(subscription *entity.Subscription) { subscription.Quantity = 2 },
+			expectCharges: 1,
+		},
+		{
+			name: "auto-renew turned off",
+			concurrent: func(subscription *entity.Subscription) {
+				subscription.AutoRenew = false
+				subscription.RenewAt = nil
+			},
+			expectCharges: 0,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			current := stored
+			claimed := current
+			// Another writer saves the subscription after the job claimed it.
+			tc.concurrent(&current)
+			current.Version++
+
+			paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}}
+			svc := NewSubscriptionService(
+				&mockSubscriptionRepo{
+					claimDueAutoRenewFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+						return []*entity.Subscription{copySubscription(&claimed)}, nil
+					},
+					findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+						return copySubscription(&current), nil
+					},
+					updateFn: func(_ context.Context, subscription *entity.Subscription) error {
+						if subscription.Version != current.Version {
+							return repository.ErrConcurrentModification
+						}
+						subscription.Version++
+						current = *copySubscription(subscription)
+						return nil
+					},
+				},
+				&mockSubscriptionTypeRepo{},
+				&mockPlanTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.PlanType, error) {
+					return &entity.PlanType{ID: 20, SubscriptionTypeID: 2, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}, nil
+				}},
+				&mockPaymentAttemptRepo{},
+				&mockSubscriptionEventRepo{},
+				&mockOutboxMessageRepo{},
+				&mockCouponRepo{},
+				&mockCouponRedemptionRepo{},
+				&mockTxManager{},
+				paySvc,
+				testConfig(),
+			)
+
+			if _, err := svc.RunAutoRenewalBatch(context.Background()); err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if len(paySvc.charges) != tc.expectCharges {
+				t.Fatalf("expected %d charges, got %d", tc.expectCharges, len(paySvc.charges))
+			}
+			if tc.expectCharges > 0 && (current.Status != entity.SubscriptionStatusActive || current.Quantity != 2 || !current.EndAt.After(endAt)) {
+				t.Fatalf("expected the renewal to apply on top of the concurrent change, got %+v", current)
+			}
+			if tc.expectCharges == 0 && current.Status != entity.SubscriptionStatusActive {
+				t.Fatalf("expected the subscription to be left alone, got status %d", current.Status)
+			}
+		})
+	}
+}
+
+func TestUpdateSubscriptionRecordsChangedFields(t *testing.T) {
+	var events []*entity.SubscriptionEvent
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{findByIDFn: func(_ context.Context, id uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: id, Status: entity.SubscriptionStatusActive, AutoRenew: true}, nil
+		}},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{createFn: func(_ context.Context, event *entity.SubscriptionEvent) error {
+			events = append(events, event)
+			return nil
+		}},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	ctx := WithActor(context.Background(), Actor{Name: "billing-service", RequestID: "rest-1"})
+	_, err := svc.UpdateSubscription(ctx, &types.UpdateSubscriptionRequest{Id: 9, HasAutoRenew: true, AutoRenew: false})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("expected one event, got %d", len(events))
+	}
+	event := events[0]
+	if event.SubscriptionID != 9 || event.Field != entity.SubscriptionEventFieldAutoRenew {
+		t.Fatalf("unexpected event target: %+v", event)
+	}
+	if event.OldValue == nil || *event.OldValue != "true" || event.NewValue == nil || *event.NewValue != "false" {
+		t.Fatalf("unexpected event values: %v -> %v", event.OldValue, event.NewValue)
+	}
+	if event.Actor != "billing-service" || event.RequestID == nil || *event.RequestID != "rest-1" {
+		t.Fatalf("unexpected event actor: %q/%v", event.Actor, event.RequestID)
+	}
+	if event.Reason != eventReasonUpdated {
+		t.Fatalf("expected reason %q, got %q", eventReasonUpdated, event.Reason)
+	}
+}
+
+func TestRunExpirationBatchRecordsSystemActor(t *testing.T) {
+	endAt := time.Now().UTC().Add(-time.Hour)
+	var events []*entity.SubscriptionEvent
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{claimExpiredActiveFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+			return []*entity.Subscription{{ID: 3, Status: entity.SubscriptionStatusActive, EndAt: &endAt}}, nil
+		}},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{createFn: func(_ context.Context, event *entity.SubscriptionEvent) error {
+			events = append(events, event)
+			return nil
+		}},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	if _, err := svc.RunExpirationBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(events) != 1 || events[0].Field != entity.SubscriptionEventFieldStatus {
+		t.Fatalf("expected one status event, got %+v", events)
+	}
+	if events[0].Actor != systemActor || events[0].RequestID != nil || events[0].Reason != eventReasonExpired {
+		t.Fatalf("unexpected event attribution: %+v", events[0])
+	}
+}
+
+func TestListSubscriptionEventsNotFound(t *testing.T) {
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	_, err := svc.ListSubscriptionEvents(context.Background(), 1)
+	if !errors.Is(err, ErrSubscriptionNotFound) {
+		t.Fatalf("expected ErrSubscriptionNotFound, got %v", err)
+	}
+}
+
+func TestSubscriptionDomainEvents(t *testing.T) {
+	endAt := time.Now().UTC().Add(24 * time.Hour)
+	extended := endAt.Add(30 * 24 * time.Hour)
+
+	cases := []struct {
+		name   string
+		before *entity.Subscription
+		after  *entity.Subscription
+		reason string
+		want   []string
+	}{
+		{
+			name:   "initial payment activates",
+			before: &entity.Subscription{Status: entity.SubscriptionStatusProcessing, EndAt: &endAt},
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusActive, EndAt: &endAt},
+			want:   []string{entity.OutboxEventSubscriptionActivated},
+		},
+		{
+			name:   "extended period renews",
+			before: &entity.Subscription{Status: entity.SubscriptionStatusProcessing, EndAt: &endAt, AutoRenew: true},
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusActive, EndAt: &extended, AutoRenew: true},
+			want:   []string{entity.OutboxEventSubscriptionRenewed},
+		},
+		{
+			name:   "starting a trial",
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusTrialing, EndAt: &endAt, TrialEndAt: &endAt},
+			reason: eventReasonTrialStarted,
+			want:   []string{entity.OutboxEventSubscriptionTrialStarted},
+		},
+		{
+			name:   "first charge after a trial activates",
+			before: &entity.Subscription{Status: entity.SubscriptionStatusProcessing, EndAt: &endAt, TrialEndAt: &endAt, AutoRenew: true},
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusActive, EndAt: &extended, TrialEndAt: &endAt, AutoRenew: true},
+			want:   []string{entity.OutboxEventSubscriptionActivated},
+		},
+		{
+			name:   "pausing",
+			before: &entity.Subscription{Status: entity.SubscriptionStatusActive, EndAt: &endAt, AutoRenew: true},
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusPaused, EndAt: &endAt, AutoRenew: true},
+			reason: eventReasonPaused,
+			want:   []string{entity.OutboxEventSubscriptionPaused},
+		},
+		{
+			name:   "resuming with a shifted period",
+			before: &entity.Subscription{Status: entity.SubscriptionStatusPaused, EndAt: &endAt, AutoRenew: true},
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusActive, EndAt: &extended, AutoRenew: true},
+			reason: eventReasonResumed,
+			want:   []string{entity.OutboxEventSubscriptionResumed},
+		},
+		{
+			name:   "first failed renewal goes past due",
+			before: &entity.Subscription{Status: entity.SubscriptionStatusProcessing, EndAt: &endAt, AutoRenew: true},
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusPastDue, EndAt: &endAt, AutoRenew: true, RenewalRetryCount: 1},
+			reason: eventReasonPaymentFailed,
+			want:   []string{entity.OutboxEventSubscriptionPastDue},
+		},
+		{
+			name:   "later dunning retries stay quiet",
+			before: &entity.Subscription{Status: entity.SubscriptionStatusProcessing, EndAt: &endAt, AutoRenew: true, RenewalRetryCount: 1},
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusPastDue, EndAt: &endAt, AutoRenew: true, RenewalRetryCount: 2},
+			reason: eventReasonPaymentFailed,
+			want:   []string{},
+		},
+		{
+			name:   "expiration job expires",
+			before: &entity.Subscription{Status: entity.SubscriptionStatusActive, AutoRenew: true},
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusInactive},
+			reason: eventReasonExpired,
+			want:   []string{entity.OutboxEventSubscriptionExpired},
+		},
+		{
+			name:   "turning off auto-renew cancels",
+			before: &entity.Subscription{Status: entity.SubscriptionStatusActive, AutoRenew: true},
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusActive},
+			reason: eventReasonCancelled,
+			want:   []string{entity.OutboxEventSubscriptionCancelled},
+		},
+		{
+			name:   "undoing a cancellation uncancels",
+			before: &entity.Subscription{Status: entity.SubscriptionStatusActive, CanceledAt: &endAt},
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusActive, AutoRenew: true},
+			reason: eventReasonCancellationUndone,
+			want:   []string{entity.OutboxEventSubscriptionUncancelled},
+		},
+		{
+			name:   "new processing subscription publishes nothing",
+			after:  &entity.Subscription{Status: entity.SubscriptionStatusProcessing},
+			reason: eventReasonCreated,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := subscriptionDomainEvents(tc.before, tc.after, tc.reason)
+			if strings.Join(got, ",") != strings.Join(tc.want, ",") {
+				t.Fatalf("expected %v, got %v", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestCancelSubscriptionQueuesOutboxMessageInTransaction(t *testing.T) {
+	var messages []*entity.OutboxMessage
+	txManager := &mockTxManager{}
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{findByIDFn: func(_ context.Context, id uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: id, Status: entity.SubscriptionStatusActive, AutoRenew: true}, nil
+		}},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{createFn: func(_ context.Context, message *entity.OutboxMessage) error {
+			messages = append(messages, message)
+			return nil
+		}},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		txManager,
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	if _, err := svc.CancelSubscription(context.Background(), &types.CancelSubscriptionRequest{Id: 12}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if txManager.calls.Load() != 1 {
+		t.Fatalf("expected one transaction, got %d", txManager.calls.Load())
+	}
+	if len(messages) != 1 || messages[0].EventType != entity.OutboxEventSubscriptionCancelled || messages[0].SubscriptionID != 12 {
+		t.Fatalf("unexpected outbox messages: %+v", messages)
+	}
+	if messages[0].Status != entity.OutboxMessageStatusPending || !strings.Contains(string(messages[0].Payload), `"reason":"subscription_cancelled"`) {
+		t.Fatalf("unexpected outbox message: %+v %s", messages[0], messages[0].Payload)
+	}
+}
+
+type fakePublisher struct {
+	err       error
+	published []publisher.Message
+}
+
+func (f *fakePublisher) Publish(_ context.Context, message publisher.Message) error {
+	f.published = append(f.published, message)
+	return f.err
+}
+
+func TestRunRelayBatchPublishesAndRetries(t *testing.T) {
+	cfg := config.OutboxConfig{WorkerID: "worker-1", ClaimTTL: 15 * time.Minute, BatchSize: 10, MaxAttempts: 3, RetryBackoff: time.Minute}
+	var updated []entity.OutboxMessage
+	var claimOwner string
+	var released []string
+	repo := &mockOutboxMessageRepo{
+		claimDueFn: func(_ context.Context, claim repository.OutboxClaim) ([]*entity.OutboxMessage, error) {
+			if claim.Limit != 10 {
+				t.Fatalf("expected batch size 10, got %d", claim.Limit)
+			}
+			if !strings.HasPrefix(claim.Owner, "worker-1/") || !claim.Until.Equal(claim.Now.Add(15*time.Minute)) {
+				t.Fatalf("expected the batch to be leased by this worker, got %+v", claim)
+			}
+			claimOwner = claim.Owner
+			return []*entity.OutboxMessage{
+				{ID: 1, SubscriptionID: 5, EventType: entity.OutboxEventSubscriptionActivated, Status: entity.OutboxMessageStatusPending},
+				{ID: 2, SubscriptionID: 6, EventType: entity.OutboxEventSubscriptionExpired, Status: entity.OutboxMessageStatusPending, Attempts: 2},
+			}, nil
+		},
+		updateFn: func(_ context.Context, message *entity.OutboxMessage) error {
+			updated = append(updated, *message)
+			return nil
+		},
+		releaseClaimsFn: func(_ context.Context, owner string) error {
+			released = append(released, owner)
+			return nil
+		},
+	}
+
+	pub := &fakePublisher{}
+	if err := NewOutboxRelayService(repo, pub, cfg).RunRelayBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(released) != 1 || released[0] != claimOwner {
+		t.Fatalf("expected the lease to be released after the batch, got %v", released)
+	}
+	if len(pub.published) != 2 || updated[0].Status != entity.OutboxMessageStatusPublished || updated[0].PublishedAt == nil {
+		t.Fatalf("expected messages to be published, got %+v", updated)
+	}
+
+	updated = nil
+	pub = &fakePublisher{err: errors.New("sink down")}
+	start := time.Now().UTC()
+	if err := NewOutboxRelayService(repo, pub, cfg).RunRelayBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated[0].Status != entity.OutboxMessageStatusPending || updated[0].Attempts != 1 || updated[0].LastError != "sink down" {
+		t.Fatalf("expected first message to be retried, got %+v", updated[0])
+	}
+	if updated[0].NextAttemptAt.Before(start.Add(time.Minute)) {
+		t.Fatalf("expected retry to be delayed by the backoff, got %v", updated[0].NextAttemptAt)
+	}
+	if updated[1].Status != entity.OutboxMessageStatusFailed || updated[1].Attempts != 3 {
+		t.Fatalf("expected second message to fail after max attempts, got %+v", updated[1])
+	}
+}
+
+func TestCreateWebhookEndpointValidatesRequest(t *testing.T) {
+	repo := &mockWebhookEndpointRepo{}
+	svc := NewWebhookService(repo, &mockWebhookDeliveryRepo{}, &fakeWebhookSender{}, config.WebhookConfig{})
+	svc.lookupIPAddr = fakeLookupIPAddr(map[string]string{"client.local": "203.0.113.10"})
+
+	_, err := svc.CreateWebhookEndpoint(context.Background(), &types.CreateWebhookEndpointRequest{Url: "http://client.local/hook"})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected insecure url to be rejected, got %v", err)
+	}
+	_, err = svc.CreateWebhookEndpoint(context.Background(), &types.CreateWebhookEndpointRequest{
+		Url:        "https://client.local/hook",
+		EventTypes: []string{"subscription.unknown"},
+	})
+	if !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected unknown event type to be rejected, got %v", err)
+	}
+
+	ctx := WithActor(context.Background(), Actor{Name: "billing-service"})
+	item, err := svc.CreateWebhookEndpoint(ctx, &types.CreateWebhookEndpointRequest{
+		Url:        "https://client.local/hook",
+		EventTypes: []string{entity.OutboxEventSubscriptionRenewed},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if item.Status != entity.WebhookEndpointStatusActive || item.CreatedBy != "billing-service" || !strings.HasPrefix(item.Secret, webhookSecretPrefix) {
+		t.Fatalf("unexpected endpoint: %+v", item)
+	}
+}
+
+func fakeLookupIPAddr(hosts map[string]string) func(context.Context, string) ([]net.IPAddr, error) {
+	return func(_ context.Context, host string) ([]net.IPAddr, error) {
+		if ip := net.ParseIP(host); ip != nil {
+			return []net.IPAddr{{IP: ip}}, nil
+		}
+		if addr, ok := hosts[host]; ok {
+			return []net.IPAddr{{IP: net.ParseIP(addr)}}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+}
+
+func TestCreateWebhookEndpointRejectsNonPublicAddresses(t *testing.T) {
+	svc := NewWebhookService(&mockWebhookEndpointRepo{}, &mockWebhookDeliveryRepo{}, &fakeWebhookSender{}, config.WebhookConfig{})
+	svc.lookupIPAddr = fakeLookupIPAddr(map[string]string{
+		"localhost":       "127.0.0.1",
+		"internal.client": "10.1.2.3",
+		"metadata.cloud":  "169.254.169.254",
+	})
+
+	for _, raw := range []string{
+		"https://localhost/hook",
+		"https://internal.client/hook",
+		"https://metadata.cloud/latest",
+		"https://127.0.0.1:8443/hook",
+		"https://192.168.1.5/hook",
+		"https://[::1]/hook",
+		"https://[fe80::1]/hook",
+		"https://0.0.0.0/hook",
+		"https://unknown.client/hook",
+	} {
+		_, err := svc.CreateWebhookEndpoint(context.Background(), &types.CreateWebhookEndpointRequest{Url: raw})
+		if !errors.Is(err, ErrInvalidRequest) {
+			t.Fatalf("expected %s to be rejected, got %v", raw, err)
+		}
+	}
+
+	svc.cfg.AllowPrivateURLs = true
+	if _, err := svc.CreateWebhookEndpoint(context.Background(), &types.CreateWebhookEndpointRequest{Url: "https://localhost/hook"}); err != nil {
+		t.Fatalf("expected private addresses to be accepted when allowed, got %v", err)
+	}
+}
+
+func TestWebhookPublishQueuesDeliveriesForSubscribedEndpoints(t *testing.T) {
+	endpoints := &mockWebhookEndpointRepo{items: map[uint64]*entity.WebhookEndpoint{
+		1: {ID: 1, Status: entity.WebhookEndpointStatusActive},
+		2: {ID: 2, Status: entity.WebhookEndpointStatusActive, EventTypes: []string{entity.OutboxEventSubscriptionExpired}},
+		3: {ID: 3, Status: entity.WebhookEndpointStatusDisabled},
+	}}
+	deliveries := &mockWebhookDeliveryRepo{}
+	svc := NewWebhookService(endpoints, deliveries, &fakeWebhookSender{}, config.WebhookConfig{})
+
+	message := publisher.Message{ID: 7, Type: entity.OutboxEventSubscriptionRenewed, SubscriptionID: 3}
+	for i := 0; i < 2; i++ {
+		if err := svc.Publish(context.Background(), message); err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+	if len(deliveries.created) != 1 || deliveries.created[0].WebhookEndpointID != 1 || deliveries.created[0].EventID != 7 {
+		t.Fatalf("expected one delivery for the catch-all endpoint, got %+v", deliveries.created)
+	}
+}
+
+func TestRunDeliveryBatchRetriesAndDeadLetters(t *testing.T) {
+	endpoints := &mockWebhookEndpointRepo{items: map[uint64]*entity.WebhookEndpoint{
+		1: {ID: 1, Status: entity.WebhookEndpointStatusActive},
+		2: {ID: 2, Status: entity.WebhookEndpointStatusDisabled},
+	}}
+	deliveries := &mockWebhookDeliveryRepo{due: []*entity.WebhookDelivery{
+		{ID: 1, WebhookEndpointID: 1, Status: entity.WebhookDeliveryStatusPending},
+		{ID: 2, WebhookEndpointID: 1, Status: entity.WebhookDeliveryStatusPending, Attempts: 2},
+		{ID: 3, WebhookEndpointID: 2, Status: entity.WebhookDeliveryStatusPending},
+	}}
+	sender := &fakeWebhookSender{status: 500, err: errors.New("webhook endpoint returned 500")}
+	cfg := config.WebhookConfig{WorkerID: "worker-1", ClaimTTL: 15 * time.Minute, BatchSize: 10, MaxAttempts: 3, RetryBackoff: time.Minute}
+
+	start := time.Now().UTC()
+	if err := NewWebhookService(endpoints, deliveries, sender, cfg).RunDeliveryBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(sender.sent) != 2 {
+		t.Fatalf("expected deliveries of the disabled endpoint not to be sent, got %v", sender.sent)
+	}
+	first := deliveries.updated[0]
+	if first.Status != entity.WebhookDeliveryStatusPending || first.Attempts != 1 || first.ResponseStatus != 500 || first.NextAttemptAt.Before(start.Add(time.Minute)) {
+		t.Fatalf("expected first delivery to be retried with backoff, got %+v", first)
+	}
+	if deliveries.updated[1].Status != entity.WebhookDeliveryStatusDead || deliveries.updated[1].Attempts != 3 {
+		t.Fatalf("expected second delivery to be dead-lettered, got %+v", deliveries.updated[1])
+	}
+	if deliveries.updated[2].Status != entity.WebhookDeliveryStatusDead {
+		t.Fatalf("expected delivery of disabled endpoint to be dead-lettered, got %+v", deliveries.updated[2])
+	}
+}
+
+// blockingWebhookSender holds the first send until a second one starts. A
+// worker sends one delivery at a time, so the second send comes from the other
+// worker and the two batches overlap.
+type blockingWebhookSender struct {
+	mu      sync.Mutex
+	sent    map[uint64]int
+	calls   int
+	started sync.WaitGroup
+}
+
+func (f *blockingWebhookSender) Send(_ context.Context, _ *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (int, error) {
+	f.mu.Lock()
+	f.sent[delivery.ID]++
+	f.calls++
+	calls := f.calls
+	f.mu.Unlock()
+	if calls <= 2 {
+		f.started.Done()
+	}
+	f.started.Wait()
+	return 200, nil
+}
+
+func TestRunDeliveryBatchConcurrentWorkersSendEachDeliveryOnce(t *testing.T) {
+	endpoints := &mockWebhookEndpointRepo{items: map[uint64]*entity.WebhookEndpoint{
+		1: {ID: 1, Status: entity.WebhookEndpointStatusActive},
+	}}
+	deliveries := &mockWebhookDeliveryRepo{}
+	for id := uint64(1); id <= 4; id++ {
+		deliveries.due = append(deliveries.due, &entity.WebhookDelivery{ID: id, WebhookEndpointID: 1, Status: entity.WebhookDeliveryStatusPending})
+	}
+	sender := &blockingWebhookSender{sent: make(map[uint64]int)}
+	sender.started.Add(2)
+	cfg := config.WebhookConfig{ClaimTTL: 15 * time.Minute, BatchSize: 2, MaxAttempts: 3, RetryBackoff: time.Minute}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, workerID := range []string{"worker-1", "worker-2"} {
+		workerCfg := cfg
+		workerCfg.WorkerID = workerID
+		svc := NewWebhookService(endpoints, deliveries, sender, workerCfg)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- svc.RunDeliveryBatch(context.Background())
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("expected no error, got %v", err)
+		}
+	}
+
+	for id := uint64(1); id <= 4; id++ {
+		if sender.sent[id] != 1 {
+			t.Fatalf("expected every delivery to be sent once, got %v", sender.sent)
+		}
+	}
+	if len(deliveries.released) != 2 || len(deliveries.claims) != 0 {
+		t.Fatalf("expected both workers to release their leases, got %v and %v", deliveries.released, deliveries.claims)
+	}
+}
+
+func TestListSubscriptionsPaginates(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var filters []repository.SubscriptionFilter
+	repo := &mockSubscriptionRepo{
+		listFn: func(_ context.Context, filter repository.SubscriptionFilter) ([]*entity.Subscription, error) {
+			filters = append(filters, filter)
+			return []*entity.Subscription{
+				{ID: 9, CreatedAt: created.Add(2 * time.Hour)},
+				{ID: 7, CreatedAt: created.Add(time.Hour)},
+				{ID: 4, CreatedAt: created},
+			}, nil
+		},
+	}
+	svc := NewSubscriptionService(
+		repo,
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	req := &types.ListSubscriptionsRequest{PageSize: 2, Sort: "created_at_desc", HasStatus: true, Status: 10, CreatedAtFrom: "2025-12-01T00:00:00Z"}
+	page, err := svc.ListSubscriptions(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(page.Subscriptions) != 2 || page.NextPageToken == "" {
+		t.Fatalf("expected a full page with a next token, got %d items token=%q", len(page.Subscriptions), page.NextPageToken)
+	}
+	first := filters[0]
+	if first.Limit != 3 || first.After != nil || first.Status == nil || *first.Status != 10 || first.CreatedAtFrom == nil {
+		t.Fatalf("unexpected first filter: %+v", first)
+	}
+
+	req.PageToken = page.NextPageToken
+	if _, err := svc.ListSubscriptions(context.Background(), req); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	after := filters[1].After
+	if after == nil || after.ID != 7 || !after.Value.Equal(created.Add(time.Hour)) {
+		t.Fatalf("expected second page to resume after subscription 7, got %+v", after)
+	}
+
+	req.Sort = "id_asc"
+	if _, err := svc.ListSubscriptions(context.Background(), req); !errors.Is(err, ErrInvalidPageToken) {
+		t.Fatalf("expected token issued for another sort to be rejected, got %v", err)
+	}
+	req.PageToken = "not-a-token"
+	if _, err := svc.ListSubscriptions(context.Background(), req); !errors.Is(err, ErrInvalidPageToken) {
+		t.Fatalf("expected malformed token to be rejected, got %v", err)
+	}
+}
+
+func TestCreatePlanTypeValidatesCatalogRules(t *testing.T) {
+	subscriptionTypes := map[uint64]*entity.SubscriptionType{
+		1: {ID: 1, Type: entity.SubscriptionTypeEmail, Status: entity.SubscriptionTypeStatusActive},
+		2: {ID: 2, Type: entity.SubscriptionTypePlan, Status: entity.SubscriptionTypeStatusActive},
+	}
+	var created *entity.PlanType
+	svc := NewCatalogService(
+		&mockSubscriptionTypeRepo{findByIDFn: func(_ context.Context, id uint64) (*entity.SubscriptionType, error) {
+			return subscriptionTypes[id], nil
+		}},
+		&mockPlanTypeRepo{createFn: func(_ context.Context, planType *entity.PlanType) error {
+			if planType.PlanCode == "taken" {
+				return repository.ErrPlanCodeAlreadyExists
+			}
+			planType.ID = 30
+			created = planType
+			return nil
+		}},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+	)
+
+	valid := func() *types.CreatePlanTypeRequest {
+		return &types.CreatePlanTypeRequest{
+			SubscriptionTypeId: 2,
+			PlanCode:           "premium-yearly",
+			DisplayName:        "Premium Yearly",
+			PriceCents:         19900,
+			Currency:           "eur",
+			IntervalUnit:       entity.PlanIntervalYear,
+			IntervalCount:      1,
+			Features:           `{"tier": "premium", "seats": 5, "support": true}`,
+		}
+	}
+
+	cases := map[string]struct {
+		mutate func(req *types.CreatePlanTypeRequest)
+		want   error
+	}{
+		"unknown subscription type": {func(req *types.CreatePlanTypeRequest) { req.SubscriptionTypeId = 9 }, ErrSubscriptionTypeNotFound},
+		"email subscription type":   {func(req *types.CreatePlanTypeRequest) { req.SubscriptionTypeId = 1 }, ErrInvalidRequest},
+		"unknown currency":          {func(req *types.CreatePlanTypeRequest) { req.Currency = "ABC" }, ErrInvalidRequest},
+		"features not an object":    {func(req *types.CreatePlanTypeRequest) { req.Features = `["tier"]` }, ErrInvalidRequest},
+		"nested feature value":      {func(req *types.CreatePlanTypeRequest) { req.Features = `{"limits": {"seats": 5}}` }, ErrInvalidRequest},
+		"duplicate plan code":       {func(req *types.CreatePlanTypeRequest) { req.PlanCode = "taken" }, ErrPlanCodeAlreadyExists},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			req := valid()
+			tc.mutate(req)
+			if _, err := svc.CreatePlanType(context.Background(), req); !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+		})
+	}
+
+	item, err := svc.CreatePlanType(context.Background(), valid())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if item != created || item.Currency != "EUR" || item.Status != entity.PlanTypeStatusActive {
+		t.Fatalf("unexpected plan type: %+v", item)
+	}
+	if item.Features != `{"seats":5,"support":true,"tier":"premium"}` {
+		t.Fatalf("expected compact features, got %s", item.Features)
+	}
+}
+
+func TestArchivePlanTypeHidesItFromListing(t *testing.T) {
+	plan := &entity.PlanType{ID: 20, SubscriptionTypeID: 2, Status: entity.PlanTypeStatusActive}
+	planRepo := &mockPlanTypeRepo{
+		findByIDFn: func(context.Context, uint64) (*entity.PlanType, error) {
+			cp := *plan
+			return &cp, nil
+		},
+		updateFn: func(_ context.Context, planType *entity.PlanType) error {
+			plan = planType
+			return nil
+		},
+		listFn: func(context.Context, uint64) ([]*entity.PlanType, error) {
+			return []*entity.PlanType{plan}, nil
+		},
+	}
+	catalog := NewCatalogService(&mockSubscriptionTypeRepo{}, planRepo, &mockCouponRepo{}, &mockCouponRedemptionRepo{})
+
+	item, err := catalog.ArchivePlanType(context.Background(), 20)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if item.Status != entity.PlanTypeStatusArchived {
+		t.Fatalf("expected archived plan, got %+v", item)
+	}
+
+	svc := NewSubscriptionService(&mockSubscriptionRepo{}, &mockSubscriptionTypeRepo{}, planRepo, &mockPaymentAttemptRepo{}, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, &fakePaymentService{}, testConfig())
+	items, err := svc.ListPlanTypes(context.Background(), &types.ListPlanTypesRequest{SubscriptionTypeId: 2})
+	if err != nil || len(items) != 0 {
+		t.Fatalf("expected archived plan to be hidden, got %d items err=%v", len(items), err)
+	}
+	items, err = svc.ListPlanTypes(context.Background(), &types.ListPlanTypesRequest{SubscriptionTypeId: 2, IncludeArchived: true})
+	if err != nil || len(items) != 1 {
+		t.Fatalf("expected archived plan with include_archived, got %d items err=%v", len(items), err)
+	}
+}
+
+func planChangeTestPlans() map[uint64]*entity.PlanType {
+	return map[uint64]*entity.PlanType{
+		20: {ID: 20, SubscriptionTypeID: 2, PriceCents: 1000, Currency: "EUR", IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30, Status: entity.PlanTypeStatusActive},
+		21: {ID: 21, SubscriptionTypeID: 2, PriceCents: 3000, Currency: "EUR", IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30, Status: entity.PlanTypeStatusActive},
+		22: {ID: 22, SubscriptionTypeID: 2, PriceCents: 400, Currency: "EUR", IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30, Status: entity.PlanTypeStatusActive},
+		30: {ID: 30, SubscriptionTypeID: 3, PriceCents: 500, Currency: "EUR", IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30, Status: entity.PlanTypeStatusActive},
+	}
+}
+
+func newPlanChangeServiceForTest(subscription *entity.Subscription, paySvc *fakePaymentService, updates *[]*entity.Subscription, attempts *[]*entity.PaymentAttempt, messages *[]*entity.OutboxMessage) *SubscriptionService {
+	plans := planChangeTestPlans()
+	return NewSubscriptionService(
+		&mockSubscriptionRepo{
+			findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+				return copySubscription(subscription), nil
+			},
+			updateFn: func(_ context.Context, item *entity.Subscription) error {
+				*updates = append(*updates, copySubscription(item))
+				return nil
+			},
+		},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{findByIDFn: func(_ context.Context, id uint64) (*entity.PlanType, error) {
+			return plans[id], nil
+		}},
+		&mockPaymentAttemptRepo{createFn: func(_ context.Context, attempt *entity.PaymentAttempt) error {
+			*attempts = append(*attempts, attempt)
+			attempt.ID = uint64(len(*attempts))
+			return nil
+		}},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{createFn: func(_ context.Context, message *entity.OutboxMessage) error {
+			*messages = append(*messages, message)
+			return nil
+		}},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		paySvc,
+		testConfig(),
+	)
+}
+
+func TestChangePlanImmediateProratesDifference(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(15 * 24 * time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, AutoRenew: true, EndAt: &endAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "ch_1"}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+
+	res, err := svc.ChangePlan(context.Background(), &types.ChangePlanRequest{Id: 4, PlanTypeId: 21, Timing: "immediate"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.ProratedAmountCents != 1000 || len(paySvc.adjustments) != 1 || paySvc.adjustments[0].AmountCents != 1000 || paySvc.adjustments[0].Currency != "EUR" {
+		t.Fatalf("expected a 1000 cent upgrade charge, got %d %+v", res.ProratedAmountCents, paySvc.adjustments)
+	}
+	if len(attempts) != 1 || attempts[0].Kind != entity.PaymentAttemptKindPlanChange || attempts[0].PlanTypeID != 21 || attempts[0].Status != entity.PaymentAttemptStatusSucceeded {
+		t.Fatalf("unexpected payment attempts: %+v", attempts)
+	}
+	if len(updates) != 1 || *updates[0].PlanTypeID != 21 || !updates[0].EndAt.Equal(endAt) {
+		t.Fatalf("expected the plan to switch within the current period, got %+v", updates)
+	}
+	if len(messages) != 1 || messages[0].EventType != entity.OutboxEventSubscriptionPlanChanged {
+		t.Fatalf("expected a plan_changed event, got %+v", messages)
+	}
+
+	updates, attempts, messages = nil, nil, nil
+	paySvc = &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "cr_1"}}
+	svc = newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+	res, err = svc.ChangePlan(context.Background(), &types.ChangePlanRequest{Id: 4, PlanTypeId: 22})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.ProratedAmountCents != -300 || len(paySvc.adjustments) != 1 || paySvc.adjustments[0].AmountCents != -300 {
+		t.Fatalf("expected a 300 cent downgrade credit, got %d %+v", res.ProratedAmountCents, paySvc.adjustments)
+	}
+	if len(updates) != 1 || *updates[0].PlanTypeID != 22 {
+		t.Fatalf("expected the plan to switch, got %+v", updates)
+	}
+}
+
+func TestChangePlanImmediateDeclinedKeepsPlan(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(15 * 24 * time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, EndAt: &endAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeFailure, Error: "card declined"}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+
+	_, err := svc.ChangePlan(context.Background(), &types.ChangePlanRequest{Id: 4, PlanTypeId: 21})
+	if !errors.Is(err, ErrPaymentDeclined) {
+		t.Fatalf("expected ErrPaymentDeclined, got %v", err)
+	}
+	if len(updates) != 0 || len(attempts) != 1 || attempts[0].Status != entity.PaymentAttemptStatusFailed {
+		t.Fatalf("expected only a failed attempt, got updates=%+v attempts=%+v", updates, attempts)
+	}
+
+	if _, err := svc.ChangePlan(context.Background(), &types.ChangePlanRequest{Id: 4, PlanTypeId: 30}); !errors.Is(err, ErrPlanTypeNotFound) {
+		t.Fatalf("expected plans of another type to be rejected, got %v", err)
+	}
+}
+
+func TestChangePlanImmediateRetriesConflictingWrite(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(15 * 24 * time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, EndAt: &endAt, Version: 3}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "ch_1"}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+	conflicts := 1
+	svc.subscriptionRepo.(*mockSubscriptionRepo).updateFn = func(_ context.Context, item *entity.Subscription) error {
+		if conflicts > 0 {
+			conflicts--
+			return repository.ErrConcurrentModification
+		}
+		updates = append(updates, copySubscription(item))
+		return nil
+	}
+
+	res, err := svc.ChangePlan(context.Background(), &types.ChangePlanRequest{Id: 4, PlanTypeId: 21})
+	if err != nil {
+		t.Fatalf("expected the plan switch to be saved on retry, got %v", err)
+	}
+	if len(paySvc.adjustments) != 1 || len(updates) != 1 || *updates[0].PlanTypeID != 21 || *res.Subscription.PlanTypeID != 21 {
+		t.Fatalf("expected one charge and one saved switch, got adjustments=%+v updates=%+v", paySvc.adjustments, updates)
+	}
+	if attempts[0].Status != entity.PaymentAttemptStatusSucceeded {
+		t.Fatalf("expected the attempt to stay succeeded, got %+v", attempts[0])
+	}
+}
+
+func TestChangePlanImmediateRetryReusesOpenAttempt(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(15 * 24 * time.Hour)
+	updatedAt := time.Now().UTC().Add(-time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, EndAt: &endAt, UpdatedAt: updatedAt}
+
+	var updates []*entity.Subscription
+	var messages []*entity.OutboxMessage
+	// A request that crashed mid-charge left its adjustment pending.
+	attempts := []*entity.PaymentAttempt{{ID: 7, SubscriptionID: 4, PlanTypeID: 21, Kind: entity.PaymentAttemptKindPlanChange, AmountCents: 990, Currency: "EUR", Quantity: 1, Status: entity.PaymentAttemptStatusPending, CreatedAt: updatedAt.Add(time.Minute)}}
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "ch_1"}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+	svc.paymentAttemptRepo.(*mockPaymentAttemptRepo).listFn = func(context.Context, uint64) ([]*entity.PaymentAttempt, error) {
+		return attempts, nil
+	}
+
+	res, err := svc.ChangePlan(context.Background(), &types.ChangePlanRequest{Id: 4, PlanTypeId: 21})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(attempts) != 1 || attempts[0].Status != entity.PaymentAttemptStatusSucceeded {
+		t.Fatalf("expected the open attempt to be reused, got %+v", attempts)
+	}
+	if len(paySvc.adjustments) != 1 || paySvc.adjustments[0].IdempotencyKey != "attempt-7" {
+		t.Fatalf("expected the retry to be sent under the same key, got %+v", paySvc.adjustments)
+	}
+	if res.ProratedAmountCents != 990 || len(updates) != 1 || *updates[0].PlanTypeID != 21 {
+		t.Fatalf("expected the plan to switch once, got %d %+v", res.ProratedAmountCents, updates)
+	}
+}
+
+func TestChangePlanImmediateReversesChargeWhenSaveFails(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(15 * 24 * time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, EndAt: &endAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "ch_1"}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+	svc.paymentAttemptRepo.(*mockPaymentAttemptRepo).listFn = func(context.Context, uint64) ([]*entity.PaymentAttempt, error) {
+		return attempts, nil
+	}
+	subscriptionRepo := svc.subscriptionRepo.(*mockSubscriptionRepo)
+	subscriptionRepo.updateFn = func(context.Context, *entity.Subscription) error {
+		return errors.New("database unavailable")
+	}
+
+	if _, err := svc.ChangePlan(context.Background(), &types.ChangePlanRequest{Id: 4, PlanTypeId: 21}); err == nil {
+		t.Fatal("expected the failed write to be reported")
+	}
+	if len(attempts) != 2 || attempts[1].Kind != entity.PaymentAttemptKindReversal || attempts[1].Status != entity.PaymentAttemptStatusSucceeded {
+		t.Fatalf("expected the charge to be reversed, got %+v", attempts)
+	}
+	if len(paySvc.adjustments) != 2 || paySvc.adjustments[1].AmountCents != -paySvc.adjustments[0].AmountCents || paySvc.adjustments[1].IdempotencyKey != "attempt-2" {
+		t.Fatalf("expected the reversal to give the charge back under its own key, got %+v", paySvc.adjustments)
+	}
+
+	// A renewal saves the subscription before the change is asked for again.
+	subscription.UpdatedAt = time.Now().UTC().Add(time.Second)
+	subscriptionRepo.updateFn = func(_ context.Context, item *entity.Subscription) error {
+		updates = append(updates, copySubscription(item))
+		return nil
+	}
+	res, err := svc.ChangePlan(context.Background(), &types.ChangePlanRequest{Id: 4, PlanTypeId: 21})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var billed int64
+	for _, adjustment := range paySvc.adjustments {
+		billed += adjustment.AmountCents
+	}
+	if len(paySvc.adjustments) != 3 || billed != res.ProratedAmountCents {
+		t.Fatalf("expected the customer to be billed once for the change, got %+v", paySvc.adjustments)
+	}
+	if len(updates) != 1 || *updates[0].PlanTypeID != 21 {
+		t.Fatalf("expected the plan to switch once, got %+v", updates)
+	}
+}
+
+func TestChangePlanAtPeriodEndIsAppliedByRenewal(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(time.Hour)
+	renewAt := time.Now().UTC().Add(-time.Minute)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, AutoRenew: true, EndAt: &endAt, RenewAt: &renewAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+
+	res, err := svc.ChangePlan(context.Background(), &types.ChangePlanRequest{Id: 4, PlanTypeId: 21, Timing: "period_end"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if paySvc.calledCount != 0 || res.ProratedAmountCents != 0 {
+		t.Fatalf("expected no payment for a period-end change, got %d calls", paySvc.calledCount)
+	}
+	scheduled := res.Subscription
+	if *scheduled.PlanTypeID != 20 || scheduled.PendingPlanTypeID == nil || *scheduled.PendingPlanTypeID != 21 {
+		t.Fatalf("expected plan 21 to be pending, got %+v", scheduled)
+	}
+
+	updates, messages = nil, nil
+	svc.subscriptionRepo.(*mockSubscriptionRepo).claimDueAutoRenewFn = func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+		return []*entity.Subscription{scheduled}, nil
+	}
+	if _, err := svc.RunAutoRenewalBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(attempts) != 1 || attempts[0].Kind != entity.PaymentAttemptKindRenewal || attempts[0].PlanTypeID != 21 || attempts[0].AmountCents != 3000 {
+		t.Fatalf("expected the renewal to charge the pending plan, got %+v", attempts)
+	}
+	final := updates[len(updates)-1]
+	if final.Status != entity.SubscriptionStatusActive || *final.PlanTypeID != 21 || final.PendingPlanTypeID != nil {
+		t.Fatalf("expected the pending plan to be applied, got %+v", final)
+	}
+	if !final.EndAt.Equal(endAt.Add(30 * 24 * time.Hour)) {
+		t.Fatalf("expected one period of the new plan, got %v", final.EndAt)
+	}
+}
+
+func TestProratedPlanChangeAmount(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	monthly := &entity.PlanType{PriceCents: 1000, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}
+	yearly := &entity.PlanType{PriceCents: 36500, IntervalUnit: entity.PlanIntervalDay, IntervalCount: 365}
+
+	tenDays := now.Add(10 * 24 * time.Hour)
+	if got := proratedPlanChangeAmount(monthly, yearly, &tenDays, now, nil); got != 1000-333 {
+		t.Fatalf("expected daily rates to be compared, got %d", got)
+	}
+	past := now.Add(-time.Hour)
+	if got := proratedPlanChangeAmount(monthly, yearly, &past, now, nil); got != 0 {
+		t.Fatalf("expected no proration after the period ended, got %d", got)
+	}
+}
+
+func TestExtendSubscriptionPeriodKeepsBillingDay(t *testing.T) {
+	monthly := &entity.PlanType{IntervalUnit: entity.PlanIntervalMonth, IntervalCount: 1}
+	endAt := time.Date(2026, 1, 31, 9, 30, 0, 0, time.UTC)
+	item := &entity.Subscription{EndAt: &endAt, BillingAnchorDay: 31}
+
+	want := []time.Time{
+		time.Date(2026, 2, 28, 9, 30, 0, 0, time.UTC),
+		time.Date(2026, 3, 31, 9, 30, 0, 0, time.UTC),
+		time.Date(2026, 4, 30, 9, 30, 0, 0, time.UTC),
+	}
+	for _, expected := range want {
+		extendSubscriptionPeriod(item, monthly, time.Now().UTC(), testConfig())
+		if !item.EndAt.Equal(expected) || item.BillingAnchorDay != 31 {
+			t.Fatalf("expected end_at %v on anchor 31, got %v (anchor %d)", expected, item.EndAt, item.BillingAnchorDay)
+		}
+	}
+
+	// A period end moved off the anchor, e.g. by a pause, re-anchors the billing day.
+	shifted := time.Date(2026, 5, 3, 9, 30, 0, 0, time.UTC)
+	item.EndAt = &shifted
+	extendSubscriptionPeriod(item, monthly, time.Now().UTC(), testConfig())
+	if !item.EndAt.Equal(time.Date(2026, 6, 3, 9, 30, 0, 0, time.UTC)) || item.BillingAnchorDay != 3 {
+		t.Fatalf("expected re-anchoring on the 3rd, got %v (anchor %d)", item.EndAt, item.BillingAnchorDay)
+	}
+}
+
+func TestPlanPeriodEndCalendarUnits(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	// 09:00 in Berlin, the day before the switch to summer time.
+	start := time.Date(2026, 3, 28, 8, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name     string
+		planType *entity.PlanType
+		start    time.Time
+		loc      *time.Location
+		want     time.Time
+	}{
+		{"daily across DST keeps local time", &entity.PlanType{IntervalUnit: entity.PlanIntervalDay, IntervalCount: 1}, start, berlin, time.Date(2026, 3, 29, 7, 0, 0, 0, time.UTC)},
+		{"two weeks", &entity.PlanType{IntervalUnit: entity.PlanIntervalWeek, IntervalCount: 2}, start, nil, start.AddDate(0, 0, 14)},
+		{"quarterly", &entity.PlanType{IntervalUnit: entity.PlanIntervalMonth, IntervalCount: 3}, time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC), nil, time.Date(2027, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{"yearly from a leap day", &entity.PlanType{IntervalUnit: entity.PlanIntervalYear, IntervalCount: 1}, time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC), nil, time.Date(2029, 2, 28, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tc := range cases {
+		if got := planPeriodEnd(tc.start, tc.planType, 0, tc.loc); !got.Equal(tc.want) {
+			t.Fatalf("%s: expected %v, got %v", tc.name, tc.want, got)
+		}
+	}
+}
+
+func TestPaymentCallbackPlanChangeSwitchesPlan(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(10 * 24 * time.Hour)
+	var updated *entity.Subscription
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, EndAt: &endAt}, nil
+		},
+		updateFn: func(_ context.Context, subscription *entity.Subscription) error {
+			updated = copySubscription(subscription)
+			return nil
+		},
+	}
+	attemptRepo := pendingAttemptRepo(4, entity.PaymentAttemptStatusPending)
+	findAttempt := attemptRepo.findByTransactionIDFn
+	attemptRepo.findByTransactionIDFn = func(ctx context.Context, transactionID string) (*entity.PaymentAttempt, error) {
+		attempt, err := findAttempt(ctx, transactionID)
+		attempt.Kind = entity.PaymentAttemptKindPlanChange
+		attempt.PlanTypeID = 21
+		return attempt, err
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, attemptRepo, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
+
+	if _, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "failed", TransactionId: "tx-1"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated == nil || updated.Status != entity.SubscriptionStatusActive || *updated.PlanTypeID != 20 || updated.RenewAt != nil {
+		t.Fatalf("expected a failed plan change to leave the subscription alone, got %+v", updated)
+	}
+
+	if _, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "tx-1"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated.Status != entity.SubscriptionStatusActive || *updated.PlanTypeID != 21 || !updated.EndAt.Equal(endAt) {
+		t.Fatalf("expected the plan to switch without extending the period, got %+v", updated)
+	}
+}
+
+func newTrialServiceForTest(usedTrial bool, created **entity.Subscription, paySvc *fakePaymentService, messages *[]*entity.OutboxMessage) *SubscriptionService {
+	return NewSubscriptionService(
+		&mockSubscriptionRepo{
+			createFn: func(_ context.Context, subscription *entity.Subscription) error {
+				subscription.ID = 104
+				*created = copySubscription(subscription)
+				return nil
+			},
+			hasUsedTrialFn: func(_ context.Context, _ uint64, _, _ *string) (bool, error) {
+				return usedTrial, nil
+			},
+		},
+		&mockSubscriptionTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.SubscriptionType, error) {
+			return &entity.SubscriptionType{ID: 2, Status: 10, Type: "plan"}, nil
+		}},
+		&mockPlanTypeRepo{listFn: func(_ context.Context, _ uint64) ([]*entity.PlanType, error) {
+			return []*entity.PlanType{{ID: 20, SubscriptionTypeID: 2, Status: entity.PlanTypeStatusActive, PriceCents: 1500, Currency: "EUR", IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30, TrialDays: 14}}, nil
+		}},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{createFn: func(_ context.Context, message *entity.OutboxMessage) error {
+			*messages = append(*messages, message)
+			return nil
+		}},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		paySvc,
+		testConfig(),
+	)
+}
+
+func TestCreatePlanSubscriptionStartsTrial(t *testing.T) {
+	startAt := time.Now().UTC().Truncate(time.Second)
+	req := &types.CreateSubscriptionRequest{SubscriptionTypeId: 2, PlanTypeId: 20, UserId: "u-1", StartAt: startAt.Format(time.RFC3339), AutoRenew: true}
+
+	var created *entity.Subscription
+	var messages []*entity.OutboxMessage
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}}
+	res, err := newTrialServiceForTest(false, &created, paySvc, &messages).CreateSubscription(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if paySvc.calledCount != 0 {
+		t.Fatalf("expected no charge during the trial, got %d calls", paySvc.calledCount)
+	}
+	trialEnd := startAt.Add(14 * 24 * time.Hour)
+	sub := res.Subscription
+	if sub.Status != entity.SubscriptionStatusTrialing || !sub.EndAt.Equal(trialEnd) || sub.TrialEndAt == nil || !sub.TrialEndAt.Equal(trialEnd) {
+		t.Fatalf("expected a 14 day trial, got %+v", sub)
+	}
+	if !sub.RenewAt.Equal(trialEnd.Add(-2 * time.Hour)) {
+		t.Fatalf("expected the first charge to be scheduled before the trial ends, got %v", sub.RenewAt)
+	}
+	if len(messages) != 1 || messages[0].EventType != entity.OutboxEventSubscriptionTrialStarted {
+		t.Fatalf("expected a trial_started event, got %+v", messages)
+	}
+
+	created, messages = nil, nil
+	paySvc = &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}}
+	res, err = newTrialServiceForTest(true, &created, paySvc, &messages).CreateSubscription(context.Background(), req)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if paySvc.calledCount != 1 || res.Subscription.Status != entity.SubscriptionStatusActive || res.Subscription.TrialEndAt != nil {
+		t.Fatalf("expected a used trial to be charged right away, got %d calls and %+v", paySvc.calledCount, res.Subscription)
+	}
+	if !res.Subscription.EndAt.Equal(startAt.Add(30 * 24 * time.Hour)) {
+		t.Fatalf("expected a full paid period, got %v", res.Subscription.EndAt)
+	}
+}
+
+func TestRunAutoRenewalBatchConvertsTrial(t *testing.T) {
+	planTypeID := uint64(20)
+	trialEnd := time.Now().UTC().Add(time.Hour)
+	renewAt := time.Now().UTC().Add(-time.Minute)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusTrialing, AutoRenew: true, EndAt: &trialEnd, TrialEndAt: &trialEnd, RenewAt: &renewAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	svc := newPlanChangeServiceForTest(subscription, &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}}, &updates, &attempts, &messages)
+	svc.subscriptionRepo.(*mockSubscriptionRepo).claimDueAutoRenewFn = func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+		return []*entity.Subscription{copySubscription(subscription)}, nil
+	}
+
+	if _, err := svc.RunAutoRenewalBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(attempts) != 1 || attempts[0].Kind != entity.PaymentAttemptKindRenewal || attempts[0].AmountCents != 1000 {
+		t.Fatalf("expected the first charge at the end of the trial, got %+v", attempts)
+	}
+	final := updates[len(updates)-1]
+	if final.Status != entity.SubscriptionStatusActive || !final.EndAt.Equal(trialEnd.Add(30*24*time.Hour)) {
+		t.Fatalf("expected a paid period after the trial, got %+v", final)
+	}
+	if len(messages) != 1 || messages[0].EventType != entity.OutboxEventSubscriptionActivated {
+		t.Fatalf("expected the conversion to publish activated, got %+v", messages)
+	}
+}
+
+func TestCancelSubscriptionImmediatelyRefundsUnusedPeriod(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(15 * 24 * time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, AutoRenew: true, EndAt: &endAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "cr_1"}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+
+	res, err := svc.CancelSubscription(context.Background(), &types.CancelSubscriptionRequest{Id: 4, Mode: "immediate", Reason: "moving abroad", Refund: true})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.RefundedAmountCents != 500 || len(paySvc.adjustments) != 1 || paySvc.adjustments[0].AmountCents != -500 {
+		t.Fatalf("expected a 500 cent credit, got %d %+v", res.RefundedAmountCents, paySvc.adjustments)
+	}
+	if len(attempts) != 1 || attempts[0].Kind != entity.PaymentAttemptKindRefund || attempts[0].AmountCents != -500 {
+		t.Fatalf("unexpected payment attempts: %+v", attempts)
+	}
+	cancelled := updates[len(updates)-1]
+	if cancelled.Status != entity.SubscriptionStatusInactive || cancelled.AutoRenew || cancelled.CanceledAt == nil ||
+		cancelled.CancelReason == nil || *cancelled.CancelReason != "moving abroad" || !cancelled.EndAt.Equal(*cancelled.CanceledAt) {
+		t.Fatalf("expected the subscription to end now, got %+v", cancelled)
+	}
+	if len(messages) != 1 || messages[0].EventType != entity.OutboxEventSubscriptionDeactivated {
+		t.Fatalf("expected a deactivated event, got %+v", messages)
+	}
+
+	if paySvc.adjustments[0].IdempotencyKey != paymentIdempotencyKey(attempts[0]) {
+		t.Fatalf("expected the credit to be keyed to the stored attempt, got %q", paySvc.adjustments[0].IdempotencyKey)
+	}
+
+	updates, attempts, messages = nil, nil, nil
+	svc = newPlanChangeServiceForTest(subscription, &fakePaymentService{result: payment.Result{Type: payment.ResultTypeFailure, Error: "card expired"}}, &updates, &attempts, &messages)
+	_, err = svc.CancelSubscription(context.Background(), &types.CancelSubscriptionRequest{Id: 4, Mode: "immediate", Refund: true})
+	if !errors.Is(err, ErrPaymentDeclined) {
+		t.Fatalf("expected ErrPaymentDeclined, got %v", err)
+	}
+	if len(updates) != 1 || updates[0].Status != entity.SubscriptionStatusInactive {
+		t.Fatalf("expected the cancellation to stand, got %+v", updates)
+	}
+	if len(attempts) != 1 || attempts[0].Status != entity.PaymentAttemptStatusFailed {
+		t.Fatalf("expected the failed credit in the ledger, got %+v", attempts)
+	}
+}
+
+func TestCancelSubscriptionSavesCancellationBeforeRefunding(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(15 * 24 * time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, AutoRenew: true, EndAt: &endAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "cr_1"}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+	svc.subscriptionRepo.(*mockSubscriptionRepo).updateFn = func(context.Context, *entity.Subscription) error {
+		return repository.ErrConcurrentModification
+	}
+
+	_, err := svc.CancelSubscription(context.Background(), &types.CancelSubscriptionRequest{Id: 4, Mode: "immediate", Refund: true})
+	if !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("expected the conflict to be reported, got %v", err)
+	}
+	if len(paySvc.adjustments) != 0 || len(attempts) != 0 {
+		t.Fatalf("expected nothing to be credited when the cancellation is not saved, got %+v %+v", paySvc.adjustments, attempts)
+	}
+
+	// Once cancelled, a repeated request cannot credit the period again.
+	cancelled := copySubscription(subscription)
+	cancelled.Status = entity.SubscriptionStatusInactive
+	svc.subscriptionRepo.(*mockSubscriptionRepo).findByIDFn = func(context.Context, uint64) (*entity.Subscription, error) {
+		return copySubscription(cancelled), nil
+	}
+	if _, err := svc.CancelSubscription(context.Background(), &types.CancelSubscriptionRequest{Id: 4, Mode: "immediate", Refund: true}); err == nil {
+		t.Fatal("expected the repeated cancellation to be rejected")
+	}
+	if len(paySvc.adjustments) != 0 {
+		t.Fatalf("expected no credit on the repeated request, got %+v", paySvc.adjustments)
+	}
+}
+
+// paidPeriod lists a succeeded renewal of amountCents after discountCents off,
+// as the charge of the subscription's current period.
+func paidPeriod(amountCents, discountCents int64) func(context.Context, uint64) ([]*entity.PaymentAttempt, error) {
+	return func(context.Context, uint64) ([]*entity.PaymentAttempt, error) {
+		return []*entity.PaymentAttempt{
+			{ID: 3, Kind: entity.PaymentAttemptKindRenewal, Status: entity.PaymentAttemptStatusSucceeded, AmountCents: amountCents, DiscountCents: discountCents},
+			{ID: 2, Kind: entity.PaymentAttemptKindInitial, Status: entity.PaymentAttemptStatusSucceeded, AmountCents: 1000},
+		}, nil
+	}
+}
+
+func TestCouponDiscountScalesRefundsAndProrations(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(15 * 24 * time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, Quantity: 2, AutoRenew: true, EndAt: &endAt}
+
+	cases := map[string]struct {
+		amountCents, discountCents int64
+		refund, planChange, seats  int64
+	}{
+		"no coupon":   {amountCents: 2000, refund: 1000, planChange: 2000, seats: 1500},
+		"50% coupon":  {amountCents: 1000, discountCents: 1000, refund: 500, planChange: 1000, seats: 750},
+		"100% coupon": {amountCents: 0, discountCents: 2000},
+	}
+	for name, tc := range cases {
+		newService := func(paySvc *fakePaymentService, updates *[]*entity.Subscription, attempts *[]*entity.PaymentAttempt) *SubscriptionService {
+			var messages []*entity.OutboxMessage
+			svc := newPlanChangeServiceForTest(subscription, paySvc, updates, attempts, &messages)
+			svc.paymentAttemptRepo.(*mockPaymentAttemptRepo).listFn = paidPeriod(tc.amountCents, tc.discountCents)
+			return svc
+		}
+
+		var updates []*entity.Subscription
+		var attempts []*entity.PaymentAttempt
+		paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}}
+		cancelled, err := newService(paySvc, &updates, &attempts).CancelSubscription(context.Background(), &types.CancelSubscriptionRequest{Id: 4, Mode: "immediate", Refund: true})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+		if cancelled.RefundedAmountCents != tc.refund || len(paySvc.adjustments) != len(attempts) {
+			t.Fatalf("%s: expected a refund of %d, got %d %+v", name, tc.refund, cancelled.RefundedAmountCents, paySvc.adjustments)
+		}
+		if tc.refund == 0 && (len(attempts) != 0 || updates[0].Status != entity.SubscriptionStatusInactive) {
+			t.Fatalf("%s: expected a cancellation without credit, got %+v %+v", name, attempts, updates)
+		}
+
+		updates, attempts = nil, nil
+		paySvc = &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}}
+		changed, err := newService(paySvc, &updates, &attempts).ChangePlan(context.Background(), &types.ChangePlanRequest{Id: 4, PlanTypeId: 21})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+		if changed.ProratedAmountCents != tc.planChange || len(paySvc.adjustments) != len(attempts) {
+			t.Fatalf("%s: expected a plan change charge of %d, got %d %+v", name, tc.planChange, changed.ProratedAmountCents, paySvc.adjustments)
+		}
+		if len(updates) != 1 || *updates[0].PlanTypeID != 21 {
+			t.Fatalf("%s: expected the plan to switch, got %+v", name, updates)
+		}
+
+		updates, attempts = nil, nil
+		paySvc = &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}}
+		resized, err := newService(paySvc, &updates, &attempts).UpdateQuantity(context.Background(), &types.UpdateQuantityRequest{Id: 4, Quantity: 5})
+		if err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+		if resized.ProratedAmountCents != tc.seats || len(paySvc.adjustments) != len(attempts) {
+			t.Fatalf("%s: expected a seat charge of %d, got %d %+v", name, tc.seats, resized.ProratedAmountCents, paySvc.adjustments)
+		}
+		if len(updates) != 1 || updates[0].Quantity != 5 {
+			t.Fatalf("%s: expected five seats, got %+v", name, updates)
+		}
+	}
+}
+
+func TestUndoCancellationRestoresAutoRenew(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(10 * 24 * time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, AutoRenew: true, EndAt: &endAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	svc := newPlanChangeServiceForTest(subscription, &fakePaymentService{}, &updates, &attempts, &messages)
+
+	res, err := svc.CancelSubscription(context.Background(), &types.CancelSubscriptionRequest{Id: 4, Reason: "too expensive"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	cancelled := res.Subscription
+	if cancelled.Status != entity.SubscriptionStatusActive || cancelled.AutoRenew || cancelled.RenewAt != nil || cancelled.CanceledAt == nil || !cancelled.EndAt.Equal(endAt) {
+		t.Fatalf("expected a period-end cancellation, got %+v", cancelled)
+	}
+
+	*subscription = *copySubscription(cancelled)
+	updates, messages = nil, nil
+	restored, err := svc.UndoCancellation(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !restored.AutoRenew || restored.CanceledAt != nil || restored.CancelReason != nil || !restored.RenewAt.Equal(endAt.Add(-2*time.Hour)) {
+		t.Fatalf("expected auto-renew to be restored, got %+v", restored)
+	}
+	if len(messages) != 1 || messages[0].EventType != entity.OutboxEventSubscriptionUncancelled {
+		t.Fatalf("expected an uncancelled event, got %+v", messages)
+	}
+
+	ended := endAt.Add(-20 * 24 * time.Hour)
+	subscription.EndAt = &ended
+	if _, err := svc.UndoCancellation(context.Background(), 4); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition once the period ended, got %v", err)
+	}
+}
+
+func TestPauseAndResumeShiftsRemainingPeriod(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(10 * 24 * time.Hour)
+	renewAt := endAt.Add(-2 * time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, AutoRenew: true, EndAt: &endAt, RenewAt: &renewAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	svc := newPlanChangeServiceForTest(subscription, &fakePaymentService{}, &updates, &attempts, &messages)
+
+	resumeAt := time.Now().UTC().Add(30 * 24 * time.Hour).Truncate(time.Second)
+	paused, err := svc.PauseSubscription(context.Background(), &types.PauseSubscriptionRequest{Id: 4, ResumeAt: resumeAt.Format(time.RFC3339)})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if paused.Status != entity.SubscriptionStatusPaused || paused.PausedAt == nil || !paused.ResumeAt.Equal(resumeAt) || paused.RenewAt != nil || !paused.EndAt.Equal(endAt) {
+		t.Fatalf("unexpected paused subscription: %+v", paused)
+	}
+	if len(messages) != 1 || messages[0].EventType != entity.OutboxEventSubscriptionPaused {
+		t.Fatalf("expected a paused event, got %+v", messages)
+	}
+
+	pausedAt := paused.PausedAt.Add(-5 * 24 * time.Hour)
+	paused.PausedAt = &pausedAt
+	*subscription = *copySubscription(paused)
+	updates, messages = nil, nil
+	resumed, err := svc.ResumeSubscription(context.Background(), 4)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	shift := resumed.EndAt.Sub(endAt)
+	if resumed.Status != entity.SubscriptionStatusActive || shift < 5*24*time.Hour || shift > 5*24*time.Hour+time.Minute {
+		t.Fatalf("expected end_at to move by the paused time, got %+v (shift %v)", resumed, shift)
+	}
+	if resumed.PausedAt != nil || resumed.ResumeAt != nil || !resumed.RenewAt.Equal(resumed.EndAt.Add(-2*time.Hour)) {
+		t.Fatalf("expected the renewal to be rescheduled, got %+v", resumed)
+	}
+	if len(messages) != 1 || messages[0].EventType != entity.OutboxEventSubscriptionResumed {
+		t.Fatalf("expected a resumed event, got %+v", messages)
+	}
+
+	subscription.Status = entity.SubscriptionStatusActive
+	if _, err := svc.ResumeSubscription(context.Background(), 4); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("expected ErrInvalidTransition for an active subscription, got %v", err)
+	}
+	if _, err := svc.PauseSubscription(context.Background(), &types.PauseSubscriptionRequest{Id: 4, ResumeAt: time.Now().UTC().Add(-time.Hour).Format(time.RFC3339)}); !errors.Is(err, ErrInvalidRequest) {
+		t.Fatalf("expected ErrInvalidRequest for a past resume_at, got %v", err)
+	}
+}
+
+func TestRunAutoResumeBatch(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(24 * time.Hour)
+	pausedAt := time.Now().UTC().Add(-48 * time.Hour)
+	resumeAt := time.Now().UTC().Add(-time.Minute)
+	due := &entity.Subscription{ID: 6, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusPaused, EndAt: &endAt, PausedAt: &pausedAt, ResumeAt: &resumeAt}
+
+	var updated []*entity.Subscription
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{
+			claimDueResumeFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+				return []*entity.Subscription{due}, nil
+			},
+			updateFn: func(_ context.Context, item *entity.Subscription) error {
+				updated = append(updated, copySubscription(item))
+				return nil
+			},
+		},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{},
+		&mockCouponRedemptionRepo{},
+		&mockTxManager{},
+		&fakePaymentService{},
+		testConfig(),
+	)
+
+	if _, err := svc.RunAutoResumeBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(updated) != 1 || updated[0].Status != entity.SubscriptionStatusActive || updated[0].RenewAt != nil || !updated[0].EndAt.After(endAt.Add(48*time.Hour-time.Minute)) {
+		t.Fatalf("expected the subscription to resume with a shifted period, got %+v", updated)
+	}
+}
+
+func newCouponServiceForTest(coupon *entity.Coupon, paySvc *fakePaymentService, redemptions *[]*entity.CouponRedemption, attempts *[]*entity.PaymentAttempt) *SubscriptionService {
+	var active *entity.CouponRedemption
+	return NewSubscriptionService(
+		&mockSubscriptionRepo{createFn: func(_ context.Context, subscription *entity.Subscription) error {
+			subscription.ID = 102
+			return nil
+		}},
+		&mockSubscriptionTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.SubscriptionType, error) {
+			return &entity.SubscriptionType{ID: 2, Status: 10, Type: "plan"}, nil
+		}},
+		&mockPlanTypeRepo{listFn: func(_ context.Context, _ uint64) ([]*entity.PlanType, error) {
+			return []*entity.PlanType{{ID: 20, SubscriptionTypeID: 2, Status: entity.PlanTypeStatusActive, PriceCents: 1500, Currency: "EUR", IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}}, nil
+		}},
+		&mockPaymentAttemptRepo{updateFn: func(_ context.Context, attempt *entity.PaymentAttempt) error {
+			*attempts = append(*attempts, attempt)
+			return nil
+		}},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{
+			findByCodeFn: func(_ context.Context, code string) (*entity.Coupon, error) {
+				if code != coupon.Code {
+					return nil, nil
+				}
+				return coupon, nil
+			},
+			findByIDFn: func(_ context.Context, _ uint64) (*entity.Coupon, error) {
+				return coupon, nil
+			},
+		},
+		&mockCouponRedemptionRepo{
+			createFn: func(_ context.Context, redemption *entity.CouponRedemption) error {
+				active = redemption
+				return nil
+			},
+			updateFn: func(_ context.Context, redemption *entity.CouponRedemption) error {
+				cp := *redemption
+				*redemptions = append(*redemptions, &cp)
+				return nil
+			},
+			findActiveBySubscriptionIDFn: func(_ context.Context, _ uint64) (*entity.CouponRedemption, error) {
+				return active, nil
+			},
+		},
+		&mockTxManager{},
+		paySvc,
+		testConfig(),
+	)
+}
+
+func TestCreatePlanSubscriptionRedeemsCoupon(t *testing.T) {
+	coupon := &entity.Coupon{ID: 7, Code: "SPRING20", PercentOff: 20, Duration: entity.CouponDurationRepeating, DurationInPeriods: 3, Status: entity.CouponStatusActive}
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "ch_1"}}
+	var redemptions []*entity.CouponRedemption
+	var attempts []*entity.PaymentAttempt
+	svc := newCouponServiceForTest(coupon, paySvc, &redemptions, &attempts)
+
+	_, err := svc.CreateSubscription(context.Background(), &types.CreateSubscriptionRequest{
+		SubscriptionTypeId: 2,
+		UserId:             "u-1",
+		StartAt:            time.Now().UTC().Format(time.RFC3339),
+		CouponCode:         " spring20 ",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(paySvc.charges) != 1 || paySvc.charges[0].AmountCents != 1200 || paySvc.charges[0].Currency != "EUR" {
+		t.Fatalf("expected a discounted charge of 1200 EUR, got %+v", paySvc.charges)
+	}
+	if len(attempts) != 1 || attempts[0].AmountCents != 1200 || attempts[0].DiscountCents != 300 {
+		t.Fatalf("unexpected payment attempts: %+v", attempts)
+	}
+	if len(redemptions) != 1 || redemptions[0].CouponID != 7 || redemptions[0].SubscriptionID != 102 ||
+		redemptions[0].DurationPeriods != 3 || redemptions[0].PeriodsApplied != 1 || redemptions[0].Status != entity.CouponRedemptionStatusActive {
+		t.Fatalf("expected the paid period to be counted against the redemption, got %+v", redemptions)
+	}
+}
+
+func TestCreatePlanSubscriptionReleasesCouponWhenChargeDeclined(t *testing.T) {
+	coupon := &entity.Coupon{ID: 7, Code: "SPRING20", PercentOff: 20, Duration: entity.CouponDurationOnce, Status: entity.CouponStatusActive, MaxRedemptions: 1}
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeFailure, Error: "card declined"}}
+	var redemptions []*entity.CouponRedemption
+	var attempts []*entity.PaymentAttempt
+	svc := newCouponServiceForTest(coupon, paySvc, &redemptions, &attempts)
+	var deleted, decremented []uint64
+	svc.redemptionRepo.(*mockCouponRedemptionRepo).deleteFn = func(_ context.Context, id uint64) error {
+		deleted = append(deleted, id)
+		return nil
+	}
+	svc.couponRepo.(*mockCouponRepo).decrementRedemptionsFn = func(_ context.Context, id uint64, _ time.Time) error {
+		decremented = append(decremented, id)
+		return nil
+	}
+
+	if _, err := svc.CreateSubscription(context.Background(), &types.CreateSubscriptionRequest{
+		SubscriptionTypeId: 2,
+		UserId:             "u-1",
+		StartAt:            time.Now().UTC().Format(time.RFC3339),
+		CouponCode:         "SPRING20",
+	}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(deleted) != 1 || len(decremented) != 1 || decremented[0] != 7 {
+		t.Fatalf("expected the declined sign-up to give its redemption back, got deleted %v decremented %v", deleted, decremented)
+	}
+	if len(redemptions) != 0 {
+		t.Fatalf("expected no discounted period to be counted, got %+v", redemptions)
+	}
+}
+
+func TestFullyDiscountedChargeSkipsProvider(t *testing.T) {
+	coupon := &entity.Coupon{ID: 7, Code: "FREEMONTH", PercentOff: 100, Duration: entity.CouponDurationOnce, Status: entity.CouponStatusActive}
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeFailure}}
+	var redemptions []*entity.CouponRedemption
+	var attempts []*entity.PaymentAttempt
+	svc := newCouponServiceForTest(coupon, paySvc, &redemptions, &attempts)
+
+	item, err := svc.CreateSubscription(context.Background(), &types.CreateSubscriptionRequest{
+		SubscriptionTypeId: 2,
+		UserId:             "u-1",
+		StartAt:            time.Now().UTC().Format(time.RFC3339),
+		CouponCode:         "FREEMONTH",
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if paySvc.calledCount != 0 {
+		t.Fatalf("expected the provider not to be called, got %d calls", paySvc.calledCount)
+	}
+	if item.Subscription.Status != entity.SubscriptionStatusActive {
+		t.Fatalf("expected active subscription, got %d", item.Subscription.Status)
+	}
+	if len(attempts) != 1 || attempts[0].AmountCents != 0 || attempts[0].DiscountCents != 1500 || attempts[0].Status != entity.PaymentAttemptStatusSucceeded {
+		t.Fatalf("unexpected payment attempts: %+v", attempts)
+	}
+	if len(redemptions) != 1 || redemptions[0].Status != entity.CouponRedemptionStatusEnded {
+		t.Fatalf("expected the once redemption to end after one period, got %+v", redemptions)
+	}
+}
+
+func TestCreatePlanSubscriptionRejectsUnredeemableCoupon(t *testing.T) {
+	past := time.Now().UTC().Add(-time.Hour)
+	cases := map[string]struct {
+		mutate func(coupon *entity.Coupon)
+		want   error
+	}{
+		"unknown":    {mutate: func(c *entity.Coupon) { c.Code = "WINTER" }, want: ErrCouponNotFound},
+		"disabled":   {mutate: func(c *entity.Coupon) { c.Status = entity.CouponStatusDisabled }, want: ErrCouponNotRedeemable},
+		"expired":    {mutate: func(c *entity.Coupon) { c.ExpiresAt = &past }, want: ErrCouponNotRedeemable},
+		"limit":      {mutate: func(c *entity.Coupon) { c.MaxRedemptions, c.TimesRedeemed = 5, 5 }, want: ErrCouponNotRedeemable},
+		"other plan": {mutate: func(c *entity.Coupon) { c.PlanTypeIDs = []uint64{21} }, want: ErrCouponNotRedeemable},
+		"currency": {mutate: func(c *entity.Coupon) {
+			c.PercentOff, c.AmountOffCents, c.Currency = 0, 500, "USD"
+		}, want: ErrCouponNotRedeemable},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			coupon := &entity.Coupon{ID: 7, Code: "SPRING20", PercentOff: 20, Duration: entity.CouponDurationOnce, Status: entity.CouponStatusActive}
+			tc.mutate(coupon)
+			paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}}
+			var redemptions []*entity.CouponRedemption
+			var attempts []*entity.PaymentAttempt
+			svc := newCouponServiceForTest(coupon, paySvc, &redemptions, &attempts)
+
+			_, err := svc.CreateSubscription(context.Background(), &types.CreateSubscriptionRequest{
+				SubscriptionTypeId: 2,
+				UserId:             "u-1",
+				StartAt:            time.Now().UTC().Format(time.RFC3339),
+				CouponCode:         "SPRING20",
+			})
+			if !errors.Is(err, tc.want) {
+				t.Fatalf("expected %v, got %v", tc.want, err)
+			}
+			if paySvc.calledCount != 0 {
+				t.Fatalf("expected no charge, got %d", paySvc.calledCount)
+			}
+		})
+	}
+}
+
+func TestRunAutoRenewalBatchEndsRepeatingCoupon(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(24 * time.Hour)
+	renewAt := time.Now().UTC().Add(-2 * time.Minute)
+	item := &entity.Subscription{ID: 11, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, AutoRenew: true, EndAt: &endAt, RenewAt: &renewAt}
+	redemption := &entity.CouponRedemption{ID: 3, CouponID: 7, SubscriptionID: 11, DurationPeriods: 2, PeriodsApplied: 1, Status: entity.CouponRedemptionStatusActive}
+
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}}
+	var updated []entity.CouponRedemption
+	svc := NewSubscriptionService(
+		&mockSubscriptionRepo{claimDueAutoRenewFn: func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+			return []*entity.Subscription{item}, nil
+		}},
+		&mockSubscriptionTypeRepo{},
+		&mockPlanTypeRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.PlanType, error) {
+			return &entity.PlanType{ID: 20, SubscriptionTypeID: 2, PriceCents: 1500, Currency: "EUR", IntervalUnit: entity.PlanIntervalDay, IntervalCount: 30}, nil
+		}},
+		&mockPaymentAttemptRepo{},
+		&mockSubscriptionEventRepo{},
+		&mockOutboxMessageRepo{},
+		&mockCouponRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.Coupon, error) {
+			return &entity.Coupon{ID: 7, AmountOffCents: 500, Currency: "EUR", Duration: entity.CouponDurationRepeating, DurationInPeriods: 2}, nil
+		}},
+		&mockCouponRedemptionRepo{
+			findActiveBySubscriptionIDFn: func(_ context.Context, _ uint64) (*entity.CouponRedemption, error) {
+				if redemption.Status != entity.CouponRedemptionStatusActive {
+					return nil, nil
+				}
+				cp := *redemption
+				return &cp, nil
+			},
+			updateFn: func(_ context.Context, r *entity.CouponRedemption) error {
+				*redemption = *r
+				updated = append(updated, *r)
+				return nil
+			},
+		},
+		&mockTxManager{},
+		paySvc,
+		testConfig(),
+	)
+
+	if _, err := svc.RunAutoRenewalBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(paySvc.charges) != 1 || paySvc.charges[0].AmountCents != 1000 {
+		t.Fatalf("expected the last discounted renewal to charge 1000, got %+v", paySvc.charges)
+	}
+	if len(updated) != 1 || updated[0].PeriodsApplied != 2 || updated[0].Status != entity.CouponRedemptionStatusEnded {
+		t.Fatalf("expected the redemption to end after its last period, got %+v", updated)
+	}
+}
+
+func TestPaymentCallbackCountsDiscountedPeriod(t *testing.T) {
+	repo := &mockSubscriptionRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+		return &entity.Subscription{ID: 4, SubscriptionTypeID: 2, Status: entity.SubscriptionStatusPendingPayment}, nil
+	}}
+	attemptRepo := pendingAttemptRepo(4, entity.PaymentAttemptStatusPending)
+	findAttempt := attemptRepo.findByTransactionIDFn
+	attemptRepo.findByTransactionIDFn = func(ctx context.Context, transactionID string) (*entity.PaymentAttempt, error) {
+		attempt, err := findAttempt(ctx, transactionID)
+		attempt.DiscountCents = 300
+		return attempt, err
+	}
+	var updated *entity.CouponRedemption
+	redemptionRepo := &mockCouponRedemptionRepo{
+		findActiveBySubscriptionIDFn: func(_ context.Context, _ uint64) (*entity.CouponRedemption, error) {
+			return &entity.CouponRedemption{ID: 3, SubscriptionID: 4, Status: entity.CouponRedemptionStatusActive}, nil
+		},
+		updateFn: func(_ context.Context, redemption *entity.CouponRedemption) error {
+			updated = redemption
+			return nil
+		},
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, attemptRepo, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, redemptionRepo, &mockTxManager{}, testConfig())
+
+	if _, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "tx-1"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated == nil || updated.PeriodsApplied != 1 || updated.Status != entity.CouponRedemptionStatusActive {
+		t.Fatalf("expected a forever redemption to count the paid period, got %+v", updated)
+	}
+}
+
+func TestPaymentCallbackReturnsDiscountedPeriodError(t *testing.T) {
+	repo := &mockSubscriptionRepo{findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+		return &entity.Subscription{ID: 4, SubscriptionTypeID: 2, Status: entity.SubscriptionStatusPendingPayment}, nil
+	}}
+	attemptRepo := pendingAttemptRepo(4, entity.PaymentAttemptStatusPending)
+	findAttempt := attemptRepo.findByTransactionIDFn
+	attemptRepo.findByTransactionIDFn = func(ctx context.Context, transactionID string) (*entity.PaymentAttempt, error) {
+		attempt, err := findAttempt(ctx, transactionID)
+		attempt.DiscountCents = 300
+		return attempt, err
+	}
+	redemptionRepo := &mockCouponRedemptionRepo{
+		findActiveBySubscriptionIDFn: func(_ context.Context, _ uint64) (*entity.CouponRedemption, error) {
+			return &entity.CouponRedemption{ID: 3, SubscriptionID: 4, Status: entity.CouponRedemptionStatusActive}, nil
+		},
+		updateFn: func(_ context.Context, _ *entity.CouponRedemption) error {
+			return errors.New("db down")
+		},
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, attemptRepo, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, redemptionRepo, &mockTxManager{}, testConfig())
+
+	if _, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "tx-1"}); err == nil {
+		t.Fatal("expected the redemption error to be returned")
+	}
+}
+
+func TestUpdateQuantityProratesSeats(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(15 * 24 * time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, Quantity: 2, AutoRenew: true, EndAt: &endAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "ch_1"}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+
+	res, err := svc.UpdateQuantity(context.Background(), &types.UpdateQuantityRequest{Id: 4, Quantity: 5})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.ProratedAmountCents != 1500 || len(paySvc.adjustments) != 1 || paySvc.adjustments[0].AmountCents != 1500 {
+		t.Fatalf("expected half a period of three seats to be charged, got %d %+v", res.ProratedAmountCents, paySvc.adjustments)
+	}
+	if len(attempts) != 1 || attempts[0].Kind != entity.PaymentAttemptKindQuantityChange || attempts[0].Quantity != 5 || attempts[0].Status != entity.PaymentAttemptStatusSucceeded {
+		t.Fatalf("unexpected payment attempts: %+v", attempts)
+	}
+	if len(updates) != 1 || updates[0].Quantity != 5 || !updates[0].EndAt.Equal(endAt) {
+		t.Fatalf("expected five seats within the current period, got %+v", updates)
+	}
+	if len(messages) != 1 || messages[0].EventType != entity.OutboxEventSubscriptionQuantityChanged {
+		t.Fatalf("expected a quantity_changed event, got %+v", messages)
+	}
+
+	updates, attempts, messages = nil, nil, nil
+	paySvc = &fakePaymentService{result: payment.Result{Type: payment.ResultTypeFailure, Error: "card declined"}}
+	svc = newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+	if _, err := svc.UpdateQuantity(context.Background(), &types.UpdateQuantityRequest{Id: 4, Quantity: 1}); !errors.Is(err, ErrPaymentDeclined) {
+		t.Fatalf("expected ErrPaymentDeclined, got %v", err)
+	}
+	if len(updates) != 0 || len(paySvc.adjustments) != 1 || paySvc.adjustments[0].AmountCents != -500 {
+		t.Fatalf("expected a declined credit to keep the quantity, got updates=%+v adjustments=%+v", updates, paySvc.adjustments)
+	}
+
+	res, err = svc.UpdateQuantity(context.Background(), &types.UpdateQuantityRequest{Id: 4, Quantity: 1, Proration: "none"})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res.ProratedAmountCents != 0 || len(paySvc.adjustments) != 1 || len(updates) != 1 || updates[0].Quantity != 1 {
+		t.Fatalf("expected the quantity to change without billing, got %d updates=%+v", res.ProratedAmountCents, updates)
+	}
+}
+
+func TestUpdateQuantityRetryReusesOpenAttempt(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(15 * 24 * time.Hour)
+	updatedAt := time.Now().UTC().Add(-time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, Quantity: 2, EndAt: &endAt, UpdatedAt: updatedAt}
+
+	var updates []*entity.Subscription
+	var messages []*entity.OutboxMessage
+	// A request that crashed mid-charge left its adjustment pending.
+	attempts := []*entity.PaymentAttempt{{ID: 7, SubscriptionID: 4, PlanTypeID: 20, Kind: entity.PaymentAttemptKindQuantityChange, AmountCents: 1500, Currency: "EUR", Quantity: 5, Status: entity.PaymentAttemptStatusPending, CreatedAt: updatedAt.Add(time.Minute)}}
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "ch_1"}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+	svc.paymentAttemptRepo.(*mockPaymentAttemptRepo).listFn = func(context.Context, uint64) ([]*entity.PaymentAttempt, error) {
+		return attempts, nil
+	}
+
+	res, err := svc.UpdateQuantity(context.Background(), &types.UpdateQuantityRequest{Id: 4, Quantity: 5})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(attempts) != 1 || attempts[0].Status != entity.PaymentAttemptStatusSucceeded {
+		t.Fatalf("expected the open attempt to be reused, got %+v", attempts)
+	}
+	if len(paySvc.adjustments) != 1 || paySvc.adjustments[0].IdempotencyKey != "attempt-7" {
+		t.Fatalf("expected the retry to be sent under the same key, got %+v", paySvc.adjustments)
+	}
+	if res.Subscription.Quantity != 5 || len(updates) != 1 || updates[0].Quantity != 5 {
+		t.Fatalf("expected five seats to be saved once, got %+v", updates)
+	}
+
+	// A different quantity is priced afresh and supersedes the open attempt.
+	attempts[0].Status = entity.PaymentAttemptStatusPending
+	if _, err := svc.UpdateQuantity(context.Background(), &types.UpdateQuantityRequest{Id: 4, Quantity: 3}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(attempts) != 2 || attempts[1].Quantity != 3 || paySvc.adjustments[1].IdempotencyKey == paySvc.adjustments[0].IdempotencyKey {
+		t.Fatalf("expected a new attempt for another quantity, got %+v", attempts)
+	}
+}
+
+func TestUpdateQuantityReversesChargeWhenSaveFails(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(15 * 24 * time.Hour)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, Quantity: 2, EndAt: &endAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "ch_1"}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+	svc.paymentAttemptRepo.(*mockPaymentAttemptRepo).listFn = func(context.Context, uint64) ([]*entity.PaymentAttempt, error) {
+		return attempts, nil
+	}
+	subscriptionRepo := svc.subscriptionRepo.(*mockSubscriptionRepo)
+	subscriptionRepo.updateFn = func(context.Context, *entity.Subscription) error {
+		return repository.ErrConcurrentModification
+	}
+
+	if _, err := svc.UpdateQuantity(context.Background(), &types.UpdateQuantityRequest{Id: 4, Quantity: 5}); !errors.Is(err, ErrConcurrentModification) {
+		t.Fatalf("expected the write to give up after the retries, got %v", err)
+	}
+	if len(attempts) != 2 || attempts[1].Kind != entity.PaymentAttemptKindReversal || attempts[1].Status != entity.PaymentAttemptStatusSucceeded {
+		t.Fatalf("expected the charge to be reversed, got %+v", attempts)
+	}
+	if len(paySvc.adjustments) != 2 || paySvc.adjustments[1].AmountCents != -1500 {
+		t.Fatalf("expected the reversal to give the charge back, got %+v", paySvc.adjustments)
+	}
+
+	// The subscription is paused and resumed before the change is asked for
+	// again.
+	subscription.UpdatedAt = time.Now().UTC().Add(time.Second)
+	subscriptionRepo.updateFn = func(_ context.Context, item *entity.Subscription) error {
+		updates = append(updates, copySubscription(item))
+		return nil
+	}
+	res, err := svc.UpdateQuantity(context.Background(), &types.UpdateQuantityRequest{Id: 4, Quantity: 5})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	var billed int64
+	for _, adjustment := range paySvc.adjustments {
+		billed += adjustment.AmountCents
+	}
+	if len(paySvc.adjustments) != 3 || billed != res.ProratedAmountCents {
+		t.Fatalf("expected the customer to be billed once for the seats, got %+v", paySvc.adjustments)
+	}
+	if len(updates) != 1 || updates[0].Quantity != 5 {
+		t.Fatalf("expected five seats to be saved once, got %+v", updates)
+	}
+}
+
+func TestRunAutoRenewalBatchChargesEverySeat(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(time.Hour)
+	renewAt := time.Now().UTC().Add(-time.Minute)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusActive, Quantity: 3, AutoRenew: true, EndAt: &endAt, RenewAt: &renewAt}
+
+	var updates []*entity.Subscription
+	var attempts []*entity.PaymentAttempt
+	var messages []*entity.OutboxMessage
+	paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess}}
+	svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+	svc.subscriptionRepo.(*mockSubscriptionRepo).claimDueAutoRenewFn = func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+		return []*entity.Subscription{copySubscription(subscription)}, nil
+	}
+
+	if _, err := svc.RunAutoRenewalBatch(context.Background()); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(paySvc.charges) != 1 || paySvc.charges[0].AmountCents != 3000 || paySvc.charges[0].Quantity != 3 {
+		t.Fatalf("expected three seats to be charged, got %+v", paySvc.charges)
+	}
+	if len(attempts) != 1 || attempts[0].AmountCents != 3000 || attempts[0].Quantity != 3 {
+		t.Fatalf("unexpected payment attempts: %+v", attempts)
+	}
+}
+
+func TestRunAutoRenewalBatchResumesInterruptedRenewal(t *testing.T) {
+	planTypeID := uint64(20)
+	endAt := time.Now().UTC().Add(time.Hour)
+	renewAt := time.Now().UTC().Add(-time.Hour)
+	startedAt := time.Now().UTC().Add(-30 * time.Minute)
+	subscription := &entity.Subscription{ID: 4, SubscriptionTypeID: 2, PlanTypeID: &planTypeID, Status: entity.SubscriptionStatusProcessing, AutoRenew: true, EndAt: &endAt, RenewAt: &renewAt, UpdatedAt: startedAt}
+
+	cases := map[string]struct {
+		left         []*entity.PaymentAttempt
+		wantProvider int
+		wantCreated  int
+		wantStatus   int32
+	}{
+		"stopped before the charge": {
+			left:         []*entity.PaymentAttempt{{ID: 8, Kind: entity.PaymentAttemptKindRenewal, Status: entity.PaymentAttemptStatusSucceeded, CreatedAt: startedAt.Add(-30 * 24 * time.Hour)}},
+			wantProvider: 1, wantCreated: 1, wantStatus: entity.SubscriptionStatusActive,
+		},
+		"stopped during the charge": {
+			left:         []*entity.PaymentAttempt{{ID: 9, Kind: entity.PaymentAttemptKindRenewal, PlanTypeID: 20, Quantity: 1, AmountCents: 1000, Status: entity.PaymentAttemptStatusPending, CreatedAt: startedAt}},
+			wantProvider: 1, wantStatus: entity.SubscriptionStatusActive,
+		},
+		"stopped after a successful charge": {
+			left:       []*entity.PaymentAttempt{{ID: 9, Kind: entity.PaymentAttemptKindRenewal, Status: entity.PaymentAttemptStatusSucceeded, ResultType: string(payment.ResultTypeSuccess), CreatedAt: startedAt.Add(time.Second)}},
+			wantStatus: entity.SubscriptionStatusActive,
+		},
+		"stopped after a declined charge": {
+			left:       []*entity.PaymentAttempt{{ID: 9, Kind: entity.PaymentAttemptKindRenewal, Status: entity.PaymentAttemptStatusFailed, ResultType: string(payment.ResultTypeFailure), Error: "card declined", CreatedAt: startedAt.Add(time.Second)}},
+			wantStatus: entity.SubscriptionStatusPastDue,
+		},
+	}
+	for name, tc := range cases {
+		var updates []*entity.Subscription
+		var attempts []*entity.PaymentAttempt
+		var messages []*entity.OutboxMessage
+		paySvc := &fakePaymentService{result: payment.Result{Type: payment.ResultTypeSuccess, TransactionID: "ch_1"}}
+		svc := newPlanChangeServiceForTest(subscription, paySvc, &updates, &attempts, &messages)
+		svc.subscriptionRepo.(*mockSubscriptionRepo).claimDueAutoRenewFn = func(_ context.Context, _ time.Time, _ repository.SubscriptionClaim) ([]*entity.Subscription, error) {
+			return []*entity.Subscription{copySubscription(subscription)}, nil
+		}
+		svc.paymentAttemptRepo.(*mockPaymentAttemptRepo).listFn = func(context.Context, uint64) ([]*entity.PaymentAttempt, error) {
+			return tc.left, nil
+		}
+
+		if _, err := svc.RunAutoRenewalBatch(context.Background()); err != nil {
+			t.Fatalf("%s: expected no error, got %v", name, err)
+		}
+		if len(paySvc.charges) != tc.wantProvider || len(attempts) != tc.wantCreated {
+			t.Fatalf("%s: expected %d charges and %d new attempts, got %+v %+v", name, tc.wantProvider, tc.wantCreated, paySvc.charges, attempts)
+		}
+		if tc.wantCreated == 0 && tc.wantProvider == 1 && paySvc.charges[0].IdempotencyKey != "attempt-9" {
+			t.Fatalf("%s: expected the pending attempt to be sent again, got %q", name, paySvc.charges[0].IdempotencyKey)
+		}
+		if len(updates) != 1 || updates[0].Status != tc.wantStatus {
+			t.Fatalf("%s: expected the renewal to finish in one write with status %d, got %+v", name, tc.wantStatus, updates)
+		}
+		if tc.wantStatus == entity.SubscriptionStatusActive && !updates[0].EndAt.After(endAt) {
+			t.Fatalf("%s: expected the period to be extended, got %+v", name, updates[0])
+		}
+	}
+}
+
+func TestPaymentCallbackQuantityChangeAppliesQuantity(t *testing.T) {
+	var updated *entity.Subscription
+	repo := &mockSubscriptionRepo{
+		findByIDFn: func(_ context.Context, _ uint64) (*entity.Subscription, error) {
+			return &entity.Subscription{ID: 4, SubscriptionTypeID: 2, Status: entity.SubscriptionStatusActive, Quantity: 2}, nil
+		},
+		updateFn: func(_ context.Context, subscription *entity.Subscription) error {
+			updated = copySubscription(subscription)
+			return nil
+		},
+	}
+	attemptRepo := pendingAttemptRepo(4, entity.PaymentAttemptStatusPending)
+	findAttempt := attemptRepo.findByTransactionIDFn
+	attemptRepo.findByTransactionIDFn = func(ctx context.Context, transactionID string) (*entity.PaymentAttempt, error) {
+		attempt, err := findAttempt(ctx, transactionID)
+		attempt.Kind = entity.PaymentAttemptKindQuantityChange
+		attempt.Quantity = 6
+		return attempt, err
+	}
+	svc := NewPaymentCallbackService(repo, &mockPlanTypeRepo{}, attemptRepo, &mockSubscriptionEventRepo{}, &mockOutboxMessageRepo{}, &mockCouponRedemptionRepo{}, &mockTxManager{}, testConfig())
+
+	if _, err := svc.PaymentCallback(context.Background(), &types.PaymentCallbackRequest{SubscriptionId: 4, Status: "success", TransactionId: "tx-1"}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if updated == nil || updated.Quantity != 6 || updated.Status != entity.SubscriptionStatusActive {
+		t.Fatalf("expected the confirmed quantity to be applied, got %+v", updated)
+	}
+}
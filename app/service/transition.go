@@ -0,0 +1,90 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+)
+
+// subscriptionTransitions lists the statuses a subscription may move to from each
+// status. Keeping the current status is always allowed.
+var subscriptionTransitions = map[int32][]int32{
+	entity.SubscriptionStatusInactive: {
+		entity.SubscriptionStatusProcessing,
+	},
+	entity.SubscriptionStatusProcessing: {
+		entity.SubscriptionStatusPendingPayment,
+		entity.SubscriptionStatusPastDue,
+		entity.SubscriptionStatusTrialing,
+		entity.SubscriptionStatusActive,
+		entity.SubscriptionStatusInactive,
+	},
+	entity.SubscriptionStatusTrialing: {
+		entity.SubscriptionStatusProcessing,
+		entity.SubscriptionStatusInactive,
+	},
+	entity.SubscriptionStatusPendingPayment: {
+		entity.SubscriptionStatusProcessing,
+		entity.SubscriptionStatusPastDue,
+		entity.SubscriptionStatusActive,
+		entity.SubscriptionStatusInactive,
+	},
+	entity.SubscriptionStatusActive: {
+		entity.SubscriptionStatusProcessing,
+		entity.SubscriptionStatusPaused,
+		entity.SubscriptionStatusInactive,
+	},
+	entity.SubscriptionStatusPastDue: {
+		entity.SubscriptionStatusProcessing,
+		entity.SubscriptionStatusActive,
+		entity.SubscriptionStatusInactive,
+	},
+	entity.SubscriptionStatusPaused: {
+		entity.SubscriptionStatusActive,
+		entity.SubscriptionStatusInactive,
+	},
+}
+
+// transitionSubscriptionStatus moves the subscription to the given status if the
+// transition table allows it. Every status change goes through here.
+func transitionSubscriptionStatus(subscription *entity.Subscription, to int32) error {
+	if !canTransitionSubscriptionStatus(subscription.Status, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrInvalidTransition, subscriptionStatusName(subscription.Status), subscriptionStatusName(to))
+	}
+	subscription.Status = to
+	return nil
+}
+
+func canTransitionSubscriptionStatus(from, to int32) bool {
+	if from == to {
+		return true
+	}
+	for _, allowed := range subscriptionTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+func subscriptionStatusName(status int32) string {
+	switch status {
+	case entity.SubscriptionStatusInactive:
+		return "inactive"
+	case entity.SubscriptionStatusProcessing:
+		return "processing"
+	case entity.SubscriptionStatusPendingPayment:
+		return "pending_payment"
+	case entity.SubscriptionStatusPaused:
+		return "paused"
+	case entity.SubscriptionStatusPastDue:
+		return "past_due"
+	case entity.SubscriptionStatusTrialing:
+		return "trialing"
+	case entity.SubscriptionStatusActive:
+		return "active"
+	default:
+		return strconv.Itoa(int(status))
+	}
+}
@@ -0,0 +1,345 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/entity"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/publisher"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+const (
+	webhookSecretPrefix     = "whsec_"
+	webhookDeliveryLogLimit = 100
+)
+
+type webhookEndpointRepository interface {
+	Create(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	Update(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+	FindByID(ctx context.Context, id uint64) (*entity.WebhookEndpoint, error)
+	List(ctx context.Context) ([]*entity.WebhookEndpoint, error)
+	ListActive(ctx context.Context) ([]*entity.WebhookEndpoint, error)
+	Delete(ctx context.Context, id uint64) error
+}
+
+type webhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) error
+	Update(ctx context.Context, delivery *entity.WebhookDelivery) error
+	ClaimDue(ctx context.Context, claim repository.WebhookDeliveryClaim) ([]*entity.WebhookDelivery, error)
+	ReleaseClaims(ctx context.Context, owner string) error
+	ListByEndpointID(ctx context.Context, endpointID uint64, limit int) ([]*entity.WebhookDelivery, error)
+}
+
+type webhookSender interface {
+	Send(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (int, error)
+}
+
+// WebhookService manages the webhook endpoints registered by client services and
+// delivers subscription domain events to them.
+type WebhookService struct {
+	endpointRepo webhookEndpointRepository
+	deliveryRepo webhookDeliveryRepository
+	sender       webhookSender
+	cfg          config.WebhookConfig
+	lookupIPAddr func(ctx context.Context, host string) ([]net.IPAddr, error)
+}
+
+func NewWebhookService(
+	endpointRepo webhookEndpointRepository,
+	deliveryRepo webhookDeliveryRepository,
+	sender webhookSender,
+	cfg config.WebhookConfig,
+) *WebhookService {
+	return &WebhookService{
+		endpointRepo: endpointRepo,
+		deliveryRepo: deliveryRepo,
+		sender:       sender,
+		cfg:          cfg,
+		lookupIPAddr: net.DefaultResolver.LookupIPAddr,
+	}
+}
+
+// CreateWebhookEndpoint registers an active endpoint with a freshly generated
+// signing secret. The secret is only ever returned by this call.
+func (s *WebhookService) CreateWebhookEndpoint(ctx context.Context, req *types.CreateWebhookEndpointRequest) (*entity.WebhookEndpoint, error) {
+	if err := s.validateURL(ctx, req.GetUrl()); err != nil {
+		return nil, err
+	}
+	if err := validateEventTypes(req.GetEventTypes()); err != nil {
+		return nil, err
+	}
+
+	secret, err := generateWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	endpoint := &entity.WebhookEndpoint{
+		URL:         req.GetUrl(),
+		Description: req.GetDescription(),
+		EventTypes:  req.GetEventTypes(),
+		Secret:      secret,
+		Status:      entity.WebhookEndpointStatusActive,
+		CreatedBy:   actorFromContext(ctx).Name,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := s.endpointRepo.Create(ctx, endpoint); err != nil {
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+func (s *WebhookService) GetWebhookEndpoint(ctx context.Context, id uint64) (*entity.WebhookEndpoint, error) {
+	endpoint, err := s.endpointRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if endpoint == nil {
+		return nil, ErrWebhookEndpointNotFound
+	}
+	return endpoint, nil
+}
+
+func (s *WebhookService) ListWebhookEndpoints(ctx context.Context) ([]*entity.WebhookEndpoint, error) {
+	return s.endpointRepo.List(ctx)
+}
+
+func (s *WebhookService) UpdateWebhookEndpoint(ctx context.Context, req *types.UpdateWebhookEndpointRequest) (*entity.WebhookEndpoint, error) {
+	if !req.GetHasUrl() && !req.GetHasDescription() && !req.GetHasEventTypes() && !req.GetHasStatus() {
+		return nil, ErrNoFieldsToUpdate
+	}
+
+	endpoint, err := s.GetWebhookEndpoint(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+
+	if req.GetHasUrl() {
+		if err := s.validateURL(ctx, req.GetUrl()); err != nil {
+			return nil, err
+		}
+		endpoint.URL = req.GetUrl()
+	}
+	if req.GetHasDescription() {
+		endpoint.Description = req.GetDescription()
+	}
+	if req.GetHasEventTypes() {
+		if err := validateEventTypes(req.GetEventTypes()); err != nil {
+			return nil, err
+		}
+		endpoint.EventTypes = req.GetEventTypes()
+	}
+	if req.GetHasStatus() {
+		switch req.GetStatus() {
+		case entity.WebhookEndpointStatusDisabled, entity.WebhookEndpointStatusActive:
+		default:
+			return nil, ErrInvalidStatus
+		}
+		endpoint.Status = req.GetStatus()
+	}
+	endpoint.UpdatedAt = time.Now().UTC()
+
+	if err := s.endpointRepo.Update(ctx, endpoint); err != nil {
+		if errors.Is(err, repository.ErrWebhookEndpointNotFound) {
+			return nil, ErrWebhookEndpointNotFound
+		}
+		return nil, err
+	}
+
+	return endpoint, nil
+}
+
+// DeleteWebhookEndpoint removes the endpoint together with its delivery log.
+func (s *WebhookService) DeleteWebhookEndpoint(ctx context.Context, id uint64) error {
+	if err := s.endpointRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrWebhookEndpointNotFound) {
+			return ErrWebhookEndpointNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// ListWebhookDeliveries returns the most recent deliveries of an endpoint, newest first.
+func (s *WebhookService) ListWebhookDeliveries(ctx context.Context, endpointID uint64) ([]*entity.WebhookDelivery, error) {
+	if _, err := s.GetWebhookEndpoint(ctx, endpointID); err != nil {
+		return nil, err
+	}
+	return s.deliveryRepo.ListByEndpointID(ctx, endpointID, webhookDeliveryLogLimit)
+}
+
+// Publish queues a delivery of message for every active endpoint subscribed to
+// its type. It lets the outbox relay fan events out to webhooks; a message the
+// relay hands over again is not queued twice for the same endpoint.
+func (s *WebhookService) Publish(ctx context.Context, message publisher.Message) error {
+	endpoints, err := s.endpointRepo.ListActive(ctx)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	now := time.Now().UTC()
+	for _, endpoint := range endpoints {
+		if !endpoint.Accepts(message.Type) {
+			continue
+		}
+		if payload == nil {
+			if payload, err = json.Marshal(message); err != nil {
+				return err
+			}
+		}
+
+		delivery := &entity.WebhookDelivery{
+			WebhookEndpointID: endpoint.ID,
+			EventID:           message.ID,
+			EventType:         message.Type,
+			SubscriptionID:    message.SubscriptionID,
+			Payload:           payload,
+			Status:            entity.WebhookDeliveryStatusPending,
+			NextAttemptAt:     now,
+			CreatedAt:         now,
+			UpdatedAt:         now,
+		}
+		if err := s.deliveryRepo.Create(ctx, delivery); err != nil && !errors.Is(err, repository.ErrWebhookDeliveryAlreadyExists) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RunDeliveryBatch sends one batch of due deliveries. A failed delivery is retried
+// with exponential backoff; after MaxAttempts, or once its endpoint is disabled,
+// the delivery is dead-lettered and kept in the delivery log. The batch is
+// leased while it is sent, so delivery workers can run side by side without
+// sending a delivery twice.
+func (s *WebhookService) RunDeliveryBatch(ctx context.Context) error {
+	owner, err := newClaimOwner(s.cfg.WorkerID)
+	if err != nil {
+		return err
+	}
+	// Deliveries the batch did not get to wait for their lease to lapse when the
+	// release fails.
+	defer func() { _ = s.deliveryRepo.ReleaseClaims(context.WithoutCancel(ctx), owner) }()
+
+	now := time.Now().UTC()
+	items, err := s.deliveryRepo.ClaimDue(ctx, repository.WebhookDeliveryClaim{
+		Owner: owner,
+		Now:   now,
+		Until: now.Add(s.cfg.ClaimTTL),
+		Limit: s.cfg.BatchSize,
+	})
+	if err != nil {
+		return err
+	}
+
+	endpoints := make(map[uint64]*entity.WebhookEndpoint)
+	for _, item := range items {
+		endpoint, ok := endpoints[item.WebhookEndpointID]
+		if !ok {
+			if endpoint, err = s.endpointRepo.FindByID(ctx, item.WebhookEndpointID); err != nil {
+				return err
+			}
+			endpoints[item.WebhookEndpointID] = endpoint
+		}
+
+		if endpoint == nil || endpoint.Status != entity.WebhookEndpointStatusActive {
+			item.Status = entity.WebhookDeliveryStatusDead
+			item.LastError = "webhook endpoint disabled"
+			item.UpdatedAt = time.Now().UTC()
+		} else {
+			responseStatus, sendErr := s.sender.Send(ctx, endpoint, item)
+			s.recordAttempt(item, responseStatus, sendErr)
+		}
+
+		if err := s.deliveryRepo.Update(ctx, item); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *WebhookService) recordAttempt(item *entity.WebhookDelivery, responseStatus int, sendErr error) {
+	now := time.Now().UTC()
+	item.Attempts++
+	item.ResponseStatus = responseStatus
+	item.UpdatedAt = now
+	if sendErr == nil {
+		item.Status = entity.WebhookDeliveryStatusSucceeded
+		item.LastError = ""
+		item.DeliveredAt = &now
+		return
+	}
+
+	item.LastError = truncateString(sendErr.Error(), maxErrorLength)
+	if item.Attempts >= s.cfg.MaxAttempts {
+		item.Status = entity.WebhookDeliveryStatusDead
+		return
+	}
+	item.NextAttemptAt = now.Add(retryBackoff(s.cfg.RetryBackoff, item.Attempts))
+}
+
+// validateURL accepts absolute https URLs whose host resolves to public
+// addresses only. The sender checks the address again when it connects, since
+// the host may resolve differently by then.
+func (s *WebhookService) validateURL(ctx context.Context, raw string) error {
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Hostname() == "" {
+		return fmt.Errorf("%w: url must be an absolute URL", ErrInvalidRequest)
+	}
+	if parsed.Scheme != "https" && !(parsed.Scheme == "http" && s.cfg.AllowInsecureURLs) {
+		return fmt.Errorf("%w: url must use https", ErrInvalidRequest)
+	}
+	if s.cfg.AllowPrivateURLs {
+		return nil
+	}
+
+	addrs, err := s.lookupIPAddr(ctx, parsed.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return fmt.Errorf("%w: url host %q does not resolve", ErrInvalidRequest, parsed.Hostname())
+	}
+	for _, addr := range addrs {
+		if err := publisher.CheckWebhookIP(addr.IP); err != nil {
+			return fmt.Errorf("%w: url host %q resolves to a non-public address", ErrInvalidRequest, parsed.Hostname())
+		}
+	}
+	return nil
+}
+
+func validateEventTypes(items []string) error {
+	for _, item := range items {
+		known := false
+		for _, eventType := range entity.OutboxEventTypes {
+			if item == eventType {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidRequest, item)
+		}
+	}
+	return nil
+}
+
+func generateWebhookSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return webhookSecretPrefix + hex.EncodeToString(buf), nil
+}
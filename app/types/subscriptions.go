@@ -1,7 +1,10 @@
 package types
 
 import (
+	"encoding/json"
 	"errors"
+	"net/url"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -9,6 +12,22 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
+const (
+	maxSubscriptionTypeLength = 50
+	maxPlanCodeLength         = 50
+	maxDisplayNameLength      = 255
+	maxTrialDays              = 365
+	maxCancelReasonLength     = 255
+	maxCouponCodeLength       = 50
+	maxCouponDescription      = 255
+	maxQuantity               = 10000
+)
+
+var (
+	planCodePattern   = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+	couponCodePattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9_-]*$`)
+)
+
 func NewListSubscriptionTypesRequestFromContext(ctx echo.Context) (*ListSubscriptionTypesRequest, error) {
 	statusRaw := strings.TrimSpace(ctx.QueryParam("status"))
 	req := &ListSubscriptionTypesRequest{Type: strings.TrimSpace(ctx.QueryParam("type"))}
@@ -31,77 +50,397 @@ func (r *ListSubscriptionTypesRequest) Validate() error {
 	return nil
 }
 
-func NewCreateSubscriptionRequestFromContext(ctx echo.Context) (*CreateSubscriptionRequest, error) {
-	var body CreateSubscriptionRequest
+func NewListPlanTypesRequestFromContext(ctx echo.Context) (*ListPlanTypesRequest, error) {
+	req := &ListPlanTypesRequest{}
+	if raw := strings.TrimSpace(ctx.QueryParam("subscription_type_id")); raw != "" {
+		subscriptionTypeID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		req.SubscriptionTypeId = subscriptionTypeID
+	}
+	if raw := strings.TrimSpace(ctx.QueryParam("include_archived")); raw != "" {
+		includeArchived, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, err
+		}
+		req.IncludeArchived = includeArchived
+	}
+	return req, nil
+}
+
+func (r *ListPlanTypesRequest) Validate() error {
+	return nil
+}
+
+func NewGetPlanTypeRequestFromContext(ctx echo.Context) (*GetPlanTypeRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &GetPlanTypeRequest{Id: id}, nil
+}
+
+func (r *GetPlanTypeRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid plan type id")
+	}
+	return nil
+}
+
+func NewCreateSubscriptionTypeRequestFromContext(ctx echo.Context) (*CreateSubscriptionTypeRequest, error) {
+	var body CreateSubscriptionTypeRequest
 	if err := ctx.Bind(&body); err != nil {
 		return nil, err
 	}
-	body.UserId = strings.TrimSpace(body.UserId)
-	body.Email = strings.TrimSpace(body.Email)
-	body.StartAt = strings.TrimSpace(body.StartAt)
+	body.Type = strings.TrimSpace(body.Type)
+	body.DisplayName = strings.TrimSpace(body.DisplayName)
 	return &body, nil
 }
 
-func (r *CreateSubscriptionRequest) Validate() error {
+func (r *CreateSubscriptionTypeRequest) Validate() error {
+	if r.GetType() == "" || len(r.GetType()) > maxSubscriptionTypeLength {
+		return errors.New("type is required and must be at most 50 characters")
+	}
+	return validateDisplayName(r.GetDisplayName())
+}
+
+func NewUpdateSubscriptionTypeRequestFromContext(ctx echo.Context) (*UpdateSubscriptionTypeRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		DisplayName *string `json:"display_name"`
+		Status      *int32  `json:"status"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+
+	req := &UpdateSubscriptionTypeRequest{Id: id}
+	if body.DisplayName != nil {
+		req.HasDisplayName = true
+		req.DisplayName = strings.TrimSpace(*body.DisplayName)
+	}
+	if body.Status != nil {
+		req.HasStatus = true
+		req.Status = *body.Status
+	}
+
+	return req, nil
+}
+
+func (r *UpdateSubscriptionTypeRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid subscription type id")
+	}
+	if !r.GetHasDisplayName() && !r.GetHasStatus() {
+		return errors.New("at least one of display_name or status is required")
+	}
+	if r.GetHasDisplayName() {
+		if err := validateDisplayName(r.GetDisplayName()); err != nil {
+			return err
+		}
+	}
+	if r.GetHasStatus() && r.GetStatus() != 0 && r.GetStatus() != 10 {
+		return errors.New("status must be 0 or 10")
+	}
+	return nil
+}
+
+func NewArchiveSubscriptionTypeRequestFromContext(ctx echo.Context) (*ArchiveSubscriptionTypeRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchiveSubscriptionTypeRequest{Id: id}, nil
+}
+
+func (r *ArchiveSubscriptionTypeRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid subscription type id")
+	}
+	return nil
+}
+
+func NewCreatePlanTypeRequestFromContext(ctx echo.Context) (*CreatePlanTypeRequest, error) {
+	var body struct {
+		SubscriptionTypeID uint64          `json:"subscription_type_id"`
+		PlanCode           string          `json:"plan_code"`
+		DisplayName        string          `json:"display_name"`
+		Description        string          `json:"description"`
+		PriceCents         int64           `json:"price_cents"`
+		Currency           string          `json:"currency"`
+		IntervalUnit       string          `json:"interval_unit"`
+		IntervalCount      int32           `json:"interval_count"`
+		TrialDays          int32           `json:"trial_days"`
+		Features           json.RawMessage `json:"features"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+
+	features, err := featuresFromJSON(body.Features)
+	if err != nil {
+		return nil, err
+	}
+	return &CreatePlanTypeRequest{
+		SubscriptionTypeId: body.SubscriptionTypeID,
+		PlanCode:           strings.TrimSpace(body.PlanCode),
+		DisplayName:        strings.TrimSpace(body.DisplayName),
+		Description:        strings.TrimSpace(body.Description),
+		PriceCents:         body.PriceCents,
+		Currency:           strings.ToUpper(strings.TrimSpace(body.Currency)),
+		IntervalUnit:       strings.ToLower(strings.TrimSpace(body.IntervalUnit)),
+		IntervalCount:      body.IntervalCount,
+		TrialDays:          body.TrialDays,
+		Features:           features,
+	}, nil
+}
+
+func (r *CreatePlanTypeRequest) Validate() error {
 	if r.GetSubscriptionTypeId() == 0 {
 		return errors.New("subscription_type_id is required")
 	}
-	if strings.TrimSpace(r.GetUserId()) == "" && strings.TrimSpace(r.GetEmail()) == "" {
-		return errors.New("at least one of user_id or email is required")
+	if !planCodePattern.MatchString(r.GetPlanCode()) || len(r.GetPlanCode()) > maxPlanCodeLength {
+		return errors.New("plan_code must be at most 50 lowercase letters, digits, '-' or '_'")
 	}
-	if r.GetStartAt() != "" {
-		if _, err := time.Parse(time.RFC3339, r.GetStartAt()); err != nil {
-			return errors.New("start_at must be RFC3339")
+	if err := validateDisplayName(r.GetDisplayName()); err != nil {
+		return err
+	}
+	if err := validatePlanPricing(r.GetPriceCents(), r.GetCurrency()); err != nil {
+		return err
+	}
+	if err := validatePlanInterval(r.GetIntervalUnit(), r.GetIntervalCount()); err != nil {
+		return err
+	}
+	return validateTrialDays(r.GetTrialDays())
+}
+
+func NewUpdatePlanTypeRequestFromContext(ctx echo.Context) (*UpdatePlanTypeRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		DisplayName   *string          `json:"display_name"`
+		Description   *string          `json:"description"`
+		PriceCents    *int64           `json:"price_cents"`
+		Currency      *string          `json:"currency"`
+		IntervalUnit  *string          `json:"interval_unit"`
+		IntervalCount *int32           `json:"interval_count"`
+		TrialDays     *int32           `json:"trial_days"`
+		Features      *json.RawMessage `json:"features"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+
+	req := &UpdatePlanTypeRequest{Id: id}
+	if body.DisplayName != nil {
+		req.HasDisplayName = true
+		req.DisplayName = strings.TrimSpace(*body.DisplayName)
+	}
+	if body.Description != nil {
+		req.HasDescription = true
+		req.Description = strings.TrimSpace(*body.Description)
+	}
+	if body.PriceCents != nil {
+		req.HasPriceCents = true
+		req.PriceCents = *body.PriceCents
+	}
+	if body.Currency != nil {
+		req.HasCurrency = true
+		req.Currency = strings.ToUpper(strings.TrimSpace(*body.Currency))
+	}
+	if body.IntervalUnit != nil {
+		req.HasIntervalUnit = true
+		req.IntervalUnit = strings.ToLower(strings.TrimSpace(*body.IntervalUnit))
+	}
+	if body.IntervalCount != nil {
+		req.HasIntervalCount = true
+		req.IntervalCount = *body.IntervalCount
+	}
+	if body.TrialDays != nil {
+		req.HasTrialDays = true
+		req.TrialDays = *body.TrialDays
+	}
+	if body.Features != nil {
+		req.HasFeatures = true
+		if req.Features, err = featuresFromJSON(*body.Features); err != nil {
+			return nil, err
+		}
+	}
+
+	return req, nil
+}
+
+func (r *UpdatePlanTypeRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid plan type id")
+	}
+	if !r.GetHasDisplayName() && !r.GetHasDescription() && !r.GetHasPriceCents() &&
+		!r.GetHasCurrency() && !r.GetHasIntervalUnit() && !r.GetHasIntervalCount() && !r.GetHasTrialDays() && !r.GetHasFeatures() {
+		return errors.New("at least one of display_name, description, price_cents, currency, interval_unit, interval_count, trial_days or features is required")
+	}
+	if r.GetHasDisplayName() {
+		if err := validateDisplayName(r.GetDisplayName()); err != nil {
+			return err
 		}
 	}
+	if r.GetHasPriceCents() && r.GetPriceCents() < 0 {
+		return errors.New("price_cents must not be negative")
+	}
+	if r.GetHasCurrency() && len(r.GetCurrency()) != 3 {
+		return errors.New("currency must be a 3-letter ISO 4217 code")
+	}
+	if r.GetHasIntervalUnit() && !isPlanIntervalUnit(r.GetIntervalUnit()) {
+		return errors.New("interval_unit must be one of day, week, month, year")
+	}
+	if r.GetHasIntervalCount() && r.GetIntervalCount() <= 0 {
+		return errors.New("interval_count must be positive")
+	}
+	if r.GetHasTrialDays() {
+		return validateTrialDays(r.GetTrialDays())
+	}
+	return nil
+}
+
+func NewArchivePlanTypeRequestFromContext(ctx echo.Context) (*ArchivePlanTypeRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &ArchivePlanTypeRequest{Id: id}, nil
+}
 
+func (r *ArchivePlanTypeRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid plan type id")
+	}
 	return nil
 }
 
-func NewGetSubscriptionRequestFromContext(ctx echo.Context) (*GetSubscriptionRequest, error) {
+func NewCreateCouponRequestFromContext(ctx echo.Context) (*CreateCouponRequest, error) {
+	var body CreateCouponRequest
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+	body.Code = normalizeCouponCode(body.Code)
+	body.Description = strings.TrimSpace(body.Description)
+	body.Currency = strings.ToUpper(strings.TrimSpace(body.Currency))
+	body.Duration = strings.ToLower(strings.TrimSpace(body.Duration))
+	body.ExpiresAt = strings.TrimSpace(body.ExpiresAt)
+	return &body, nil
+}
+
+func (r *CreateCouponRequest) Validate() error {
+	if !couponCodePattern.MatchString(r.GetCode()) || len(r.GetCode()) > maxCouponCodeLength {
+		return errors.New("code must be at most 50 letters, digits, '-' or '_'")
+	}
+	if len(r.GetDescription()) > maxCouponDescription {
+		return errors.New("description must be at most 255 characters")
+	}
+	if (r.GetPercentOff() != 0) == (r.GetAmountOffCents() != 0) {
+		return errors.New("exactly one of percent_off or amount_off_cents is required")
+	}
+	if r.GetPercentOff() != 0 {
+		if r.GetPercentOff() < 1 || r.GetPercentOff() > 100 {
+			return errors.New("percent_off must be between 1 and 100")
+		}
+		if r.GetCurrency() != "" {
+			return errors.New("currency is only used with amount_off_cents")
+		}
+	} else {
+		if r.GetAmountOffCents() < 0 {
+			return errors.New("amount_off_cents must be positive")
+		}
+		if len(r.GetCurrency()) != 3 {
+			return errors.New("currency must be a 3-letter ISO 4217 code")
+		}
+	}
+	switch r.GetDuration() {
+	case "once", "forever":
+		if r.GetDurationInPeriods() != 0 {
+			return errors.New("duration_in_periods is only used with the repeating duration")
+		}
+	case "repeating":
+		if r.GetDurationInPeriods() <= 0 {
+			return errors.New("duration_in_periods must be positive for repeating coupons")
+		}
+	default:
+		return errors.New("duration must be one of once, repeating, forever")
+	}
+	if r.GetMaxRedemptions() < 0 {
+		return errors.New("max_redemptions must not be negative")
+	}
+	for _, id := range r.GetPlanTypeIds() {
+		if id == 0 {
+			return errors.New("plan_type_ids must not contain 0")
+		}
+	}
+	if r.GetExpiresAt() != "" {
+		if _, err := time.Parse(time.RFC3339, r.GetExpiresAt()); err != nil {
+			return errors.New("expires_at must be RFC3339")
+		}
+	}
+	return nil
+}
+
+func NewGetCouponRequestFromContext(ctx echo.Context) (*GetCouponRequest, error) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	return &GetSubscriptionRequest{Id: id}, nil
+	return &GetCouponRequest{Id: id}, nil
 }
 
-func (r *GetSubscriptionRequest) Validate() error {
+func (r *GetCouponRequest) Validate() error {
 	if r.GetId() == 0 {
-		return errors.New("invalid subscription id")
+		return errors.New("invalid coupon id")
 	}
 	return nil
 }
 
-func NewListSubscriptionsRequestFromContext(ctx echo.Context) (*ListSubscriptionsRequest, error) {
-	return &ListSubscriptionsRequest{
-		UserId: strings.TrimSpace(ctx.QueryParam("user_id")),
-		Email:  strings.TrimSpace(ctx.QueryParam("email")),
-	}, nil
+func NewListCouponsRequestFromContext(_ echo.Context) (*ListCouponsRequest, error) {
+	return &ListCouponsRequest{}, nil
 }
 
-func (r *ListSubscriptionsRequest) Validate() error {
+func (r *ListCouponsRequest) Validate() error {
 	return nil
 }
 
-func NewUpdateSubscriptionRequestFromContext(ctx echo.Context) (*UpdateSubscriptionRequest, error) {
+func NewUpdateCouponRequestFromContext(ctx echo.Context) (*UpdateCouponRequest, error) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
 		return nil, err
 	}
 
 	var body struct {
-		AutoRenew *bool  `json:"auto_renew"`
-		Status    *int32 `json:"status"`
+		Description    *string `json:"description"`
+		MaxRedemptions *int32  `json:"max_redemptions"`
+		ExpiresAt      *string `json:"expires_at"`
+		Status         *int32  `json:"status"`
 	}
 	if err := ctx.Bind(&body); err != nil {
 		return nil, err
 	}
 
-	req := &UpdateSubscriptionRequest{Id: id}
-	if body.AutoRenew != nil {
-		req.HasAutoRenew = true
-		req.AutoRenew = *body.AutoRenew
+	req := &UpdateCouponRequest{Id: id}
+	if body.Description != nil {
+		req.HasDescription = true
+		req.Description = strings.TrimSpace(*body.Description)
+	}
+	if body.MaxRedemptions != nil {
+		req.HasMaxRedemptions = true
+		req.MaxRedemptions = *body.MaxRedemptions
+	}
+	if body.ExpiresAt != nil {
+		req.HasExpiresAt = true
+		req.ExpiresAt = strings.TrimSpace(*body.ExpiresAt)
 	}
 	if body.Status != nil {
 		req.HasStatus = true
@@ -111,69 +450,690 @@ func NewUpdateSubscriptionRequestFromContext(ctx echo.Context) (*UpdateSubscript
 	return req, nil
 }
 
-func (r *UpdateSubscriptionRequest) Validate() error {
+// Validate checks the update. An empty expires_at removes the expiry.
+func (r *UpdateCouponRequest) Validate() error {
 	if r.GetId() == 0 {
-		return errors.New("invalid subscription id")
+		return errors.New("invalid coupon id")
 	}
-	if !r.GetHasAutoRenew() && !r.GetHasStatus() {
-		return errors.New("at least one of auto_renew or status is required")
+	if !r.GetHasDescription() && !r.GetHasMaxRedemptions() && !r.GetHasExpiresAt() && !r.GetHasStatus() {
+		return errors.New("at least one of description, max_redemptions, expires_at or status is required")
 	}
-	if r.GetHasStatus() {
-		switch r.GetStatus() {
-		case 0, 1, 2, 10:
-		default:
-			return errors.New("status must be one of 0, 1, 2, 10")
+	if r.GetHasDescription() && len(r.GetDescription()) > maxCouponDescription {
+		return errors.New("description must be at most 255 characters")
+	}
+	if r.GetHasMaxRedemptions() && r.GetMaxRedemptions() < 0 {
+		return errors.New("max_redemptions must not be negative")
+	}
+	if r.GetHasExpiresAt() && r.GetExpiresAt() != "" {
+		if _, err := time.Parse(time.RFC3339, r.GetExpiresAt()); err != nil {
+			return errors.New("expires_at must be RFC3339")
 		}
 	}
+	if r.GetHasStatus() && r.GetStatus() != 0 && r.GetStatus() != 10 {
+		return errors.New("status must be 0 or 10")
+	}
 	return nil
 }
 
-func NewDeleteSubscriptionRequestFromContext(ctx echo.Context) (*DeleteSubscriptionRequest, error) {
+func NewListCouponRedemptionsRequestFromContext(ctx echo.Context) (*ListCouponRedemptionsRequest, error) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	return &DeleteSubscriptionRequest{Id: id}, nil
+	return &ListCouponRedemptionsRequest{CouponId: id}, nil
 }
 
-func (r *DeleteSubscriptionRequest) Validate() error {
-	if r.GetId() == 0 {
-		return errors.New("invalid subscription id")
+func (r *ListCouponRedemptionsRequest) Validate() error {
+	if r.GetCouponId() == 0 {
+		return errors.New("invalid coupon id")
 	}
 	return nil
 }
 
-func NewCancelSubscriptionRequestFromContext(ctx echo.Context) (*CancelSubscriptionRequest, error) {
+func NewCreateSubscriptionRequestFromContext(ctx echo.Context) (*CreateSubscriptionRequest, error) {
+	var body CreateSubscriptionRequest
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+	body.UserId = strings.TrimSpace(body.UserId)
+	body.Email = strings.TrimSpace(body.Email)
+	body.StartAt = strings.TrimSpace(body.StartAt)
+	body.CouponCode = normalizeCouponCode(body.CouponCode)
+	return &body, nil
+}
+
+func (r *CreateSubscriptionRequest) Validate() error {
+	if r.GetSubscriptionTypeId() == 0 {
+		return errors.New("subscription_type_id is required")
+	}
+	if strings.TrimSpace(r.GetUserId()) == "" && strings.TrimSpace(r.GetEmail()) == "" {
+		return errors.New("at least one of user_id or email is required")
+	}
+	if r.GetStartAt() != "" {
+		if _, err := time.Parse(time.RFC3339, r.GetStartAt()); err != nil {
+			return errors.New("start_at must be RFC3339")
+		}
+	}
+	if len(r.GetCouponCode()) > maxCouponCodeLength {
+		return errors.New("coupon_code must be at most 50 characters")
+	}
+	if r.GetQuantity() < 0 || r.GetQuantity() > maxQuantity {
+		return errors.New("quantity must be between 1 and 10000")
+	}
+
+	return nil
+}
+
+func NewGetSubscriptionRequestFromContext(ctx echo.Context) (*GetSubscriptionRequest, error) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
 	if err != nil {
 		return nil, err
 	}
-	return &CancelSubscriptionRequest{Id: id}, nil
+	return &GetSubscriptionRequest{Id: id}, nil
 }
 
-func (r *CancelSubscriptionRequest) Validate() error {
+func (r *GetSubscriptionRequest) Validate() error {
 	if r.GetId() == 0 {
 		return errors.New("invalid subscription id")
 	}
 	return nil
 }
 
-func NewPaymentCallbackRequestFromContext(ctx echo.Context) (*PaymentCallbackRequest, error) {
-	var body PaymentCallbackRequest
-	if err := ctx.Bind(&body); err != nil {
-		return nil, err
+func NewListSubscriptionsRequestFromContext(ctx echo.Context) (*ListSubscriptionsRequest, error) {
+	req := &ListSubscriptionsRequest{
+		UserId:        strings.TrimSpace(ctx.QueryParam("user_id")),
+		Email:         strings.TrimSpace(ctx.QueryParam("email")),
+		PageToken:     strings.TrimSpace(ctx.QueryParam("page_token")),
+		EndAtFrom:     strings.TrimSpace(ctx.QueryParam("end_at_from")),
+		EndAtTo:       strings.TrimSpace(ctx.QueryParam("end_at_to")),
+		CreatedAtFrom: strings.TrimSpace(ctx.QueryParam("created_at_from")),
+		CreatedAtTo:   strings.TrimSpace(ctx.QueryParam("created_at_to")),
+		Sort:          strings.TrimSpace(strings.ToLower(ctx.QueryParam("sort"))),
 	}
-	body.Status = strings.TrimSpace(strings.ToLower(body.Status))
-	body.TransactionId = strings.TrimSpace(body.TransactionId)
-	return &body, nil
-}
-
-func (r *PaymentCallbackRequest) Validate() error {
-	if r.GetSubscriptionId() == 0 {
-		return errors.New("subscription_id is required")
+	if raw := strings.TrimSpace(ctx.QueryParam("page_size")); raw != "" {
+		pageSize, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		req.PageSize = int32(pageSize)
 	}
-	if r.GetStatus() != "success" && r.GetStatus() != "failed" {
-		return errors.New("status must be success or failed")
+	if raw := strings.TrimSpace(ctx.QueryParam("status")); raw != "" {
+		statusValue, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		req.HasStatus = true
+		req.Status = int32(statusValue)
+	}
+	if raw := strings.TrimSpace(ctx.QueryParam("subscription_type_id")); raw != "" {
+		subscriptionTypeID, err := strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		req.SubscriptionTypeId = subscriptionTypeID
+	}
+	if raw := strings.TrimSpace(ctx.QueryParam("auto_renew")); raw != "" {
+		autoRenew, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, err
+		}
+		req.HasAutoRenew = true
+		req.AutoRenew = autoRenew
+	}
+
+	return req, nil
+}
+
+func (r *ListSubscriptionsRequest) Validate() error {
+	if r.GetPageSize() < 0 || r.GetPageSize() > 200 {
+		return errors.New("page_size must be between 0 and 200")
+	}
+	if r.GetHasStatus() {
+		switch r.GetStatus() {
+		case 0, 1, 2, 3, 4, 5, 10:
+		default:
+			return errors.New("status must be one of 0, 1, 2, 3, 4, 5, 10")
+		}
+	}
+	switch r.GetSort() {
+	case "", "id_desc", "id_asc", "created_at_desc", "created_at_asc", "end_at_desc", "end_at_asc":
+	default:
+		return errors.New("sort must be one of id_desc, id_asc, created_at_desc, created_at_asc, end_at_desc, end_at_asc")
+	}
+	if err := validateTimeRange("end_at", r.GetEndAtFrom(), r.GetEndAtTo()); err != nil {
+		return err
+	}
+	return validateTimeRange("created_at", r.GetCreatedAtFrom(), r.GetCreatedAtTo())
+}
+
+func NewUpdateSubscriptionRequestFromContext(ctx echo.Context) (*UpdateSubscriptionRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		AutoRenew *bool  `json:"auto_renew"`
+		Status    *int32 `json:"status"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+
+	expectedVersion, err := parseIfMatch(ctx.Request().Header.Get("If-Match"))
+	if err != nil {
+		return nil, err
+	}
+
+	req := &UpdateSubscriptionRequest{Id: id, ExpectedVersion: expectedVersion}
+	if body.AutoRenew != nil {
+		req.HasAutoRenew = true
+		req.AutoRenew = *body.AutoRenew
+	}
+	if body.Status != nil {
+		req.HasStatus = true
+		req.Status = *body.Status
+	}
+
+	return req, nil
+}
+
+func (r *UpdateSubscriptionRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid subscription id")
+	}
+	if !r.GetHasAutoRenew() && !r.GetHasStatus() {
+		return errors.New("at least one of auto_renew or status is required")
+	}
+	if r.GetExpectedVersion() < 0 {
+		return errors.New("expected_version must not be negative")
+	}
+	if r.GetHasStatus() {
+		switch r.GetStatus() {
+		case 0, 1, 2, 3, 4, 5, 10:
+		default:
+			return errors.New("status must be one of 0, 1, 2, 3, 4, 5, 10")
+		}
+	}
+	return nil
+}
+
+func NewDeleteSubscriptionRequestFromContext(ctx echo.Context) (*DeleteSubscriptionRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	expectedVersion, err := parseIfMatch(ctx.Request().Header.Get("If-Match"))
+	if err != nil {
+		return nil, err
+	}
+	return &DeleteSubscriptionRequest{Id: id, ExpectedVersion: expectedVersion}, nil
+}
+
+func (r *DeleteSubscriptionRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid subscription id")
+	}
+	if r.GetExpectedVersion() < 0 {
+		return errors.New("expected_version must not be negative")
+	}
+	return nil
+}
+
+// SubscriptionETag is the entity tag of a subscription at version, as sent in
+// the ETag header and expected back in If-Match.
+func SubscriptionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// parseIfMatch returns the subscription version an If-Match header names. No
+// header, or *, matches every version and yields 0.
+func parseIfMatch(header string) (int64, error) {
+	value := strings.TrimSpace(header)
+	if value == "" || value == "*" {
+		return 0, nil
+	}
+	if len(value) < 3 || value[0] != '"' || value[len(value)-1] != '"' {
+		return 0, errors.New("If-Match must be a subscription ETag")
+	}
+	version, err := strconv.ParseInt(value[1:len(value)-1], 10, 64)
+	if err != nil || version <= 0 {
+		return 0, errors.New("If-Match must be a subscription ETag")
+	}
+	return version, nil
+}
+
+func NewCancelSubscriptionRequestFromContext(ctx echo.Context) (*CancelSubscriptionRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var body CancelSubscriptionRequest
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+	body.Id = id
+	body.Mode = strings.TrimSpace(strings.ToLower(body.Mode))
+	body.Reason = strings.TrimSpace(body.Reason)
+	return &body, nil
+}
+
+func (r *CancelSubscriptionRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid subscription id")
+	}
+	switch r.GetMode() {
+	case "", "period_end":
+		if r.GetRefund() {
+			return errors.New("refund is only allowed for immediate cancellations")
+		}
+	case "immediate":
+	default:
+		return errors.New("mode must be period_end or immediate")
+	}
+	if len(r.GetReason()) > maxCancelReasonLength {
+		return errors.New("reason must be at most 255 characters")
+	}
+	return nil
+}
+
+func NewUndoCancellationRequestFromContext(ctx echo.Context) (*UndoCancellationRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &UndoCancellationRequest{Id: id}, nil
+}
+
+func (r *UndoCancellationRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid subscription id")
+	}
+	return nil
+}
+
+func NewPauseSubscriptionRequestFromContext(ctx echo.Context) (*PauseSubscriptionRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var body PauseSubscriptionRequest
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+	body.Id = id
+	body.ResumeAt = strings.TrimSpace(body.ResumeAt)
+	return &body, nil
+}
+
+func (r *PauseSubscriptionRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid subscription id")
+	}
+	if r.GetResumeAt() != "" {
+		if _, err := time.Parse(time.RFC3339, r.GetResumeAt()); err != nil {
+			return errors.New("resume_at must be RFC3339")
+		}
+	}
+	return nil
+}
+
+func NewResumeSubscriptionRequestFromContext(ctx echo.Context) (*ResumeSubscriptionRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &ResumeSubscriptionRequest{Id: id}, nil
+}
+
+func (r *ResumeSubscriptionRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid subscription id")
+	}
+	return nil
+}
+
+func NewChangePlanRequestFromContext(ctx echo.Context) (*ChangePlanRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var body ChangePlanRequest
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+	body.Id = id
+	body.Timing = strings.TrimSpace(strings.ToLower(body.Timing))
+	return &body, nil
+}
+
+func (r *ChangePlanRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid subscription id")
+	}
+	if r.GetPlanTypeId() == 0 {
+		return errors.New("plan_type_id is required")
+	}
+	switch r.GetTiming() {
+	case "", "immediate", "period_end":
+	default:
+		return errors.New("timing must be immediate or period_end")
+	}
+	return nil
+}
+
+func NewUpdateQuantityRequestFromContext(ctx echo.Context) (*UpdateQuantityRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var body UpdateQuantityRequest
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+	body.Id = id
+	body.Proration = strings.TrimSpace(strings.ToLower(body.Proration))
+	return &body, nil
+}
+
+func (r *UpdateQuantityRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid subscription id")
+	}
+	if r.GetQuantity() < 1 || r.GetQuantity() > maxQuantity {
+		return errors.New("quantity must be between 1 and 10000")
+	}
+	switch r.GetProration() {
+	case "", "prorate", "none":
+	default:
+		return errors.New("proration must be prorate or none")
+	}
+	return nil
+}
+
+func NewPaymentCallbackRequestFromContext(ctx echo.Context) (*PaymentCallbackRequest, error) {
+	var body PaymentCallbackRequest
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+	body.Status = strings.TrimSpace(strings.ToLower(body.Status))
+	body.TransactionId = strings.TrimSpace(body.TransactionId)
+	return &body, nil
+}
+
+func (r *PaymentCallbackRequest) Validate() error {
+	if r.GetSubscriptionId() == 0 {
+		return errors.New("subscription_id is required")
+	}
+	if r.GetStatus() != "success" && r.GetStatus() != "failed" {
+		return errors.New("status must be success or failed")
+	}
+	if strings.TrimSpace(r.GetTransactionId()) == "" {
+		return errors.New("transaction_id is required")
+	}
+	return nil
+}
+
+func NewListPaymentAttemptsRequestFromContext(ctx echo.Context) (*ListPaymentAttemptsRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &ListPaymentAttemptsRequest{SubscriptionId: id}, nil
+}
+
+func (r *ListPaymentAttemptsRequest) Validate() error {
+	if r.GetSubscriptionId() == 0 {
+		return errors.New("invalid subscription id")
+	}
+	return nil
+}
+
+func NewListSubscriptionEventsRequestFromContext(ctx echo.Context) (*ListSubscriptionEventsRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &ListSubscriptionEventsRequest{SubscriptionId: id}, nil
+}
+
+func (r *ListSubscriptionEventsRequest) Validate() error {
+	if r.GetSubscriptionId() == 0 {
+		return errors.New("invalid subscription id")
+	}
+	return nil
+}
+
+func NewCreateWebhookEndpointRequestFromContext(ctx echo.Context) (*CreateWebhookEndpointRequest, error) {
+	var body CreateWebhookEndpointRequest
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+	body.Url = strings.TrimSpace(body.Url)
+	body.Description = strings.TrimSpace(body.Description)
+	body.EventTypes = normalizeEventTypes(body.EventTypes)
+	return &body, nil
+}
+
+func (r *CreateWebhookEndpointRequest) Validate() error {
+	return validateWebhookURL(r.GetUrl())
+}
+
+func NewGetWebhookEndpointRequestFromContext(ctx echo.Context) (*GetWebhookEndpointRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &GetWebhookEndpointRequest{Id: id}, nil
+}
+
+func (r *GetWebhookEndpointRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid webhook endpoint id")
+	}
+	return nil
+}
+
+func NewListWebhookEndpointsRequestFromContext(_ echo.Context) (*ListWebhookEndpointsRequest, error) {
+	return &ListWebhookEndpointsRequest{}, nil
+}
+
+func (r *ListWebhookEndpointsRequest) Validate() error {
+	return nil
+}
+
+func NewUpdateWebhookEndpointRequestFromContext(ctx echo.Context) (*UpdateWebhookEndpointRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	var body struct {
+		URL         *string   `json:"url"`
+		Description *string   `json:"description"`
+		EventTypes  *[]string `json:"event_types"`
+		Status      *int32    `json:"status"`
+	}
+	if err := ctx.Bind(&body); err != nil {
+		return nil, err
+	}
+
+	req := &UpdateWebhookEndpointRequest{Id: id}
+	if body.URL != nil {
+		req.HasUrl = true
+		req.Url = strings.TrimSpace(*body.URL)
+	}
+	if body.Description != nil {
+		req.HasDescription = true
+		req.Description = strings.TrimSpace(*body.Description)
+	}
+	if body.EventTypes != nil {
+		req.HasEventTypes = true
+		req.EventTypes = normalizeEventTypes(*body.EventTypes)
+	}
+	if body.Status != nil {
+		req.HasStatus = true
+		req.Status = *body.Status
+	}
+
+	return req, nil
+}
+
+func (r *UpdateWebhookEndpointRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid webhook endpoint id")
+	}
+	if !r.GetHasUrl() && !r.GetHasDescription() && !r.GetHasEventTypes() && !r.GetHasStatus() {
+		return errors.New("at least one of url, description, event_types or status is required")
+	}
+	if r.GetHasUrl() {
+		if err := validateWebhookURL(r.GetUrl()); err != nil {
+			return err
+		}
+	}
+	if r.GetHasStatus() && r.GetStatus() != 0 && r.GetStatus() != 10 {
+		return errors.New("status must be 0 or 10")
+	}
+	return nil
+}
+
+func NewDeleteWebhookEndpointRequestFromContext(ctx echo.Context) (*DeleteWebhookEndpointRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &DeleteWebhookEndpointRequest{Id: id}, nil
+}
+
+func (r *DeleteWebhookEndpointRequest) Validate() error {
+	if r.GetId() == 0 {
+		return errors.New("invalid webhook endpoint id")
+	}
+	return nil
+}
+
+func NewListWebhookDeliveriesRequestFromContext(ctx echo.Context) (*ListWebhookDeliveriesRequest, error) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &ListWebhookDeliveriesRequest{WebhookEndpointId: id}, nil
+}
+
+func (r *ListWebhookDeliveriesRequest) Validate() error {
+	if r.GetWebhookEndpointId() == 0 {
+		return errors.New("invalid webhook endpoint id")
+	}
+	return nil
+}
+
+func validateDisplayName(name string) error {
+	if strings.TrimSpace(name) == "" || len(name) > maxDisplayNameLength {
+		return errors.New("display_name is required and must be at most 255 characters")
+	}
+	return nil
+}
+
+func validatePlanPricing(priceCents int64, currency string) error {
+	if priceCents < 0 {
+		return errors.New("price_cents must not be negative")
+	}
+	if len(currency) != 3 {
+		return errors.New("currency must be a 3-letter ISO 4217 code")
+	}
+	return nil
+}
+
+func validatePlanInterval(unit string, count int32) error {
+	if !isPlanIntervalUnit(unit) {
+		return errors.New("interval_unit must be one of day, week, month, year")
+	}
+	if count <= 0 {
+		return errors.New("interval_count must be positive")
+	}
+	return nil
+}
+
+func isPlanIntervalUnit(unit string) bool {
+	switch unit {
+	case "day", "week", "month", "year":
+		return true
+	default:
+		return false
+	}
+}
+
+// Coupon codes are matched case-insensitively and stored in upper case.
+func normalizeCouponCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func validateTrialDays(trialDays int32) error {
+	if trialDays < 0 || trialDays > maxTrialDays {
+		return errors.New("trial_days must be between 0 and 365")
+	}
+	return nil
+}
+
+// featuresFromJSON accepts plan features either as a JSON object or as a string
+// holding one, and returns the JSON text.
+func featuresFromJSON(raw json.RawMessage) (string, error) {
+	trimmed := strings.TrimSpace(string(raw))
+	if trimmed == "" || trimmed == "null" {
+		return "", nil
+	}
+	if strings.HasPrefix(trimmed, `"`) {
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(text), nil
+	}
+	return trimmed, nil
+}
+
+func validateWebhookURL(raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		return errors.New("url is required")
+	}
+	parsed, err := url.Parse(raw)
+	if err != nil || parsed.Host == "" || (parsed.Scheme != "https" && parsed.Scheme != "http") {
+		return errors.New("url must be an absolute http(s) URL")
+	}
+	return nil
+}
+
+func normalizeEventTypes(items []string) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// validateTimeRange checks an optional inclusive RFC3339 range named name.
+func validateTimeRange(name, from, to string) error {
+	var fromTime, toTime time.Time
+	var err error
+	if from != "" {
+		if fromTime, err = time.Parse(time.RFC3339, from); err != nil {
+			return errors.New(name + "_from must be RFC3339")
+		}
+	}
+	if to != "" {
+		if toTime, err = time.Parse(time.RFC3339, to); err != nil {
+			return errors.New(name + "_to must be RFC3339")
+		}
+	}
+	if from != "" && to != "" && toTime.Before(fromTime) {
+		return errors.New(name + "_to must not be before " + name + "_from")
 	}
 	return nil
 }
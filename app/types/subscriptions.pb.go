@@ -1,6 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.5
+// 	protoc-gen-go v1.36.11
 // 	protoc        v6.33.4
 // source: subscriptions.proto
 
@@ -289,32 +289,3252 @@ func (x *ListSubscriptionTypesResponse) GetSubscriptionTypes() []*SubscriptionTy
 	return nil
 }
 
-type CreateSubscriptionRequest struct {
+type PlanType struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubscriptionTypeId uint64                 `protobuf:"varint,2,opt,name=subscription_type_id,json=subscriptionTypeId,proto3" json:"subscription_type_id,omitempty"`
+	PlanCode           string                 `protobuf:"bytes,3,opt,name=plan_code,json=planCode,proto3" json:"plan_code,omitempty"`
+	DisplayName        string                 `protobuf:"bytes,4,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Description        string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	PriceCents         int64                  `protobuf:"varint,6,opt,name=price_cents,json=priceCents,proto3" json:"price_cents,omitempty"`
+	Currency           string                 `protobuf:"bytes,7,opt,name=currency,proto3" json:"currency,omitempty"`
+	Features           string                 `protobuf:"bytes,9,opt,name=features,proto3" json:"features,omitempty"`
+	CreatedAt          string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt          string                 `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Status             int32                  `protobuf:"varint,12,opt,name=status,proto3" json:"status,omitempty"`
+	TrialDays          int32                  `protobuf:"varint,13,opt,name=trial_days,json=trialDays,proto3" json:"trial_days,omitempty"`
+	IntervalUnit       string                 `protobuf:"bytes,14,opt,name=interval_unit,json=intervalUnit,proto3" json:"interval_unit,omitempty"`
+	IntervalCount      int32                  `protobuf:"varint,15,opt,name=interval_count,json=intervalCount,proto3" json:"interval_count,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *PlanType) Reset() {
+	*x = PlanType{}
+	mi := &file_subscriptions_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanType) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanType) ProtoMessage() {}
+
+func (x *PlanType) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanType.ProtoReflect.Descriptor instead.
+func (*PlanType) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PlanType) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PlanType) GetSubscriptionTypeId() uint64 {
+	if x != nil {
+		return x.SubscriptionTypeId
+	}
+	return 0
+}
+
+func (x *PlanType) GetPlanCode() string {
+	if x != nil {
+		return x.PlanCode
+	}
+	return ""
+}
+
+func (x *PlanType) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *PlanType) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *PlanType) GetPriceCents() int64 {
+	if x != nil {
+		return x.PriceCents
+	}
+	return 0
+}
+
+func (x *PlanType) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PlanType) GetFeatures() string {
+	if x != nil {
+		return x.Features
+	}
+	return ""
+}
+
+func (x *PlanType) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *PlanType) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *PlanType) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *PlanType) GetTrialDays() int32 {
+	if x != nil {
+		return x.TrialDays
+	}
+	return 0
+}
+
+func (x *PlanType) GetIntervalUnit() string {
+	if x != nil {
+		return x.IntervalUnit
+	}
+	return ""
+}
+
+func (x *PlanType) GetIntervalCount() int32 {
+	if x != nil {
+		return x.IntervalCount
+	}
+	return 0
+}
+
+type ListPlanTypesRequest struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
 	SubscriptionTypeId uint64                 `protobuf:"varint,1,opt,name=subscription_type_id,json=subscriptionTypeId,proto3" json:"subscription_type_id,omitempty"`
-	UserId             string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Email              string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
-	StartAt            string                 `protobuf:"bytes,4,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
-	AutoRenew          bool                   `protobuf:"varint,5,opt,name=auto_renew,json=autoRenew,proto3" json:"auto_renew,omitempty"`
+	IncludeArchived    bool                   `protobuf:"varint,2,opt,name=include_archived,json=includeArchived,proto3" json:"include_archived,omitempty"`
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
 
-func (x *CreateSubscriptionRequest) Reset() {
-	*x = CreateSubscriptionRequest{}
-	mi := &file_subscriptions_proto_msgTypes[5]
+func (x *ListPlanTypesRequest) Reset() {
+	*x = ListPlanTypesRequest{}
+	mi := &file_subscriptions_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPlanTypesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPlanTypesRequest) ProtoMessage() {}
+
+func (x *ListPlanTypesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPlanTypesRequest.ProtoReflect.Descriptor instead.
+func (*ListPlanTypesRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *ListPlanTypesRequest) GetSubscriptionTypeId() uint64 {
+	if x != nil {
+		return x.SubscriptionTypeId
+	}
+	return 0
+}
+
+func (x *ListPlanTypesRequest) GetIncludeArchived() bool {
+	if x != nil {
+		return x.IncludeArchived
+	}
+	return false
+}
+
+type ListPlanTypesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlanTypes     []*PlanType            `protobuf:"bytes,1,rep,name=plan_types,json=planTypes,proto3" json:"plan_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListPlanTypesResponse) Reset() {
+	*x = ListPlanTypesResponse{}
+	mi := &file_subscriptions_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPlanTypesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPlanTypesResponse) ProtoMessage() {}
+
+func (x *ListPlanTypesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPlanTypesResponse.ProtoReflect.Descriptor instead.
+func (*ListPlanTypesResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListPlanTypesResponse) GetPlanTypes() []*PlanType {
+	if x != nil {
+		return x.PlanTypes
+	}
+	return nil
+}
+
+type GetPlanTypeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPlanTypeRequest) Reset() {
+	*x = GetPlanTypeRequest{}
+	mi := &file_subscriptions_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPlanTypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPlanTypeRequest) ProtoMessage() {}
+
+func (x *GetPlanTypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPlanTypeRequest.ProtoReflect.Descriptor instead.
+func (*GetPlanTypeRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetPlanTypeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type PlanTypeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlanType      *PlanType              `protobuf:"bytes,1,opt,name=plan_type,json=planType,proto3" json:"plan_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlanTypeResponse) Reset() {
+	*x = PlanTypeResponse{}
+	mi := &file_subscriptions_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlanTypeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlanTypeResponse) ProtoMessage() {}
+
+func (x *PlanTypeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlanTypeResponse.ProtoReflect.Descriptor instead.
+func (*PlanTypeResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *PlanTypeResponse) GetPlanType() *PlanType {
+	if x != nil {
+		return x.PlanType
+	}
+	return nil
+}
+
+type CreateSubscriptionTypeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	DisplayName   string                 `protobuf:"bytes,2,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionTypeRequest) Reset() {
+	*x = CreateSubscriptionTypeRequest{}
+	mi := &file_subscriptions_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionTypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionTypeRequest) ProtoMessage() {}
+
+func (x *CreateSubscriptionTypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionTypeRequest.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionTypeRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateSubscriptionTypeRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionTypeRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+type UpdateSubscriptionTypeRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	HasDisplayName bool                   `protobuf:"varint,2,opt,name=has_display_name,json=hasDisplayName,proto3" json:"has_display_name,omitempty"`
+	DisplayName    string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	HasStatus      bool                   `protobuf:"varint,4,opt,name=has_status,json=hasStatus,proto3" json:"has_status,omitempty"`
+	Status         int32                  `protobuf:"varint,5,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionTypeRequest) Reset() {
+	*x = UpdateSubscriptionTypeRequest{}
+	mi := &file_subscriptions_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionTypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionTypeRequest) ProtoMessage() {}
+
+func (x *UpdateSubscriptionTypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionTypeRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionTypeRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateSubscriptionTypeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateSubscriptionTypeRequest) GetHasDisplayName() bool {
+	if x != nil {
+		return x.HasDisplayName
+	}
+	return false
+}
+
+func (x *UpdateSubscriptionTypeRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *UpdateSubscriptionTypeRequest) GetHasStatus() bool {
+	if x != nil {
+		return x.HasStatus
+	}
+	return false
+}
+
+func (x *UpdateSubscriptionTypeRequest) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type ArchiveSubscriptionTypeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchiveSubscriptionTypeRequest) Reset() {
+	*x = ArchiveSubscriptionTypeRequest{}
+	mi := &file_subscriptions_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchiveSubscriptionTypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchiveSubscriptionTypeRequest) ProtoMessage() {}
+
+func (x *ArchiveSubscriptionTypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchiveSubscriptionTypeRequest.ProtoReflect.Descriptor instead.
+func (*ArchiveSubscriptionTypeRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ArchiveSubscriptionTypeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type SubscriptionTypeResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionType *SubscriptionType      `protobuf:"bytes,1,opt,name=subscription_type,json=subscriptionType,proto3" json:"subscription_type,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *SubscriptionTypeResponse) Reset() {
+	*x = SubscriptionTypeResponse{}
+	mi := &file_subscriptions_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionTypeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionTypeResponse) ProtoMessage() {}
+
+func (x *SubscriptionTypeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionTypeResponse.ProtoReflect.Descriptor instead.
+func (*SubscriptionTypeResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *SubscriptionTypeResponse) GetSubscriptionType() *SubscriptionType {
+	if x != nil {
+		return x.SubscriptionType
+	}
+	return nil
+}
+
+type CreatePlanTypeRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionTypeId uint64                 `protobuf:"varint,1,opt,name=subscription_type_id,json=subscriptionTypeId,proto3" json:"subscription_type_id,omitempty"`
+	PlanCode           string                 `protobuf:"bytes,2,opt,name=plan_code,json=planCode,proto3" json:"plan_code,omitempty"`
+	DisplayName        string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	Description        string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	PriceCents         int64                  `protobuf:"varint,5,opt,name=price_cents,json=priceCents,proto3" json:"price_cents,omitempty"`
+	Currency           string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	Features           string                 `protobuf:"bytes,8,opt,name=features,proto3" json:"features,omitempty"`
+	TrialDays          int32                  `protobuf:"varint,9,opt,name=trial_days,json=trialDays,proto3" json:"trial_days,omitempty"`
+	IntervalUnit       string                 `protobuf:"bytes,10,opt,name=interval_unit,json=intervalUnit,proto3" json:"interval_unit,omitempty"`
+	IntervalCount      int32                  `protobuf:"varint,11,opt,name=interval_count,json=intervalCount,proto3" json:"interval_count,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CreatePlanTypeRequest) Reset() {
+	*x = CreatePlanTypeRequest{}
+	mi := &file_subscriptions_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreatePlanTypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreatePlanTypeRequest) ProtoMessage() {}
+
+func (x *CreatePlanTypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreatePlanTypeRequest.ProtoReflect.Descriptor instead.
+func (*CreatePlanTypeRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CreatePlanTypeRequest) GetSubscriptionTypeId() uint64 {
+	if x != nil {
+		return x.SubscriptionTypeId
+	}
+	return 0
+}
+
+func (x *CreatePlanTypeRequest) GetPlanCode() string {
+	if x != nil {
+		return x.PlanCode
+	}
+	return ""
+}
+
+func (x *CreatePlanTypeRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *CreatePlanTypeRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreatePlanTypeRequest) GetPriceCents() int64 {
+	if x != nil {
+		return x.PriceCents
+	}
+	return 0
+}
+
+func (x *CreatePlanTypeRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreatePlanTypeRequest) GetFeatures() string {
+	if x != nil {
+		return x.Features
+	}
+	return ""
+}
+
+func (x *CreatePlanTypeRequest) GetTrialDays() int32 {
+	if x != nil {
+		return x.TrialDays
+	}
+	return 0
+}
+
+func (x *CreatePlanTypeRequest) GetIntervalUnit() string {
+	if x != nil {
+		return x.IntervalUnit
+	}
+	return ""
+}
+
+func (x *CreatePlanTypeRequest) GetIntervalCount() int32 {
+	if x != nil {
+		return x.IntervalCount
+	}
+	return 0
+}
+
+type UpdatePlanTypeRequest struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	HasDisplayName   bool                   `protobuf:"varint,2,opt,name=has_display_name,json=hasDisplayName,proto3" json:"has_display_name,omitempty"`
+	DisplayName      string                 `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	HasDescription   bool                   `protobuf:"varint,4,opt,name=has_description,json=hasDescription,proto3" json:"has_description,omitempty"`
+	Description      string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	HasPriceCents    bool                   `protobuf:"varint,6,opt,name=has_price_cents,json=hasPriceCents,proto3" json:"has_price_cents,omitempty"`
+	PriceCents       int64                  `protobuf:"varint,7,opt,name=price_cents,json=priceCents,proto3" json:"price_cents,omitempty"`
+	HasCurrency      bool                   `protobuf:"varint,8,opt,name=has_currency,json=hasCurrency,proto3" json:"has_currency,omitempty"`
+	Currency         string                 `protobuf:"bytes,9,opt,name=currency,proto3" json:"currency,omitempty"`
+	HasFeatures      bool                   `protobuf:"varint,12,opt,name=has_features,json=hasFeatures,proto3" json:"has_features,omitempty"`
+	Features         string                 `protobuf:"bytes,13,opt,name=features,proto3" json:"features,omitempty"`
+	HasTrialDays     bool                   `protobuf:"varint,14,opt,name=has_trial_days,json=hasTrialDays,proto3" json:"has_trial_days,omitempty"`
+	TrialDays        int32                  `protobuf:"varint,15,opt,name=trial_days,json=trialDays,proto3" json:"trial_days,omitempty"`
+	HasIntervalUnit  bool                   `protobuf:"varint,16,opt,name=has_interval_unit,json=hasIntervalUnit,proto3" json:"has_interval_unit,omitempty"`
+	IntervalUnit     string                 `protobuf:"bytes,17,opt,name=interval_unit,json=intervalUnit,proto3" json:"interval_unit,omitempty"`
+	HasIntervalCount bool                   `protobuf:"varint,18,opt,name=has_interval_count,json=hasIntervalCount,proto3" json:"has_interval_count,omitempty"`
+	IntervalCount    int32                  `protobuf:"varint,19,opt,name=interval_count,json=intervalCount,proto3" json:"interval_count,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UpdatePlanTypeRequest) Reset() {
+	*x = UpdatePlanTypeRequest{}
+	mi := &file_subscriptions_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdatePlanTypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdatePlanTypeRequest) ProtoMessage() {}
+
+func (x *UpdatePlanTypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdatePlanTypeRequest.ProtoReflect.Descriptor instead.
+func (*UpdatePlanTypeRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *UpdatePlanTypeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdatePlanTypeRequest) GetHasDisplayName() bool {
+	if x != nil {
+		return x.HasDisplayName
+	}
+	return false
+}
+
+func (x *UpdatePlanTypeRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *UpdatePlanTypeRequest) GetHasDescription() bool {
+	if x != nil {
+		return x.HasDescription
+	}
+	return false
+}
+
+func (x *UpdatePlanTypeRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdatePlanTypeRequest) GetHasPriceCents() bool {
+	if x != nil {
+		return x.HasPriceCents
+	}
+	return false
+}
+
+func (x *UpdatePlanTypeRequest) GetPriceCents() int64 {
+	if x != nil {
+		return x.PriceCents
+	}
+	return 0
+}
+
+func (x *UpdatePlanTypeRequest) GetHasCurrency() bool {
+	if x != nil {
+		return x.HasCurrency
+	}
+	return false
+}
+
+func (x *UpdatePlanTypeRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *UpdatePlanTypeRequest) GetHasFeatures() bool {
+	if x != nil {
+		return x.HasFeatures
+	}
+	return false
+}
+
+func (x *UpdatePlanTypeRequest) GetFeatures() string {
+	if x != nil {
+		return x.Features
+	}
+	return ""
+}
+
+func (x *UpdatePlanTypeRequest) GetHasTrialDays() bool {
+	if x != nil {
+		return x.HasTrialDays
+	}
+	return false
+}
+
+func (x *UpdatePlanTypeRequest) GetTrialDays() int32 {
+	if x != nil {
+		return x.TrialDays
+	}
+	return 0
+}
+
+func (x *UpdatePlanTypeRequest) GetHasIntervalUnit() bool {
+	if x != nil {
+		return x.HasIntervalUnit
+	}
+	return false
+}
+
+func (x *UpdatePlanTypeRequest) GetIntervalUnit() string {
+	if x != nil {
+		return x.IntervalUnit
+	}
+	return ""
+}
+
+func (x *UpdatePlanTypeRequest) GetHasIntervalCount() bool {
+	if x != nil {
+		return x.HasIntervalCount
+	}
+	return false
+}
+
+func (x *UpdatePlanTypeRequest) GetIntervalCount() int32 {
+	if x != nil {
+		return x.IntervalCount
+	}
+	return 0
+}
+
+type ArchivePlanTypeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ArchivePlanTypeRequest) Reset() {
+	*x = ArchivePlanTypeRequest{}
+	mi := &file_subscriptions_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ArchivePlanTypeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ArchivePlanTypeRequest) ProtoMessage() {}
+
+func (x *ArchivePlanTypeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ArchivePlanTypeRequest.ProtoReflect.Descriptor instead.
+func (*ArchivePlanTypeRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ArchivePlanTypeRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type Coupon struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code              string                 `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"`
+	Description       string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	PercentOff        int32                  `protobuf:"varint,4,opt,name=percent_off,json=percentOff,proto3" json:"percent_off,omitempty"`
+	AmountOffCents    int64                  `protobuf:"varint,5,opt,name=amount_off_cents,json=amountOffCents,proto3" json:"amount_off_cents,omitempty"`
+	Currency          string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"`
+	Duration          string                 `protobuf:"bytes,7,opt,name=duration,proto3" json:"duration,omitempty"`
+	DurationInPeriods int32                  `protobuf:"varint,8,opt,name=duration_in_periods,json=durationInPeriods,proto3" json:"duration_in_periods,omitempty"`
+	MaxRedemptions    int32                  `protobuf:"varint,9,opt,name=max_redemptions,json=maxRedemptions,proto3" json:"max_redemptions,omitempty"`
+	TimesRedeemed     int32                  `protobuf:"varint,10,opt,name=times_redeemed,json=timesRedeemed,proto3" json:"times_redeemed,omitempty"`
+	PlanTypeIds       []uint64               `protobuf:"varint,11,rep,packed,name=plan_type_ids,json=planTypeIds,proto3" json:"plan_type_ids,omitempty"`
+	ExpiresAt         string                 `protobuf:"bytes,12,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	Status            int32                  `protobuf:"varint,13,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt         string                 `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         string                 `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Coupon) Reset() {
+	*x = Coupon{}
+	mi := &file_subscriptions_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Coupon) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Coupon) ProtoMessage() {}
+
+func (x *Coupon) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Coupon.ProtoReflect.Descriptor instead.
+func (*Coupon) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Coupon) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Coupon) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Coupon) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Coupon) GetPercentOff() int32 {
+	if x != nil {
+		return x.PercentOff
+	}
+	return 0
+}
+
+func (x *Coupon) GetAmountOffCents() int64 {
+	if x != nil {
+		return x.AmountOffCents
+	}
+	return 0
+}
+
+func (x *Coupon) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *Coupon) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *Coupon) GetDurationInPeriods() int32 {
+	if x != nil {
+		return x.DurationInPeriods
+	}
+	return 0
+}
+
+func (x *Coupon) GetMaxRedemptions() int32 {
+	if x != nil {
+		return x.MaxRedemptions
+	}
+	return 0
+}
+
+func (x *Coupon) GetTimesRedeemed() int32 {
+	if x != nil {
+		return x.TimesRedeemed
+	}
+	return 0
+}
+
+func (x *Coupon) GetPlanTypeIds() []uint64 {
+	if x != nil {
+		return x.PlanTypeIds
+	}
+	return nil
+}
+
+func (x *Coupon) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *Coupon) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *Coupon) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Coupon) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type CreateCouponRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Code              string                 `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Description       string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	PercentOff        int32                  `protobuf:"varint,3,opt,name=percent_off,json=percentOff,proto3" json:"percent_off,omitempty"`
+	AmountOffCents    int64                  `protobuf:"varint,4,opt,name=amount_off_cents,json=amountOffCents,proto3" json:"amount_off_cents,omitempty"`
+	Currency          string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	Duration          string                 `protobuf:"bytes,6,opt,name=duration,proto3" json:"duration,omitempty"`
+	DurationInPeriods int32                  `protobuf:"varint,7,opt,name=duration_in_periods,json=durationInPeriods,proto3" json:"duration_in_periods,omitempty"`
+	MaxRedemptions    int32                  `protobuf:"varint,8,opt,name=max_redemptions,json=maxRedemptions,proto3" json:"max_redemptions,omitempty"`
+	PlanTypeIds       []uint64               `protobuf:"varint,9,rep,packed,name=plan_type_ids,json=planTypeIds,proto3" json:"plan_type_ids,omitempty"`
+	ExpiresAt         string                 `protobuf:"bytes,10,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CreateCouponRequest) Reset() {
+	*x = CreateCouponRequest{}
+	mi := &file_subscriptions_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCouponRequest) ProtoMessage() {}
+
+func (x *CreateCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCouponRequest.ProtoReflect.Descriptor instead.
+func (*CreateCouponRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *CreateCouponRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *CreateCouponRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *CreateCouponRequest) GetPercentOff() int32 {
+	if x != nil {
+		return x.PercentOff
+	}
+	return 0
+}
+
+func (x *CreateCouponRequest) GetAmountOffCents() int64 {
+	if x != nil {
+		return x.AmountOffCents
+	}
+	return 0
+}
+
+func (x *CreateCouponRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *CreateCouponRequest) GetDuration() string {
+	if x != nil {
+		return x.Duration
+	}
+	return ""
+}
+
+func (x *CreateCouponRequest) GetDurationInPeriods() int32 {
+	if x != nil {
+		return x.DurationInPeriods
+	}
+	return 0
+}
+
+func (x *CreateCouponRequest) GetMaxRedemptions() int32 {
+	if x != nil {
+		return x.MaxRedemptions
+	}
+	return 0
+}
+
+func (x *CreateCouponRequest) GetPlanTypeIds() []uint64 {
+	if x != nil {
+		return x.PlanTypeIds
+	}
+	return nil
+}
+
+func (x *CreateCouponRequest) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+type GetCouponRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCouponRequest) Reset() {
+	*x = GetCouponRequest{}
+	mi := &file_subscriptions_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCouponRequest) ProtoMessage() {}
+
+func (x *GetCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCouponRequest.ProtoReflect.Descriptor instead.
+func (*GetCouponRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetCouponRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ListCouponsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCouponsRequest) Reset() {
+	*x = ListCouponsRequest{}
+	mi := &file_subscriptions_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCouponsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCouponsRequest) ProtoMessage() {}
+
+func (x *ListCouponsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCouponsRequest.ProtoReflect.Descriptor instead.
+func (*ListCouponsRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{20}
+}
+
+type UpdateCouponRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	HasDescription    bool                   `protobuf:"varint,2,opt,name=has_description,json=hasDescription,proto3" json:"has_description,omitempty"`
+	Description       string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	HasMaxRedemptions bool                   `protobuf:"varint,4,opt,name=has_max_redemptions,json=hasMaxRedemptions,proto3" json:"has_max_redemptions,omitempty"`
+	MaxRedemptions    int32                  `protobuf:"varint,5,opt,name=max_redemptions,json=maxRedemptions,proto3" json:"max_redemptions,omitempty"`
+	HasExpiresAt      bool                   `protobuf:"varint,6,opt,name=has_expires_at,json=hasExpiresAt,proto3" json:"has_expires_at,omitempty"`
+	ExpiresAt         string                 `protobuf:"bytes,7,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	HasStatus         bool                   `protobuf:"varint,8,opt,name=has_status,json=hasStatus,proto3" json:"has_status,omitempty"`
+	Status            int32                  `protobuf:"varint,9,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *UpdateCouponRequest) Reset() {
+	*x = UpdateCouponRequest{}
+	mi := &file_subscriptions_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCouponRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCouponRequest) ProtoMessage() {}
+
+func (x *UpdateCouponRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCouponRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCouponRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UpdateCouponRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateCouponRequest) GetHasDescription() bool {
+	if x != nil {
+		return x.HasDescription
+	}
+	return false
+}
+
+func (x *UpdateCouponRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateCouponRequest) GetHasMaxRedemptions() bool {
+	if x != nil {
+		return x.HasMaxRedemptions
+	}
+	return false
+}
+
+func (x *UpdateCouponRequest) GetMaxRedemptions() int32 {
+	if x != nil {
+		return x.MaxRedemptions
+	}
+	return 0
+}
+
+func (x *UpdateCouponRequest) GetHasExpiresAt() bool {
+	if x != nil {
+		return x.HasExpiresAt
+	}
+	return false
+}
+
+func (x *UpdateCouponRequest) GetExpiresAt() string {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return ""
+}
+
+func (x *UpdateCouponRequest) GetHasStatus() bool {
+	if x != nil {
+		return x.HasStatus
+	}
+	return false
+}
+
+func (x *UpdateCouponRequest) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+type CouponResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coupon        *Coupon                `protobuf:"bytes,1,opt,name=coupon,proto3" json:"coupon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CouponResponse) Reset() {
+	*x = CouponResponse{}
+	mi := &file_subscriptions_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CouponResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CouponResponse) ProtoMessage() {}
+
+func (x *CouponResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CouponResponse.ProtoReflect.Descriptor instead.
+func (*CouponResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *CouponResponse) GetCoupon() *Coupon {
+	if x != nil {
+		return x.Coupon
+	}
+	return nil
+}
+
+type ListCouponsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Coupons       []*Coupon              `protobuf:"bytes,1,rep,name=coupons,proto3" json:"coupons,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCouponsResponse) Reset() {
+	*x = ListCouponsResponse{}
+	mi := &file_subscriptions_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCouponsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCouponsResponse) ProtoMessage() {}
+
+func (x *ListCouponsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCouponsResponse.ProtoReflect.Descriptor instead.
+func (*ListCouponsResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *ListCouponsResponse) GetCoupons() []*Coupon {
+	if x != nil {
+		return x.Coupons
+	}
+	return nil
+}
+
+type ListCouponRedemptionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CouponId      uint64                 `protobuf:"varint,1,opt,name=coupon_id,json=couponId,proto3" json:"coupon_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListCouponRedemptionsRequest) Reset() {
+	*x = ListCouponRedemptionsRequest{}
+	mi := &file_subscriptions_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCouponRedemptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCouponRedemptionsRequest) ProtoMessage() {}
+
+func (x *ListCouponRedemptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCouponRedemptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListCouponRedemptionsRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListCouponRedemptionsRequest) GetCouponId() uint64 {
+	if x != nil {
+		return x.CouponId
+	}
+	return 0
+}
+
+type CouponRedemption struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CouponId        uint64                 `protobuf:"varint,2,opt,name=coupon_id,json=couponId,proto3" json:"coupon_id,omitempty"`
+	SubscriptionId  uint64                 `protobuf:"varint,3,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	DurationPeriods int32                  `protobuf:"varint,4,opt,name=duration_periods,json=durationPeriods,proto3" json:"duration_periods,omitempty"`
+	PeriodsApplied  int32                  `protobuf:"varint,5,opt,name=periods_applied,json=periodsApplied,proto3" json:"periods_applied,omitempty"`
+	Status          int32                  `protobuf:"varint,6,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedAt       string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CouponRedemption) Reset() {
+	*x = CouponRedemption{}
+	mi := &file_subscriptions_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CouponRedemption) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CouponRedemption) ProtoMessage() {}
+
+func (x *CouponRedemption) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CouponRedemption.ProtoReflect.Descriptor instead.
+func (*CouponRedemption) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *CouponRedemption) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CouponRedemption) GetCouponId() uint64 {
+	if x != nil {
+		return x.CouponId
+	}
+	return 0
+}
+
+func (x *CouponRedemption) GetSubscriptionId() uint64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+func (x *CouponRedemption) GetDurationPeriods() int32 {
+	if x != nil {
+		return x.DurationPeriods
+	}
+	return 0
+}
+
+func (x *CouponRedemption) GetPeriodsApplied() int32 {
+	if x != nil {
+		return x.PeriodsApplied
+	}
+	return 0
+}
+
+func (x *CouponRedemption) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *CouponRedemption) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *CouponRedemption) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type ListCouponRedemptionsResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	CouponRedemptions []*CouponRedemption    `protobuf:"bytes,1,rep,name=coupon_redemptions,json=couponRedemptions,proto3" json:"coupon_redemptions,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListCouponRedemptionsResponse) Reset() {
+	*x = ListCouponRedemptionsResponse{}
+	mi := &file_subscriptions_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCouponRedemptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCouponRedemptionsResponse) ProtoMessage() {}
+
+func (x *ListCouponRedemptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCouponRedemptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListCouponRedemptionsResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListCouponRedemptionsResponse) GetCouponRedemptions() []*CouponRedemption {
+	if x != nil {
+		return x.CouponRedemptions
+	}
+	return nil
+}
+
+type CreateSubscriptionRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionTypeId uint64                 `protobuf:"varint,1,opt,name=subscription_type_id,json=subscriptionTypeId,proto3" json:"subscription_type_id,omitempty"`
+	UserId             string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email              string                 `protobuf:"bytes,3,opt,name=email,proto3" json:"email,omitempty"`
+	StartAt            string                 `protobuf:"bytes,4,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	AutoRenew          bool                   `protobuf:"varint,5,opt,name=auto_renew,json=autoRenew,proto3" json:"auto_renew,omitempty"`
+	PlanTypeId         uint64                 `protobuf:"varint,6,opt,name=plan_type_id,json=planTypeId,proto3" json:"plan_type_id,omitempty"`
+	CouponCode         string                 `protobuf:"bytes,7,opt,name=coupon_code,json=couponCode,proto3" json:"coupon_code,omitempty"`
+	Quantity           int32                  `protobuf:"varint,8,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionRequest) Reset() {
+	*x = CreateSubscriptionRequest{}
+	mi := &file_subscriptions_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionRequest) ProtoMessage() {}
+
+func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CreateSubscriptionRequest) GetSubscriptionTypeId() uint64 {
+	if x != nil {
+		return x.SubscriptionTypeId
+	}
+	return 0
+}
+
+func (x *CreateSubscriptionRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetStartAt() string {
+	if x != nil {
+		return x.StartAt
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetAutoRenew() bool {
+	if x != nil {
+		return x.AutoRenew
+	}
+	return false
+}
+
+func (x *CreateSubscriptionRequest) GetPlanTypeId() uint64 {
+	if x != nil {
+		return x.PlanTypeId
+	}
+	return 0
+}
+
+func (x *CreateSubscriptionRequest) GetCouponCode() string {
+	if x != nil {
+		return x.CouponCode
+	}
+	return ""
+}
+
+func (x *CreateSubscriptionRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type Subscription struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubscriptionTypeId uint64                 `protobuf:"varint,2,opt,name=subscription_type_id,json=subscriptionTypeId,proto3" json:"subscription_type_id,omitempty"`
+	UserId             string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email              string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
+	Status             int32                  `protobuf:"varint,5,opt,name=status,proto3" json:"status,omitempty"`
+	StartAt            string                 `protobuf:"bytes,6,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
+	EndAt              string                 `protobuf:"bytes,7,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"`
+	RenewAt            string                 `protobuf:"bytes,8,opt,name=renew_at,json=renewAt,proto3" json:"renew_at,omitempty"`
+	AutoRenew          bool                   `protobuf:"varint,9,opt,name=auto_renew,json=autoRenew,proto3" json:"auto_renew,omitempty"`
+	CreatedAt          string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt          string                 `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	PlanTypeId         uint64                 `protobuf:"varint,12,opt,name=plan_type_id,json=planTypeId,proto3" json:"plan_type_id,omitempty"`
+	PendingPlanTypeId  uint64                 `protobuf:"varint,13,opt,name=pending_plan_type_id,json=pendingPlanTypeId,proto3" json:"pending_plan_type_id,omitempty"`
+	TrialEndAt         string                 `protobuf:"bytes,14,opt,name=trial_end_at,json=trialEndAt,proto3" json:"trial_end_at,omitempty"`
+	CanceledAt         string                 `protobuf:"bytes,15,opt,name=canceled_at,json=canceledAt,proto3" json:"canceled_at,omitempty"`
+	CancelReason       string                 `protobuf:"bytes,16,opt,name=cancel_reason,json=cancelReason,proto3" json:"cancel_reason,omitempty"`
+	PausedAt           string                 `protobuf:"bytes,17,opt,name=paused_at,json=pausedAt,proto3" json:"paused_at,omitempty"`
+	ResumeAt           string                 `protobuf:"bytes,18,opt,name=resume_at,json=resumeAt,proto3" json:"resume_at,omitempty"`
+	RenewalRetryCount  int32                  `protobuf:"varint,19,opt,name=renewal_retry_count,json=renewalRetryCount,proto3" json:"renewal_retry_count,omitempty"`
+	Quantity           int32                  `protobuf:"varint,20,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Version            int64                  `protobuf:"varint,21,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Subscription) Reset() {
+	*x = Subscription{}
+	mi := &file_subscriptions_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Subscription) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Subscription) ProtoMessage() {}
+
+func (x *Subscription) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
+func (*Subscription) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *Subscription) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Subscription) GetSubscriptionTypeId() uint64 {
+	if x != nil {
+		return x.SubscriptionTypeId
+	}
+	return 0
+}
+
+func (x *Subscription) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *Subscription) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *Subscription) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *Subscription) GetStartAt() string {
+	if x != nil {
+		return x.StartAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetEndAt() string {
+	if x != nil {
+		return x.EndAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetRenewAt() string {
+	if x != nil {
+		return x.RenewAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetAutoRenew() bool {
+	if x != nil {
+		return x.AutoRenew
+	}
+	return false
+}
+
+func (x *Subscription) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetPlanTypeId() uint64 {
+	if x != nil {
+		return x.PlanTypeId
+	}
+	return 0
+}
+
+func (x *Subscription) GetPendingPlanTypeId() uint64 {
+	if x != nil {
+		return x.PendingPlanTypeId
+	}
+	return 0
+}
+
+func (x *Subscription) GetTrialEndAt() string {
+	if x != nil {
+		return x.TrialEndAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetCanceledAt() string {
+	if x != nil {
+		return x.CanceledAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetCancelReason() string {
+	if x != nil {
+		return x.CancelReason
+	}
+	return ""
+}
+
+func (x *Subscription) GetPausedAt() string {
+	if x != nil {
+		return x.PausedAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetResumeAt() string {
+	if x != nil {
+		return x.ResumeAt
+	}
+	return ""
+}
+
+func (x *Subscription) GetRenewalRetryCount() int32 {
+	if x != nil {
+		return x.RenewalRetryCount
+	}
+	return 0
+}
+
+func (x *Subscription) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *Subscription) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type CreateSubscriptionResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	PaymentUrl    string                 `protobuf:"bytes,2,opt,name=payment_url,json=paymentUrl,proto3" json:"payment_url,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateSubscriptionResponse) Reset() {
+	*x = CreateSubscriptionResponse{}
+	mi := &file_subscriptions_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateSubscriptionResponse) ProtoMessage() {}
+
+func (x *CreateSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*CreateSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CreateSubscriptionResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *CreateSubscriptionResponse) GetPaymentUrl() string {
+	if x != nil {
+		return x.PaymentUrl
+	}
+	return ""
+}
+
+type GetSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSubscriptionRequest) Reset() {
+	*x = GetSubscriptionRequest{}
+	mi := &file_subscriptions_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSubscriptionRequest) ProtoMessage() {}
+
+func (x *GetSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*GetSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *GetSubscriptionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type SubscriptionEnvelopeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscriptionEnvelopeResponse) Reset() {
+	*x = SubscriptionEnvelopeResponse{}
+	mi := &file_subscriptions_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionEnvelopeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionEnvelopeResponse) ProtoMessage() {}
+
+func (x *SubscriptionEnvelopeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionEnvelopeResponse.ProtoReflect.Descriptor instead.
+func (*SubscriptionEnvelopeResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *SubscriptionEnvelopeResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+type ListSubscriptionsRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	UserId             string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Email              string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
+	PageSize           int32                  `protobuf:"varint,3,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageToken          string                 `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	HasStatus          bool                   `protobuf:"varint,5,opt,name=has_status,json=hasStatus,proto3" json:"has_status,omitempty"`
+	Status             int32                  `protobuf:"varint,6,opt,name=status,proto3" json:"status,omitempty"`
+	SubscriptionTypeId uint64                 `protobuf:"varint,7,opt,name=subscription_type_id,json=subscriptionTypeId,proto3" json:"subscription_type_id,omitempty"`
+	HasAutoRenew       bool                   `protobuf:"varint,8,opt,name=has_auto_renew,json=hasAutoRenew,proto3" json:"has_auto_renew,omitempty"`
+	AutoRenew          bool                   `protobuf:"varint,9,opt,name=auto_renew,json=autoRenew,proto3" json:"auto_renew,omitempty"`
+	EndAtFrom          string                 `protobuf:"bytes,10,opt,name=end_at_from,json=endAtFrom,proto3" json:"end_at_from,omitempty"`
+	EndAtTo            string                 `protobuf:"bytes,11,opt,name=end_at_to,json=endAtTo,proto3" json:"end_at_to,omitempty"`
+	CreatedAtFrom      string                 `protobuf:"bytes,12,opt,name=created_at_from,json=createdAtFrom,proto3" json:"created_at_from,omitempty"`
+	CreatedAtTo        string                 `protobuf:"bytes,13,opt,name=created_at_to,json=createdAtTo,proto3" json:"created_at_to,omitempty"`
+	Sort               string                 `protobuf:"bytes,14,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsRequest) Reset() {
+	*x = ListSubscriptionsRequest{}
+	mi := &file_subscriptions_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ListSubscriptionsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ListSubscriptionsRequest) GetEmail() string {
+	if x != nil {
+		return x.Email
+	}
+	return ""
+}
+
+func (x *ListSubscriptionsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListSubscriptionsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListSubscriptionsRequest) GetHasStatus() bool {
+	if x != nil {
+		return x.HasStatus
+	}
+	return false
+}
+
+func (x *ListSubscriptionsRequest) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *ListSubscriptionsRequest) GetSubscriptionTypeId() uint64 {
+	if x != nil {
+		return x.SubscriptionTypeId
+	}
+	return 0
+}
+
+func (x *ListSubscriptionsRequest) GetHasAutoRenew() bool {
+	if x != nil {
+		return x.HasAutoRenew
+	}
+	return false
+}
+
+func (x *ListSubscriptionsRequest) GetAutoRenew() bool {
+	if x != nil {
+		return x.AutoRenew
+	}
+	return false
+}
+
+func (x *ListSubscriptionsRequest) GetEndAtFrom() string {
+	if x != nil {
+		return x.EndAtFrom
+	}
+	return ""
+}
+
+func (x *ListSubscriptionsRequest) GetEndAtTo() string {
+	if x != nil {
+		return x.EndAtTo
+	}
+	return ""
+}
+
+func (x *ListSubscriptionsRequest) GetCreatedAtFrom() string {
+	if x != nil {
+		return x.CreatedAtFrom
+	}
+	return ""
+}
+
+func (x *ListSubscriptionsRequest) GetCreatedAtTo() string {
+	if x != nil {
+		return x.CreatedAtTo
+	}
+	return ""
+}
+
+func (x *ListSubscriptionsRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type ListSubscriptionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Subscriptions []*Subscription        `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
+	NextPageToken string                 `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionsResponse) Reset() {
+	*x = ListSubscriptionsResponse{}
+	mi := &file_subscriptions_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionsResponse) ProtoMessage() {}
+
+func (x *ListSubscriptionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionsResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ListSubscriptionsResponse) GetSubscriptions() []*Subscription {
+	if x != nil {
+		return x.Subscriptions
+	}
+	return nil
+}
+
+func (x *ListSubscriptionsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+type UpdateSubscriptionRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	HasAutoRenew    bool                   `protobuf:"varint,2,opt,name=has_auto_renew,json=hasAutoRenew,proto3" json:"has_auto_renew,omitempty"`
+	AutoRenew       bool                   `protobuf:"varint,3,opt,name=auto_renew,json=autoRenew,proto3" json:"auto_renew,omitempty"`
+	HasStatus       bool                   `protobuf:"varint,4,opt,name=has_status,json=hasStatus,proto3" json:"has_status,omitempty"`
+	Status          int32                  `protobuf:"varint,5,opt,name=status,proto3" json:"status,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,6,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *UpdateSubscriptionRequest) Reset() {
+	*x = UpdateSubscriptionRequest{}
+	mi := &file_subscriptions_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateSubscriptionRequest) ProtoMessage() {}
+
+func (x *UpdateSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*UpdateSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *UpdateSubscriptionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateSubscriptionRequest) GetHasAutoRenew() bool {
+	if x != nil {
+		return x.HasAutoRenew
+	}
+	return false
+}
+
+func (x *UpdateSubscriptionRequest) GetAutoRenew() bool {
+	if x != nil {
+		return x.AutoRenew
+	}
+	return false
+}
+
+func (x *UpdateSubscriptionRequest) GetHasStatus() bool {
+	if x != nil {
+		return x.HasStatus
+	}
+	return false
+}
+
+func (x *UpdateSubscriptionRequest) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *UpdateSubscriptionRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type DeleteSubscriptionRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ExpectedVersion int64                  `protobuf:"varint,2,opt,name=expected_version,json=expectedVersion,proto3" json:"expected_version,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeleteSubscriptionRequest) Reset() {
+	*x = DeleteSubscriptionRequest{}
+	mi := &file_subscriptions_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteSubscriptionRequest) ProtoMessage() {}
+
+func (x *DeleteSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*DeleteSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *DeleteSubscriptionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *DeleteSubscriptionRequest) GetExpectedVersion() int64 {
+	if x != nil {
+		return x.ExpectedVersion
+	}
+	return 0
+}
+
+type CancelSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Mode          string                 `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"`
+	Refund        bool                   `protobuf:"varint,4,opt,name=refund,proto3" json:"refund,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelSubscriptionRequest) Reset() {
+	*x = CancelSubscriptionRequest{}
+	mi := &file_subscriptions_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelSubscriptionRequest) ProtoMessage() {}
+
+func (x *CancelSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*CancelSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *CancelSubscriptionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *CancelSubscriptionRequest) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *CancelSubscriptionRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *CancelSubscriptionRequest) GetRefund() bool {
+	if x != nil {
+		return x.Refund
+	}
+	return false
+}
+
+type CancelSubscriptionResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Message             string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Subscription        *Subscription          `protobuf:"bytes,2,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	RefundedAmountCents int64                  `protobuf:"varint,3,opt,name=refunded_amount_cents,json=refundedAmountCents,proto3" json:"refunded_amount_cents,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *CancelSubscriptionResponse) Reset() {
+	*x = CancelSubscriptionResponse{}
+	mi := &file_subscriptions_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelSubscriptionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelSubscriptionResponse) ProtoMessage() {}
+
+func (x *CancelSubscriptionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelSubscriptionResponse.ProtoReflect.Descriptor instead.
+func (*CancelSubscriptionResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *CancelSubscriptionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *CancelSubscriptionResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *CancelSubscriptionResponse) GetRefundedAmountCents() int64 {
+	if x != nil {
+		return x.RefundedAmountCents
+	}
+	return 0
+}
+
+type UndoCancellationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UndoCancellationRequest) Reset() {
+	*x = UndoCancellationRequest{}
+	mi := &file_subscriptions_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UndoCancellationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UndoCancellationRequest) ProtoMessage() {}
+
+func (x *UndoCancellationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UndoCancellationRequest.ProtoReflect.Descriptor instead.
+func (*UndoCancellationRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *UndoCancellationRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type PauseSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	ResumeAt      string                 `protobuf:"bytes,2,opt,name=resume_at,json=resumeAt,proto3" json:"resume_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseSubscriptionRequest) Reset() {
+	*x = PauseSubscriptionRequest{}
+	mi := &file_subscriptions_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseSubscriptionRequest) ProtoMessage() {}
+
+func (x *PauseSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*PauseSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *PauseSubscriptionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PauseSubscriptionRequest) GetResumeAt() string {
+	if x != nil {
+		return x.ResumeAt
+	}
+	return ""
+}
+
+type ResumeSubscriptionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeSubscriptionRequest) Reset() {
+	*x = ResumeSubscriptionRequest{}
+	mi := &file_subscriptions_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeSubscriptionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeSubscriptionRequest) ProtoMessage() {}
+
+func (x *ResumeSubscriptionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeSubscriptionRequest.ProtoReflect.Descriptor instead.
+func (*ResumeSubscriptionRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *ResumeSubscriptionRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type ChangePlanRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	PlanTypeId    uint64                 `protobuf:"varint,2,opt,name=plan_type_id,json=planTypeId,proto3" json:"plan_type_id,omitempty"`
+	Timing        string                 `protobuf:"bytes,3,opt,name=timing,proto3" json:"timing,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePlanRequest) Reset() {
+	*x = ChangePlanRequest{}
+	mi := &file_subscriptions_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePlanRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePlanRequest) ProtoMessage() {}
+
+func (x *ChangePlanRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePlanRequest.ProtoReflect.Descriptor instead.
+func (*ChangePlanRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *ChangePlanRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *ChangePlanRequest) GetPlanTypeId() uint64 {
+	if x != nil {
+		return x.PlanTypeId
+	}
+	return 0
+}
+
+func (x *ChangePlanRequest) GetTiming() string {
+	if x != nil {
+		return x.Timing
+	}
+	return ""
+}
+
+type ChangePlanResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Subscription        *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	ProratedAmountCents int64                  `protobuf:"varint,2,opt,name=prorated_amount_cents,json=proratedAmountCents,proto3" json:"prorated_amount_cents,omitempty"`
+	PaymentUrl          string                 `protobuf:"bytes,3,opt,name=payment_url,json=paymentUrl,proto3" json:"payment_url,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *ChangePlanResponse) Reset() {
+	*x = ChangePlanResponse{}
+	mi := &file_subscriptions_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePlanResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePlanResponse) ProtoMessage() {}
+
+func (x *ChangePlanResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePlanResponse.ProtoReflect.Descriptor instead.
+func (*ChangePlanResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *ChangePlanResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *ChangePlanResponse) GetProratedAmountCents() int64 {
+	if x != nil {
+		return x.ProratedAmountCents
+	}
+	return 0
+}
+
+func (x *ChangePlanResponse) GetPaymentUrl() string {
+	if x != nil {
+		return x.PaymentUrl
+	}
+	return ""
+}
+
+type UpdateQuantityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	Proration     string                 `protobuf:"bytes,3,opt,name=proration,proto3" json:"proration,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateQuantityRequest) Reset() {
+	*x = UpdateQuantityRequest{}
+	mi := &file_subscriptions_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateQuantityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateQuantityRequest) ProtoMessage() {}
+
+func (x *UpdateQuantityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateQuantityRequest.ProtoReflect.Descriptor instead.
+func (*UpdateQuantityRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *UpdateQuantityRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateQuantityRequest) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+func (x *UpdateQuantityRequest) GetProration() string {
+	if x != nil {
+		return x.Proration
+	}
+	return ""
+}
+
+type UpdateQuantityResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	Subscription        *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	ProratedAmountCents int64                  `protobuf:"varint,2,opt,name=prorated_amount_cents,json=proratedAmountCents,proto3" json:"prorated_amount_cents,omitempty"`
+	PaymentUrl          string                 `protobuf:"bytes,3,opt,name=payment_url,json=paymentUrl,proto3" json:"payment_url,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *UpdateQuantityResponse) Reset() {
+	*x = UpdateQuantityResponse{}
+	mi := &file_subscriptions_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateQuantityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateQuantityResponse) ProtoMessage() {}
+
+func (x *UpdateQuantityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateQuantityResponse.ProtoReflect.Descriptor instead.
+func (*UpdateQuantityResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *UpdateQuantityResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *UpdateQuantityResponse) GetProratedAmountCents() int64 {
+	if x != nil {
+		return x.ProratedAmountCents
+	}
+	return 0
+}
+
+func (x *UpdateQuantityResponse) GetPaymentUrl() string {
+	if x != nil {
+		return x.PaymentUrl
+	}
+	return ""
+}
+
+type PaymentCallbackRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId uint64                 `protobuf:"varint,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Status         string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	TransactionId  string                 `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PaymentCallbackRequest) Reset() {
+	*x = PaymentCallbackRequest{}
+	mi := &file_subscriptions_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentCallbackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentCallbackRequest) ProtoMessage() {}
+
+func (x *PaymentCallbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentCallbackRequest.ProtoReflect.Descriptor instead.
+func (*PaymentCallbackRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *PaymentCallbackRequest) GetSubscriptionId() uint64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+func (x *PaymentCallbackRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *PaymentCallbackRequest) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+type PaymentCallbackResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Message          string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
+	Subscription     *Subscription          `protobuf:"bytes,2,opt,name=subscription,proto3" json:"subscription,omitempty"`
+	AlreadyProcessed bool                   `protobuf:"varint,3,opt,name=already_processed,json=alreadyProcessed,proto3" json:"already_processed,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PaymentCallbackResponse) Reset() {
+	*x = PaymentCallbackResponse{}
+	mi := &file_subscriptions_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentCallbackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentCallbackResponse) ProtoMessage() {}
+
+func (x *PaymentCallbackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentCallbackResponse.ProtoReflect.Descriptor instead.
+func (*PaymentCallbackResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *PaymentCallbackResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *PaymentCallbackResponse) GetSubscription() *Subscription {
+	if x != nil {
+		return x.Subscription
+	}
+	return nil
+}
+
+func (x *PaymentCallbackResponse) GetAlreadyProcessed() bool {
+	if x != nil {
+		return x.AlreadyProcessed
+	}
+	return false
+}
+
+type ListPaymentAttemptsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId uint64                 `protobuf:"varint,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListPaymentAttemptsRequest) Reset() {
+	*x = ListPaymentAttemptsRequest{}
+	mi := &file_subscriptions_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListPaymentAttemptsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListPaymentAttemptsRequest) ProtoMessage() {}
+
+func (x *ListPaymentAttemptsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListPaymentAttemptsRequest.ProtoReflect.Descriptor instead.
+func (*ListPaymentAttemptsRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *ListPaymentAttemptsRequest) GetSubscriptionId() uint64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+type PaymentAttempt struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubscriptionId uint64                 `protobuf:"varint,2,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	PlanTypeId     uint64                 `protobuf:"varint,3,opt,name=plan_type_id,json=planTypeId,proto3" json:"plan_type_id,omitempty"`
+	AmountCents    int64                  `protobuf:"varint,4,opt,name=amount_cents,json=amountCents,proto3" json:"amount_cents,omitempty"`
+	Currency       string                 `protobuf:"bytes,5,opt,name=currency,proto3" json:"currency,omitempty"`
+	TransactionId  string                 `protobuf:"bytes,6,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
+	ResultType     string                 `protobuf:"bytes,7,opt,name=result_type,json=resultType,proto3" json:"result_type,omitempty"`
+	Status         int32                  `protobuf:"varint,8,opt,name=status,proto3" json:"status,omitempty"`
+	Error          string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	CompletedAt    string                 `protobuf:"bytes,10,opt,name=completed_at,json=completedAt,proto3" json:"completed_at,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,11,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt      string                 `protobuf:"bytes,12,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Kind           string                 `protobuf:"bytes,13,opt,name=kind,proto3" json:"kind,omitempty"`
+	DiscountCents  int64                  `protobuf:"varint,14,opt,name=discount_cents,json=discountCents,proto3" json:"discount_cents,omitempty"`
+	Quantity       int32                  `protobuf:"varint,15,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PaymentAttempt) Reset() {
+	*x = PaymentAttempt{}
+	mi := &file_subscriptions_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PaymentAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PaymentAttempt) ProtoMessage() {}
+
+func (x *PaymentAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PaymentAttempt.ProtoReflect.Descriptor instead.
+func (*PaymentAttempt) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *PaymentAttempt) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *PaymentAttempt) GetSubscriptionId() uint64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+func (x *PaymentAttempt) GetPlanTypeId() uint64 {
+	if x != nil {
+		return x.PlanTypeId
+	}
+	return 0
+}
+
+func (x *PaymentAttempt) GetAmountCents() int64 {
+	if x != nil {
+		return x.AmountCents
+	}
+	return 0
+}
+
+func (x *PaymentAttempt) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *PaymentAttempt) GetTransactionId() string {
+	if x != nil {
+		return x.TransactionId
+	}
+	return ""
+}
+
+func (x *PaymentAttempt) GetResultType() string {
+	if x != nil {
+		return x.ResultType
+	}
+	return ""
+}
+
+func (x *PaymentAttempt) GetStatus() int32 {
+	if x != nil {
+		return x.Status
+	}
+	return 0
+}
+
+func (x *PaymentAttempt) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *PaymentAttempt) GetCompletedAt() string {
+	if x != nil {
+		return x.CompletedAt
+	}
+	return ""
+}
+
+func (x *PaymentAttempt) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *PaymentAttempt) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+func (x *PaymentAttempt) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *PaymentAttempt) GetDiscountCents() int64 {
+	if x != nil {
+		return x.DiscountCents
+	}
+	return 0
+}
+
+func (x *PaymentAttempt) GetQuantity() int32 {
+	if x != nil {
+		return x.Quantity
+	}
+	return 0
+}
+
+type ListPaymentAttemptsResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	PaymentAttempts []*PaymentAttempt      `protobuf:"bytes,1,rep,name=payment_attempts,json=paymentAttempts,proto3" json:"payment_attempts,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *ListPaymentAttemptsResponse) Reset() {
+	*x = ListPaymentAttemptsResponse{}
+	mi := &file_subscriptions_proto_msgTypes[49]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateSubscriptionRequest) String() string {
+func (x *ListPaymentAttemptsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateSubscriptionRequest) ProtoMessage() {}
+func (*ListPaymentAttemptsResponse) ProtoMessage() {}
 
-func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[5]
+func (x *ListPaymentAttemptsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[49]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -325,78 +3545,192 @@ func (x *CreateSubscriptionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateSubscriptionRequest.ProtoReflect.Descriptor instead.
-func (*CreateSubscriptionRequest) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use ListPaymentAttemptsResponse.ProtoReflect.Descriptor instead.
+func (*ListPaymentAttemptsResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{49}
 }
 
-func (x *CreateSubscriptionRequest) GetSubscriptionTypeId() uint64 {
+func (x *ListPaymentAttemptsResponse) GetPaymentAttempts() []*PaymentAttempt {
 	if x != nil {
-		return x.SubscriptionTypeId
+		return x.PaymentAttempts
+	}
+	return nil
+}
+
+type ListSubscriptionEventsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	SubscriptionId uint64                 `protobuf:"varint,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListSubscriptionEventsRequest) Reset() {
+	*x = ListSubscriptionEventsRequest{}
+	mi := &file_subscriptions_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListSubscriptionEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListSubscriptionEventsRequest) ProtoMessage() {}
+
+func (x *ListSubscriptionEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListSubscriptionEventsRequest.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionEventsRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *ListSubscriptionEventsRequest) GetSubscriptionId() uint64 {
+	if x != nil {
+		return x.SubscriptionId
 	}
 	return 0
 }
 
-func (x *CreateSubscriptionRequest) GetUserId() string {
+type SubscriptionEvent struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	SubscriptionId uint64                 `protobuf:"varint,2,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Field          string                 `protobuf:"bytes,3,opt,name=field,proto3" json:"field,omitempty"`
+	OldValue       string                 `protobuf:"bytes,4,opt,name=old_value,json=oldValue,proto3" json:"old_value,omitempty"`
+	NewValue       string                 `protobuf:"bytes,5,opt,name=new_value,json=newValue,proto3" json:"new_value,omitempty"`
+	Actor          string                 `protobuf:"bytes,6,opt,name=actor,proto3" json:"actor,omitempty"`
+	RequestId      string                 `protobuf:"bytes,7,opt,name=request_id,json=requestId,proto3" json:"request_id,omitempty"`
+	Reason         string                 `protobuf:"bytes,8,opt,name=reason,proto3" json:"reason,omitempty"`
+	CreatedAt      string                 `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SubscriptionEvent) Reset() {
+	*x = SubscriptionEvent{}
+	mi := &file_subscriptions_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscriptionEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscriptionEvent) ProtoMessage() {}
+
+func (x *SubscriptionEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[51]
 	if x != nil {
-		return x.UserId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscriptionEvent.ProtoReflect.Descriptor instead.
+func (*SubscriptionEvent) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *SubscriptionEvent) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *SubscriptionEvent) GetSubscriptionId() uint64 {
+	if x != nil {
+		return x.SubscriptionId
+	}
+	return 0
+}
+
+func (x *SubscriptionEvent) GetField() string {
+	if x != nil {
+		return x.Field
 	}
 	return ""
 }
 
-func (x *CreateSubscriptionRequest) GetEmail() string {
+func (x *SubscriptionEvent) GetOldValue() string {
 	if x != nil {
-		return x.Email
+		return x.OldValue
 	}
 	return ""
 }
 
-func (x *CreateSubscriptionRequest) GetStartAt() string {
+func (x *SubscriptionEvent) GetNewValue() string {
 	if x != nil {
-		return x.StartAt
+		return x.NewValue
 	}
 	return ""
 }
 
-func (x *CreateSubscriptionRequest) GetAutoRenew() bool {
+func (x *SubscriptionEvent) GetActor() string {
 	if x != nil {
-		return x.AutoRenew
+		return x.Actor
 	}
-	return false
+	return ""
 }
 
-type Subscription struct {
+func (x *SubscriptionEvent) GetRequestId() string {
+	if x != nil {
+		return x.RequestId
+	}
+	return ""
+}
+
+func (x *SubscriptionEvent) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *SubscriptionEvent) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+type ListSubscriptionEventsResponse struct {
 	state              protoimpl.MessageState `protogen:"open.v1"`
-	Id                 uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	SubscriptionTypeId uint64                 `protobuf:"varint,2,opt,name=subscription_type_id,json=subscriptionTypeId,proto3" json:"subscription_type_id,omitempty"`
-	UserId             string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Email              string                 `protobuf:"bytes,4,opt,name=email,proto3" json:"email,omitempty"`
-	Status             int32                  `protobuf:"varint,5,opt,name=status,proto3" json:"status,omitempty"`
-	StartAt            string                 `protobuf:"bytes,6,opt,name=start_at,json=startAt,proto3" json:"start_at,omitempty"`
-	EndAt              string                 `protobuf:"bytes,7,opt,name=end_at,json=endAt,proto3" json:"end_at,omitempty"`
-	RenewAt            string                 `protobuf:"bytes,8,opt,name=renew_at,json=renewAt,proto3" json:"renew_at,omitempty"`
-	AutoRenew          bool                   `protobuf:"varint,9,opt,name=auto_renew,json=autoRenew,proto3" json:"auto_renew,omitempty"`
-	CreatedAt          string                 `protobuf:"bytes,10,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt          string                 `protobuf:"bytes,11,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	SubscriptionEvents []*SubscriptionEvent   `protobuf:"bytes,1,rep,name=subscription_events,json=subscriptionEvents,proto3" json:"subscription_events,omitempty"`
 	unknownFields      protoimpl.UnknownFields
 	sizeCache          protoimpl.SizeCache
 }
 
-func (x *Subscription) Reset() {
-	*x = Subscription{}
-	mi := &file_subscriptions_proto_msgTypes[6]
+func (x *ListSubscriptionEventsResponse) Reset() {
+	*x = ListSubscriptionEventsResponse{}
+	mi := &file_subscriptions_proto_msgTypes[52]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Subscription) String() string {
+func (x *ListSubscriptionEventsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Subscription) ProtoMessage() {}
+func (*ListSubscriptionEventsResponse) ProtoMessage() {}
 
-func (x *Subscription) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[6]
+func (x *ListSubscriptionEventsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[52]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -407,111 +3741,201 @@ func (x *Subscription) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Subscription.ProtoReflect.Descriptor instead.
-func (*Subscription) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ListSubscriptionEventsResponse.ProtoReflect.Descriptor instead.
+func (*ListSubscriptionEventsResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{52}
 }
 
-func (x *Subscription) GetId() uint64 {
+func (x *ListSubscriptionEventsResponse) GetSubscriptionEvents() []*SubscriptionEvent {
 	if x != nil {
-		return x.Id
+		return x.SubscriptionEvents
 	}
-	return 0
+	return nil
 }
 
-func (x *Subscription) GetSubscriptionTypeId() uint64 {
+type WebhookEndpoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	EventTypes    []string               `protobuf:"bytes,4,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	Status        int32                  `protobuf:"varint,5,opt,name=status,proto3" json:"status,omitempty"`
+	CreatedBy     string                 `protobuf:"bytes,6,opt,name=created_by,json=createdBy,proto3" json:"created_by,omitempty"`
+	CreatedAt     string                 `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     string                 `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookEndpoint) Reset() {
+	*x = WebhookEndpoint{}
+	mi := &file_subscriptions_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookEndpoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookEndpoint) ProtoMessage() {}
+
+func (x *WebhookEndpoint) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[53]
 	if x != nil {
-		return x.SubscriptionTypeId
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookEndpoint.ProtoReflect.Descriptor instead.
+func (*WebhookEndpoint) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *WebhookEndpoint) GetId() uint64 {
+	if x != nil {
+		return x.Id
 	}
 	return 0
 }
 
-func (x *Subscription) GetUserId() string {
+func (x *WebhookEndpoint) GetUrl() string {
 	if x != nil {
-		return x.UserId
+		return x.Url
 	}
 	return ""
 }
 
-func (x *Subscription) GetEmail() string {
+func (x *WebhookEndpoint) GetDescription() string {
 	if x != nil {
-		return x.Email
+		return x.Description
 	}
 	return ""
 }
 
-func (x *Subscription) GetStatus() int32 {
+func (x *WebhookEndpoint) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *WebhookEndpoint) GetStatus() int32 {
 	if x != nil {
 		return x.Status
 	}
 	return 0
 }
 
-func (x *Subscription) GetStartAt() string {
+func (x *WebhookEndpoint) GetCreatedBy() string {
 	if x != nil {
-		return x.StartAt
+		return x.CreatedBy
 	}
 	return ""
 }
 
-func (x *Subscription) GetEndAt() string {
+func (x *WebhookEndpoint) GetCreatedAt() string {
 	if x != nil {
-		return x.EndAt
+		return x.CreatedAt
 	}
 	return ""
 }
 
-func (x *Subscription) GetRenewAt() string {
+func (x *WebhookEndpoint) GetUpdatedAt() string {
 	if x != nil {
-		return x.RenewAt
+		return x.UpdatedAt
 	}
 	return ""
 }
 
-func (x *Subscription) GetAutoRenew() bool {
+type CreateWebhookEndpointRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	EventTypes    []string               `protobuf:"bytes,3,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookEndpointRequest) Reset() {
+	*x = CreateWebhookEndpointRequest{}
+	mi := &file_subscriptions_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookEndpointRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookEndpointRequest) ProtoMessage() {}
+
+func (x *CreateWebhookEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[54]
 	if x != nil {
-		return x.AutoRenew
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *Subscription) GetCreatedAt() string {
+// Deprecated: Use CreateWebhookEndpointRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *CreateWebhookEndpointRequest) GetUrl() string {
 	if x != nil {
-		return x.CreatedAt
+		return x.Url
 	}
 	return ""
 }
 
-func (x *Subscription) GetUpdatedAt() string {
+func (x *CreateWebhookEndpointRequest) GetDescription() string {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Description
 	}
 	return ""
 }
 
-type CreateSubscriptionResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
-	PaymentUrl    string                 `protobuf:"bytes,2,opt,name=payment_url,json=paymentUrl,proto3" json:"payment_url,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *CreateWebhookEndpointRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
 }
 
-func (x *CreateSubscriptionResponse) Reset() {
-	*x = CreateSubscriptionResponse{}
-	mi := &file_subscriptions_proto_msgTypes[7]
+type CreateWebhookEndpointResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	WebhookEndpoint *WebhookEndpoint       `protobuf:"bytes,1,opt,name=webhook_endpoint,json=webhookEndpoint,proto3" json:"webhook_endpoint,omitempty"`
+	Secret          string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *CreateWebhookEndpointResponse) Reset() {
+	*x = CreateWebhookEndpointResponse{}
+	mi := &file_subscriptions_proto_msgTypes[55]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateSubscriptionResponse) String() string {
+func (x *CreateWebhookEndpointResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateSubscriptionResponse) ProtoMessage() {}
+func (*CreateWebhookEndpointResponse) ProtoMessage() {}
 
-func (x *CreateSubscriptionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[7]
+func (x *CreateWebhookEndpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[55]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -522,47 +3946,47 @@ func (x *CreateSubscriptionResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateSubscriptionResponse.ProtoReflect.Descriptor instead.
-func (*CreateSubscriptionResponse) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use CreateWebhookEndpointResponse.ProtoReflect.Descriptor instead.
+func (*CreateWebhookEndpointResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{55}
 }
 
-func (x *CreateSubscriptionResponse) GetSubscription() *Subscription {
+func (x *CreateWebhookEndpointResponse) GetWebhookEndpoint() *WebhookEndpoint {
 	if x != nil {
-		return x.Subscription
+		return x.WebhookEndpoint
 	}
 	return nil
 }
 
-func (x *CreateSubscriptionResponse) GetPaymentUrl() string {
+func (x *CreateWebhookEndpointResponse) GetSecret() string {
 	if x != nil {
-		return x.PaymentUrl
+		return x.Secret
 	}
 	return ""
 }
 
-type GetSubscriptionRequest struct {
+type GetWebhookEndpointRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetSubscriptionRequest) Reset() {
-	*x = GetSubscriptionRequest{}
-	mi := &file_subscriptions_proto_msgTypes[8]
+func (x *GetWebhookEndpointRequest) Reset() {
+	*x = GetWebhookEndpointRequest{}
+	mi := &file_subscriptions_proto_msgTypes[56]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetSubscriptionRequest) String() string {
+func (x *GetWebhookEndpointRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetSubscriptionRequest) ProtoMessage() {}
+func (*GetWebhookEndpointRequest) ProtoMessage() {}
 
-func (x *GetSubscriptionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[8]
+func (x *GetWebhookEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[56]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -573,40 +3997,40 @@ func (x *GetSubscriptionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetSubscriptionRequest.ProtoReflect.Descriptor instead.
-func (*GetSubscriptionRequest) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use GetWebhookEndpointRequest.ProtoReflect.Descriptor instead.
+func (*GetWebhookEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{56}
 }
 
-func (x *GetSubscriptionRequest) GetId() uint64 {
+func (x *GetWebhookEndpointRequest) GetId() uint64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-type SubscriptionEnvelopeResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Subscription  *Subscription          `protobuf:"bytes,1,opt,name=subscription,proto3" json:"subscription,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type WebhookEndpointResponse struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	WebhookEndpoint *WebhookEndpoint       `protobuf:"bytes,1,opt,name=webhook_endpoint,json=webhookEndpoint,proto3" json:"webhook_endpoint,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
-func (x *SubscriptionEnvelopeResponse) Reset() {
-	*x = SubscriptionEnvelopeResponse{}
-	mi := &file_subscriptions_proto_msgTypes[9]
+func (x *WebhookEndpointResponse) Reset() {
+	*x = WebhookEndpointResponse{}
+	mi := &file_subscriptions_proto_msgTypes[57]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SubscriptionEnvelopeResponse) String() string {
+func (x *WebhookEndpointResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SubscriptionEnvelopeResponse) ProtoMessage() {}
+func (*WebhookEndpointResponse) ProtoMessage() {}
 
-func (x *SubscriptionEnvelopeResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[9]
+func (x *WebhookEndpointResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[57]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -617,41 +4041,39 @@ func (x *SubscriptionEnvelopeResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SubscriptionEnvelopeResponse.ProtoReflect.Descriptor instead.
-func (*SubscriptionEnvelopeResponse) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use WebhookEndpointResponse.ProtoReflect.Descriptor instead.
+func (*WebhookEndpointResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{57}
 }
 
-func (x *SubscriptionEnvelopeResponse) GetSubscription() *Subscription {
+func (x *WebhookEndpointResponse) GetWebhookEndpoint() *WebhookEndpoint {
 	if x != nil {
-		return x.Subscription
+		return x.WebhookEndpoint
 	}
 	return nil
 }
 
-type ListSubscriptionsRequest struct {
+type ListWebhookEndpointsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Email         string                 `protobuf:"bytes,2,opt,name=email,proto3" json:"email,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListSubscriptionsRequest) Reset() {
-	*x = ListSubscriptionsRequest{}
-	mi := &file_subscriptions_proto_msgTypes[10]
+func (x *ListWebhookEndpointsRequest) Reset() {
+	*x = ListWebhookEndpointsRequest{}
+	mi := &file_subscriptions_proto_msgTypes[58]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListSubscriptionsRequest) String() string {
+func (x *ListWebhookEndpointsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSubscriptionsRequest) ProtoMessage() {}
+func (*ListWebhookEndpointsRequest) ProtoMessage() {}
 
-func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[10]
+func (x *ListWebhookEndpointsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[58]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -662,47 +4084,33 @@ func (x *ListSubscriptionsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSubscriptionsRequest.ProtoReflect.Descriptor instead.
-func (*ListSubscriptionsRequest) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{10}
-}
-
-func (x *ListSubscriptionsRequest) GetUserId() string {
-	if x != nil {
-		return x.UserId
-	}
-	return ""
-}
-
-func (x *ListSubscriptionsRequest) GetEmail() string {
-	if x != nil {
-		return x.Email
-	}
-	return ""
+// Deprecated: Use ListWebhookEndpointsRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookEndpointsRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{58}
 }
 
-type ListSubscriptionsResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Subscriptions []*Subscription        `protobuf:"bytes,1,rep,name=subscriptions,proto3" json:"subscriptions,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type ListWebhookEndpointsResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	WebhookEndpoints []*WebhookEndpoint     `protobuf:"bytes,1,rep,name=webhook_endpoints,json=webhookEndpoints,proto3" json:"webhook_endpoints,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
-func (x *ListSubscriptionsResponse) Reset() {
-	*x = ListSubscriptionsResponse{}
-	mi := &file_subscriptions_proto_msgTypes[11]
+func (x *ListWebhookEndpointsResponse) Reset() {
+	*x = ListWebhookEndpointsResponse{}
+	mi := &file_subscriptions_proto_msgTypes[59]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListSubscriptionsResponse) String() string {
+func (x *ListWebhookEndpointsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListSubscriptionsResponse) ProtoMessage() {}
+func (*ListWebhookEndpointsResponse) ProtoMessage() {}
 
-func (x *ListSubscriptionsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[11]
+func (x *ListWebhookEndpointsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[59]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -713,44 +4121,48 @@ func (x *ListSubscriptionsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListSubscriptionsResponse.ProtoReflect.Descriptor instead.
-func (*ListSubscriptionsResponse) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use ListWebhookEndpointsResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookEndpointsResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{59}
 }
 
-func (x *ListSubscriptionsResponse) GetSubscriptions() []*Subscription {
+func (x *ListWebhookEndpointsResponse) GetWebhookEndpoints() []*WebhookEndpoint {
 	if x != nil {
-		return x.Subscriptions
+		return x.WebhookEndpoints
 	}
 	return nil
 }
 
-type UpdateSubscriptionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	HasAutoRenew  bool                   `protobuf:"varint,2,opt,name=has_auto_renew,json=hasAutoRenew,proto3" json:"has_auto_renew,omitempty"`
-	AutoRenew     bool                   `protobuf:"varint,3,opt,name=auto_renew,json=autoRenew,proto3" json:"auto_renew,omitempty"`
-	HasStatus     bool                   `protobuf:"varint,4,opt,name=has_status,json=hasStatus,proto3" json:"has_status,omitempty"`
-	Status        int32                  `protobuf:"varint,5,opt,name=status,proto3" json:"status,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type UpdateWebhookEndpointRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	HasUrl         bool                   `protobuf:"varint,2,opt,name=has_url,json=hasUrl,proto3" json:"has_url,omitempty"`
+	Url            string                 `protobuf:"bytes,3,opt,name=url,proto3" json:"url,omitempty"`
+	HasDescription bool                   `protobuf:"varint,4,opt,name=has_description,json=hasDescription,proto3" json:"has_description,omitempty"`
+	Description    string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	HasEventTypes  bool                   `protobuf:"varint,6,opt,name=has_event_types,json=hasEventTypes,proto3" json:"has_event_types,omitempty"`
+	EventTypes     []string               `protobuf:"bytes,7,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	HasStatus      bool                   `protobuf:"varint,8,opt,name=has_status,json=hasStatus,proto3" json:"has_status,omitempty"`
+	Status         int32                  `protobuf:"varint,9,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
-func (x *UpdateSubscriptionRequest) Reset() {
-	*x = UpdateSubscriptionRequest{}
-	mi := &file_subscriptions_proto_msgTypes[12]
+func (x *UpdateWebhookEndpointRequest) Reset() {
+	*x = UpdateWebhookEndpointRequest{}
+	mi := &file_subscriptions_proto_msgTypes[60]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateSubscriptionRequest) String() string {
+func (x *UpdateWebhookEndpointRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateSubscriptionRequest) ProtoMessage() {}
+func (*UpdateWebhookEndpointRequest) ProtoMessage() {}
 
-func (x *UpdateSubscriptionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[12]
+func (x *UpdateWebhookEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[60]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -761,68 +4173,96 @@ func (x *UpdateSubscriptionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateSubscriptionRequest.ProtoReflect.Descriptor instead.
-func (*UpdateSubscriptionRequest) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use UpdateWebhookEndpointRequest.ProtoReflect.Descriptor instead.
+func (*UpdateWebhookEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{60}
 }
 
-func (x *UpdateSubscriptionRequest) GetId() uint64 {
+func (x *UpdateWebhookEndpointRequest) GetId() uint64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-func (x *UpdateSubscriptionRequest) GetHasAutoRenew() bool {
+func (x *UpdateWebhookEndpointRequest) GetHasUrl() bool {
 	if x != nil {
-		return x.HasAutoRenew
+		return x.HasUrl
 	}
 	return false
 }
 
-func (x *UpdateSubscriptionRequest) GetAutoRenew() bool {
+func (x *UpdateWebhookEndpointRequest) GetUrl() string {
 	if x != nil {
-		return x.AutoRenew
+		return x.Url
+	}
+	return ""
+}
+
+func (x *UpdateWebhookEndpointRequest) GetHasDescription() bool {
+	if x != nil {
+		return x.HasDescription
 	}
 	return false
 }
 
-func (x *UpdateSubscriptionRequest) GetHasStatus() bool {
+func (x *UpdateWebhookEndpointRequest) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *UpdateWebhookEndpointRequest) GetHasEventTypes() bool {
+	if x != nil {
+		return x.HasEventTypes
+	}
+	return false
+}
+
+func (x *UpdateWebhookEndpointRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *UpdateWebhookEndpointRequest) GetHasStatus() bool {
 	if x != nil {
 		return x.HasStatus
 	}
 	return false
 }
 
-func (x *UpdateSubscriptionRequest) GetStatus() int32 {
+func (x *UpdateWebhookEndpointRequest) GetStatus() int32 {
 	if x != nil {
 		return x.Status
 	}
 	return 0
 }
 
-type DeleteSubscriptionRequest struct {
+type DeleteWebhookEndpointRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteSubscriptionRequest) Reset() {
-	*x = DeleteSubscriptionRequest{}
-	mi := &file_subscriptions_proto_msgTypes[13]
+func (x *DeleteWebhookEndpointRequest) Reset() {
+	*x = DeleteWebhookEndpointRequest{}
+	mi := &file_subscriptions_proto_msgTypes[61]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteSubscriptionRequest) String() string {
+func (x *DeleteWebhookEndpointRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteSubscriptionRequest) ProtoMessage() {}
+func (*DeleteWebhookEndpointRequest) ProtoMessage() {}
 
-func (x *DeleteSubscriptionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[13]
+func (x *DeleteWebhookEndpointRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[61]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -833,40 +4273,40 @@ func (x *DeleteSubscriptionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteSubscriptionRequest.ProtoReflect.Descriptor instead.
-func (*DeleteSubscriptionRequest) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use DeleteWebhookEndpointRequest.ProtoReflect.Descriptor instead.
+func (*DeleteWebhookEndpointRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{61}
 }
 
-func (x *DeleteSubscriptionRequest) GetId() uint64 {
+func (x *DeleteWebhookEndpointRequest) GetId() uint64 {
 	if x != nil {
 		return x.Id
 	}
 	return 0
 }
 
-type CancelSubscriptionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type ListWebhookDeliveriesRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	WebhookEndpointId uint64                 `protobuf:"varint,1,opt,name=webhook_endpoint_id,json=webhookEndpointId,proto3" json:"webhook_endpoint_id,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
-func (x *CancelSubscriptionRequest) Reset() {
-	*x = CancelSubscriptionRequest{}
-	mi := &file_subscriptions_proto_msgTypes[14]
+func (x *ListWebhookDeliveriesRequest) Reset() {
+	*x = ListWebhookDeliveriesRequest{}
+	mi := &file_subscriptions_proto_msgTypes[62]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CancelSubscriptionRequest) String() string {
+func (x *ListWebhookDeliveriesRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CancelSubscriptionRequest) ProtoMessage() {}
+func (*ListWebhookDeliveriesRequest) ProtoMessage() {}
 
-func (x *CancelSubscriptionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[14]
+func (x *ListWebhookDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[62]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -877,42 +4317,52 @@ func (x *CancelSubscriptionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CancelSubscriptionRequest.ProtoReflect.Descriptor instead.
-func (*CancelSubscriptionRequest) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use ListWebhookDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{62}
 }
 
-func (x *CancelSubscriptionRequest) GetId() uint64 {
+func (x *ListWebhookDeliveriesRequest) GetWebhookEndpointId() uint64 {
 	if x != nil {
-		return x.Id
+		return x.WebhookEndpointId
 	}
 	return 0
 }
 
-type PaymentCallbackRequest struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	SubscriptionId uint64                 `protobuf:"varint,1,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
-	Status         string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
-	TransactionId  string                 `protobuf:"bytes,3,opt,name=transaction_id,json=transactionId,proto3" json:"transaction_id,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+type WebhookDelivery struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookEndpointId uint64                 `protobuf:"varint,2,opt,name=webhook_endpoint_id,json=webhookEndpointId,proto3" json:"webhook_endpoint_id,omitempty"`
+	EventId           uint64                 `protobuf:"varint,3,opt,name=event_id,json=eventId,proto3" json:"event_id,omitempty"`
+	EventType         string                 `protobuf:"bytes,4,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	SubscriptionId    uint64                 `protobuf:"varint,5,opt,name=subscription_id,json=subscriptionId,proto3" json:"subscription_id,omitempty"`
+	Status            int32                  `protobuf:"varint,6,opt,name=status,proto3" json:"status,omitempty"`
+	Attempts          int32                  `protobuf:"varint,7,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	ResponseStatus    int32                  `protobuf:"varint,8,opt,name=response_status,json=responseStatus,proto3" json:"response_status,omitempty"`
+	LastError         string                 `protobuf:"bytes,9,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	NextAttemptAt     string                 `protobuf:"bytes,10,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	DeliveredAt       string                 `protobuf:"bytes,11,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	CreatedAt         string                 `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         string                 `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
 }
 
-func (x *PaymentCallbackRequest) Reset() {
-	*x = PaymentCallbackRequest{}
-	mi := &file_subscriptions_proto_msgTypes[15]
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_subscriptions_proto_msgTypes[63]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PaymentCallbackRequest) String() string {
+func (x *WebhookDelivery) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PaymentCallbackRequest) ProtoMessage() {}
+func (*WebhookDelivery) ProtoMessage() {}
 
-func (x *PaymentCallbackRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[15]
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[63]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -923,32 +4373,146 @@ func (x *PaymentCallbackRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PaymentCallbackRequest.ProtoReflect.Descriptor instead.
-func (*PaymentCallbackRequest) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{63}
 }
 
-func (x *PaymentCallbackRequest) GetSubscriptionId() uint64 {
+func (x *WebhookDelivery) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetWebhookEndpointId() uint64 {
+	if x != nil {
+		return x.WebhookEndpointId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetEventId() uint64 {
+	if x != nil {
+		return x.EventId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetSubscriptionId() uint64 {
 	if x != nil {
 		return x.SubscriptionId
 	}
 	return 0
 }
 
-func (x *PaymentCallbackRequest) GetStatus() string {
+func (x *WebhookDelivery) GetStatus() int32 {
 	if x != nil {
 		return x.Status
 	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetResponseStatus() int32 {
+	if x != nil {
+		return x.ResponseStatus
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
 	return ""
 }
 
-func (x *PaymentCallbackRequest) GetTransactionId() string {
+func (x *WebhookDelivery) GetNextAttemptAt() string {
 	if x != nil {
-		return x.TransactionId
+		return x.NextAttemptAt
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetDeliveredAt() string {
+	if x != nil {
+		return x.DeliveredAt
 	}
 	return ""
 }
 
+func (x *WebhookDelivery) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetUpdatedAt() string {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return ""
+}
+
+type ListWebhookDeliveriesResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	WebhookDeliveries []*WebhookDelivery     `protobuf:"bytes,1,rep,name=webhook_deliveries,json=webhookDeliveries,proto3" json:"webhook_deliveries,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *ListWebhookDeliveriesResponse) Reset() {
+	*x = ListWebhookDeliveriesResponse{}
+	mi := &file_subscriptions_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListWebhookDeliveriesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListWebhookDeliveriesResponse) ProtoMessage() {}
+
+func (x *ListWebhookDeliveriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_subscriptions_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListWebhookDeliveriesResponse.ProtoReflect.Descriptor instead.
+func (*ListWebhookDeliveriesResponse) Descriptor() ([]byte, []int) {
+	return file_subscriptions_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *ListWebhookDeliveriesResponse) GetWebhookDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.WebhookDeliveries
+	}
+	return nil
+}
+
 type MessageResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Message       string                 `protobuf:"bytes,1,opt,name=message,proto3" json:"message,omitempty"`
@@ -959,7 +4523,7 @@ type MessageResponse struct {
 
 func (x *MessageResponse) Reset() {
 	*x = MessageResponse{}
-	mi := &file_subscriptions_proto_msgTypes[16]
+	mi := &file_subscriptions_proto_msgTypes[65]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -971,7 +4535,7 @@ func (x *MessageResponse) String() string {
 func (*MessageResponse) ProtoMessage() {}
 
 func (x *MessageResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[16]
+	mi := &file_subscriptions_proto_msgTypes[65]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -984,7 +4548,7 @@ func (x *MessageResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MessageResponse.ProtoReflect.Descriptor instead.
 func (*MessageResponse) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{16}
+	return file_subscriptions_proto_rawDescGZIP(), []int{65}
 }
 
 func (x *MessageResponse) GetMessage() string {
@@ -1010,7 +4574,7 @@ type ErrorResponse struct {
 
 func (x *ErrorResponse) Reset() {
 	*x = ErrorResponse{}
-	mi := &file_subscriptions_proto_msgTypes[17]
+	mi := &file_subscriptions_proto_msgTypes[66]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1022,7 +4586,7 @@ func (x *ErrorResponse) String() string {
 func (*ErrorResponse) ProtoMessage() {}
 
 func (x *ErrorResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_subscriptions_proto_msgTypes[17]
+	mi := &file_subscriptions_proto_msgTypes[66]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1035,7 +4599,7 @@ func (x *ErrorResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ErrorResponse.ProtoReflect.Descriptor instead.
 func (*ErrorResponse) Descriptor() ([]byte, []int) {
-	return file_subscriptions_proto_rawDescGZIP(), []int{17}
+	return file_subscriptions_proto_rawDescGZIP(), []int{66}
 }
 
 func (x *ErrorResponse) GetError() string {
@@ -1047,194 +4611,451 @@ func (x *ErrorResponse) GetError() string {
 
 var File_subscriptions_proto protoreflect.FileDescriptor
 
-var file_subscriptions_proto_rawDesc = string([]byte{
-	0x0a, 0x13, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e,
-	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x73, 0x22, 0x0f, 0x0a, 0x0d, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x22, 0x28, 0x0a, 0x0e, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22,
-	0x69, 0x0a, 0x1c, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x1d, 0x0a, 0x0a, 0x68, 0x61, 0x73, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x08, 0x52, 0x09, 0x68, 0x61, 0x73, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16,
-	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x03,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0xaf, 0x01, 0x0a, 0x10, 0x53,
-	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12,
-	0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12,
-	0x12, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74,
-	0x79, 0x70, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e,
-	0x61, 0x6d, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c,
-	0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x1d,
-	0x0a, 0x0a, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a,
-	0x0a, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x6f, 0x0a, 0x1d,
-	0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x54, 0x79, 0x70, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4e, 0x0a,
-	0x12, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x79,
-	0x70, 0x65, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x73, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x11, 0x73, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x73, 0x22, 0xb6, 0x01,
-	0x0a, 0x19, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x30, 0x0a, 0x14, 0x73,
-	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65,
-	0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x73, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x49, 0x64, 0x12, 0x17, 0x0a,
-	0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18,
-	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x19, 0x0a, 0x08,
-	0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x61, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07,
-	0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x75, 0x74, 0x6f, 0x5f,
-	0x72, 0x65, 0x6e, 0x65, 0x77, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x75, 0x74,
-	0x6f, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x22, 0xc1, 0x02, 0x0a, 0x0c, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x30, 0x0a, 0x14, 0x73, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x5f, 0x69, 0x64, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x04, 0x52, 0x12, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65,
-	0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72,
-	0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x18, 0x04, 0x20, 0x01, 0x28,
-	0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x12, 0x19, 0x0a, 0x08, 0x73, 0x74, 0x61, 0x72, 0x74, 0x5f, 0x61, 0x74, 0x18, 0x06, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x07, 0x73, 0x74, 0x61, 0x72, 0x74, 0x41, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x65,
-	0x6e, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6e, 0x64,
-	0x41, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x5f, 0x61, 0x74, 0x18, 0x08,
-	0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x41, 0x74, 0x12, 0x1d, 0x0a,
-	0x0a, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x72, 0x65, 0x6e, 0x65, 0x77, 0x18, 0x09, 0x20, 0x01, 0x28,
-	0x08, 0x52, 0x09, 0x61, 0x75, 0x74, 0x6f, 0x52, 0x65, 0x6e, 0x65, 0x77, 0x12, 0x1d, 0x0a, 0x0a,
-	0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x09, 0x63, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x75,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x09, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x22, 0x7e, 0x0a, 0x1a, 0x43, 0x72,
-	0x65, 0x61, 0x74, 0x65, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b,
-	0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x53,
-	0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x73, 0x75, 0x62,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1f, 0x0a, 0x0b, 0x70, 0x61, 0x79,
-	0x6d, 0x65, 0x6e, 0x74, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
-	0x70, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74, 0x55, 0x72, 0x6c, 0x22, 0x28, 0x0a, 0x16, 0x47, 0x65,
-	0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x02, 0x69, 0x64, 0x22, 0x5f, 0x0a, 0x1c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x52, 0x65, 0x73, 0x70,
-	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3f, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x75, 0x62,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x49, 0x0a, 0x18, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x6d,
-	0x61, 0x69, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x6d, 0x61, 0x69, 0x6c,
-	0x22, 0x5e, 0x0a, 0x19, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x41, 0x0a,
-	0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x52, 0x0d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x22, 0xa7, 0x01, 0x0a, 0x19, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e,
-	0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x12, 0x24,
-	0x0a, 0x0e, 0x68, 0x61, 0x73, 0x5f, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x72, 0x65, 0x6e, 0x65, 0x77,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x68, 0x61, 0x73, 0x41, 0x75, 0x74, 0x6f, 0x52,
-	0x65, 0x6e, 0x65, 0x77, 0x12, 0x1d, 0x0a, 0x0a, 0x61, 0x75, 0x74, 0x6f, 0x5f, 0x72, 0x65, 0x6e,
-	0x65, 0x77, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x61, 0x75, 0x74, 0x6f, 0x52, 0x65,
-	0x6e, 0x65, 0x77, 0x12, 0x1d, 0x0a, 0x0a, 0x68, 0x61, 0x73, 0x5f, 0x73, 0x74, 0x61, 0x74, 0x75,
-	0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x68, 0x61, 0x73, 0x53, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x2b, 0x0a, 0x19, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x04, 0x52, 0x02, 0x69, 0x64, 0x22, 0x2b, 0x0a, 0x19, 0x43, 0x61, 0x6e, 0x63, 0x65,
-	0x6c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71,
-	0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04,
-	0x52, 0x02, 0x69, 0x64, 0x22, 0x80, 0x01, 0x0a, 0x16, 0x50, 0x61, 0x79, 0x6d, 0x65, 0x6e, 0x74,
-	0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x27, 0x0a, 0x0f, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
-	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x04, 0x52, 0x0e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74,
-	0x75, 0x73, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73,
-	0x12, 0x25, 0x0a, 0x0e, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x5f,
-	0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x61,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x49, 0x64, 0x22, 0x6c, 0x0a, 0x0f, 0x4d, 0x65, 0x73, 0x73, 0x61,
-	0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65,
-	0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73,
-	0x73, 0x61, 0x67, 0x65, 0x12, 0x3f, 0x0a, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x73, 0x75, 0x62,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x0c, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x25, 0x0a, 0x0d, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x65,
-	0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x32, 0x92, 0x07, 0x0a,
-	0x14, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x53, 0x65,
-	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x45, 0x0a, 0x06, 0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x12,
-	0x1c, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e,
-	0x48, 0x65, 0x61, 0x6c, 0x74, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e,
-	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x48, 0x65,
-	0x61, 0x6c, 0x74, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x72, 0x0a, 0x15,
-	0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x54, 0x79, 0x70, 0x65, 0x73, 0x12, 0x2b, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65,
-	0x73, 0x74, 0x1a, 0x2c, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x69, 0x0a, 0x12, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x75, 0x62,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x29, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x2e, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x65, 0x0a, 0x0f, 0x47,
-	0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x25,
-	0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x47,
-	0x65, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
-	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70,
-	0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69,
-	0x6f, 0x6e, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e,
-	0x73, 0x65, 0x12, 0x66, 0x0a, 0x11, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x27, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74,
-	0x1a, 0x28, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x2e, 0x4c, 0x69, 0x73, 0x74, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f,
-	0x6e, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x6b, 0x0a, 0x12, 0x55, 0x70,
-	0x64, 0x61, 0x74, 0x65, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x12, 0x28, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x2b, 0x2e, 0x73, 0x75, 0x62,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x53, 0x75, 0x62, 0x73, 0x63,
-	0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5e, 0x0a, 0x12, 0x44, 0x65, 0x6c, 0x65, 0x74,
-	0x65, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x2e,
-	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x44, 0x65,
-	0x6c, 0x65, 0x74, 0x65, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x5e, 0x0a, 0x12, 0x43, 0x61, 0x6e, 0x63, 0x65,
-	0x6c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x28, 0x2e,
-	0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x43, 0x61,
-	0x6e, 0x63, 0x65, 0x6c, 0x53, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1e, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72,
-	0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52,
-	0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x58, 0x0a, 0x0f, 0x50, 0x61, 0x79, 0x6d, 0x65,
-	0x6e, 0x74, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x12, 0x25, 0x2e, 0x73, 0x75, 0x62,
-	0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x2e, 0x50, 0x61, 0x79, 0x6d, 0x65,
-	0x6e, 0x74, 0x43, 0x61, 0x6c, 0x6c, 0x62, 0x61, 0x63, 0x6b, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
-	0x74, 0x1a, 0x1e, 0x2e, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e,
-	0x73, 0x2e, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x42, 0x41, 0x5a, 0x3f, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
-	0x76, 0x69, 0x62, 0x61, 0x73, 0x74, 0x2d, 0x73, 0x6f, 0x6c, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x73,
-	0x2f, 0x6d, 0x73, 0x2d, 0x67, 0x6f, 0x2d, 0x73, 0x75, 0x62, 0x73, 0x63, 0x72, 0x69, 0x70, 0x74,
-	0x69, 0x6f, 0x6e, 0x73, 0x2f, 0x61, 0x70, 0x70, 0x2f, 0x74, 0x79, 0x70, 0x65, 0x73, 0x3b, 0x74,
-	0x79, 0x70, 0x65, 0x73, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
-})
+const file_subscriptions_proto_rawDesc = "" +
+	"\n" +
+	"\x13subscriptions.proto\x12\rsubscriptions\"\x0f\n" +
+	"\rHealthRequest\"(\n" +
+	"\x0eHealthResponse\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\"i\n" +
+	"\x1cListSubscriptionTypesRequest\x12\x1d\n" +
+	"\n" +
+	"has_status\x18\x01 \x01(\bR\thasStatus\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\x05R\x06status\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\"\xaf\x01\n" +
+	"\x10SubscriptionType\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\x05R\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x05 \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x06 \x01(\tR\tupdatedAt\"o\n" +
+	"\x1dListSubscriptionTypesResponse\x12N\n" +
+	"\x12subscription_types\x18\x01 \x03(\v2\x1f.subscriptions.SubscriptionTypeR\x11subscriptionTypes\"\xc8\x03\n" +
+	"\bPlanType\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x120\n" +
+	"\x14subscription_type_id\x18\x02 \x01(\x04R\x12subscriptionTypeId\x12\x1b\n" +
+	"\tplan_code\x18\x03 \x01(\tR\bplanCode\x12!\n" +
+	"\fdisplay_name\x18\x04 \x01(\tR\vdisplayName\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vprice_cents\x18\x06 \x01(\x03R\n" +
+	"priceCents\x12\x1a\n" +
+	"\bcurrency\x18\a \x01(\tR\bcurrency\x12\x1a\n" +
+	"\bfeatures\x18\t \x01(\tR\bfeatures\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\tR\tupdatedAt\x12\x16\n" +
+	"\x06status\x18\f \x01(\x05R\x06status\x12\x1d\n" +
+	"\n" +
+	"trial_days\x18\r \x01(\x05R\ttrialDays\x12#\n" +
+	"\rinterval_unit\x18\x0e \x01(\tR\fintervalUnit\x12%\n" +
+	"\x0einterval_count\x18\x0f \x01(\x05R\rintervalCount\"s\n" +
+	"\x14ListPlanTypesRequest\x120\n" +
+	"\x14subscription_type_id\x18\x01 \x01(\x04R\x12subscriptionTypeId\x12)\n" +
+	"\x10include_archived\x18\x02 \x01(\bR\x0fincludeArchived\"O\n" +
+	"\x15ListPlanTypesResponse\x126\n" +
+	"\n" +
+	"plan_types\x18\x01 \x03(\v2\x17.subscriptions.PlanTypeR\tplanTypes\"$\n" +
+	"\x12GetPlanTypeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"H\n" +
+	"\x10PlanTypeResponse\x124\n" +
+	"\tplan_type\x18\x01 \x01(\v2\x17.subscriptions.PlanTypeR\bplanType\"V\n" +
+	"\x1dCreateSubscriptionTypeRequest\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12!\n" +
+	"\fdisplay_name\x18\x02 \x01(\tR\vdisplayName\"\xb3\x01\n" +
+	"\x1dUpdateSubscriptionTypeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12(\n" +
+	"\x10has_display_name\x18\x02 \x01(\bR\x0ehasDisplayName\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12\x1d\n" +
+	"\n" +
+	"has_status\x18\x04 \x01(\bR\thasStatus\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\x05R\x06status\"0\n" +
+	"\x1eArchiveSubscriptionTypeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"h\n" +
+	"\x18SubscriptionTypeResponse\x12L\n" +
+	"\x11subscription_type\x18\x01 \x01(\v2\x1f.subscriptions.SubscriptionTypeR\x10subscriptionType\"\xef\x02\n" +
+	"\x15CreatePlanTypeRequest\x120\n" +
+	"\x14subscription_type_id\x18\x01 \x01(\x04R\x12subscriptionTypeId\x12\x1b\n" +
+	"\tplan_code\x18\x02 \x01(\tR\bplanCode\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vprice_cents\x18\x05 \x01(\x03R\n" +
+	"priceCents\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12\x1a\n" +
+	"\bfeatures\x18\b \x01(\tR\bfeatures\x12\x1d\n" +
+	"\n" +
+	"trial_days\x18\t \x01(\x05R\ttrialDays\x12#\n" +
+	"\rinterval_unit\x18\n" +
+	" \x01(\tR\fintervalUnit\x12%\n" +
+	"\x0einterval_count\x18\v \x01(\x05R\rintervalCount\"\xf1\x04\n" +
+	"\x15UpdatePlanTypeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12(\n" +
+	"\x10has_display_name\x18\x02 \x01(\bR\x0ehasDisplayName\x12!\n" +
+	"\fdisplay_name\x18\x03 \x01(\tR\vdisplayName\x12'\n" +
+	"\x0fhas_description\x18\x04 \x01(\bR\x0ehasDescription\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12&\n" +
+	"\x0fhas_price_cents\x18\x06 \x01(\bR\rhasPriceCents\x12\x1f\n" +
+	"\vprice_cents\x18\a \x01(\x03R\n" +
+	"priceCents\x12!\n" +
+	"\fhas_currency\x18\b \x01(\bR\vhasCurrency\x12\x1a\n" +
+	"\bcurrency\x18\t \x01(\tR\bcurrency\x12!\n" +
+	"\fhas_features\x18\f \x01(\bR\vhasFeatures\x12\x1a\n" +
+	"\bfeatures\x18\r \x01(\tR\bfeatures\x12$\n" +
+	"\x0ehas_trial_days\x18\x0e \x01(\bR\fhasTrialDays\x12\x1d\n" +
+	"\n" +
+	"trial_days\x18\x0f \x01(\x05R\ttrialDays\x12*\n" +
+	"\x11has_interval_unit\x18\x10 \x01(\bR\x0fhasIntervalUnit\x12#\n" +
+	"\rinterval_unit\x18\x11 \x01(\tR\fintervalUnit\x12,\n" +
+	"\x12has_interval_count\x18\x12 \x01(\bR\x10hasIntervalCount\x12%\n" +
+	"\x0einterval_count\x18\x13 \x01(\x05R\rintervalCount\"(\n" +
+	"\x16ArchivePlanTypeRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\xea\x03\n" +
+	"\x06Coupon\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04code\x18\x02 \x01(\tR\x04code\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vpercent_off\x18\x04 \x01(\x05R\n" +
+	"percentOff\x12(\n" +
+	"\x10amount_off_cents\x18\x05 \x01(\x03R\x0eamountOffCents\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\x12\x1a\n" +
+	"\bduration\x18\a \x01(\tR\bduration\x12.\n" +
+	"\x13duration_in_periods\x18\b \x01(\x05R\x11durationInPeriods\x12'\n" +
+	"\x0fmax_redemptions\x18\t \x01(\x05R\x0emaxRedemptions\x12%\n" +
+	"\x0etimes_redeemed\x18\n" +
+	" \x01(\x05R\rtimesRedeemed\x12\"\n" +
+	"\rplan_type_ids\x18\v \x03(\x04R\vplanTypeIds\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\f \x01(\tR\texpiresAt\x12\x16\n" +
+	"\x06status\x18\r \x01(\x05R\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x0f \x01(\tR\tupdatedAt\"\xea\x02\n" +
+	"\x13CreateCouponRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vpercent_off\x18\x03 \x01(\x05R\n" +
+	"percentOff\x12(\n" +
+	"\x10amount_off_cents\x18\x04 \x01(\x03R\x0eamountOffCents\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12\x1a\n" +
+	"\bduration\x18\x06 \x01(\tR\bduration\x12.\n" +
+	"\x13duration_in_periods\x18\a \x01(\x05R\x11durationInPeriods\x12'\n" +
+	"\x0fmax_redemptions\x18\b \x01(\x05R\x0emaxRedemptions\x12\"\n" +
+	"\rplan_type_ids\x18\t \x03(\x04R\vplanTypeIds\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\n" +
+	" \x01(\tR\texpiresAt\"\"\n" +
+	"\x10GetCouponRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\x14\n" +
+	"\x12ListCouponsRequest\"\xc5\x02\n" +
+	"\x13UpdateCouponRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12'\n" +
+	"\x0fhas_description\x18\x02 \x01(\bR\x0ehasDescription\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12.\n" +
+	"\x13has_max_redemptions\x18\x04 \x01(\bR\x11hasMaxRedemptions\x12'\n" +
+	"\x0fmax_redemptions\x18\x05 \x01(\x05R\x0emaxRedemptions\x12$\n" +
+	"\x0ehas_expires_at\x18\x06 \x01(\bR\fhasExpiresAt\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\a \x01(\tR\texpiresAt\x12\x1d\n" +
+	"\n" +
+	"has_status\x18\b \x01(\bR\thasStatus\x12\x16\n" +
+	"\x06status\x18\t \x01(\x05R\x06status\"?\n" +
+	"\x0eCouponResponse\x12-\n" +
+	"\x06coupon\x18\x01 \x01(\v2\x15.subscriptions.CouponR\x06coupon\"F\n" +
+	"\x13ListCouponsResponse\x12/\n" +
+	"\acoupons\x18\x01 \x03(\v2\x15.subscriptions.CouponR\acoupons\";\n" +
+	"\x1cListCouponRedemptionsRequest\x12\x1b\n" +
+	"\tcoupon_id\x18\x01 \x01(\x04R\bcouponId\"\x92\x02\n" +
+	"\x10CouponRedemption\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1b\n" +
+	"\tcoupon_id\x18\x02 \x01(\x04R\bcouponId\x12'\n" +
+	"\x0fsubscription_id\x18\x03 \x01(\x04R\x0esubscriptionId\x12)\n" +
+	"\x10duration_periods\x18\x04 \x01(\x05R\x0fdurationPeriods\x12'\n" +
+	"\x0fperiods_applied\x18\x05 \x01(\x05R\x0eperiodsApplied\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\x05R\x06status\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\"o\n" +
+	"\x1dListCouponRedemptionsResponse\x12N\n" +
+	"\x12coupon_redemptions\x18\x01 \x03(\v2\x1f.subscriptions.CouponRedemptionR\x11couponRedemptions\"\x95\x02\n" +
+	"\x19CreateSubscriptionRequest\x120\n" +
+	"\x14subscription_type_id\x18\x01 \x01(\x04R\x12subscriptionTypeId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05email\x18\x03 \x01(\tR\x05email\x12\x19\n" +
+	"\bstart_at\x18\x04 \x01(\tR\astartAt\x12\x1d\n" +
+	"\n" +
+	"auto_renew\x18\x05 \x01(\bR\tautoRenew\x12 \n" +
+	"\fplan_type_id\x18\x06 \x01(\x04R\n" +
+	"planTypeId\x12\x1f\n" +
+	"\vcoupon_code\x18\a \x01(\tR\n" +
+	"couponCode\x12\x1a\n" +
+	"\bquantity\x18\b \x01(\x05R\bquantity\"\x9c\x05\n" +
+	"\fSubscription\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x120\n" +
+	"\x14subscription_type_id\x18\x02 \x01(\x04R\x12subscriptionTypeId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05email\x18\x04 \x01(\tR\x05email\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\x05R\x06status\x12\x19\n" +
+	"\bstart_at\x18\x06 \x01(\tR\astartAt\x12\x15\n" +
+	"\x06end_at\x18\a \x01(\tR\x05endAt\x12\x19\n" +
+	"\brenew_at\x18\b \x01(\tR\arenewAt\x12\x1d\n" +
+	"\n" +
+	"auto_renew\x18\t \x01(\bR\tautoRenew\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\n" +
+	" \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\v \x01(\tR\tupdatedAt\x12 \n" +
+	"\fplan_type_id\x18\f \x01(\x04R\n" +
+	"planTypeId\x12/\n" +
+	"\x14pending_plan_type_id\x18\r \x01(\x04R\x11pendingPlanTypeId\x12 \n" +
+	"\ftrial_end_at\x18\x0e \x01(\tR\n" +
+	"trialEndAt\x12\x1f\n" +
+	"\vcanceled_at\x18\x0f \x01(\tR\n" +
+	"canceledAt\x12#\n" +
+	"\rcancel_reason\x18\x10 \x01(\tR\fcancelReason\x12\x1b\n" +
+	"\tpaused_at\x18\x11 \x01(\tR\bpausedAt\x12\x1b\n" +
+	"\tresume_at\x18\x12 \x01(\tR\bresumeAt\x12.\n" +
+	"\x13renewal_retry_count\x18\x13 \x01(\x05R\x11renewalRetryCount\x12\x1a\n" +
+	"\bquantity\x18\x14 \x01(\x05R\bquantity\x12\x18\n" +
+	"\aversion\x18\x15 \x01(\x03R\aversion\"~\n" +
+	"\x1aCreateSubscriptionResponse\x12?\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x1b.subscriptions.SubscriptionR\fsubscription\x12\x1f\n" +
+	"\vpayment_url\x18\x02 \x01(\tR\n" +
+	"paymentUrl\"(\n" +
+	"\x16GetSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"_\n" +
+	"\x1cSubscriptionEnvelopeResponse\x12?\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x1b.subscriptions.SubscriptionR\fsubscription\"\xcf\x03\n" +
+	"\x18ListSubscriptionsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
+	"\x05email\x18\x02 \x01(\tR\x05email\x12\x1b\n" +
+	"\tpage_size\x18\x03 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12\x1d\n" +
+	"\n" +
+	"has_status\x18\x05 \x01(\bR\thasStatus\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\x05R\x06status\x120\n" +
+	"\x14subscription_type_id\x18\a \x01(\x04R\x12subscriptionTypeId\x12$\n" +
+	"\x0ehas_auto_renew\x18\b \x01(\bR\fhasAutoRenew\x12\x1d\n" +
+	"\n" +
+	"auto_renew\x18\t \x01(\bR\tautoRenew\x12\x1e\n" +
+	"\vend_at_from\x18\n" +
+	" \x01(\tR\tendAtFrom\x12\x1a\n" +
+	"\tend_at_to\x18\v \x01(\tR\aendAtTo\x12&\n" +
+	"\x0fcreated_at_from\x18\f \x01(\tR\rcreatedAtFrom\x12\"\n" +
+	"\rcreated_at_to\x18\r \x01(\tR\vcreatedAtTo\x12\x12\n" +
+	"\x04sort\x18\x0e \x01(\tR\x04sort\"\x86\x01\n" +
+	"\x19ListSubscriptionsResponse\x12A\n" +
+	"\rsubscriptions\x18\x01 \x03(\v2\x1b.subscriptions.SubscriptionR\rsubscriptions\x12&\n" +
+	"\x0fnext_page_token\x18\x02 \x01(\tR\rnextPageToken\"\xd2\x01\n" +
+	"\x19UpdateSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12$\n" +
+	"\x0ehas_auto_renew\x18\x02 \x01(\bR\fhasAutoRenew\x12\x1d\n" +
+	"\n" +
+	"auto_renew\x18\x03 \x01(\bR\tautoRenew\x12\x1d\n" +
+	"\n" +
+	"has_status\x18\x04 \x01(\bR\thasStatus\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\x05R\x06status\x12)\n" +
+	"\x10expected_version\x18\x06 \x01(\x03R\x0fexpectedVersion\"V\n" +
+	"\x19DeleteSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12)\n" +
+	"\x10expected_version\x18\x02 \x01(\x03R\x0fexpectedVersion\"o\n" +
+	"\x19CancelSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\tR\x04mode\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\x12\x16\n" +
+	"\x06refund\x18\x04 \x01(\bR\x06refund\"\xab\x01\n" +
+	"\x1aCancelSubscriptionResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12?\n" +
+	"\fsubscription\x18\x02 \x01(\v2\x1b.subscriptions.SubscriptionR\fsubscription\x122\n" +
+	"\x15refunded_amount_cents\x18\x03 \x01(\x03R\x13refundedAmountCents\")\n" +
+	"\x17UndoCancellationRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"G\n" +
+	"\x18PauseSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1b\n" +
+	"\tresume_at\x18\x02 \x01(\tR\bresumeAt\"+\n" +
+	"\x19ResumeSubscriptionRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"]\n" +
+	"\x11ChangePlanRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12 \n" +
+	"\fplan_type_id\x18\x02 \x01(\x04R\n" +
+	"planTypeId\x12\x16\n" +
+	"\x06timing\x18\x03 \x01(\tR\x06timing\"\xaa\x01\n" +
+	"\x12ChangePlanResponse\x12?\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x1b.subscriptions.SubscriptionR\fsubscription\x122\n" +
+	"\x15prorated_amount_cents\x18\x02 \x01(\x03R\x13proratedAmountCents\x12\x1f\n" +
+	"\vpayment_url\x18\x03 \x01(\tR\n" +
+	"paymentUrl\"a\n" +
+	"\x15UpdateQuantityRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12\x1c\n" +
+	"\tproration\x18\x03 \x01(\tR\tproration\"\xae\x01\n" +
+	"\x16UpdateQuantityResponse\x12?\n" +
+	"\fsubscription\x18\x01 \x01(\v2\x1b.subscriptions.SubscriptionR\fsubscription\x122\n" +
+	"\x15prorated_amount_cents\x18\x02 \x01(\x03R\x13proratedAmountCents\x12\x1f\n" +
+	"\vpayment_url\x18\x03 \x01(\tR\n" +
+	"paymentUrl\"\x80\x01\n" +
+	"\x16PaymentCallbackRequest\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\x04R\x0esubscriptionId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12%\n" +
+	"\x0etransaction_id\x18\x03 \x01(\tR\rtransactionId\"\xa1\x01\n" +
+	"\x17PaymentCallbackResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12?\n" +
+	"\fsubscription\x18\x02 \x01(\v2\x1b.subscriptions.SubscriptionR\fsubscription\x12+\n" +
+	"\x11already_processed\x18\x03 \x01(\bR\x10alreadyProcessed\"E\n" +
+	"\x1aListPaymentAttemptsRequest\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\x04R\x0esubscriptionId\"\xd8\x03\n" +
+	"\x0ePaymentAttempt\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12'\n" +
+	"\x0fsubscription_id\x18\x02 \x01(\x04R\x0esubscriptionId\x12 \n" +
+	"\fplan_type_id\x18\x03 \x01(\x04R\n" +
+	"planTypeId\x12!\n" +
+	"\famount_cents\x18\x04 \x01(\x03R\vamountCents\x12\x1a\n" +
+	"\bcurrency\x18\x05 \x01(\tR\bcurrency\x12%\n" +
+	"\x0etransaction_id\x18\x06 \x01(\tR\rtransactionId\x12\x1f\n" +
+	"\vresult_type\x18\a \x01(\tR\n" +
+	"resultType\x12\x16\n" +
+	"\x06status\x18\b \x01(\x05R\x06status\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x12!\n" +
+	"\fcompleted_at\x18\n" +
+	" \x01(\tR\vcompletedAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\v \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\f \x01(\tR\tupdatedAt\x12\x12\n" +
+	"\x04kind\x18\r \x01(\tR\x04kind\x12%\n" +
+	"\x0ediscount_cents\x18\x0e \x01(\x03R\rdiscountCents\x12\x1a\n" +
+	"\bquantity\x18\x0f \x01(\x05R\bquantity\"g\n" +
+	"\x1bListPaymentAttemptsResponse\x12H\n" +
+	"\x10payment_attempts\x18\x01 \x03(\v2\x1d.subscriptions.PaymentAttemptR\x0fpaymentAttempts\"H\n" +
+	"\x1dListSubscriptionEventsRequest\x12'\n" +
+	"\x0fsubscription_id\x18\x01 \x01(\x04R\x0esubscriptionId\"\x88\x02\n" +
+	"\x11SubscriptionEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12'\n" +
+	"\x0fsubscription_id\x18\x02 \x01(\x04R\x0esubscriptionId\x12\x14\n" +
+	"\x05field\x18\x03 \x01(\tR\x05field\x12\x1b\n" +
+	"\told_value\x18\x04 \x01(\tR\boldValue\x12\x1b\n" +
+	"\tnew_value\x18\x05 \x01(\tR\bnewValue\x12\x14\n" +
+	"\x05actor\x18\x06 \x01(\tR\x05actor\x12\x1d\n" +
+	"\n" +
+	"request_id\x18\a \x01(\tR\trequestId\x12\x16\n" +
+	"\x06reason\x18\b \x01(\tR\x06reason\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\t \x01(\tR\tcreatedAt\"s\n" +
+	"\x1eListSubscriptionEventsResponse\x12Q\n" +
+	"\x13subscription_events\x18\x01 \x03(\v2 .subscriptions.SubscriptionEventR\x12subscriptionEvents\"\xeb\x01\n" +
+	"\x0fWebhookEndpoint\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12 \n" +
+	"\vdescription\x18\x03 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vevent_types\x18\x04 \x03(\tR\n" +
+	"eventTypes\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\x05R\x06status\x12\x1d\n" +
+	"\n" +
+	"created_by\x18\x06 \x01(\tR\tcreatedBy\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\a \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\b \x01(\tR\tupdatedAt\"s\n" +
+	"\x1cCreateWebhookEndpointRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1f\n" +
+	"\vevent_types\x18\x03 \x03(\tR\n" +
+	"eventTypes\"\x82\x01\n" +
+	"\x1dCreateWebhookEndpointResponse\x12I\n" +
+	"\x10webhook_endpoint\x18\x01 \x01(\v2\x1e.subscriptions.WebhookEndpointR\x0fwebhookEndpoint\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\"+\n" +
+	"\x19GetWebhookEndpointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"d\n" +
+	"\x17WebhookEndpointResponse\x12I\n" +
+	"\x10webhook_endpoint\x18\x01 \x01(\v2\x1e.subscriptions.WebhookEndpointR\x0fwebhookEndpoint\"\x1d\n" +
+	"\x1bListWebhookEndpointsRequest\"k\n" +
+	"\x1cListWebhookEndpointsResponse\x12K\n" +
+	"\x11webhook_endpoints\x18\x01 \x03(\v2\x1e.subscriptions.WebhookEndpointR\x10webhookEndpoints\"\xa4\x02\n" +
+	"\x1cUpdateWebhookEndpointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x17\n" +
+	"\ahas_url\x18\x02 \x01(\bR\x06hasUrl\x12\x10\n" +
+	"\x03url\x18\x03 \x01(\tR\x03url\x12'\n" +
+	"\x0fhas_description\x18\x04 \x01(\bR\x0ehasDescription\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12&\n" +
+	"\x0fhas_event_types\x18\x06 \x01(\bR\rhasEventTypes\x12\x1f\n" +
+	"\vevent_types\x18\a \x03(\tR\n" +
+	"eventTypes\x12\x1d\n" +
+	"\n" +
+	"has_status\x18\b \x01(\bR\thasStatus\x12\x16\n" +
+	"\x06status\x18\t \x01(\x05R\x06status\".\n" +
+	"\x1cDeleteWebhookEndpointRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"N\n" +
+	"\x1cListWebhookDeliveriesRequest\x12.\n" +
+	"\x13webhook_endpoint_id\x18\x01 \x01(\x04R\x11webhookEndpointId\"\xb9\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12.\n" +
+	"\x13webhook_endpoint_id\x18\x02 \x01(\x04R\x11webhookEndpointId\x12\x19\n" +
+	"\bevent_id\x18\x03 \x01(\x04R\aeventId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x04 \x01(\tR\teventType\x12'\n" +
+	"\x0fsubscription_id\x18\x05 \x01(\x04R\x0esubscriptionId\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\x05R\x06status\x12\x1a\n" +
+	"\battempts\x18\a \x01(\x05R\battempts\x12'\n" +
+	"\x0fresponse_status\x18\b \x01(\x05R\x0eresponseStatus\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\t \x01(\tR\tlastError\x12&\n" +
+	"\x0fnext_attempt_at\x18\n" +
+	" \x01(\tR\rnextAttemptAt\x12!\n" +
+	"\fdelivered_at\x18\v \x01(\tR\vdeliveredAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\f \x01(\tR\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\tR\tupdatedAt\"n\n" +
+	"\x1dListWebhookDeliveriesResponse\x12M\n" +
+	"\x12webhook_deliveries\x18\x01 \x03(\v2\x1e.subscriptions.WebhookDeliveryR\x11webhookDeliveries\"l\n" +
+	"\x0fMessageResponse\x12\x18\n" +
+	"\amessage\x18\x01 \x01(\tR\amessage\x12?\n" +
+	"\fsubscription\x18\x02 \x01(\v2\x1b.subscriptions.SubscriptionR\fsubscription\"%\n" +
+	"\rErrorResponse\x12\x14\n" +
+	"\x05error\x18\x01 \x01(\tR\x05error2\xb9\x1b\n" +
+	"\x14SubscriptionsService\x12E\n" +
+	"\x06Health\x12\x1c.subscriptions.HealthRequest\x1a\x1d.subscriptions.HealthResponse\x12r\n" +
+	"\x15ListSubscriptionTypes\x12+.subscriptions.ListSubscriptionTypesRequest\x1a,.subscriptions.ListSubscriptionTypesResponse\x12Z\n" +
+	"\rListPlanTypes\x12#.subscriptions.ListPlanTypesRequest\x1a$.subscriptions.ListPlanTypesResponse\x12Q\n" +
+	"\vGetPlanType\x12!.subscriptions.GetPlanTypeRequest\x1a\x1f.subscriptions.PlanTypeResponse\x12o\n" +
+	"\x16CreateSubscriptionType\x12,.subscriptions.CreateSubscriptionTypeRequest\x1a'.subscriptions.SubscriptionTypeResponse\x12o\n" +
+	"\x16UpdateSubscriptionType\x12,.subscriptions.UpdateSubscriptionTypeRequest\x1a'.subscriptions.SubscriptionTypeResponse\x12q\n" +
+	"\x17ArchiveSubscriptionType\x12-.subscriptions.ArchiveSubscriptionTypeRequest\x1a'.subscriptions.SubscriptionTypeResponse\x12W\n" +
+	"\x0eCreatePlanType\x12$.subscriptions.CreatePlanTypeRequest\x1a\x1f.subscriptions.PlanTypeResponse\x12W\n" +
+	"\x0eUpdatePlanType\x12$.subscriptions.UpdatePlanTypeRequest\x1a\x1f.subscriptions.PlanTypeResponse\x12Y\n" +
+	"\x0fArchivePlanType\x12%.subscriptions.ArchivePlanTypeRequest\x1a\x1f.subscriptions.PlanTypeResponse\x12Q\n" +
+	"\fCreateCoupon\x12\".subscriptions.CreateCouponRequest\x1a\x1d.subscriptions.CouponResponse\x12K\n" +
+	"\tGetCoupon\x12\x1f.subscriptions.GetCouponRequest\x1a\x1d.subscriptions.CouponResponse\x12T\n" +
+	"\vListCoupons\x12!.subscriptions.ListCouponsRequest\x1a\".subscriptions.ListCouponsResponse\x12Q\n" +
+	"\fUpdateCoupon\x12\".subscriptions.UpdateCouponRequest\x1a\x1d.subscriptions.CouponResponse\x12r\n" +
+	"\x15ListCouponRedemptions\x12+.subscriptions.ListCouponRedemptionsRequest\x1a,.subscriptions.ListCouponRedemptionsResponse\x12i\n" +
+	"\x12CreateSubscription\x12(.subscriptions.CreateSubscriptionRequest\x1a).subscriptions.CreateSubscriptionResponse\x12e\n" +
+	"\x0fGetSubscription\x12%.subscriptions.GetSubscriptionRequest\x1a+.subscriptions.SubscriptionEnvelopeResponse\x12f\n" +
+	"\x11ListSubscriptions\x12'.subscriptions.ListSubscriptionsRequest\x1a(.subscriptions.ListSubscriptionsResponse\x12k\n" +
+	"\x12UpdateSubscription\x12(.subscriptions.UpdateSubscriptionRequest\x1a+.subscriptions.SubscriptionEnvelopeResponse\x12^\n" +
+	"\x12DeleteSubscription\x12(.subscriptions.DeleteSubscriptionRequest\x1a\x1e.subscriptions.MessageResponse\x12i\n" +
+	"\x12CancelSubscription\x12(.subscriptions.CancelSubscriptionRequest\x1a).subscriptions.CancelSubscriptionResponse\x12Z\n" +
+	"\x10UndoCancellation\x12&.subscriptions.UndoCancellationRequest\x1a\x1e.subscriptions.MessageResponse\x12\\\n" +
+	"\x11PauseSubscription\x12'.subscriptions.PauseSubscriptionRequest\x1a\x1e.subscriptions.MessageResponse\x12^\n" +
+	"\x12ResumeSubscription\x12(.subscriptions.ResumeSubscriptionRequest\x1a\x1e.subscriptions.MessageResponse\x12Q\n" +
+	"\n" +
+	"ChangePlan\x12 .subscriptions.ChangePlanRequest\x1a!.subscriptions.ChangePlanResponse\x12]\n" +
+	"\x0eUpdateQuantity\x12$.subscriptions.UpdateQuantityRequest\x1a%.subscriptions.UpdateQuantityResponse\x12`\n" +
+	"\x0fPaymentCallback\x12%.subscriptions.PaymentCallbackRequest\x1a&.subscriptions.PaymentCallbackResponse\x12l\n" +
+	"\x13ListPaymentAttempts\x12).subscriptions.ListPaymentAttemptsRequest\x1a*.subscriptions.ListPaymentAttemptsResponse\x12u\n" +
+	"\x16ListSubscriptionEvents\x12,.subscriptions.ListSubscriptionEventsRequest\x1a-.subscriptions.ListSubscriptionEventsResponse\x12r\n" +
+	"\x15CreateWebhookEndpoint\x12+.subscriptions.CreateWebhookEndpointRequest\x1a,.subscriptions.CreateWebhookEndpointResponse\x12f\n" +
+	"\x12GetWebhookEndpoint\x12(.subscriptions.GetWebhookEndpointRequest\x1a&.subscriptions.WebhookEndpointResponse\x12o\n" +
+	"\x14ListWebhookEndpoints\x12*.subscriptions.ListWebhookEndpointsRequest\x1a+.subscriptions.ListWebhookEndpointsResponse\x12l\n" +
+	"\x15UpdateWebhookEndpoint\x12+.subscriptions.UpdateWebhookEndpointRequest\x1a&.subscriptions.WebhookEndpointResponse\x12d\n" +
+	"\x15DeleteWebhookEndpoint\x12+.subscriptions.DeleteWebhookEndpointRequest\x1a\x1e.subscriptions.MessageResponse\x12r\n" +
+	"\x15ListWebhookDeliveries\x12+.subscriptions.ListWebhookDeliveriesRequest\x1a,.subscriptions.ListWebhookDeliveriesResponseBAZ?github.com/vibast-solutions/ms-go-subscriptions/app/types;typesb\x06proto3"
 
 var (
 	file_subscriptions_proto_rawDescOnce sync.Once
@@ -1248,56 +5069,173 @@ func file_subscriptions_proto_rawDescGZIP() []byte {
 	return file_subscriptions_proto_rawDescData
 }
 
-var file_subscriptions_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
+var file_subscriptions_proto_msgTypes = make([]protoimpl.MessageInfo, 67)
 var file_subscriptions_proto_goTypes = []any{
-	(*HealthRequest)(nil),                 // 0: subscriptions.HealthRequest
-	(*HealthResponse)(nil),                // 1: subscriptions.HealthResponse
-	(*ListSubscriptionTypesRequest)(nil),  // 2: subscriptions.ListSubscriptionTypesRequest
-	(*SubscriptionType)(nil),              // 3: subscriptions.SubscriptionType
-	(*ListSubscriptionTypesResponse)(nil), // 4: subscriptions.ListSubscriptionTypesResponse
-	(*CreateSubscriptionRequest)(nil),     // 5: subscriptions.CreateSubscriptionRequest
-	(*Subscription)(nil),                  // 6: subscriptions.Subscription
-	(*CreateSubscriptionResponse)(nil),    // 7: subscriptions.CreateSubscriptionResponse
-	(*GetSubscriptionRequest)(nil),        // 8: subscriptions.GetSubscriptionRequest
-	(*SubscriptionEnvelopeResponse)(nil),  // 9: subscriptions.SubscriptionEnvelopeResponse
-	(*ListSubscriptionsRequest)(nil),      // 10: subscriptions.ListSubscriptionsRequest
-	(*ListSubscriptionsResponse)(nil),     // 11: subscriptions.ListSubscriptionsResponse
-	(*UpdateSubscriptionRequest)(nil),     // 12: subscriptions.UpdateSubscriptionRequest
-	(*DeleteSubscriptionRequest)(nil),     // 13: subscriptions.DeleteSubscriptionRequest
-	(*CancelSubscriptionRequest)(nil),     // 14: subscriptions.CancelSubscriptionRequest
-	(*PaymentCallbackRequest)(nil),        // 15: subscriptions.PaymentCallbackRequest
-	(*MessageResponse)(nil),               // 16: subscriptions.MessageResponse
-	(*ErrorResponse)(nil),                 // 17: subscriptions.ErrorResponse
+	(*HealthRequest)(nil),                  // 0: subscriptions.HealthRequest
+	(*HealthResponse)(nil),                 // 1: subscriptions.HealthResponse
+	(*ListSubscriptionTypesRequest)(nil),   // 2: subscriptions.ListSubscriptionTypesRequest
+	(*SubscriptionType)(nil),               // 3: subscriptions.SubscriptionType
+	(*ListSubscriptionTypesResponse)(nil),  // 4: subscriptions.ListSubscriptionTypesResponse
+	(*PlanType)(nil),                       // 5: subscriptions.PlanType
+	(*ListPlanTypesRequest)(nil),           // 6: subscriptions.ListPlanTypesRequest
+	(*ListPlanTypesResponse)(nil),          // 7: subscriptions.ListPlanTypesResponse
+	(*GetPlanTypeRequest)(nil),             // 8: subscriptions.GetPlanTypeRequest
+	(*PlanTypeResponse)(nil),               // 9: subscriptions.PlanTypeResponse
+	(*CreateSubscriptionTypeRequest)(nil),  // 10: subscriptions.CreateSubscriptionTypeRequest
+	(*UpdateSubscriptionTypeRequest)(nil),  // 11: subscriptions.UpdateSubscriptionTypeRequest
+	(*ArchiveSubscriptionTypeRequest)(nil), // 12: subscriptions.ArchiveSubscriptionTypeRequest
+	(*SubscriptionTypeResponse)(nil),       // 13: subscriptions.SubscriptionTypeResponse
+	(*CreatePlanTypeRequest)(nil),          // 14: subscriptions.CreatePlanTypeRequest
+	(*UpdatePlanTypeRequest)(nil),          // 15: subscriptions.UpdatePlanTypeRequest
+	(*ArchivePlanTypeRequest)(nil),         // 16: subscriptions.ArchivePlanTypeRequest
+	(*Coupon)(nil),                         // 17: subscriptions.Coupon
+	(*CreateCouponRequest)(nil),            // 18: subscriptions.CreateCouponRequest
+	(*GetCouponRequest)(nil),               // 19: subscriptions.GetCouponRequest
+	(*ListCouponsRequest)(nil),             // 20: subscriptions.ListCouponsRequest
+	(*UpdateCouponRequest)(nil),            // 21: subscriptions.UpdateCouponRequest
+	(*CouponResponse)(nil),                 // 22: subscriptions.CouponResponse
+	(*ListCouponsResponse)(nil),            // 23: subscriptions.ListCouponsResponse
+	(*ListCouponRedemptionsRequest)(nil),   // 24: subscriptions.ListCouponRedemptionsRequest
+	(*CouponRedemption)(nil),               // 25: subscriptions.CouponRedemption
+	(*ListCouponRedemptionsResponse)(nil),  // 26: subscriptions.ListCouponRedemptionsResponse
+	(*CreateSubscriptionRequest)(nil),      // 27: subscriptions.CreateSubscriptionRequest
+	(*Subscription)(nil),                   // 28: subscriptions.Subscription
+	(*CreateSubscriptionResponse)(nil),     // 29: subscriptions.CreateSubscriptionResponse
+	(*GetSubscriptionRequest)(nil),         // 30: subscriptions.GetSubscriptionRequest
+	(*SubscriptionEnvelopeResponse)(nil),   // 31: subscriptions.SubscriptionEnvelopeResponse
+	(*ListSubscriptionsRequest)(nil),       // 32: subscriptions.ListSubscriptionsRequest
+	(*ListSubscriptionsResponse)(nil),      // 33: subscriptions.ListSubscriptionsResponse
+	(*UpdateSubscriptionRequest)(nil),      // 34: subscriptions.UpdateSubscriptionRequest
+	(*DeleteSubscriptionRequest)(nil),      // 35: subscriptions.DeleteSubscriptionRequest
+	(*CancelSubscriptionRequest)(nil),      // 36: subscriptions.CancelSubscriptionRequest
+	(*CancelSubscriptionResponse)(nil),     // 37: subscriptions.CancelSubscriptionResponse
+	(*UndoCancellationRequest)(nil),        // 38: subscriptions.UndoCancellationRequest
+	(*PauseSubscriptionRequest)(nil),       // 39: subscriptions.PauseSubscriptionRequest
+	(*ResumeSubscriptionRequest)(nil),      // 40: subscriptions.ResumeSubscriptionRequest
+	(*ChangePlanRequest)(nil),              // 41: subscriptions.ChangePlanRequest
+	(*ChangePlanResponse)(nil),             // 42: subscriptions.ChangePlanResponse
+	(*UpdateQuantityRequest)(nil),          // 43: subscriptions.UpdateQuantityRequest
+	(*UpdateQuantityResponse)(nil),         // 44: subscriptions.UpdateQuantityResponse
+	(*PaymentCallbackRequest)(nil),         // 45: subscriptions.PaymentCallbackRequest
+	(*PaymentCallbackResponse)(nil),        // 46: subscriptions.PaymentCallbackResponse
+	(*ListPaymentAttemptsRequest)(nil),     // 47: subscriptions.ListPaymentAttemptsRequest
+	(*PaymentAttempt)(nil),                 // 48: subscriptions.PaymentAttempt
+	(*ListPaymentAttemptsResponse)(nil),    // 49: subscriptions.ListPaymentAttemptsResponse
+	(*ListSubscriptionEventsRequest)(nil),  // 50: subscriptions.ListSubscriptionEventsRequest
+	(*SubscriptionEvent)(nil),              // 51: subscriptions.SubscriptionEvent
+	(*ListSubscriptionEventsResponse)(nil), // 52: subscriptions.ListSubscriptionEventsResponse
+	(*WebhookEndpoint)(nil),                // 53: subscriptions.WebhookEndpoint
+	(*CreateWebhookEndpointRequest)(nil),   // 54: subscriptions.CreateWebhookEndpointRequest
+	(*CreateWebhookEndpointResponse)(nil),  // 55: subscriptions.CreateWebhookEndpointResponse
+	(*GetWebhookEndpointRequest)(nil),      // 56: subscriptions.GetWebhookEndpointRequest
+	(*WebhookEndpointResponse)(nil),        // 57: subscriptions.WebhookEndpointResponse
+	(*ListWebhookEndpointsRequest)(nil),    // 58: subscriptions.ListWebhookEndpointsRequest
+	(*ListWebhookEndpointsResponse)(nil),   // 59: subscriptions.ListWebhookEndpointsResponse
+	(*UpdateWebhookEndpointRequest)(nil),   // 60: subscriptions.UpdateWebhookEndpointRequest
+	(*DeleteWebhookEndpointRequest)(nil),   // 61: subscriptions.DeleteWebhookEndpointRequest
+	(*ListWebhookDeliveriesRequest)(nil),   // 62: subscriptions.ListWebhookDeliveriesRequest
+	(*WebhookDelivery)(nil),                // 63: subscriptions.WebhookDelivery
+	(*ListWebhookDeliveriesResponse)(nil),  // 64: subscriptions.ListWebhookDeliveriesResponse
+	(*MessageResponse)(nil),                // 65: subscriptions.MessageResponse
+	(*ErrorResponse)(nil),                  // 66: subscriptions.ErrorResponse
 }
 var file_subscriptions_proto_depIdxs = []int32{
 	3,  // 0: subscriptions.ListSubscriptionTypesResponse.subscription_types:type_name -> subscriptions.SubscriptionType
-	6,  // 1: subscriptions.CreateSubscriptionResponse.subscription:type_name -> subscriptions.Subscription
-	6,  // 2: subscriptions.SubscriptionEnvelopeResponse.subscription:type_name -> subscriptions.Subscription
-	6,  // 3: subscriptions.ListSubscriptionsResponse.subscriptions:type_name -> subscriptions.Subscription
-	6,  // 4: subscriptions.MessageResponse.subscription:type_name -> subscriptions.Subscription
-	0,  // 5: subscriptions.SubscriptionsService.Health:input_type -> subscriptions.HealthRequest
-	2,  // 6: subscriptions.SubscriptionsService.ListSubscriptionTypes:input_type -> subscriptions.ListSubscriptionTypesRequest
-	5,  // 7: subscriptions.SubscriptionsService.CreateSubscription:input_type -> subscriptions.CreateSubscriptionRequest
-	8,  // 8: subscriptions.SubscriptionsService.GetSubscription:input_type -> subscriptions.GetSubscriptionRequest
-	10, // 9: subscriptions.SubscriptionsService.ListSubscriptions:input_type -> subscriptions.ListSubscriptionsRequest
-	12, // 10: subscriptions.SubscriptionsService.UpdateSubscription:input_type -> subscriptions.UpdateSubscriptionRequest
-	13, // 11: subscriptions.SubscriptionsService.DeleteSubscription:input_type -> subscriptions.DeleteSubscriptionRequest
-	14, // 12: subscriptions.SubscriptionsService.CancelSubscription:input_type -> subscriptions.CancelSubscriptionRequest
-	15, // 13: subscriptions.SubscriptionsService.PaymentCallback:input_type -> subscriptions.PaymentCallbackRequest
-	1,  // 14: subscriptions.SubscriptionsService.Health:output_type -> subscriptions.HealthResponse
-	4,  // 15: subscriptions.SubscriptionsService.ListSubscriptionTypes:output_type -> subscriptions.ListSubscriptionTypesResponse
-	7,  // 16: subscriptions.SubscriptionsService.CreateSubscription:output_type -> subscriptions.CreateSubscriptionResponse
-	9,  // 17: subscriptions.SubscriptionsService.GetSubscription:output_type -> subscriptions.SubscriptionEnvelopeResponse
-	11, // 18: subscriptions.SubscriptionsService.ListSubscriptions:output_type -> subscriptions.ListSubscriptionsResponse
-	9,  // 19: subscriptions.SubscriptionsService.UpdateSubscription:output_type -> subscriptions.SubscriptionEnvelopeResponse
-	16, // 20: subscriptions.SubscriptionsService.DeleteSubscription:output_type -> subscriptions.MessageResponse
-	16, // 21: subscriptions.SubscriptionsService.CancelSubscription:output_type -> subscriptions.MessageResponse
-	16, // 22: subscriptions.SubscriptionsService.PaymentCallback:output_type -> subscriptions.MessageResponse
-	14, // [14:23] is the sub-list for method output_type
-	5,  // [5:14] is the sub-list for method input_type
-	5,  // [5:5] is the sub-list for extension type_name
-	5,  // [5:5] is the sub-list for extension extendee
-	0,  // [0:5] is the sub-list for field type_name
+	5,  // 1: subscriptions.ListPlanTypesResponse.plan_types:type_name -> subscriptions.PlanType
+	5,  // 2: subscriptions.PlanTypeResponse.plan_type:type_name -> subscriptions.PlanType
+	3,  // 3: subscriptions.SubscriptionTypeResponse.subscription_type:type_name -> subscriptions.SubscriptionType
+	17, // 4: subscriptions.CouponResponse.coupon:type_name -> subscriptions.Coupon
+	17, // 5: subscriptions.ListCouponsResponse.coupons:type_name -> subscriptions.Coupon
+	25, // 6: subscriptions.ListCouponRedemptionsResponse.coupon_redemptions:type_name -> subscriptions.CouponRedemption
+	28, // 7: subscriptions.CreateSubscriptionResponse.subscription:type_name -> subscriptions.Subscription
+	28, // 8: subscriptions.SubscriptionEnvelopeResponse.subscription:type_name -> subscriptions.Subscription
+	28, // 9: subscriptions.ListSubscriptionsResponse.subscriptions:type_name -> subscriptions.Subscription
+	28, // 10: subscriptions.CancelSubscriptionResponse.subscription:type_name -> subscriptions.Subscription
+	28, // 11: subscriptions.ChangePlanResponse.subscription:type_name -> subscriptions.Subscription
+	28, // 12: subscriptions.UpdateQuantityResponse.subscription:type_name -> subscriptions.Subscription
+	28, // 13: subscriptions.PaymentCallbackResponse.subscription:type_name -> subscriptions.Subscription
+	48, // 14: subscriptions.ListPaymentAttemptsResponse.payment_attempts:type_name -> subscriptions.PaymentAttempt
+	51, // 15: subscriptions.ListSubscriptionEventsResponse.subscription_events:type_name -> subscriptions.SubscriptionEvent
+	53, // 16: subscriptions.CreateWebhookEndpointResponse.webhook_endpoint:type_name -> subscriptions.WebhookEndpoint
+	53, // 17: subscriptions.WebhookEndpointResponse.webhook_endpoint:type_name -> subscriptions.WebhookEndpoint
+	53, // 18: subscriptions.ListWebhookEndpointsResponse.webhook_endpoints:type_name -> subscriptions.WebhookEndpoint
+	63, // 19: subscriptions.ListWebhookDeliveriesResponse.webhook_deliveries:type_name -> subscriptions.WebhookDelivery
+	28, // 20: subscriptions.MessageResponse.subscription:type_name -> subscriptions.Subscription
+	0,  // 21: subscriptions.SubscriptionsService.Health:input_type -> subscriptions.HealthRequest
+	2,  // 22: subscriptions.SubscriptionsService.ListSubscriptionTypes:input_type -> subscriptions.ListSubscriptionTypesRequest
+	6,  // 23: subscriptions.SubscriptionsService.ListPlanTypes:input_type -> subscriptions.ListPlanTypesRequest
+	8,  // 24: subscriptions.SubscriptionsService.GetPlanType:input_type -> subscriptions.GetPlanTypeRequest
+	10, // 25: subscriptions.SubscriptionsService.CreateSubscriptionType:input_type -> subscriptions.CreateSubscriptionTypeRequest
+	11, // 26: subscriptions.SubscriptionsService.UpdateSubscriptionType:input_type -> subscriptions.UpdateSubscriptionTypeRequest
+	12, // 27: subscriptions.SubscriptionsService.ArchiveSubscriptionType:input_type -> subscriptions.ArchiveSubscriptionTypeRequest
+	14, // 28: subscriptions.SubscriptionsService.CreatePlanType:input_type -> subscriptions.CreatePlanTypeRequest
+	15, // 29: subscriptions.SubscriptionsService.UpdatePlanType:input_type -> subscriptions.UpdatePlanTypeRequest
+	16, // 30: subscriptions.SubscriptionsService.ArchivePlanType:input_type -> subscriptions.ArchivePlanTypeRequest
+	18, // 31: subscriptions.SubscriptionsService.CreateCoupon:input_type -> subscriptions.CreateCouponRequest
+	19, // 32: subscriptions.SubscriptionsService.GetCoupon:input_type -> subscriptions.GetCouponRequest
+	20, // 33: subscriptions.SubscriptionsService.ListCoupons:input_type -> subscriptions.ListCouponsRequest
+	21, // 34: subscriptions.SubscriptionsService.UpdateCoupon:input_type -> subscriptions.UpdateCouponRequest
+	24, // 35: subscriptions.SubscriptionsService.ListCouponRedemptions:input_type -> subscriptions.ListCouponRedemptionsRequest
+	27, // 36: subscriptions.SubscriptionsService.CreateSubscription:input_type -> subscriptions.CreateSubscriptionRequest
+	30, // 37: subscriptions.SubscriptionsService.GetSubscription:input_type -> subscriptions.GetSubscriptionRequest
+	32, // 38: subscriptions.SubscriptionsService.ListSubscriptions:input_type -> subscriptions.ListSubscriptionsRequest
+	34, // 39: subscriptions.SubscriptionsService.UpdateSubscription:input_type -> subscriptions.UpdateSubscriptionRequest
+	35, // 40: subscriptions.SubscriptionsService.DeleteSubscription:input_type -> subscriptions.DeleteSubscriptionRequest
+	36, // 41: subscriptions.SubscriptionsService.CancelSubscription:input_type -> subscriptions.CancelSubscriptionRequest
+	38, // 42: subscriptions.SubscriptionsService.UndoCancellation:input_type -> subscriptions.UndoCancellationRequest
+	39, // 43: subscriptions.SubscriptionsService.PauseSubscription:input_type -> subscriptions.PauseSubscriptionRequest
+	40, // 44: subscriptions.SubscriptionsService.ResumeSubscription:input_type -> subscriptions.ResumeSubscriptionRequest
+	41, // 45: subscriptions.SubscriptionsService.ChangePlan:input_type -> subscriptions.ChangePlanRequest
+	43, // 46: subscriptions.SubscriptionsService.UpdateQuantity:input_type -> subscriptions.UpdateQuantityRequest
+	45, // 47: subscriptions.SubscriptionsService.PaymentCallback:input_type -> subscriptions.PaymentCallbackRequest
+	47, // 48: subscriptions.SubscriptionsService.ListPaymentAttempts:input_type -> subscriptions.ListPaymentAttemptsRequest
+	50, // 49: subscriptions.SubscriptionsService.ListSubscriptionEvents:input_type -> subscriptions.ListSubscriptionEventsRequest
+	54, // 50: subscriptions.SubscriptionsService.CreateWebhookEndpoint:input_type -> subscriptions.CreateWebhookEndpointRequest
+	56, // 51: subscriptions.SubscriptionsService.GetWebhookEndpoint:input_type -> subscriptions.GetWebhookEndpointRequest
+	58, // 52: subscriptions.SubscriptionsService.ListWebhookEndpoints:input_type -> subscriptions.ListWebhookEndpointsRequest
+	60, // 53: subscriptions.SubscriptionsService.UpdateWebhookEndpoint:input_type -> subscriptions.UpdateWebhookEndpointRequest
+	61, // 54: subscriptions.SubscriptionsService.DeleteWebhookEndpoint:input_type -> subscriptions.DeleteWebhookEndpointRequest
+	62, // 55: subscriptions.SubscriptionsService.ListWebhookDeliveries:input_type -> subscriptions.ListWebhookDeliveriesRequest
+	1,  // 56: subscriptions.SubscriptionsService.Health:output_type -> subscriptions.HealthResponse
+	4,  // 57: subscriptions.SubscriptionsService.ListSubscriptionTypes:output_type -> subscriptions.ListSubscriptionTypesResponse
+	7,  // 58: subscriptions.SubscriptionsService.ListPlanTypes:output_type -> subscriptions.ListPlanTypesResponse
+	9,  // 59: subscriptions.SubscriptionsService.GetPlanType:output_type -> subscriptions.PlanTypeResponse
+	13, // 60: subscriptions.SubscriptionsService.CreateSubscriptionType:output_type -> subscriptions.SubscriptionTypeResponse
+	13, // 61: subscriptions.SubscriptionsService.UpdateSubscriptionType:output_type -> subscriptions.SubscriptionTypeResponse
+	13, // 62: subscriptions.SubscriptionsService.ArchiveSubscriptionType:output_type -> subscriptions.SubscriptionTypeResponse
+	9,  // 63: subscriptions.SubscriptionsService.CreatePlanType:output_type -> subscriptions.PlanTypeResponse
+	9,  // 64: subscriptions.SubscriptionsService.UpdatePlanType:output_type -> subscriptions.PlanTypeResponse
+	9,  // 65: subscriptions.SubscriptionsService.ArchivePlanType:output_type -> subscriptions.PlanTypeResponse
+	22, // 66: subscriptions.SubscriptionsService.CreateCoupon:output_type -> subscriptions.CouponResponse
+	22, // 67: subscriptions.SubscriptionsService.GetCoupon:output_type -> subscriptions.CouponResponse
+	23, // 68: subscriptions.SubscriptionsService.ListCoupons:output_type -> subscriptions.ListCouponsResponse
+	22, // 69: subscriptions.SubscriptionsService.UpdateCoupon:output_type -> subscriptions.CouponResponse
+	26, // 70: subscriptions.SubscriptionsService.ListCouponRedemptions:output_type -> subscriptions.ListCouponRedemptionsResponse
+	29, // 71: subscriptions.SubscriptionsService.CreateSubscription:output_type -> subscriptions.CreateSubscriptionResponse
+	31, // 72: subscriptions.SubscriptionsService.GetSubscription:output_type -> subscriptions.SubscriptionEnvelopeResponse
+	33, // 73: subscriptions.SubscriptionsService.ListSubscriptions:output_type -> subscriptions.ListSubscriptionsResponse
+	31, // 74: subscriptions.SubscriptionsService.UpdateSubscription:output_type -> subscriptions.SubscriptionEnvelopeResponse
+	65, // 75: subscriptions.SubscriptionsService.DeleteSubscription:output_type -> subscriptions.MessageResponse
+	37, // 76: subscriptions.SubscriptionsService.CancelSubscription:output_type -> subscriptions.CancelSubscriptionResponse
+	65, // 77: subscriptions.SubscriptionsService.UndoCancellation:output_type -> subscriptions.MessageResponse
+	65, // 78: subscriptions.SubscriptionsService.PauseSubscription:output_type -> subscriptions.MessageResponse
+	65, // 79: subscriptions.SubscriptionsService.ResumeSubscription:output_type -> subscriptions.MessageResponse
+	42, // 80: subscriptions.SubscriptionsService.ChangePlan:output_type -> subscriptions.ChangePlanResponse
+	44, // 81: subscriptions.SubscriptionsService.UpdateQuantity:output_type -> subscriptions.UpdateQuantityResponse
+	46, // 82: subscriptions.SubscriptionsService.PaymentCallback:output_type -> subscriptions.PaymentCallbackResponse
+	49, // 83: subscriptions.SubscriptionsService.ListPaymentAttempts:output_type -> subscriptions.ListPaymentAttemptsResponse
+	52, // 84: subscriptions.SubscriptionsService.ListSubscriptionEvents:output_type -> subscriptions.ListSubscriptionEventsResponse
+	55, // 85: subscriptions.SubscriptionsService.CreateWebhookEndpoint:output_type -> subscriptions.CreateWebhookEndpointResponse
+	57, // 86: subscriptions.SubscriptionsService.GetWebhookEndpoint:output_type -> subscriptions.WebhookEndpointResponse
+	59, // 87: subscriptions.SubscriptionsService.ListWebhookEndpoints:output_type -> subscriptions.ListWebhookEndpointsResponse
+	57, // 88: subscriptions.SubscriptionsService.UpdateWebhookEndpoint:output_type -> subscriptions.WebhookEndpointResponse
+	65, // 89: subscriptions.SubscriptionsService.DeleteWebhookEndpoint:output_type -> subscriptions.MessageResponse
+	64, // 90: subscriptions.SubscriptionsService.ListWebhookDeliveries:output_type -> subscriptions.ListWebhookDeliveriesResponse
+	56, // [56:91] is the sub-list for method output_type
+	21, // [21:56] is the sub-list for method input_type
+	21, // [21:21] is the sub-list for extension type_name
+	21, // [21:21] is the sub-list for extension extendee
+	0,  // [0:21] is the sub-list for field type_name
 }
 
 func init() { file_subscriptions_proto_init() }
@@ -1311,7 +5249,7 @@ func file_subscriptions_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_subscriptions_proto_rawDesc), len(file_subscriptions_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   18,
+			NumMessages:   67,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
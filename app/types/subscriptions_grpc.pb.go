@@ -19,15 +19,41 @@ import (
 const _ = grpc.SupportPackageIsVersion7
 
 const (
-	SubscriptionsService_Health_FullMethodName                = "/subscriptions.SubscriptionsService/Health"
-	SubscriptionsService_ListSubscriptionTypes_FullMethodName = "/subscriptions.SubscriptionsService/ListSubscriptionTypes"
-	SubscriptionsService_CreateSubscription_FullMethodName    = "/subscriptions.SubscriptionsService/CreateSubscription"
-	SubscriptionsService_GetSubscription_FullMethodName       = "/subscriptions.SubscriptionsService/GetSubscription"
-	SubscriptionsService_ListSubscriptions_FullMethodName     = "/subscriptions.SubscriptionsService/ListSubscriptions"
-	SubscriptionsService_UpdateSubscription_FullMethodName    = "/subscriptions.SubscriptionsService/UpdateSubscription"
-	SubscriptionsService_DeleteSubscription_FullMethodName    = "/subscriptions.SubscriptionsService/DeleteSubscription"
-	SubscriptionsService_CancelSubscription_FullMethodName    = "/subscriptions.SubscriptionsService/CancelSubscription"
-	SubscriptionsService_PaymentCallback_FullMethodName       = "/subscriptions.SubscriptionsService/PaymentCallback"
+	SubscriptionsService_Health_FullMethodName                  = "/subscriptions.SubscriptionsService/Health"
+	SubscriptionsService_ListSubscriptionTypes_FullMethodName   = "/subscriptions.SubscriptionsService/ListSubscriptionTypes"
+	SubscriptionsService_ListPlanTypes_FullMethodName           = "/subscriptions.SubscriptionsService/ListPlanTypes"
+	SubscriptionsService_GetPlanType_FullMethodName             = "/subscriptions.SubscriptionsService/GetPlanType"
+	SubscriptionsService_CreateSubscriptionType_FullMethodName  = "/subscriptions.SubscriptionsService/CreateSubscriptionType"
+	SubscriptionsService_UpdateSubscriptionType_FullMethodName  = "/subscriptions.SubscriptionsService/UpdateSubscriptionType"
+	SubscriptionsService_ArchiveSubscriptionType_FullMethodName = "/subscriptions.SubscriptionsService/ArchiveSubscriptionType"
+	SubscriptionsService_CreatePlanType_FullMethodName          = "/subscriptions.SubscriptionsService/CreatePlanType"
+	SubscriptionsService_UpdatePlanType_FullMethodName          = "/subscriptions.SubscriptionsService/UpdatePlanType"
+	SubscriptionsService_ArchivePlanType_FullMethodName         = "/subscriptions.SubscriptionsService/ArchivePlanType"
+	SubscriptionsService_CreateCoupon_FullMethodName            = "/subscriptions.SubscriptionsService/CreateCoupon"
+	SubscriptionsService_GetCoupon_FullMethodName               = "/subscriptions.SubscriptionsService/GetCoupon"
+	SubscriptionsService_ListCoupons_FullMethodName             = "/subscriptions.SubscriptionsService/ListCoupons"
+	SubscriptionsService_UpdateCoupon_FullMethodName            = "/subscriptions.SubscriptionsService/UpdateCoupon"
+	SubscriptionsService_ListCouponRedemptions_FullMethodName   = "/subscriptions.SubscriptionsService/ListCouponRedemptions"
+	SubscriptionsService_CreateSubscription_FullMethodName      = "/subscriptions.SubscriptionsService/CreateSubscription"
+	SubscriptionsService_GetSubscription_FullMethodName         = "/subscriptions.SubscriptionsService/GetSubscription"
+	SubscriptionsService_ListSubscriptions_FullMethodName       = "/subscriptions.SubscriptionsService/ListSubscriptions"
+	SubscriptionsService_UpdateSubscription_FullMethodName      = "/subscriptions.SubscriptionsService/UpdateSubscription"
+	SubscriptionsService_DeleteSubscription_FullMethodName      = "/subscriptions.SubscriptionsService/DeleteSubscription"
+	SubscriptionsService_CancelSubscription_FullMethodName      = "/subscriptions.SubscriptionsService/CancelSubscription"
+	SubscriptionsService_UndoCancellation_FullMethodName        = "/subscriptions.SubscriptionsService/UndoCancellation"
+	SubscriptionsService_PauseSubscription_FullMethodName       = "/subscriptions.SubscriptionsService/PauseSubscription"
+	SubscriptionsService_ResumeSubscription_FullMethodName      = "/subscriptions.SubscriptionsService/ResumeSubscription"
+	SubscriptionsService_ChangePlan_FullMethodName              = "/subscriptions.SubscriptionsService/ChangePlan"
+	SubscriptionsService_UpdateQuantity_FullMethodName          = "/subscriptions.SubscriptionsService/UpdateQuantity"
+	SubscriptionsService_PaymentCallback_FullMethodName         = "/subscriptions.SubscriptionsService/PaymentCallback"
+	SubscriptionsService_ListPaymentAttempts_FullMethodName     = "/subscriptions.SubscriptionsService/ListPaymentAttempts"
+	SubscriptionsService_ListSubscriptionEvents_FullMethodName  = "/subscriptions.SubscriptionsService/ListSubscriptionEvents"
+	SubscriptionsService_CreateWebhookEndpoint_FullMethodName   = "/subscriptions.SubscriptionsService/CreateWebhookEndpoint"
+	SubscriptionsService_GetWebhookEndpoint_FullMethodName      = "/subscriptions.SubscriptionsService/GetWebhookEndpoint"
+	SubscriptionsService_ListWebhookEndpoints_FullMethodName    = "/subscriptions.SubscriptionsService/ListWebhookEndpoints"
+	SubscriptionsService_UpdateWebhookEndpoint_FullMethodName   = "/subscriptions.SubscriptionsService/UpdateWebhookEndpoint"
+	SubscriptionsService_DeleteWebhookEndpoint_FullMethodName   = "/subscriptions.SubscriptionsService/DeleteWebhookEndpoint"
+	SubscriptionsService_ListWebhookDeliveries_FullMethodName   = "/subscriptions.SubscriptionsService/ListWebhookDeliveries"
 )
 
 // SubscriptionsServiceClient is the client API for SubscriptionsService service.
@@ -36,13 +62,39 @@ const (
 type SubscriptionsServiceClient interface {
 	Health(ctx context.Context, in *HealthRequest, opts ...grpc.CallOption) (*HealthResponse, error)
 	ListSubscriptionTypes(ctx context.Context, in *ListSubscriptionTypesRequest, opts ...grpc.CallOption) (*ListSubscriptionTypesResponse, error)
+	ListPlanTypes(ctx context.Context, in *ListPlanTypesRequest, opts ...grpc.CallOption) (*ListPlanTypesResponse, error)
+	GetPlanType(ctx context.Context, in *GetPlanTypeRequest, opts ...grpc.CallOption) (*PlanTypeResponse, error)
+	CreateSubscriptionType(ctx context.Context, in *CreateSubscriptionTypeRequest, opts ...grpc.CallOption) (*SubscriptionTypeResponse, error)
+	UpdateSubscriptionType(ctx context.Context, in *UpdateSubscriptionTypeRequest, opts ...grpc.CallOption) (*SubscriptionTypeResponse, error)
+	ArchiveSubscriptionType(ctx context.Context, in *ArchiveSubscriptionTypeRequest, opts ...grpc.CallOption) (*SubscriptionTypeResponse, error)
+	CreatePlanType(ctx context.Context, in *CreatePlanTypeRequest, opts ...grpc.CallOption) (*PlanTypeResponse, error)
+	UpdatePlanType(ctx context.Context, in *UpdatePlanTypeRequest, opts ...grpc.CallOption) (*PlanTypeResponse, error)
+	ArchivePlanType(ctx context.Context, in *ArchivePlanTypeRequest, opts ...grpc.CallOption) (*PlanTypeResponse, error)
+	CreateCoupon(ctx context.Context, in *CreateCouponRequest, opts ...grpc.CallOption) (*CouponResponse, error)
+	GetCoupon(ctx context.Context, in *GetCouponRequest, opts ...grpc.CallOption) (*CouponResponse, error)
+	ListCoupons(ctx context.Context, in *ListCouponsRequest, opts ...grpc.CallOption) (*ListCouponsResponse, error)
+	UpdateCoupon(ctx context.Context, in *UpdateCouponRequest, opts ...grpc.CallOption) (*CouponResponse, error)
+	ListCouponRedemptions(ctx context.Context, in *ListCouponRedemptionsRequest, opts ...grpc.CallOption) (*ListCouponRedemptionsResponse, error)
 	CreateSubscription(ctx context.Context, in *CreateSubscriptionRequest, opts ...grpc.CallOption) (*CreateSubscriptionResponse, error)
 	GetSubscription(ctx context.Context, in *GetSubscriptionRequest, opts ...grpc.CallOption) (*SubscriptionEnvelopeResponse, error)
 	ListSubscriptions(ctx context.Context, in *ListSubscriptionsRequest, opts ...grpc.CallOption) (*ListSubscriptionsResponse, error)
 	UpdateSubscription(ctx context.Context, in *UpdateSubscriptionRequest, opts ...grpc.CallOption) (*SubscriptionEnvelopeResponse, error)
 	DeleteSubscription(ctx context.Context, in *DeleteSubscriptionRequest, opts ...grpc.CallOption) (*MessageResponse, error)
-	CancelSubscription(ctx context.Context, in *CancelSubscriptionRequest, opts ...grpc.CallOption) (*MessageResponse, error)
-	PaymentCallback(ctx context.Context, in *PaymentCallbackRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	CancelSubscription(ctx context.Context, in *CancelSubscriptionRequest, opts ...grpc.CallOption) (*CancelSubscriptionResponse, error)
+	UndoCancellation(ctx context.Context, in *UndoCancellationRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	PauseSubscription(ctx context.Context, in *PauseSubscriptionRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	ResumeSubscription(ctx context.Context, in *ResumeSubscriptionRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	ChangePlan(ctx context.Context, in *ChangePlanRequest, opts ...grpc.CallOption) (*ChangePlanResponse, error)
+	UpdateQuantity(ctx context.Context, in *UpdateQuantityRequest, opts ...grpc.CallOption) (*UpdateQuantityResponse, error)
+	PaymentCallback(ctx context.Context, in *PaymentCallbackRequest, opts ...grpc.CallOption) (*PaymentCallbackResponse, error)
+	ListPaymentAttempts(ctx context.Context, in *ListPaymentAttemptsRequest, opts ...grpc.CallOption) (*ListPaymentAttemptsResponse, error)
+	ListSubscriptionEvents(ctx context.Context, in *ListSubscriptionEventsRequest, opts ...grpc.CallOption) (*ListSubscriptionEventsResponse, error)
+	CreateWebhookEndpoint(ctx context.Context, in *CreateWebhookEndpointRequest, opts ...grpc.CallOption) (*CreateWebhookEndpointResponse, error)
+	GetWebhookEndpoint(ctx context.Context, in *GetWebhookEndpointRequest, opts ...grpc.CallOption) (*WebhookEndpointResponse, error)
+	ListWebhookEndpoints(ctx context.Context, in *ListWebhookEndpointsRequest, opts ...grpc.CallOption) (*ListWebhookEndpointsResponse, error)
+	UpdateWebhookEndpoint(ctx context.Context, in *UpdateWebhookEndpointRequest, opts ...grpc.CallOption) (*WebhookEndpointResponse, error)
+	DeleteWebhookEndpoint(ctx context.Context, in *DeleteWebhookEndpointRequest, opts ...grpc.CallOption) (*MessageResponse, error)
+	ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error)
 }
 
 type subscriptionsServiceClient struct {
@@ -71,6 +123,123 @@ func (c *subscriptionsServiceClient) ListSubscriptionTypes(ctx context.Context,
 	return out, nil
 }
 
+func (c *subscriptionsServiceClient) ListPlanTypes(ctx context.Context, in *ListPlanTypesRequest, opts ...grpc.CallOption) (*ListPlanTypesResponse, error) {
+	out := new(ListPlanTypesResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_ListPlanTypes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) GetPlanType(ctx context.Context, in *GetPlanTypeRequest, opts ...grpc.CallOption) (*PlanTypeResponse, error) {
+	out := new(PlanTypeResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_GetPlanType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) CreateSubscriptionType(ctx context.Context, in *CreateSubscriptionTypeRequest, opts ...grpc.CallOption) (*SubscriptionTypeResponse, error) {
+	out := new(SubscriptionTypeResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_CreateSubscriptionType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) UpdateSubscriptionType(ctx context.Context, in *UpdateSubscriptionTypeRequest, opts ...grpc.CallOption) (*SubscriptionTypeResponse, error) {
+	out := new(SubscriptionTypeResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_UpdateSubscriptionType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) ArchiveSubscriptionType(ctx context.Context, in *ArchiveSubscriptionTypeRequest, opts ...grpc.CallOption) (*SubscriptionTypeResponse, error) {
+	out := new(SubscriptionTypeResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_ArchiveSubscriptionType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) CreatePlanType(ctx context.Context, in *CreatePlanTypeRequest, opts ...grpc.CallOption) (*PlanTypeResponse, error) {
+	out := new(PlanTypeResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_CreatePlanType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) UpdatePlanType(ctx context.Context, in *UpdatePlanTypeRequest, opts ...grpc.CallOption) (*PlanTypeResponse, error) {
+	out := new(PlanTypeResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_UpdatePlanType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) ArchivePlanType(ctx context.Context, in *ArchivePlanTypeRequest, opts ...grpc.CallOption) (*PlanTypeResponse, error) {
+	out := new(PlanTypeResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_ArchivePlanType_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) CreateCoupon(ctx context.Context, in *CreateCouponRequest, opts ...grpc.CallOption) (*CouponResponse, error) {
+	out := new(CouponResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_CreateCoupon_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) GetCoupon(ctx context.Context, in *GetCouponRequest, opts ...grpc.CallOption) (*CouponResponse, error) {
+	out := new(CouponResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_GetCoupon_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) ListCoupons(ctx context.Context, in *ListCouponsRequest, opts ...grpc.CallOption) (*ListCouponsResponse, error) {
+	out := new(ListCouponsResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_ListCoupons_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) UpdateCoupon(ctx context.Context, in *UpdateCouponRequest, opts ...grpc.CallOption) (*CouponResponse, error) {
+	out := new(CouponResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_UpdateCoupon_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) ListCouponRedemptions(ctx context.Context, in *ListCouponRedemptionsRequest, opts ...grpc.CallOption) (*ListCouponRedemptionsResponse, error) {
+	out := new(ListCouponRedemptionsResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_ListCouponRedemptions_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *subscriptionsServiceClient) CreateSubscription(ctx context.Context, in *CreateSubscriptionRequest, opts ...grpc.CallOption) (*CreateSubscriptionResponse, error) {
 	out := new(CreateSubscriptionResponse)
 	err := c.cc.Invoke(ctx, SubscriptionsService_CreateSubscription_FullMethodName, in, out, opts...)
@@ -116,8 +285,8 @@ func (c *subscriptionsServiceClient) DeleteSubscription(ctx context.Context, in
 	return out, nil
 }
 
-func (c *subscriptionsServiceClient) CancelSubscription(ctx context.Context, in *CancelSubscriptionRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
-	out := new(MessageResponse)
+func (c *subscriptionsServiceClient) CancelSubscription(ctx context.Context, in *CancelSubscriptionRequest, opts ...grpc.CallOption) (*CancelSubscriptionResponse, error) {
+	out := new(CancelSubscriptionResponse)
 	err := c.cc.Invoke(ctx, SubscriptionsService_CancelSubscription_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -125,8 +294,53 @@ func (c *subscriptionsServiceClient) CancelSubscription(ctx context.Context, in
 	return out, nil
 }
 
-func (c *subscriptionsServiceClient) PaymentCallback(ctx context.Context, in *PaymentCallbackRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
+func (c *subscriptionsServiceClient) UndoCancellation(ctx context.Context, in *UndoCancellationRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
+	out := new(MessageResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_UndoCancellation_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) PauseSubscription(ctx context.Context, in *PauseSubscriptionRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
+	out := new(MessageResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_PauseSubscription_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) ResumeSubscription(ctx context.Context, in *ResumeSubscriptionRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
 	out := new(MessageResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_ResumeSubscription_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) ChangePlan(ctx context.Context, in *ChangePlanRequest, opts ...grpc.CallOption) (*ChangePlanResponse, error) {
+	out := new(ChangePlanResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_ChangePlan_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) UpdateQuantity(ctx context.Context, in *UpdateQuantityRequest, opts ...grpc.CallOption) (*UpdateQuantityResponse, error) {
+	out := new(UpdateQuantityResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_UpdateQuantity_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) PaymentCallback(ctx context.Context, in *PaymentCallbackRequest, opts ...grpc.CallOption) (*PaymentCallbackResponse, error) {
+	out := new(PaymentCallbackResponse)
 	err := c.cc.Invoke(ctx, SubscriptionsService_PaymentCallback_FullMethodName, in, out, opts...)
 	if err != nil {
 		return nil, err
@@ -134,19 +348,117 @@ func (c *subscriptionsServiceClient) PaymentCallback(ctx context.Context, in *Pa
 	return out, nil
 }
 
+func (c *subscriptionsServiceClient) ListPaymentAttempts(ctx context.Context, in *ListPaymentAttemptsRequest, opts ...grpc.CallOption) (*ListPaymentAttemptsResponse, error) {
+	out := new(ListPaymentAttemptsResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_ListPaymentAttempts_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) ListSubscriptionEvents(ctx context.Context, in *ListSubscriptionEventsRequest, opts ...grpc.CallOption) (*ListSubscriptionEventsResponse, error) {
+	out := new(ListSubscriptionEventsResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_ListSubscriptionEvents_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) CreateWebhookEndpoint(ctx context.Context, in *CreateWebhookEndpointRequest, opts ...grpc.CallOption) (*CreateWebhookEndpointResponse, error) {
+	out := new(CreateWebhookEndpointResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_CreateWebhookEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) GetWebhookEndpoint(ctx context.Context, in *GetWebhookEndpointRequest, opts ...grpc.CallOption) (*WebhookEndpointResponse, error) {
+	out := new(WebhookEndpointResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_GetWebhookEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) ListWebhookEndpoints(ctx context.Context, in *ListWebhookEndpointsRequest, opts ...grpc.CallOption) (*ListWebhookEndpointsResponse, error) {
+	out := new(ListWebhookEndpointsResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_ListWebhookEndpoints_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) UpdateWebhookEndpoint(ctx context.Context, in *UpdateWebhookEndpointRequest, opts ...grpc.CallOption) (*WebhookEndpointResponse, error) {
+	out := new(WebhookEndpointResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_UpdateWebhookEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) DeleteWebhookEndpoint(ctx context.Context, in *DeleteWebhookEndpointRequest, opts ...grpc.CallOption) (*MessageResponse, error) {
+	out := new(MessageResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_DeleteWebhookEndpoint_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *subscriptionsServiceClient) ListWebhookDeliveries(ctx context.Context, in *ListWebhookDeliveriesRequest, opts ...grpc.CallOption) (*ListWebhookDeliveriesResponse, error) {
+	out := new(ListWebhookDeliveriesResponse)
+	err := c.cc.Invoke(ctx, SubscriptionsService_ListWebhookDeliveries_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // SubscriptionsServiceServer is the server API for SubscriptionsService service.
 // All implementations must embed UnimplementedSubscriptionsServiceServer
 // for forward compatibility
 type SubscriptionsServiceServer interface {
 	Health(context.Context, *HealthRequest) (*HealthResponse, error)
 	ListSubscriptionTypes(context.Context, *ListSubscriptionTypesRequest) (*ListSubscriptionTypesResponse, error)
+	ListPlanTypes(context.Context, *ListPlanTypesRequest) (*ListPlanTypesResponse, error)
+	GetPlanType(context.Context, *GetPlanTypeRequest) (*PlanTypeResponse, error)
+	CreateSubscriptionType(context.Context, *CreateSubscriptionTypeRequest) (*SubscriptionTypeResponse, error)
+	UpdateSubscriptionType(context.Context, *UpdateSubscriptionTypeRequest) (*SubscriptionTypeResponse, error)
+	ArchiveSubscriptionType(context.Context, *ArchiveSubscriptionTypeRequest) (*SubscriptionTypeResponse, error)
+	CreatePlanType(context.Context, *CreatePlanTypeRequest) (*PlanTypeResponse, error)
+	UpdatePlanType(context.Context, *UpdatePlanTypeRequest) (*PlanTypeResponse, error)
+	ArchivePlanType(context.Context, *ArchivePlanTypeRequest) (*PlanTypeResponse, error)
+	CreateCoupon(context.Context, *CreateCouponRequest) (*CouponResponse, error)
+	GetCoupon(context.Context, *GetCouponRequest) (*CouponResponse, error)
+	ListCoupons(context.Context, *ListCouponsRequest) (*ListCouponsResponse, error)
+	UpdateCoupon(context.Context, *UpdateCouponRequest) (*CouponResponse, error)
+	ListCouponRedemptions(context.Context, *ListCouponRedemptionsRequest) (*ListCouponRedemptionsResponse, error)
 	CreateSubscription(context.Context, *CreateSubscriptionRequest) (*CreateSubscriptionResponse, error)
 	GetSubscription(context.Context, *GetSubscriptionRequest) (*SubscriptionEnvelopeResponse, error)
 	ListSubscriptions(context.Context, *ListSubscriptionsRequest) (*ListSubscriptionsResponse, error)
 	UpdateSubscription(context.Context, *UpdateSubscriptionRequest) (*SubscriptionEnvelopeResponse, error)
 	DeleteSubscription(context.Context, *DeleteSubscriptionRequest) (*MessageResponse, error)
-	CancelSubscription(context.Context, *CancelSubscriptionRequest) (*MessageResponse, error)
-	PaymentCallback(context.Context, *PaymentCallbackRequest) (*MessageResponse, error)
+	CancelSubscription(context.Context, *CancelSubscriptionRequest) (*CancelSubscriptionResponse, error)
+	UndoCancellation(context.Context, *UndoCancellationRequest) (*MessageResponse, error)
+	PauseSubscription(context.Context, *PauseSubscriptionRequest) (*MessageResponse, error)
+	ResumeSubscription(context.Context, *ResumeSubscriptionRequest) (*MessageResponse, error)
+	ChangePlan(context.Context, *ChangePlanRequest) (*ChangePlanResponse, error)
+	UpdateQuantity(context.Context, *UpdateQuantityRequest) (*UpdateQuantityResponse, error)
+	PaymentCallback(context.Context, *PaymentCallbackRequest) (*PaymentCallbackResponse, error)
+	ListPaymentAttempts(context.Context, *ListPaymentAttemptsRequest) (*ListPaymentAttemptsResponse, error)
+	ListSubscriptionEvents(context.Context, *ListSubscriptionEventsRequest) (*ListSubscriptionEventsResponse, error)
+	CreateWebhookEndpoint(context.Context, *CreateWebhookEndpointRequest) (*CreateWebhookEndpointResponse, error)
+	GetWebhookEndpoint(context.Context, *GetWebhookEndpointRequest) (*WebhookEndpointResponse, error)
+	ListWebhookEndpoints(context.Context, *ListWebhookEndpointsRequest) (*ListWebhookEndpointsResponse, error)
+	UpdateWebhookEndpoint(context.Context, *UpdateWebhookEndpointRequest) (*WebhookEndpointResponse, error)
+	DeleteWebhookEndpoint(context.Context, *DeleteWebhookEndpointRequest) (*MessageResponse, error)
+	ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error)
 	mustEmbedUnimplementedSubscriptionsServiceServer()
 }
 
@@ -160,6 +472,45 @@ func (UnimplementedSubscriptionsServiceServer) Health(context.Context, *HealthRe
 func (UnimplementedSubscriptionsServiceServer) ListSubscriptionTypes(context.Context, *ListSubscriptionTypesRequest) (*ListSubscriptionTypesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListSubscriptionTypes not implemented")
 }
+func (UnimplementedSubscriptionsServiceServer) ListPlanTypes(context.Context, *ListPlanTypesRequest) (*ListPlanTypesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPlanTypes not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) GetPlanType(context.Context, *GetPlanTypeRequest) (*PlanTypeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPlanType not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) CreateSubscriptionType(context.Context, *CreateSubscriptionTypeRequest) (*SubscriptionTypeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateSubscriptionType not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) UpdateSubscriptionType(context.Context, *UpdateSubscriptionTypeRequest) (*SubscriptionTypeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateSubscriptionType not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) ArchiveSubscriptionType(context.Context, *ArchiveSubscriptionTypeRequest) (*SubscriptionTypeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchiveSubscriptionType not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) CreatePlanType(context.Context, *CreatePlanTypeRequest) (*PlanTypeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreatePlanType not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) UpdatePlanType(context.Context, *UpdatePlanTypeRequest) (*PlanTypeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdatePlanType not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) ArchivePlanType(context.Context, *ArchivePlanTypeRequest) (*PlanTypeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ArchivePlanType not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) CreateCoupon(context.Context, *CreateCouponRequest) (*CouponResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateCoupon not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) GetCoupon(context.Context, *GetCouponRequest) (*CouponResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetCoupon not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) ListCoupons(context.Context, *ListCouponsRequest) (*ListCouponsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCoupons not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) UpdateCoupon(context.Context, *UpdateCouponRequest) (*CouponResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateCoupon not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) ListCouponRedemptions(context.Context, *ListCouponRedemptionsRequest) (*ListCouponRedemptionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCouponRedemptions not implemented")
+}
 func (UnimplementedSubscriptionsServiceServer) CreateSubscription(context.Context, *CreateSubscriptionRequest) (*CreateSubscriptionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CreateSubscription not implemented")
 }
@@ -175,12 +526,51 @@ func (UnimplementedSubscriptionsServiceServer) UpdateSubscription(context.Contex
 func (UnimplementedSubscriptionsServiceServer) DeleteSubscription(context.Context, *DeleteSubscriptionRequest) (*MessageResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteSubscription not implemented")
 }
-func (UnimplementedSubscriptionsServiceServer) CancelSubscription(context.Context, *CancelSubscriptionRequest) (*MessageResponse, error) {
+func (UnimplementedSubscriptionsServiceServer) CancelSubscription(context.Context, *CancelSubscriptionRequest) (*CancelSubscriptionResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method CancelSubscription not implemented")
 }
-func (UnimplementedSubscriptionsServiceServer) PaymentCallback(context.Context, *PaymentCallbackRequest) (*MessageResponse, error) {
+func (UnimplementedSubscriptionsServiceServer) UndoCancellation(context.Context, *UndoCancellationRequest) (*MessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UndoCancellation not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) PauseSubscription(context.Context, *PauseSubscriptionRequest) (*MessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PauseSubscription not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) ResumeSubscription(context.Context, *ResumeSubscriptionRequest) (*MessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ResumeSubscription not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) ChangePlan(context.Context, *ChangePlanRequest) (*ChangePlanResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ChangePlan not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) UpdateQuantity(context.Context, *UpdateQuantityRequest) (*UpdateQuantityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateQuantity not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) PaymentCallback(context.Context, *PaymentCallbackRequest) (*PaymentCallbackResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method PaymentCallback not implemented")
 }
+func (UnimplementedSubscriptionsServiceServer) ListPaymentAttempts(context.Context, *ListPaymentAttemptsRequest) (*ListPaymentAttemptsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPaymentAttempts not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) ListSubscriptionEvents(context.Context, *ListSubscriptionEventsRequest) (*ListSubscriptionEventsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSubscriptionEvents not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) CreateWebhookEndpoint(context.Context, *CreateWebhookEndpointRequest) (*CreateWebhookEndpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateWebhookEndpoint not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) GetWebhookEndpoint(context.Context, *GetWebhookEndpointRequest) (*WebhookEndpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetWebhookEndpoint not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) ListWebhookEndpoints(context.Context, *ListWebhookEndpointsRequest) (*ListWebhookEndpointsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookEndpoints not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) UpdateWebhookEndpoint(context.Context, *UpdateWebhookEndpointRequest) (*WebhookEndpointResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateWebhookEndpoint not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) DeleteWebhookEndpoint(context.Context, *DeleteWebhookEndpointRequest) (*MessageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteWebhookEndpoint not implemented")
+}
+func (UnimplementedSubscriptionsServiceServer) ListWebhookDeliveries(context.Context, *ListWebhookDeliveriesRequest) (*ListWebhookDeliveriesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListWebhookDeliveries not implemented")
+}
 func (UnimplementedSubscriptionsServiceServer) mustEmbedUnimplementedSubscriptionsServiceServer() {}
 
 // UnsafeSubscriptionsServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -230,175 +620,747 @@ func _SubscriptionsService_ListSubscriptionTypes_Handler(srv interface{}, ctx co
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SubscriptionsService_CreateSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CreateSubscriptionRequest)
+func _SubscriptionsService_ListPlanTypes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPlanTypesRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SubscriptionsServiceServer).CreateSubscription(ctx, in)
+		return srv.(SubscriptionsServiceServer).ListPlanTypes(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SubscriptionsService_CreateSubscription_FullMethodName,
+		FullMethod: SubscriptionsService_ListPlanTypes_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SubscriptionsServiceServer).CreateSubscription(ctx, req.(*CreateSubscriptionRequest))
+		return srv.(SubscriptionsServiceServer).ListPlanTypes(ctx, req.(*ListPlanTypesRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SubscriptionsService_GetSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(GetSubscriptionRequest)
+func _SubscriptionsService_GetPlanType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPlanTypeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SubscriptionsServiceServer).GetSubscription(ctx, in)
+		return srv.(SubscriptionsServiceServer).GetPlanType(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SubscriptionsService_GetSubscription_FullMethodName,
+		FullMethod: SubscriptionsService_GetPlanType_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SubscriptionsServiceServer).GetSubscription(ctx, req.(*GetSubscriptionRequest))
+		return srv.(SubscriptionsServiceServer).GetPlanType(ctx, req.(*GetPlanTypeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SubscriptionsService_ListSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(ListSubscriptionsRequest)
+func _SubscriptionsService_CreateSubscriptionType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSubscriptionTypeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SubscriptionsServiceServer).ListSubscriptions(ctx, in)
+		return srv.(SubscriptionsServiceServer).CreateSubscriptionType(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SubscriptionsService_ListSubscriptions_FullMethodName,
+		FullMethod: SubscriptionsService_CreateSubscriptionType_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SubscriptionsServiceServer).ListSubscriptions(ctx, req.(*ListSubscriptionsRequest))
+		return srv.(SubscriptionsServiceServer).CreateSubscriptionType(ctx, req.(*CreateSubscriptionTypeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SubscriptionsService_UpdateSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(UpdateSubscriptionRequest)
+func _SubscriptionsService_UpdateSubscriptionType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSubscriptionTypeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SubscriptionsServiceServer).UpdateSubscription(ctx, in)
+		return srv.(SubscriptionsServiceServer).UpdateSubscriptionType(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SubscriptionsService_UpdateSubscription_FullMethodName,
+		FullMethod: SubscriptionsService_UpdateSubscriptionType_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SubscriptionsServiceServer).UpdateSubscription(ctx, req.(*UpdateSubscriptionRequest))
+		return srv.(SubscriptionsServiceServer).UpdateSubscriptionType(ctx, req.(*UpdateSubscriptionTypeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SubscriptionsService_DeleteSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(DeleteSubscriptionRequest)
+func _SubscriptionsService_ArchiveSubscriptionType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchiveSubscriptionTypeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SubscriptionsServiceServer).DeleteSubscription(ctx, in)
+		return srv.(SubscriptionsServiceServer).ArchiveSubscriptionType(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SubscriptionsService_DeleteSubscription_FullMethodName,
+		FullMethod: SubscriptionsService_ArchiveSubscriptionType_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SubscriptionsServiceServer).DeleteSubscription(ctx, req.(*DeleteSubscriptionRequest))
+		return srv.(SubscriptionsServiceServer).ArchiveSubscriptionType(ctx, req.(*ArchiveSubscriptionTypeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SubscriptionsService_CancelSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(CancelSubscriptionRequest)
+func _SubscriptionsService_CreatePlanType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreatePlanTypeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SubscriptionsServiceServer).CancelSubscription(ctx, in)
+		return srv.(SubscriptionsServiceServer).CreatePlanType(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SubscriptionsService_CancelSubscription_FullMethodName,
+		FullMethod: SubscriptionsService_CreatePlanType_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SubscriptionsServiceServer).CancelSubscription(ctx, req.(*CancelSubscriptionRequest))
+		return srv.(SubscriptionsServiceServer).CreatePlanType(ctx, req.(*CreatePlanTypeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-func _SubscriptionsService_PaymentCallback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
-	in := new(PaymentCallbackRequest)
+func _SubscriptionsService_UpdatePlanType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdatePlanTypeRequest)
 	if err := dec(in); err != nil {
 		return nil, err
 	}
 	if interceptor == nil {
-		return srv.(SubscriptionsServiceServer).PaymentCallback(ctx, in)
+		return srv.(SubscriptionsServiceServer).UpdatePlanType(ctx, in)
 	}
 	info := &grpc.UnaryServerInfo{
 		Server:     srv,
-		FullMethod: SubscriptionsService_PaymentCallback_FullMethodName,
+		FullMethod: SubscriptionsService_UpdatePlanType_FullMethodName,
 	}
 	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
-		return srv.(SubscriptionsServiceServer).PaymentCallback(ctx, req.(*PaymentCallbackRequest))
+		return srv.(SubscriptionsServiceServer).UpdatePlanType(ctx, req.(*UpdatePlanTypeRequest))
 	}
 	return interceptor(ctx, in, info, handler)
 }
 
-// SubscriptionsService_ServiceDesc is the grpc.ServiceDesc for SubscriptionsService service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var SubscriptionsService_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "subscriptions.SubscriptionsService",
-	HandlerType: (*SubscriptionsServiceServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "Health",
-			Handler:    _SubscriptionsService_Health_Handler,
-		},
-		{
-			MethodName: "ListSubscriptionTypes",
-			Handler:    _SubscriptionsService_ListSubscriptionTypes_Handler,
-		},
-		{
-			MethodName: "CreateSubscription",
-			Handler:    _SubscriptionsService_CreateSubscription_Handler,
-		},
-		{
-			MethodName: "GetSubscription",
-			Handler:    _SubscriptionsService_GetSubscription_Handler,
-		},
-		{
-			MethodName: "ListSubscriptions",
-			Handler:    _SubscriptionsService_ListSubscriptions_Handler,
-		},
-		{
-			MethodName: "UpdateSubscription",
-			Handler:    _SubscriptionsService_UpdateSubscription_Handler,
-		},
-		{
-			MethodName: "DeleteSubscription",
-			Handler:    _SubscriptionsService_DeleteSubscription_Handler,
-		},
+func _SubscriptionsService_ArchivePlanType_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ArchivePlanTypeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).ArchivePlanType(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_ArchivePlanType_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).ArchivePlanType(ctx, req.(*ArchivePlanTypeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_CreateCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateCouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).CreateCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_CreateCoupon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).CreateCoupon(ctx, req.(*CreateCouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_GetCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetCouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).GetCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_GetCoupon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).GetCoupon(ctx, req.(*GetCouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_ListCoupons_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCouponsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).ListCoupons(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_ListCoupons_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).ListCoupons(ctx, req.(*ListCouponsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_UpdateCoupon_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateCouponRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).UpdateCoupon(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_UpdateCoupon_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).UpdateCoupon(ctx, req.(*UpdateCouponRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_ListCouponRedemptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListCouponRedemptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).ListCouponRedemptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_ListCouponRedemptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).ListCouponRedemptions(ctx, req.(*ListCouponRedemptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_CreateSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).CreateSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_CreateSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).CreateSubscription(ctx, req.(*CreateSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_GetSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).GetSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_GetSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).GetSubscription(ctx, req.(*GetSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_ListSubscriptions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubscriptionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).ListSubscriptions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_ListSubscriptions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).ListSubscriptions(ctx, req.(*ListSubscriptionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_UpdateSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).UpdateSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_UpdateSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).UpdateSubscription(ctx, req.(*UpdateSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_DeleteSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).DeleteSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_DeleteSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).DeleteSubscription(ctx, req.(*DeleteSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_CancelSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CancelSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).CancelSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_CancelSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).CancelSubscription(ctx, req.(*CancelSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_UndoCancellation_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UndoCancellationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).UndoCancellation(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_UndoCancellation_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).UndoCancellation(ctx, req.(*UndoCancellationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_PauseSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PauseSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).PauseSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_PauseSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).PauseSubscription(ctx, req.(*PauseSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_ResumeSubscription_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ResumeSubscriptionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).ResumeSubscription(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_ResumeSubscription_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).ResumeSubscription(ctx, req.(*ResumeSubscriptionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_ChangePlan_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangePlanRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).ChangePlan(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_ChangePlan_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).ChangePlan(ctx, req.(*ChangePlanRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_UpdateQuantity_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateQuantityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).UpdateQuantity(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_UpdateQuantity_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).UpdateQuantity(ctx, req.(*UpdateQuantityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_PaymentCallback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PaymentCallbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).PaymentCallback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_PaymentCallback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).PaymentCallback(ctx, req.(*PaymentCallbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_ListPaymentAttempts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPaymentAttemptsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).ListPaymentAttempts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_ListPaymentAttempts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).ListPaymentAttempts(ctx, req.(*ListPaymentAttemptsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_ListSubscriptionEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSubscriptionEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).ListSubscriptionEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_ListSubscriptionEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).ListSubscriptionEvents(ctx, req.(*ListSubscriptionEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_CreateWebhookEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateWebhookEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).CreateWebhookEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_CreateWebhookEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).CreateWebhookEndpoint(ctx, req.(*CreateWebhookEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_GetWebhookEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetWebhookEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).GetWebhookEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_GetWebhookEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).GetWebhookEndpoint(ctx, req.(*GetWebhookEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_ListWebhookEndpoints_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookEndpointsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).ListWebhookEndpoints(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_ListWebhookEndpoints_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).ListWebhookEndpoints(ctx, req.(*ListWebhookEndpointsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_UpdateWebhookEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateWebhookEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).UpdateWebhookEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_UpdateWebhookEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).UpdateWebhookEndpoint(ctx, req.(*UpdateWebhookEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_DeleteWebhookEndpoint_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteWebhookEndpointRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).DeleteWebhookEndpoint(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_DeleteWebhookEndpoint_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).DeleteWebhookEndpoint(ctx, req.(*DeleteWebhookEndpointRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _SubscriptionsService_ListWebhookDeliveries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListWebhookDeliveriesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(SubscriptionsServiceServer).ListWebhookDeliveries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: SubscriptionsService_ListWebhookDeliveries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(SubscriptionsServiceServer).ListWebhookDeliveries(ctx, req.(*ListWebhookDeliveriesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// SubscriptionsService_ServiceDesc is the grpc.ServiceDesc for SubscriptionsService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var SubscriptionsService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "subscriptions.SubscriptionsService",
+	HandlerType: (*SubscriptionsServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Health",
+			Handler:    _SubscriptionsService_Health_Handler,
+		},
+		{
+			MethodName: "ListSubscriptionTypes",
+			Handler:    _SubscriptionsService_ListSubscriptionTypes_Handler,
+		},
+		{
+			MethodName: "ListPlanTypes",
+			Handler:    _SubscriptionsService_ListPlanTypes_Handler,
+		},
+		{
+			MethodName: "GetPlanType",
+			Handler:    _SubscriptionsService_GetPlanType_Handler,
+		},
+		{
+			MethodName: "CreateSubscriptionType",
+			Handler:    _SubscriptionsService_CreateSubscriptionType_Handler,
+		},
+		{
+			MethodName: "UpdateSubscriptionType",
+			Handler:    _SubscriptionsService_UpdateSubscriptionType_Handler,
+		},
+		{
+			MethodName: "ArchiveSubscriptionType",
+			Handler:    _SubscriptionsService_ArchiveSubscriptionType_Handler,
+		},
+		{
+			MethodName: "CreatePlanType",
+			Handler:    _SubscriptionsService_CreatePlanType_Handler,
+		},
+		{
+			MethodName: "UpdatePlanType",
+			Handler:    _SubscriptionsService_UpdatePlanType_Handler,
+		},
+		{
+			MethodName: "ArchivePlanType",
+			Handler:    _SubscriptionsService_ArchivePlanType_Handler,
+		},
+		{
+			MethodName: "CreateCoupon",
+			Handler:    _SubscriptionsService_CreateCoupon_Handler,
+		},
+		{
+			MethodName: "GetCoupon",
+			Handler:    _SubscriptionsService_GetCoupon_Handler,
+		},
+		{
+			MethodName: "ListCoupons",
+			Handler:    _SubscriptionsService_ListCoupons_Handler,
+		},
+		{
+			MethodName: "UpdateCoupon",
+			Handler:    _SubscriptionsService_UpdateCoupon_Handler,
+		},
+		{
+			MethodName: "ListCouponRedemptions",
+			Handler:    _SubscriptionsService_ListCouponRedemptions_Handler,
+		},
+		{
+			MethodName: "CreateSubscription",
+			Handler:    _SubscriptionsService_CreateSubscription_Handler,
+		},
+		{
+			MethodName: "GetSubscription",
+			Handler:    _SubscriptionsService_GetSubscription_Handler,
+		},
+		{
+			MethodName: "ListSubscriptions",
+			Handler:    _SubscriptionsService_ListSubscriptions_Handler,
+		},
+		{
+			MethodName: "UpdateSubscription",
+			Handler:    _SubscriptionsService_UpdateSubscription_Handler,
+		},
+		{
+			MethodName: "DeleteSubscription",
+			Handler:    _SubscriptionsService_DeleteSubscription_Handler,
+		},
 		{
 			MethodName: "CancelSubscription",
 			Handler:    _SubscriptionsService_CancelSubscription_Handler,
 		},
+		{
+			MethodName: "UndoCancellation",
+			Handler:    _SubscriptionsService_UndoCancellation_Handler,
+		},
+		{
+			MethodName: "PauseSubscription",
+			Handler:    _SubscriptionsService_PauseSubscription_Handler,
+		},
+		{
+			MethodName: "ResumeSubscription",
+			Handler:    _SubscriptionsService_ResumeSubscription_Handler,
+		},
+		{
+			MethodName: "ChangePlan",
+			Handler:    _SubscriptionsService_ChangePlan_Handler,
+		},
+		{
+			MethodName: "UpdateQuantity",
+			Handler:    _SubscriptionsService_UpdateQuantity_Handler,
+		},
 		{
 			MethodName: "PaymentCallback",
 			Handler:    _SubscriptionsService_PaymentCallback_Handler,
 		},
+		{
+			MethodName: "ListPaymentAttempts",
+			Handler:    _SubscriptionsService_ListPaymentAttempts_Handler,
+		},
+		{
+			MethodName: "ListSubscriptionEvents",
+			Handler:    _SubscriptionsService_ListSubscriptionEvents_Handler,
+		},
+		{
+			MethodName: "CreateWebhookEndpoint",
+			Handler:    _SubscriptionsService_CreateWebhookEndpoint_Handler,
+		},
+		{
+			MethodName: "GetWebhookEndpoint",
+			Handler:    _SubscriptionsService_GetWebhookEndpoint_Handler,
+		},
+		{
+			MethodName: "ListWebhookEndpoints",
+			Handler:    _SubscriptionsService_ListWebhookEndpoints_Handler,
+		},
+		{
+			MethodName: "UpdateWebhookEndpoint",
+			Handler:    _SubscriptionsService_UpdateWebhookEndpoint_Handler,
+		},
+		{
+			MethodName: "DeleteWebhookEndpoint",
+			Handler:    _SubscriptionsService_DeleteWebhookEndpoint_Handler,
+		},
+		{
+			MethodName: "ListWebhookDeliveries",
+			Handler:    _SubscriptionsService_ListWebhookDeliveries_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "subscriptions.proto",
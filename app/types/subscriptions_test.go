@@ -30,6 +30,26 @@ func TestListSubscriptionTypesValidate(t *testing.T) {
 	}
 }
 
+func TestNewListPlanTypesRequestFromContext(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest("GET", "/plan-types?subscription_type_id=2", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	parsed, err := NewListPlanTypesRequestFromContext(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if parsed.GetSubscriptionTypeId() != 2 {
+		t.Fatalf("unexpected parsed request: %+v", parsed)
+	}
+
+	req = httptest.NewRequest("GET", "/plan-types?subscription_type_id=abc", nil)
+	if _, err := NewListPlanTypesRequestFromContext(e.NewContext(req, rec)); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
 func TestCreateSubscriptionValidate(t *testing.T) {
 	req := &CreateSubscriptionRequest{SubscriptionTypeId: 1}
 	if err := req.Validate(); err == nil {
@@ -65,6 +85,45 @@ func TestNewUpdateSubscriptionRequestFromContext(t *testing.T) {
 	}
 }
 
+func TestNewDeleteSubscriptionRequestFromContextReadsIfMatch(t *testing.T) {
+	e := echo.New()
+	cases := []struct {
+		ifMatch string
+		version int64
+		valid   bool
+	}{
+		{ifMatch: "", version: 0, valid: true},
+		{ifMatch: "*", version: 0, valid: true},
+		{ifMatch: SubscriptionETag(7), version: 7, valid: true},
+		{ifMatch: "7", valid: false},
+		{ifMatch: `W/"7"`, valid: false},
+		{ifMatch: `"0"`, valid: false},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("DELETE", "/subscriptions/12", nil)
+		if tc.ifMatch != "" {
+			req.Header.Set("If-Match", tc.ifMatch)
+		}
+		ctx := e.NewContext(req, httptest.NewRecorder())
+		ctx.SetParamNames("id")
+		ctx.SetParamValues("12")
+
+		parsed, err := NewDeleteSubscriptionRequestFromContext(ctx)
+		if !tc.valid {
+			if err == nil {
+				t.Fatalf("expected error for If-Match %q", tc.ifMatch)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("expected no error for If-Match %q, got %v", tc.ifMatch, err)
+		}
+		if parsed.GetExpectedVersion() != tc.version {
+			t.Fatalf("expected version %d for If-Match %q, got %d", tc.version, tc.ifMatch, parsed.GetExpectedVersion())
+		}
+	}
+}
+
 func TestUpdateSubscriptionValidate(t *testing.T) {
 	req := &UpdateSubscriptionRequest{Id: 1}
 	if err := req.Validate(); err == nil {
@@ -94,6 +153,11 @@ func TestNewPaymentCallbackRequestFromContext(t *testing.T) {
 	if err := parsed.Validate(); err != nil {
 		t.Fatalf("expected valid callback, got %v", err)
 	}
+
+	parsed.TransactionId = ""
+	if err := parsed.Validate(); err == nil {
+		t.Fatal("expected callback without transaction_id to be invalid")
+	}
 }
 
 func TestDeleteAndCancelValidate(t *testing.T) {
@@ -103,4 +167,298 @@ func TestDeleteAndCancelValidate(t *testing.T) {
 	if err := (&CancelSubscriptionRequest{}).Validate(); err == nil {
 		t.Fatal("expected invalid cancel request")
 	}
+	if err := (&CancelSubscriptionRequest{Id: 5, Refund: true}).Validate(); err == nil {
+		t.Fatal("expected refund without immediate mode to be invalid")
+	}
+	if err := (&CancelSubscriptionRequest{Id: 5, Mode: "later"}).Validate(); err == nil {
+		t.Fatal("expected invalid cancel mode")
+	}
+	if err := (&UndoCancellationRequest{}).Validate(); err == nil {
+		t.Fatal("expected invalid undo cancellation request")
+	}
+}
+
+func TestNewCancelSubscriptionRequestFromContext(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest("POST", "/subscriptions/5/cancel", bytes.NewBufferString(`{"mode":" Immediate ","reason":" moving abroad ","refund":true}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	ctx := e.NewContext(req, httptest.NewRecorder())
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("5")
+
+	parsed, err := NewCancelSubscriptionRequestFromContext(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if parsed.GetId() != 5 || parsed.GetMode() != "immediate" || parsed.GetReason() != "moving abroad" || !parsed.GetRefund() {
+		t.Fatalf("unexpected parsed request: %+v", parsed)
+	}
+	if err := parsed.Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+
+	req = httptest.NewRequest("POST", "/subscriptions/5/cancel", nil)
+	ctx = e.NewContext(req, httptest.NewRecorder())
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("5")
+	parsed, err = NewCancelSubscriptionRequestFromContext(ctx)
+	if err != nil {
+		t.Fatalf("expected an empty body to be accepted, got %v", err)
+	}
+	if parsed.GetMode() != "" || parsed.Validate() != nil {
+		t.Fatalf("expected a period-end cancellation, got %+v", parsed)
+	}
+}
+
+func TestNewChangePlanRequestFromContext(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest("POST", "/subscriptions/5/change-plan", bytes.NewBufferString(`{"plan_type_id":7,"timing":" Period_End "}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("5")
+
+	parsed, err := NewChangePlanRequestFromContext(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if parsed.GetId() != 5 || parsed.GetPlanTypeId() != 7 || parsed.GetTiming() != "period_end" {
+		t.Fatalf("unexpected parsed request: %+v", parsed)
+	}
+	if err := parsed.Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+
+	parsed.Timing = "tomorrow"
+	if err := parsed.Validate(); err == nil {
+		t.Fatal("expected invalid timing")
+	}
+	if err := (&ChangePlanRequest{Id: 5}).Validate(); err == nil {
+		t.Fatal("expected missing plan_type_id to be invalid")
+	}
+}
+
+func TestNewUpdateQuantityRequestFromContext(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest("POST", "/subscriptions/5/update-quantity", bytes.NewBufferString(`{"quantity":12,"proration":" None "}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	ctx := e.NewContext(req, httptest.NewRecorder())
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("5")
+
+	parsed, err := NewUpdateQuantityRequestFromContext(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if parsed.GetId() != 5 || parsed.GetQuantity() != 12 || parsed.GetProration() != "none" {
+		t.Fatalf("unexpected parsed request: %+v", parsed)
+	}
+	if err := parsed.Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+
+	parsed.Proration = "later"
+	if err := parsed.Validate(); err == nil {
+		t.Fatal("expected invalid proration")
+	}
+	if err := (&UpdateQuantityRequest{Id: 5}).Validate(); err == nil {
+		t.Fatal("expected zero quantity to be invalid")
+	}
+	if err := (&CreateSubscriptionRequest{SubscriptionTypeId: 2, UserId: "u-1", Quantity: 10001}).Validate(); err == nil {
+		t.Fatal("expected too many seats to be invalid")
+	}
+}
+
+func TestListPaymentAttemptsValidate(t *testing.T) {
+	if err := (&ListPaymentAttemptsRequest{}).Validate(); err == nil {
+		t.Fatal("expected invalid list payment attempts request")
+	}
+	if err := (&ListPaymentAttemptsRequest{SubscriptionId: 1}).Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+}
+
+func TestListSubscriptionEventsValidate(t *testing.T) {
+	if err := (&ListSubscriptionEventsRequest{}).Validate(); err == nil {
+		t.Fatal("expected invalid list subscription events request")
+	}
+	if err := (&ListSubscriptionEventsRequest{SubscriptionId: 1}).Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+}
+
+func TestNewUpdateWebhookEndpointRequestFromContext(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest("PATCH", "/webhook-endpoints/3", bytes.NewBufferString(`{"event_types":[" Subscription.Renewed ",""],"status":0}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+	ctx.SetParamNames("id")
+	ctx.SetParamValues("3")
+
+	parsed, err := NewUpdateWebhookEndpointRequestFromContext(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if parsed.GetId() != 3 || parsed.GetHasUrl() || !parsed.GetHasEventTypes() || !parsed.GetHasStatus() || parsed.GetStatus() != 0 {
+		t.Fatalf("unexpected parsed request: %+v", parsed)
+	}
+	if len(parsed.GetEventTypes()) != 1 || parsed.GetEventTypes()[0] != "subscription.renewed" {
+		t.Fatalf("unexpected event types: %v", parsed.GetEventTypes())
+	}
+}
+
+func TestWebhookEndpointValidate(t *testing.T) {
+	if err := (&CreateWebhookEndpointRequest{}).Validate(); err == nil {
+		t.Fatal("expected url required error")
+	}
+	if err := (&CreateWebhookEndpointRequest{Url: "ftp://example.com/hook"}).Validate(); err == nil {
+		t.Fatal("expected url scheme error")
+	}
+	if err := (&CreateWebhookEndpointRequest{Url: "https://example.com/hook"}).Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+	if err := (&UpdateWebhookEndpointRequest{Id: 1}).Validate(); err == nil {
+		t.Fatal("expected no fields error")
+	}
+	if err := (&UpdateWebhookEndpointRequest{Id: 1, HasStatus: true, Status: 1}).Validate(); err == nil {
+		t.Fatal("expected status validation error")
+	}
+	if err := (&ListWebhookDeliveriesRequest{}).Validate(); err == nil {
+		t.Fatal("expected webhook endpoint id validation error")
+	}
+}
+
+func TestNewListSubscriptionsRequestFromContext(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest("GET", "/subscriptions?user_id=u1&status=10&auto_renew=false&subscription_type_id=3&page_size=20&sort=END_AT_ASC&end_at_to=2026-02-01T00:00:00Z", nil)
+	rec := httptest.NewRecorder()
+	ctx := e.NewContext(req, rec)
+
+	parsed, err := NewListSubscriptionsRequestFromContext(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if parsed.GetUserId() != "u1" || !parsed.GetHasStatus() || parsed.GetStatus() != 10 || !parsed.GetHasAutoRenew() || parsed.GetAutoRenew() {
+		t.Fatalf("unexpected parsed request: %+v", parsed)
+	}
+	if parsed.GetSubscriptionTypeId() != 3 || parsed.GetPageSize() != 20 || parsed.GetSort() != "end_at_asc" || parsed.GetEndAtTo() == "" {
+		t.Fatalf("unexpected parsed request: %+v", parsed)
+	}
+	if err := parsed.Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+}
+
+func TestListSubscriptionsValidate(t *testing.T) {
+	cases := []*ListSubscriptionsRequest{
+		{PageSize: 500},
+		{HasStatus: true, Status: 6},
+		{Sort: "email"},
+		{CreatedAtFrom: "yesterday"},
+		{EndAtFrom: "2026-02-01T00:00:00Z", EndAtTo: "2026-01-01T00:00:00Z"},
+	}
+	for _, req := range cases {
+		if err := req.Validate(); err == nil {
+			t.Fatalf("expected validation error for %+v", req)
+		}
+	}
+}
+
+func TestNewCreatePlanTypeRequestFromContext(t *testing.T) {
+	e := echo.New()
+	body := `{"subscription_type_id":2,"plan_code":"premium-yearly","display_name":" Premium Yearly ","price_cents":19900,"currency":"eur","interval_unit":" Year ","interval_count":1,"features":{"tier":"premium"}}`
+	req := httptest.NewRequest("POST", "/admin/plan-types", bytes.NewBufferString(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	ctx := e.NewContext(req, httptest.NewRecorder())
+
+	parsed, err := NewCreatePlanTypeRequestFromContext(ctx)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if parsed.GetDisplayName() != "Premium Yearly" || parsed.GetCurrency() != "EUR" || parsed.GetIntervalUnit() != "year" || parsed.GetFeatures() != `{"tier":"premium"}` {
+		t.Fatalf("unexpected parsed request: %+v", parsed)
+	}
+	if err := parsed.Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+}
+
+func TestPlanTypeValidate(t *testing.T) {
+	valid := func() *CreatePlanTypeRequest {
+		return &CreatePlanTypeRequest{SubscriptionTypeId: 2, PlanCode: "basic", DisplayName: "Basic", Currency: "USD", IntervalUnit: "month", IntervalCount: 1}
+	}
+	invalid := []func(req *CreatePlanTypeRequest){
+		func(req *CreatePlanTypeRequest) { req.PlanCode = "Basic Plan" },
+		func(req *CreatePlanTypeRequest) { req.DisplayName = "" },
+		func(req *CreatePlanTypeRequest) { req.PriceCents = -1 },
+		func(req *CreatePlanTypeRequest) { req.Currency = "US" },
+		func(req *CreatePlanTypeRequest) { req.IntervalUnit = "quarter" },
+		func(req *CreatePlanTypeRequest) { req.IntervalCount = 0 },
+		func(req *CreatePlanTypeRequest) { req.TrialDays = -1 },
+		func(req *CreatePlanTypeRequest) { req.TrialDays = 366 },
+	}
+	for i, mutate := range invalid {
+		req := valid()
+		mutate(req)
+		if err := req.Validate(); err == nil {
+			t.Fatalf("case %d: expected validation error", i)
+		}
+	}
+
+	if err := (&UpdatePlanTypeRequest{Id: 4}).Validate(); err == nil {
+		t.Fatal("expected error for update without fields")
+	}
+	if err := (&UpdatePlanTypeRequest{Id: 4, HasIntervalCount: true, IntervalCount: -5}).Validate(); err == nil {
+		t.Fatal("expected error for non-positive interval count")
+	}
+	if err := (&UpdatePlanTypeRequest{Id: 4, HasIntervalUnit: true, IntervalUnit: "fortnight"}).Validate(); err == nil {
+		t.Fatal("expected error for unknown interval unit")
+	}
+	if err := (&UpdatePlanTypeRequest{Id: 4, HasTrialDays: true, TrialDays: 0}).Validate(); err != nil {
+		t.Fatalf("expected removing the trial to be valid, got %v", err)
+	}
+}
+
+func TestCouponValidate(t *testing.T) {
+	valid := func() *CreateCouponRequest {
+		return &CreateCouponRequest{Code: "SPRING25", PercentOff: 25, Duration: "repeating", DurationInPeriods: 3}
+	}
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("expected valid request, got %v", err)
+	}
+	if err := (&CreateCouponRequest{Code: "WELCOME", AmountOffCents: 500, Currency: "EUR", Duration: "once"}).Validate(); err != nil {
+		t.Fatalf("expected valid amount-off request, got %v", err)
+	}
+	invalid := []func(req *CreateCouponRequest){
+		func(req *CreateCouponRequest) { req.Code = "spring 25" },
+		func(req *CreateCouponRequest) { req.PercentOff = 0 },
+		func(req *CreateCouponRequest) { req.PercentOff = 101 },
+		func(req *CreateCouponRequest) { req.AmountOffCents = 500 },
+		func(req *CreateCouponRequest) { req.Currency = "EUR" },
+		func(req *CreateCouponRequest) { req.Duration = "weekly" },
+		func(req *CreateCouponRequest) { req.DurationInPeriods = 0 },
+		func(req *CreateCouponRequest) { req.Duration = "forever" },
+		func(req *CreateCouponRequest) { req.MaxRedemptions = -1 },
+		func(req *CreateCouponRequest) { req.PlanTypeIds = []uint64{0} },
+		func(req *CreateCouponRequest) { req.ExpiresAt = "tomorrow" },
+	}
+	for i, mutate := range invalid {
+		req := valid()
+		mutate(req)
+		if err := req.Validate(); err == nil {
+			t.Fatalf("case %d: expected validation error", i)
+		}
+	}
+
+	if err := (&UpdateCouponRequest{Id: 4}).Validate(); err == nil {
+		t.Fatal("expected error for update without fields")
+	}
+	if err := (&UpdateCouponRequest{Id: 4, HasStatus: true, Status: 5}).Validate(); err == nil {
+		t.Fatal("expected error for unknown status")
+	}
+	if err := (&UpdateCouponRequest{Id: 4, HasExpiresAt: true}).Validate(); err != nil {
+		t.Fatalf("expected clearing the expiry to be valid, got %v", err)
+	}
 }
@@ -0,0 +1,21 @@
+package webhooksig
+
+import "testing"
+
+func TestSign(t *testing.T) {
+	got := Sign("secret", 1700000000, []byte(`{"id":1}`))
+	if got != "sha256=3dd1b9aef568d75f6790a84bd2e5dfa1f44409eef3cbdbd3f10b837376100c11" {
+		t.Fatalf("unexpected signature %q", got)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":1}`)
+	signature := Sign("secret", 1700000000, body)
+	if !Verify("secret", 1700000000, body, signature) {
+		t.Fatal("expected the signature to verify")
+	}
+	if Verify("other", 1700000000, body, signature) || Verify("secret", 1700000001, body, signature) || Verify("secret", 1700000000, []byte(`{"id":2}`), signature) {
+		t.Fatal("expected a different secret, timestamp or body to fail verification")
+	}
+}
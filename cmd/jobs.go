@@ -3,14 +3,12 @@ package cmd
 import (
 	"context"
 	"database/sql"
-	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
-	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/service"
 	"github.com/vibast-solutions/ms-go-subscriptions/config"
@@ -29,13 +27,27 @@ var renewCmd = &cobra.Command{
 		runCommand(
 			"renew",
 			func(cfg *config.Config) time.Duration { return cfg.Jobs.AutoRenewInterval },
-			func(s *service.SubscriptionService, ctx context.Context) error {
+			func(s *service.SubscriptionService, ctx context.Context) (service.BatchResult, error) {
 				return s.RunAutoRenewalBatch(ctx)
 			},
 		)
 	},
 }
 
+var resumeCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "Resume paused subscriptions whose resume date has come",
+	Run: func(_ *cobra.Command, _ []string) {
+		runCommand(
+			"resume",
+			func(cfg *config.Config) time.Duration { return cfg.Jobs.AutoResumeInterval },
+			func(s *service.SubscriptionService, ctx context.Context) (service.BatchResult, error) {
+				return s.RunAutoResumeBatch(ctx)
+			},
+		)
+	},
+}
+
 var cancelCmd = &cobra.Command{
 	Use:   "cancel",
 	Short: "Run cancellation-related processing commands",
@@ -48,7 +60,7 @@ var cancelPendingPaymentCmd = &cobra.Command{
 		runCommand(
 			"cancel_pending_payment",
 			func(cfg *config.Config) time.Duration { return cfg.Jobs.PendingCleanupInterval },
-			func(s *service.SubscriptionService, ctx context.Context) error {
+			func(s *service.SubscriptionService, ctx context.Context) (service.BatchResult, error) {
 				return s.RunPendingPaymentCleanupBatch(ctx)
 			},
 		)
@@ -62,7 +74,7 @@ var cancelExpiredCmd = &cobra.Command{
 		runCommand(
 			"cancel_expired",
 			func(cfg *config.Config) time.Duration { return cfg.Jobs.ExpirationCheckInterval },
-			func(s *service.SubscriptionService, ctx context.Context) error {
+			func(s *service.SubscriptionService, ctx context.Context) (service.BatchResult, error) {
 				return s.RunExpirationBatch(ctx)
 			},
 		)
@@ -71,6 +83,7 @@ var cancelExpiredCmd = &cobra.Command{
 
 func init() {
 	rootCmd.AddCommand(renewCmd)
+	rootCmd.AddCommand(resumeCmd)
 	rootCmd.AddCommand(cancelCmd)
 	cancelCmd.AddCommand(cancelPendingPaymentCmd)
 	cancelCmd.AddCommand(cancelExpiredCmd)
@@ -81,26 +94,27 @@ func init() {
 func runCommand(
 	name string,
 	intervalResolver func(cfg *config.Config) time.Duration,
-	fn func(s *service.SubscriptionService, ctx context.Context) error,
+	fn func(s *service.SubscriptionService, ctx context.Context) (service.BatchResult, error),
 ) {
 	cfg, subscriptionService, cleanup := mustCreateSubscriptionService()
 	defer cleanup()
 
+	run := batchJob(name, func(ctx context.Context) (service.BatchResult, error) {
+		return fn(subscriptionService, ctx)
+	})
 	if workerMode {
-		runWorker(name, intervalResolver(cfg), subscriptionService, fn)
+		runWorker(name, intervalResolver(cfg), run)
 		return
 	}
 
-	ctx := context.Background()
-	runJob(name, func() error { return fn(subscriptionService, ctx) })
+	ctx, stop := shutdownContext(jobContext(context.Background(), name))
+	defer stop()
+	runJob(name, func() error { return run(ctx) })
 }
 
-func runWorker(
-	name string,
-	interval time.Duration,
-	subscriptionService *service.SubscriptionService,
-	fn func(s *service.SubscriptionService, ctx context.Context) error,
-) {
+// runWorker runs fn every interval until SIGINT or SIGTERM. The signal cancels
+// the context of a run under way, so it can wind down before the worker exits.
+func runWorker(name string, interval time.Duration, fn func(ctx context.Context) error) {
 	if interval <= 0 {
 		logrus.WithField("job", name).Fatal("invalid worker interval")
 	}
@@ -108,25 +122,70 @@ func runWorker(
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	ctx, stop := shutdownContext(jobContext(context.Background(), name))
+	defer stop()
 
-	runJob(name, func() error { return fn(subscriptionService, ctx) })
+	runJob(name, func() error { return fn(ctx) })
 
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	for {
 		select {
-		case <-quit:
+		case <-ctx.Done():
 			logrus.WithField("job", name).Info("Worker shutdown requested")
 			return
 		case <-ticker.C:
-			runJob(name, func() error { return fn(subscriptionService, ctx) })
+			runJob(name, func() error { return fn(ctx) })
 		}
 	}
 }
 
+// shutdownContext is canceled on SIGINT or SIGTERM.
+func shutdownContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+}
+
+// finishOnShutdown lets a batch under way run to its end after a shutdown
+// signal, for jobs that do not check for cancellation between items.
+func finishOnShutdown(fn func(ctx context.Context) error) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		return fn(context.WithoutCancel(ctx))
+	}
+}
+
+// batchJob runs one batch of a subscription job and logs what it did.
+func batchJob(name string, fn func(ctx context.Context) (service.BatchResult, error)) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		result, err := fn(ctx)
+		logrus.WithField("job", name).
+			WithField("processed", result.Processed).
+			WithField("failed", result.Failed).
+			WithField("skipped", result.Skipped).
+			Info("job_batch_result")
+		return err
+	}
+}
+
 func mustCreateSubscriptionService() (*config.Config, *service.SubscriptionService, func()) {
+	cfg, db := mustOpenDatabase()
+	return cfg, newSubscriptionService(cfg, db), closeDatabase(db)
+}
+
+func newSubscriptionService(cfg *config.Config, db *sql.DB) *service.SubscriptionService {
+	return service.NewSubscriptionService(
+		repository.NewSubscriptionRepository(db),
+		repository.NewSubscriptionTypeRepository(db),
+		repository.NewPlanTypeRepository(db),
+		repository.NewPaymentAttemptRepository(db),
+		repository.NewSubscriptionEventRepository(db),
+		repository.NewOutboxMessageRepository(db),
+		repository.NewCouponRepository(db),
+		repository.NewCouponRedemptionRepository(db),
+		repository.NewTxManager(db),
+		newPaymentService(cfg),
+		cfg.Subscriptions,
+	)
+}
+
+func mustOpenDatabase() (*config.Config, *sql.DB) {
 	cfg, err := config.Load()
 	if err != nil {
 		logrus.WithError(err).Fatal("Failed to load configuration")
@@ -149,24 +208,20 @@ func mustCreateSubscriptionService() (*config.Config, *service.SubscriptionServi
 		logrus.WithError(err).Fatal("Failed to ping database")
 	}
 
-	subscriptionRepo := repository.NewSubscriptionRepository(db)
-	subscriptionTypeRepo := repository.NewSubscriptionTypeRepository(db)
-	planTypeRepo := repository.NewPlanTypeRepository(db)
-	subscriptionService := service.NewSubscriptionService(
-		subscriptionRepo,
-		subscriptionTypeRepo,
-		planTypeRepo,
-		payment.NewStubService(),
-		cfg.Subscriptions,
-	)
+	return cfg, db
+}
 
-	cleanup := func() {
+func closeDatabase(db *sql.DB) func() {
+	return func() {
 		if err := db.Close(); err != nil {
 			logrus.WithError(err).Warn("Failed to close database")
 		}
 	}
+}
 
-	return cfg, subscriptionService, cleanup
+// jobContext attributes subscription changes made by the job to its name.
+func jobContext(ctx context.Context, name string) context.Context {
+	return service.WithActor(ctx, service.Actor{Name: "job:" + name})
 }
 
 func runJob(name string, fn func() error) {
@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"github.com/sirupsen/logrus"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+func newPaymentService(cfg *config.Config) payment.Service {
+	switch cfg.Payment.Provider {
+	case config.PaymentProviderHTTP:
+		logrus.WithField("base_url", cfg.Payment.HTTPBaseURL).Info("Using HTTP payment provider")
+		return payment.NewHTTPService(cfg.Payment)
+	default:
+		logrus.Warn("Using stub payment provider; plan payments will fail")
+		return payment.NewStubService()
+	}
+}
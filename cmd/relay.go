@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/publisher"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/service"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+var relayCmd = &cobra.Command{
+	Use:   "relay",
+	Short: "Publish queued subscription domain events",
+	Run: func(_ *cobra.Command, _ []string) {
+		cfg, db := mustOpenDatabase()
+		defer closeDatabase(db)()
+
+		relayService := newRelayService(cfg, db)
+
+		if workerMode {
+			runWorker("relay", cfg.Jobs.OutboxRelayInterval, finishOnShutdown(relayService.RunRelayBatch))
+			return
+		}
+
+		ctx := jobContext(context.Background(), "relay")
+		runJob("relay", func() error { return relayService.RunRelayBatch(ctx) })
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(relayCmd)
+}
+
+func newRelayService(cfg *config.Config, db *sql.DB) *service.OutboxRelayService {
+	return service.NewOutboxRelayService(
+		repository.NewOutboxMessageRepository(db),
+		publisher.Multi{newPublisher(cfg), newWebhookService(cfg, db)},
+		cfg.Outbox,
+	)
+}
+
+func newPublisher(cfg *config.Config) publisher.Publisher {
+	switch cfg.Outbox.Publisher {
+	case config.OutboxPublisherHTTP:
+		logrus.WithField("url", cfg.Outbox.HTTPURL).Info("Using HTTP event publisher")
+		return publisher.NewHTTPPublisher(cfg.Outbox)
+	default:
+		logrus.Info("Using log event publisher")
+		return publisher.NewLogPublisher()
+	}
+}
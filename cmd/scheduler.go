@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/cobra"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/scheduler"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+var schedulerCmd = &cobra.Command{
+	Use:   "scheduler",
+	Short: "Run every background job on its schedule in one process",
+	Long: "Run the renewal, resume, cancellation, relay and webhook delivery jobs on their configured " +
+		"intervals or cron expressions. Of several replicas, only the one holding the MySQL leader lock schedules.",
+	Run: func(_ *cobra.Command, _ []string) {
+		cfg, db := mustOpenDatabase()
+		defer closeDatabase(db)()
+
+		ctx, stop := shutdownContext(context.Background())
+		defer stop()
+
+		logrus.Info("Starting scheduler")
+		mustCreateScheduler(cfg, db).Run(ctx)
+		logrus.Info("Scheduler stopped")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(schedulerCmd)
+}
+
+func mustCreateScheduler(cfg *config.Config, db *sql.DB) *scheduler.Scheduler {
+	subscriptionService := newSubscriptionService(cfg, db)
+	relayService := newRelayService(cfg, db)
+	webhookService := newWebhookService(cfg, db)
+
+	jobs := []scheduler.Job{
+		scheduledJob("renew", mustSchedule("renew", cfg.Jobs.AutoRenewInterval, cfg.Jobs.AutoRenewCron),
+			batchJob("renew", subscriptionService.RunAutoRenewalBatch)),
+		scheduledJob("resume", mustSchedule("resume", cfg.Jobs.AutoResumeInterval, cfg.Jobs.AutoResumeCron),
+			batchJob("resume", subscriptionService.RunAutoResumeBatch)),
+		scheduledJob("cancel_pending_payment", mustSchedule("cancel_pending_payment", cfg.Jobs.PendingCleanupInterval, cfg.Jobs.PendingCleanupCron),
+			batchJob("cancel_pending_payment", subscriptionService.RunPendingPaymentCleanupBatch)),
+		scheduledJob("cancel_expired", mustSchedule("cancel_expired", cfg.Jobs.ExpirationCheckInterval, cfg.Jobs.ExpirationCheckCron),
+			batchJob("cancel_expired", subscriptionService.RunExpirationBatch)),
+		scheduledJob("relay", mustSchedule("relay", cfg.Jobs.OutboxRelayInterval, ""),
+			finishOnShutdown(relayService.RunRelayBatch)),
+		scheduledJob("webhooks_deliver", mustSchedule("webhooks_deliver", cfg.Jobs.WebhookDeliveryInterval, ""),
+			finishOnShutdown(webhookService.RunDeliveryBatch)),
+	}
+
+	return scheduler.NewScheduler(
+		repository.NewLeaderLock(db, cfg.Jobs.SchedulerLockName),
+		jobs,
+		cfg.Jobs.SchedulerJitter,
+		cfg.Jobs.SchedulerLeaderCheckInterval,
+	)
+}
+
+// scheduledJob runs fn as job name, logged and attributed like the job commands.
+func scheduledJob(name string, schedule scheduler.Schedule, fn func(ctx context.Context) error) scheduler.Job {
+	return scheduler.Job{
+		Name:     name,
+		Schedule: schedule,
+		Run: func(ctx context.Context) {
+			runJob(name, func() error { return fn(jobContext(ctx, name)) })
+		},
+	}
+}
+
+func mustSchedule(name string, interval time.Duration, cron string) scheduler.Schedule {
+	schedule, err := scheduler.ScheduleFor(interval, cron)
+	if err != nil {
+		logrus.WithError(err).WithField("job", name).Fatal("Invalid job schedule")
+	}
+	return schedule
+}
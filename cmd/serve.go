@@ -17,7 +17,7 @@ import (
 	authlibservice "github.com/vibast-solutions/lib-go-auth/service"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/controller"
 	grpcserver "github.com/vibast-solutions/ms-go-subscriptions/app/grpc"
-	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
+	appmiddleware "github.com/vibast-solutions/ms-go-subscriptions/app/middleware"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/service"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
@@ -38,8 +38,12 @@ var serveCmd = &cobra.Command{
 	Run:   runServe,
 }
 
+var serveWithScheduler bool
+
 func init() {
 	rootCmd.AddCommand(serveCmd)
+
+	serveCmd.Flags().BoolVar(&serveWithScheduler, "scheduler", false, "Also run the job scheduler in this process")
 }
 
 func runServe(_ *cobra.Command, _ []string) {
@@ -68,11 +72,21 @@ func runServe(_ *cobra.Command, _ []string) {
 	subscriptionRepo := repository.NewSubscriptionRepository(db)
 	subscriptionTypeRepo := repository.NewSubscriptionTypeRepository(db)
 	planTypeRepo := repository.NewPlanTypeRepository(db)
-	paymentService := payment.NewStubService()
-	subscriptionService := service.NewSubscriptionService(subscriptionRepo, subscriptionTypeRepo, planTypeRepo, paymentService, cfg.Subscriptions)
-	paymentCallbackService := service.NewPaymentCallbackService(subscriptionRepo, cfg.Subscriptions)
-	grpcSubscriptionServer := grpcserver.NewServer(subscriptionService, paymentCallbackService)
+	paymentAttemptRepo := repository.NewPaymentAttemptRepository(db)
+	subscriptionEventRepo := repository.NewSubscriptionEventRepository(db)
+	outboxMessageRepo := repository.NewOutboxMessageRepository(db)
+	couponRepo := repository.NewCouponRepository(db)
+	couponRedemptionRepo := repository.NewCouponRedemptionRepository(db)
+	txManager := repository.NewTxManager(db)
+	paymentService := newPaymentService(cfg)
+	subscriptionService := service.NewSubscriptionService(subscriptionRepo, subscriptionTypeRepo, planTypeRepo, paymentAttemptRepo, subscriptionEventRepo, outboxMessageRepo, couponRepo, couponRedemptionRepo, txManager, paymentService, cfg.Subscriptions)
+	paymentCallbackService := service.NewPaymentCallbackService(subscriptionRepo, planTypeRepo, paymentAttemptRepo, subscriptionEventRepo, outboxMessageRepo, couponRedemptionRepo, txManager, cfg.Subscriptions)
+	webhookService := newWebhookService(cfg, db)
+	catalogService := service.NewCatalogService(subscriptionTypeRepo, planTypeRepo, couponRepo, couponRedemptionRepo)
+	grpcSubscriptionServer := grpcserver.NewServer(subscriptionService, paymentCallbackService, webhookService, catalogService)
 	subscriptionController := controller.NewSubscriptionController(subscriptionService, paymentCallbackService)
+	webhookController := controller.NewWebhookController(webhookService)
+	catalogController := controller.NewCatalogController(catalogService)
 
 	authGRPCClient, err := authclient.NewGRPCClientFromAddr(context.Background(), cfg.InternalEndpoints.AuthGRPCAddr)
 	if err != nil {
@@ -83,8 +97,11 @@ func runServe(_ *cobra.Command, _ []string) {
 	echoInternalAuthMiddleware := authmiddleware.NewEchoInternalAuthMiddleware(internalAuthService)
 	grpcInternalAuthMiddleware := authmiddleware.NewGRPCInternalAuthMiddleware(internalAuthService)
 
-	e := setupHTTPServer(subscriptionController, echoInternalAuthMiddleware, cfg.App.ServiceName)
-	grpcSrv, lis := setupGRPCServer(cfg, grpcSubscriptionServer, grpcInternalAuthMiddleware, cfg.App.ServiceName)
+	webhookSignatureMiddleware := appmiddleware.NewWebhookSignatureMiddleware(cfg.Payment)
+	adminAccess := appmiddleware.NewAdminAccess(cfg.App.AdminServices)
+
+	e := setupHTTPServer(subscriptionController, webhookController, catalogController, echoInternalAuthMiddleware, webhookSignatureMiddleware, adminAccess, cfg.App.ServiceName)
+	grpcSrv, lis := setupGRPCServer(cfg, grpcSubscriptionServer, grpcInternalAuthMiddleware, adminAccess, cfg.App.ServiceName)
 
 	go func() {
 		httpAddr := net.JoinHostPort(cfg.HTTP.Host, cfg.HTTP.Port)
@@ -101,10 +118,24 @@ func runServe(_ *cobra.Command, _ []string) {
 		}
 	}()
 
+	schedulerCtx, stopScheduler := context.WithCancel(context.Background())
+	schedulerDone := make(chan struct{})
+	if serveWithScheduler {
+		jobScheduler := mustCreateScheduler(cfg, db)
+		go func() {
+			defer close(schedulerDone)
+			logrus.Info("Starting scheduler")
+			jobScheduler.Run(schedulerCtx)
+		}()
+	} else {
+		close(schedulerDone)
+	}
+
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 	logrus.Info("Shutting down...")
+	stopScheduler()
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -113,13 +144,19 @@ func runServe(_ *cobra.Command, _ []string) {
 		logrus.WithError(err).Warn("HTTP shutdown error")
 	}
 	grpcSrv.GracefulStop()
+	// Scheduled runs under way finish before the database is closed.
+	<-schedulerDone
 
 	logrus.Info("Server stopped")
 }
 
 func setupHTTPServer(
 	subscriptionController *controller.SubscriptionController,
+	webhookController *controller.WebhookController,
+	catalogController *controller.CatalogController,
 	internalAuthMiddleware *authmiddleware.EchoInternalAuthMiddleware,
+	webhookSignatureMiddleware *appmiddleware.WebhookSignatureMiddleware,
+	adminAccess *appmiddleware.AdminAccess,
 	appServiceName string,
 ) *echo.Echo {
 	e := echo.New()
@@ -161,21 +198,56 @@ func setupHTTPServer(
 			return fmt.Sprintf("rest-%s", uuid.New().String())
 		},
 	}))
-	e.Use(internalAuthMiddleware.RequireInternalAccess(appServiceName))
+	internalAccess := internalAuthMiddleware.RequireInternalAccess(appServiceName)
 
-	e.GET("/health", subscriptionController.Health)
+	api := e.Group("", internalAccess)
+	api.GET("/health", subscriptionController.Health)
 
-	e.GET("/subscription-types", subscriptionController.ListSubscriptionTypes)
+	api.GET("/subscription-types", subscriptionController.ListSubscriptionTypes)
+	api.GET("/plan-types", subscriptionController.ListPlanTypes)
+	api.GET("/plan-types/:id", subscriptionController.GetPlanType)
 
-	subscriptions := e.Group("/subscriptions")
+	subscriptions := api.Group("/subscriptions")
 	subscriptions.POST("", subscriptionController.CreateSubscription)
 	subscriptions.GET("", subscriptionController.ListSubscriptions)
 	subscriptions.GET("/:id", subscriptionController.GetSubscription)
 	subscriptions.PATCH("/:id", subscriptionController.UpdateSubscription)
 	subscriptions.DELETE("/:id", subscriptionController.DeleteSubscription)
 	subscriptions.POST("/:id/cancel", subscriptionController.CancelSubscription)
+	subscriptions.POST("/:id/undo-cancellation", subscriptionController.UndoCancellation)
+	subscriptions.POST("/:id/pause", subscriptionController.PauseSubscription)
+	subscriptions.POST("/:id/resume", subscriptionController.ResumeSubscription)
+	subscriptions.POST("/:id/change-plan", subscriptionController.ChangePlan)
+	subscriptions.POST("/:id/update-quantity", subscriptionController.UpdateQuantity)
+	subscriptions.GET("/:id/payment-attempts", subscriptionController.ListPaymentAttempts)
+	subscriptions.GET("/:id/events", subscriptionController.ListSubscriptionEvents)
+
+	// Registering, changing and removing endpoints decides where event data is
+	// sent, so it takes admin access; internal callers can still read them.
+	webhookEndpoints := api.Group("/webhook-endpoints")
+	webhookEndpoints.POST("", webhookController.CreateWebhookEndpoint, adminAccess.RequireAdmin)
+	webhookEndpoints.GET("", webhookController.ListWebhookEndpoints)
+	webhookEndpoints.GET("/:id", webhookController.GetWebhookEndpoint)
+	webhookEndpoints.PATCH("/:id", webhookController.UpdateWebhookEndpoint, adminAccess.RequireAdmin)
+	webhookEndpoints.DELETE("/:id", webhookController.DeleteWebhookEndpoint, adminAccess.RequireAdmin)
+	webhookEndpoints.GET("/:id/deliveries", webhookController.ListWebhookDeliveries)
+
+	admin := api.Group("/admin", adminAccess.RequireAdmin)
+	admin.POST("/subscription-types", catalogController.CreateSubscriptionType)
+	admin.PATCH("/subscription-types/:id", catalogController.UpdateSubscriptionType)
+	admin.POST("/subscription-types/:id/archive", catalogController.ArchiveSubscriptionType)
+	admin.POST("/plan-types", catalogController.CreatePlanType)
+	admin.PATCH("/plan-types/:id", catalogController.UpdatePlanType)
+	admin.POST("/plan-types/:id/archive", catalogController.ArchivePlanType)
+	admin.POST("/coupons", catalogController.CreateCoupon)
+	admin.GET("/coupons", catalogController.ListCoupons)
+	admin.GET("/coupons/:id", catalogController.GetCoupon)
+	admin.PATCH("/coupons/:id", catalogController.UpdateCoupon)
+	admin.GET("/coupons/:id/redemptions", catalogController.ListCouponRedemptions)
 
-	webhooks := e.Group("/webhooks")
+	// Payment providers cannot present an internal API key, so webhooks accept a
+	// provider signature instead and fall back to internal auth when unsigned.
+	webhooks := e.Group("/webhooks", webhookSignatureMiddleware.RequireSignatureOr(internalAccess))
 	webhooks.POST("/payment-callback", subscriptionController.PaymentCallback)
 
 	return e
@@ -185,6 +257,7 @@ func setupGRPCServer(
 	cfg *config.Config,
 	subscriptionServer *grpcserver.Server,
 	internalAuthMiddleware *authmiddleware.GRPCInternalAuthMiddleware,
+	adminAccess *appmiddleware.AdminAccess,
 	appServiceName string,
 ) (*grpc.Server, net.Listener) {
 	grpcAddr := net.JoinHostPort(cfg.GRPC.Host, cfg.GRPC.Port)
@@ -199,6 +272,7 @@ func setupGRPCServer(
 			grpcserver.RequestIDInterceptor(),
 			grpcserver.LoggingInterceptor(),
 			internalAuthMiddleware.UnaryRequireInternalAccess(appServiceName),
+			grpcserver.AdminAccessInterceptor(adminAccess, grpcserver.AdminMethods...),
 		),
 	)
 	types.RegisterSubscriptionsServiceServer(grpcSrv, subscriptionServer)
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/spf13/cobra"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/publisher"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/repository"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/service"
+	"github.com/vibast-solutions/ms-go-subscriptions/config"
+)
+
+var webhooksCmd = &cobra.Command{
+	Use:   "webhooks",
+	Short: "Run outgoing webhook processing commands",
+}
+
+var webhooksDeliverCmd = &cobra.Command{
+	Use:   "deliver",
+	Short: "Deliver queued events to registered webhook endpoints",
+	Run: func(_ *cobra.Command, _ []string) {
+		cfg, db := mustOpenDatabase()
+		defer closeDatabase(db)()
+
+		webhookService := newWebhookService(cfg, db)
+
+		if workerMode {
+			runWorker("webhooks_deliver", cfg.Jobs.WebhookDeliveryInterval, finishOnShutdown(webhookService.RunDeliveryBatch))
+			return
+		}
+
+		ctx := jobContext(context.Background(), "webhooks_deliver")
+		runJob("webhooks_deliver", func() error { return webhookService.RunDeliveryBatch(ctx) })
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(webhooksCmd)
+	webhooksCmd.AddCommand(webhooksDeliverCmd)
+}
+
+func newWebhookService(cfg *config.Config, db *sql.DB) *service.WebhookService {
+	return service.NewWebhookService(
+		repository.NewWebhookEndpointRepository(db),
+		repository.NewWebhookDeliveryRepository(db),
+		publisher.NewWebhookSender(cfg.Webhooks),
+		cfg.Webhooks,
+	)
+}
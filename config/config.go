@@ -2,9 +2,13 @@ package config
 
 import (
 	"errors"
+	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
+	// Embedded so BILLING_TIMEZONE resolves on images without zoneinfo.
+	_ "time/tzdata"
 
 	"github.com/joho/godotenv"
 )
@@ -18,11 +22,15 @@ type Config struct {
 	InternalEndpoints InternalEndpointsConfig
 	Subscriptions     SubscriptionConfig
 	Jobs              JobsConfig
+	Payment           PaymentConfig
+	Outbox            OutboxConfig
+	Webhooks          WebhookConfig
 }
 
 type AppConfig struct {
-	ServiceName string
-	APIKey      string
+	ServiceName   string
+	APIKey        string
+	AdminServices []string
 }
 
 type ServerConfig struct {
@@ -48,14 +56,105 @@ type InternalEndpointsConfig struct {
 type SubscriptionConfig struct {
 	RenewBeforeEndMinutes       time.Duration
 	RenewalRetryIntervalMinutes time.Duration
-	MaxRenewalRetryAgeMinutes   time.Duration
-	PendingPaymentTimeout       time.Duration
+	// DunningSchedule lists when failed renewals are retried, as offsets from
+	// end_at. The subscription is inactivated once the last retry fails.
+	DunningSchedule       []time.Duration
+	PendingPaymentTimeout time.Duration
+	// BillingLocation is the time zone billing periods are counted in, so that
+	// day, month and year boundaries follow its calendar and DST changes.
+	BillingLocation *time.Location
+	// WorkerID names this process in the leases batch jobs take on the rows they
+	// process. A lease lapses after ClaimTTL, so the rows of a worker that died
+	// mid-batch are picked up again; one batch leases at most ClaimBatchSize rows,
+	// and the renewal job fewer when it could not charge them within ClaimTTL.
+	WorkerID       string
+	ClaimTTL       time.Duration
+	ClaimBatchSize int
+	// BatchMaxPerRun caps how many subscriptions one job run goes through, batch
+	// after batch; the rest wait for the next run.
+	BatchMaxPerRun int
+	// RenewalConcurrency is how many subscriptions the renewal job charges at
+	// once. Each charge gets RenewalChargeTimeout, and the process starts at most
+	// PaymentRateLimit charges per second; zero leaves the rate unlimited.
+	RenewalConcurrency   int
+	RenewalChargeTimeout time.Duration
+	PaymentRateLimit     int
 }
 
 type JobsConfig struct {
 	AutoRenewInterval       time.Duration
 	PendingCleanupInterval  time.Duration
 	ExpirationCheckInterval time.Duration
+	AutoResumeInterval      time.Duration
+	OutboxRelayInterval     time.Duration
+	WebhookDeliveryInterval time.Duration
+	// The cron expressions replace the interval of their job in the scheduler
+	// when set.
+	AutoRenewCron       string
+	PendingCleanupCron  string
+	ExpirationCheckCron string
+	AutoResumeCron      string
+	// SchedulerJitter delays every scheduled run by up to its value.
+	// SchedulerLockName is the MySQL lock the scheduler replicas elect a leader
+	// with; the leader confirms it holds the lock, and the others try to take
+	// it, every SchedulerLeaderCheckInterval.
+	SchedulerJitter              time.Duration
+	SchedulerLockName            string
+	SchedulerLeaderCheckInterval time.Duration
+}
+
+const (
+	PaymentProviderStub = "stub"
+	PaymentProviderHTTP = "http"
+)
+
+type PaymentConfig struct {
+	Provider          string
+	HTTPBaseURL       string
+	HTTPAPIKey        string
+	HTTPTimeout       time.Duration
+	HTTPMaxRetries    int
+	HTTPRetryBackoff  time.Duration
+	CheckoutReturnURL string
+	// WebhookSecrets maps a provider name (X-Webhook-Provider) to the shared
+	// secret used to sign its payment callbacks.
+	WebhookSecrets   map[string]string
+	WebhookTolerance time.Duration
+}
+
+const (
+	OutboxPublisherLog  = "log"
+	OutboxPublisherHTTP = "http"
+)
+
+type OutboxConfig struct {
+	Publisher    string
+	HTTPURL      string
+	HTTPAPIKey   string
+	HTTPTimeout  time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	// WorkerID and ClaimTTL lease the messages a relay run publishes, as they
+	// do the rows of the subscription batch jobs.
+	WorkerID string
+	ClaimTTL time.Duration
+}
+
+type WebhookConfig struct {
+	Timeout      time.Duration
+	BatchSize    int
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	// AllowInsecureURLs accepts plain http:// endpoints; meant for local development.
+	AllowInsecureURLs bool
+	// AllowPrivateURLs accepts endpoints on loopback, private and link-local
+	// addresses; meant for local development.
+	AllowPrivateURLs bool
+	// WorkerID and ClaimTTL lease the deliveries a delivery run sends, as they
+	// do the messages of the relay.
+	WorkerID string
+	ClaimTTL time.Duration
 }
 
 func Load() (*Config, error) {
@@ -66,10 +165,119 @@ func Load() (*Config, error) {
 		return nil, errors.New("MYSQL_DSN environment variable is required")
 	}
 
+	paymentCfg := PaymentConfig{
+		Provider:          strings.ToLower(strings.TrimSpace(getEnv("PAYMENT_PROVIDER", PaymentProviderStub))),
+		HTTPBaseURL:       strings.TrimRight(strings.TrimSpace(getEnv("PAYMENT_HTTP_BASE_URL", "")), "/"),
+		HTTPAPIKey:        getEnv("PAYMENT_HTTP_API_KEY", ""),
+		HTTPTimeout:       getDurationSecondsEnv("PAYMENT_HTTP_TIMEOUT_SECONDS", 10*time.Second),
+		HTTPMaxRetries:    getIntEnv("PAYMENT_HTTP_MAX_RETRIES", 2),
+		HTTPRetryBackoff:  getDurationMillisecondsEnv("PAYMENT_HTTP_RETRY_BACKOFF_MS", 500*time.Millisecond),
+		CheckoutReturnURL: getEnv("PAYMENT_CHECKOUT_RETURN_URL", ""),
+		WebhookTolerance:  getDurationSecondsEnv("PAYMENT_WEBHOOK_TOLERANCE_SECONDS", 5*time.Minute),
+	}
+	webhookSecrets, err := parseKeyValueList(getEnv("PAYMENT_WEBHOOK_SECRETS", ""))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PAYMENT_WEBHOOK_SECRETS: %w", err)
+	}
+	paymentCfg.WebhookSecrets = webhookSecrets
+	switch paymentCfg.Provider {
+	case PaymentProviderStub:
+	case PaymentProviderHTTP:
+		if paymentCfg.HTTPBaseURL == "" {
+			return nil, errors.New("PAYMENT_HTTP_BASE_URL is required when PAYMENT_PROVIDER=http")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported PAYMENT_PROVIDER %q", paymentCfg.Provider)
+	}
+
+	workerID := getEnv("WORKER_ID", defaultWorkerID())
+	claimTTL := getDurationEnv("CLAIM_TTL_MINUTES", 15*time.Minute)
+
+	outboxCfg := OutboxConfig{
+		Publisher:    strings.ToLower(strings.TrimSpace(getEnv("OUTBOX_PUBLISHER", OutboxPublisherLog))),
+		HTTPURL:      strings.TrimSpace(getEnv("OUTBOX_HTTP_URL", "")),
+		HTTPAPIKey:   getEnv("OUTBOX_HTTP_API_KEY", ""),
+		HTTPTimeout:  getDurationSecondsEnv("OUTBOX_HTTP_TIMEOUT_SECONDS", 10*time.Second),
+		BatchSize:    getIntEnv("OUTBOX_BATCH_SIZE", 100),
+		MaxAttempts:  getIntEnv("OUTBOX_MAX_ATTEMPTS", 10),
+		RetryBackoff: getDurationSecondsEnv("OUTBOX_RETRY_BACKOFF_SECONDS", 30*time.Second),
+		WorkerID:     workerID,
+		ClaimTTL:     claimTTL,
+	}
+	switch outboxCfg.Publisher {
+	case OutboxPublisherLog:
+	case OutboxPublisherHTTP:
+		if outboxCfg.HTTPURL == "" {
+			return nil, errors.New("OUTBOX_HTTP_URL is required when OUTBOX_PUBLISHER=http")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported OUTBOX_PUBLISHER %q", outboxCfg.Publisher)
+	}
+	if outboxCfg.BatchSize <= 0 || outboxCfg.MaxAttempts <= 0 {
+		return nil, errors.New("OUTBOX_BATCH_SIZE and OUTBOX_MAX_ATTEMPTS must be positive")
+	}
+
+	webhookCfg := WebhookConfig{
+		Timeout:           getDurationSecondsEnv("WEBHOOK_TIMEOUT_SECONDS", 10*time.Second),
+		BatchSize:         getIntEnv("WEBHOOK_BATCH_SIZE", 100),
+		MaxAttempts:       getIntEnv("WEBHOOK_MAX_ATTEMPTS", 8),
+		RetryBackoff:      getDurationSecondsEnv("WEBHOOK_RETRY_BACKOFF_SECONDS", 30*time.Second),
+		AllowInsecureURLs: getBoolEnv("WEBHOOK_ALLOW_INSECURE_URLS", false),
+		AllowPrivateURLs:  getBoolEnv("WEBHOOK_ALLOW_PRIVATE_URLS", false),
+		WorkerID:          workerID,
+		ClaimTTL:          claimTTL,
+	}
+	if webhookCfg.BatchSize <= 0 || webhookCfg.MaxAttempts <= 0 {
+		return nil, errors.New("WEBHOOK_BATCH_SIZE and WEBHOOK_MAX_ATTEMPTS must be positive")
+	}
+
+	billingLocation, err := time.LoadLocation(getEnv("BILLING_TIMEZONE", "UTC"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BILLING_TIMEZONE: %w", err)
+	}
+	dunningSchedule, err := parseMinutesList(getEnv("DUNNING_SCHEDULE_MINUTES", "1440,4320,10080"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid DUNNING_SCHEDULE_MINUTES: %w", err)
+	}
+
+	subscriptionsCfg := SubscriptionConfig{
+		RenewBeforeEndMinutes:       getDurationEnv("RENEW_BEFORE_END_MINUTES", 1440*time.Minute),
+		RenewalRetryIntervalMinutes: getDurationEnv("RENEWAL_RETRY_INTERVAL_MINUTES", 60*time.Minute),
+		DunningSchedule:             dunningSchedule,
+		BillingLocation:             billingLocation,
+		PendingPaymentTimeout:       getDurationEnv("PENDING_PAYMENT_TIMEOUT_MINUTES", 30*time.Minute),
+		WorkerID:                    workerID,
+		ClaimTTL:                    claimTTL,
+		ClaimBatchSize:              getIntEnv("CLAIM_BATCH_SIZE", 100),
+		BatchMaxPerRun:              getIntEnv("BATCH_MAX_PER_RUN", 1000),
+		RenewalConcurrency:          getIntEnv("RENEWAL_CONCURRENCY", 4),
+		RenewalChargeTimeout:        getDurationSecondsEnv("RENEWAL_CHARGE_TIMEOUT_SECONDS", 30*time.Second),
+		PaymentRateLimit:            getIntEnv("PAYMENT_RATE_LIMIT_PER_SECOND", 10),
+	}
+	if subscriptionsCfg.ClaimTTL <= 0 || subscriptionsCfg.ClaimBatchSize <= 0 {
+		return nil, errors.New("CLAIM_TTL_MINUTES and CLAIM_BATCH_SIZE must be positive")
+	}
+	if subscriptionsCfg.BatchMaxPerRun <= 0 {
+		return nil, errors.New("BATCH_MAX_PER_RUN must be positive")
+	}
+	if subscriptionsCfg.RenewalConcurrency <= 0 || subscriptionsCfg.RenewalChargeTimeout <= 0 {
+		return nil, errors.New("RENEWAL_CONCURRENCY and RENEWAL_CHARGE_TIMEOUT_SECONDS must be positive")
+	}
+	if subscriptionsCfg.PaymentRateLimit < 0 {
+		return nil, errors.New("PAYMENT_RATE_LIMIT_PER_SECOND must not be negative")
+	}
+
+	schedulerJitter := getDurationSecondsEnv("SCHEDULER_JITTER_SECONDS", 5*time.Second)
+	schedulerLeaderCheck := getDurationSecondsEnv("SCHEDULER_LEADER_CHECK_SECONDS", 15*time.Second)
+	if schedulerJitter < 0 || schedulerLeaderCheck <= 0 {
+		return nil, errors.New("SCHEDULER_JITTER_SECONDS must not be negative and SCHEDULER_LEADER_CHECK_SECONDS must be positive")
+	}
+
 	return &Config{
 		App: AppConfig{
-			ServiceName: getEnv("APP_SERVICE_NAME", "subscriptions-service"),
-			APIKey:      getEnv("APP_API_KEY", ""),
+			ServiceName:   getEnv("APP_SERVICE_NAME", "subscriptions-service"),
+			APIKey:        getEnv("APP_API_KEY", ""),
+			AdminServices: getListEnv("APP_ADMIN_SERVICES"),
 		},
 		HTTP: ServerConfig{
 			Host: getEnv("HTTP_HOST", "0.0.0.0"),
@@ -89,20 +297,38 @@ func Load() (*Config, error) {
 		InternalEndpoints: InternalEndpointsConfig{
 			AuthGRPCAddr: getEnv("AUTH_SERVICE_GRPC_ADDR", "localhost:9090"),
 		},
-		Subscriptions: SubscriptionConfig{
-			RenewBeforeEndMinutes:       getDurationEnv("RENEW_BEFORE_END_MINUTES", 1440*time.Minute),
-			RenewalRetryIntervalMinutes: getDurationEnv("RENEWAL_RETRY_INTERVAL_MINUTES", 60*time.Minute),
-			MaxRenewalRetryAgeMinutes:   getDurationEnv("MAX_RENEWAL_RETRY_AGE_MINUTES", 10080*time.Minute),
-			PendingPaymentTimeout:       getDurationEnv("PENDING_PAYMENT_TIMEOUT_MINUTES", 30*time.Minute),
-		},
+		Subscriptions: subscriptionsCfg,
 		Jobs: JobsConfig{
 			AutoRenewInterval:       getDurationEnv("AUTO_RENEW_INTERVAL_MINUTES", time.Minute),
 			PendingCleanupInterval:  getDurationEnv("PENDING_CLEANUP_INTERVAL_MINUTES", 10*time.Minute),
 			ExpirationCheckInterval: getDurationEnv("EXPIRATION_CHECK_INTERVAL_MINUTES", time.Hour),
+			AutoResumeInterval:      getDurationEnv("AUTO_RESUME_INTERVAL_MINUTES", 10*time.Minute),
+			OutboxRelayInterval:     getDurationSecondsEnv("OUTBOX_RELAY_INTERVAL_SECONDS", 5*time.Second),
+			WebhookDeliveryInterval: getDurationSecondsEnv("WEBHOOK_DELIVERY_INTERVAL_SECONDS", 5*time.Second),
+			AutoRenewCron:           getEnv("AUTO_RENEW_CRON", ""),
+			PendingCleanupCron:      getEnv("PENDING_CLEANUP_CRON", ""),
+			ExpirationCheckCron:     getEnv("EXPIRATION_CHECK_CRON", ""),
+			AutoResumeCron:          getEnv("AUTO_RESUME_CRON", ""),
+
+			SchedulerJitter:              schedulerJitter,
+			SchedulerLockName:            getEnv("SCHEDULER_LOCK_NAME", "subscriptions-scheduler"),
+			SchedulerLeaderCheckInterval: schedulerLeaderCheck,
 		},
+		Payment:  paymentCfg,
+		Outbox:   outboxCfg,
+		Webhooks: webhookCfg,
 	}, nil
 }
 
+// defaultWorkerID is the host name, which tells worker replicas apart in most
+// deployments.
+func defaultWorkerID() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return "worker"
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -119,6 +345,15 @@ func getIntEnv(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getBoolEnv(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if b, err := strconv.ParseBool(value); err == nil {
+			return b
+		}
+	}
+	return defaultValue
+}
+
 func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	if value := os.Getenv(key); value != "" {
 		if minutes, err := strconv.Atoi(value); err == nil {
@@ -127,3 +362,80 @@ func getDurationEnv(key string, defaultValue time.Duration) time.Duration {
 	}
 	return defaultValue
 }
+
+func getDurationSecondsEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if seconds, err := strconv.Atoi(value); err == nil {
+			return time.Duration(seconds) * time.Second
+		}
+	}
+	return defaultValue
+}
+
+// getListEnv splits a comma-separated variable, dropping empty entries.
+func getListEnv(key string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseKeyValueList parses "name=value,name2=value2" into a map. Names are
+// lower-cased; empty names, empty values and duplicates are rejected.
+func parseKeyValueList(raw string) (map[string]string, error) {
+	result := make(map[string]string)
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		name, value, ok := strings.Cut(item, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.TrimSpace(value)
+		if !ok || name == "" || value == "" {
+			return nil, fmt.Errorf("entry %q must be name=value", item)
+		}
+		if _, exists := result[name]; exists {
+			return nil, fmt.Errorf("duplicate entry %q", name)
+		}
+		result[name] = value
+	}
+	return result, nil
+}
+
+// parseMinutesList parses "1440,4320" into durations. Entries must be positive
+// and increasing, and at least one is required.
+func parseMinutesList(raw string) ([]time.Duration, error) {
+	result := make([]time.Duration, 0)
+	for _, item := range strings.Split(raw, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		minutes, err := strconv.Atoi(item)
+		if err != nil || minutes <= 0 {
+			return nil, fmt.Errorf("entry %q must be a positive number of minutes", item)
+		}
+		value := time.Duration(minutes) * time.Minute
+		if len(result) > 0 && value <= result[len(result)-1] {
+			return nil, fmt.Errorf("entry %q must be greater than the previous one", item)
+		}
+		result = append(result, value)
+	}
+	if len(result) == 0 {
+		return nil, errors.New("at least one entry is required")
+	}
+	return result, nil
+}
+
+func getDurationMillisecondsEnv(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if millis, err := strconv.Atoi(value); err == nil {
+			return time.Duration(millis) * time.Millisecond
+		}
+	}
+	return defaultValue
+}
@@ -50,7 +50,7 @@ func TestLoadDefaultsAndOverrides(t *testing.T) {
 	setEnv(t, "MYSQL_CONN_MAX_LIFETIME_MINUTES", "40")
 	setEnv(t, "RENEW_BEFORE_END_MINUTES", "30")
 	setEnv(t, "RENEWAL_RETRY_INTERVAL_MINUTES", "15")
-	setEnv(t, "MAX_RENEWAL_RETRY_AGE_MINUTES", "120")
+	setEnv(t, "DUNNING_SCHEDULE_MINUTES", "60, 120")
 	setEnv(t, "PENDING_PAYMENT_TIMEOUT_MINUTES", "5")
 
 	cfg, err := Load()
@@ -76,10 +76,333 @@ func TestLoadDefaultsAndOverrides(t *testing.T) {
 	if cfg.Subscriptions.RenewalRetryIntervalMinutes != 15*time.Minute {
 		t.Fatalf("unexpected retry interval: %v", cfg.Subscriptions.RenewalRetryIntervalMinutes)
 	}
-	if cfg.Subscriptions.MaxRenewalRetryAgeMinutes != 120*time.Minute {
-		t.Fatalf("unexpected max retry age: %v", cfg.Subscriptions.MaxRenewalRetryAgeMinutes)
+	if len(cfg.Subscriptions.DunningSchedule) != 2 || cfg.Subscriptions.DunningSchedule[0] != time.Hour || cfg.Subscriptions.DunningSchedule[1] != 2*time.Hour {
+		t.Fatalf("unexpected dunning schedule: %v", cfg.Subscriptions.DunningSchedule)
 	}
 	if cfg.Subscriptions.PendingPaymentTimeout != 5*time.Minute {
 		t.Fatalf("unexpected pending timeout: %v", cfg.Subscriptions.PendingPaymentTimeout)
 	}
 }
+
+func TestLoadPaymentDefaults(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	unsetEnv(t, "PAYMENT_PROVIDER")
+	unsetEnv(t, "PAYMENT_HTTP_TIMEOUT_SECONDS")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Payment.Provider != PaymentProviderStub {
+		t.Fatalf("expected stub provider by default, got %q", cfg.Payment.Provider)
+	}
+	if cfg.Payment.HTTPTimeout != 10*time.Second {
+		t.Fatalf("unexpected default payment timeout: %v", cfg.Payment.HTTPTimeout)
+	}
+}
+
+func TestLoadHTTPPaymentProvider(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	setEnv(t, "PAYMENT_PROVIDER", "http")
+	unsetEnv(t, "PAYMENT_HTTP_BASE_URL")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for missing PAYMENT_HTTP_BASE_URL")
+	}
+
+	setEnv(t, "PAYMENT_HTTP_BASE_URL", "https://payments.local/")
+	setEnv(t, "PAYMENT_HTTP_TIMEOUT_SECONDS", "3")
+	setEnv(t, "PAYMENT_HTTP_MAX_RETRIES", "4")
+	setEnv(t, "PAYMENT_HTTP_RETRY_BACKOFF_MS", "250")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Payment.HTTPBaseURL != "https://payments.local" {
+		t.Fatalf("expected trailing slash to be trimmed, got %q", cfg.Payment.HTTPBaseURL)
+	}
+	if cfg.Payment.HTTPTimeout != 3*time.Second || cfg.Payment.HTTPMaxRetries != 4 || cfg.Payment.HTTPRetryBackoff != 250*time.Millisecond {
+		t.Fatalf("unexpected payment config: %+v", cfg.Payment)
+	}
+}
+
+func TestLoadRejectsUnknownPaymentProvider(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	setEnv(t, "PAYMENT_PROVIDER", "paypal")
+
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unsupported payment provider")
+	}
+}
+
+func TestLoadPaymentWebhookSecrets(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	unsetEnv(t, "PAYMENT_PROVIDER")
+	setEnv(t, "PAYMENT_WEBHOOK_SECRETS", " Acme = secret-1 , other=secret-2")
+	setEnv(t, "PAYMENT_WEBHOOK_TOLERANCE_SECONDS", "60")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(cfg.Payment.WebhookSecrets) != 2 || cfg.Payment.WebhookSecrets["acme"] != "secret-1" || cfg.Payment.WebhookSecrets["other"] != "secret-2" {
+		t.Fatalf("unexpected webhook secrets: %+v", cfg.Payment.WebhookSecrets)
+	}
+	if cfg.Payment.WebhookTolerance != time.Minute {
+		t.Fatalf("unexpected webhook tolerance: %v", cfg.Payment.WebhookTolerance)
+	}
+
+	setEnv(t, "PAYMENT_WEBHOOK_SECRETS", "acme")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for malformed webhook secrets")
+	}
+}
+
+func TestLoadOutboxPublisher(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	unsetEnv(t, "OUTBOX_PUBLISHER")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Outbox.Publisher != OutboxPublisherLog || cfg.Outbox.MaxAttempts != 10 || cfg.Jobs.OutboxRelayInterval != 5*time.Second {
+		t.Fatalf("unexpected outbox defaults: %+v relay=%v", cfg.Outbox, cfg.Jobs.OutboxRelayInterval)
+	}
+
+	setEnv(t, "OUTBOX_PUBLISHER", "http")
+	unsetEnv(t, "OUTBOX_HTTP_URL")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for missing OUTBOX_HTTP_URL")
+	}
+
+	setEnv(t, "OUTBOX_PUBLISHER", "kafka")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unsupported outbox publisher")
+	}
+}
+
+func TestLoadWebhookConfig(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	unsetEnv(t, "WEBHOOK_ALLOW_INSECURE_URLS")
+	unsetEnv(t, "WEBHOOK_ALLOW_PRIVATE_URLS")
+	unsetEnv(t, "WEBHOOK_MAX_ATTEMPTS")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Webhooks.MaxAttempts != 8 || cfg.Webhooks.AllowInsecureURLs || cfg.Webhooks.AllowPrivateURLs || cfg.Jobs.WebhookDeliveryInterval != 5*time.Second {
+		t.Fatalf("unexpected webhook defaults: %+v delivery=%v", cfg.Webhooks, cfg.Jobs.WebhookDeliveryInterval)
+	}
+
+	setEnv(t, "WEBHOOK_ALLOW_INSECURE_URLS", "true")
+	setEnv(t, "WEBHOOK_ALLOW_PRIVATE_URLS", "true")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !cfg.Webhooks.AllowInsecureURLs || !cfg.Webhooks.AllowPrivateURLs {
+		t.Fatal("expected insecure and private webhook urls to be allowed")
+	}
+
+	setEnv(t, "WEBHOOK_MAX_ATTEMPTS", "0")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for non-positive WEBHOOK_MAX_ATTEMPTS")
+	}
+}
+
+func TestLoadAdminServices(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	unsetEnv(t, "APP_ADMIN_SERVICES")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(cfg.App.AdminServices) != 0 {
+		t.Fatalf("expected no admin services by default, got %v", cfg.App.AdminServices)
+	}
+
+	setEnv(t, "APP_ADMIN_SERVICES", " backoffice-service, ,billing-admin ")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(cfg.App.AdminServices) != 2 || cfg.App.AdminServices[0] != "backoffice-service" || cfg.App.AdminServices[1] != "billing-admin" {
+		t.Fatalf("unexpected admin services: %v", cfg.App.AdminServices)
+	}
+}
+
+func TestLoadDunningSchedule(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	unsetEnv(t, "DUNNING_SCHEDULE_MINUTES")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	expected := []time.Duration{24 * time.Hour, 72 * time.Hour, 168 * time.Hour}
+	if len(cfg.Subscriptions.DunningSchedule) != len(expected) {
+		t.Fatalf("unexpected default dunning schedule: %v", cfg.Subscriptions.DunningSchedule)
+	}
+	for i, value := range expected {
+		if cfg.Subscriptions.DunningSchedule[i] != value {
+			t.Fatalf("unexpected default dunning schedule: %v", cfg.Subscriptions.DunningSchedule)
+		}
+	}
+
+	for _, raw := range []string{"1440,abc", "0", "4320,1440", ","} {
+		setEnv(t, "DUNNING_SCHEDULE_MINUTES", raw)
+		if _, err := Load(); err == nil {
+			t.Fatalf("expected error for DUNNING_SCHEDULE_MINUTES=%q", raw)
+		}
+	}
+}
+
+func TestLoadBillingTimezone(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	unsetEnv(t, "BILLING_TIMEZONE")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Subscriptions.BillingLocation != time.UTC {
+		t.Fatalf("expected UTC by default, got %v", cfg.Subscriptions.BillingLocation)
+	}
+
+	setEnv(t, "BILLING_TIMEZONE", "Europe/Berlin")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Subscriptions.BillingLocation.String() != "Europe/Berlin" {
+		t.Fatalf("unexpected billing location: %v", cfg.Subscriptions.BillingLocation)
+	}
+
+	setEnv(t, "BILLING_TIMEZONE", "Mars/Olympus")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for unknown BILLING_TIMEZONE")
+	}
+}
+
+func TestLoadRenewalConcurrencyConfig(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	unsetEnv(t, "RENEWAL_CONCURRENCY")
+	unsetEnv(t, "RENEWAL_CHARGE_TIMEOUT_SECONDS")
+	unsetEnv(t, "PAYMENT_RATE_LIMIT_PER_SECOND")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Subscriptions.RenewalConcurrency != 4 || cfg.Subscriptions.RenewalChargeTimeout != 30*time.Second || cfg.Subscriptions.PaymentRateLimit != 10 {
+		t.Fatalf("unexpected renewal defaults: %+v", cfg.Subscriptions)
+	}
+
+	setEnv(t, "RENEWAL_CONCURRENCY", "8")
+	setEnv(t, "RENEWAL_CHARGE_TIMEOUT_SECONDS", "5")
+	setEnv(t, "PAYMENT_RATE_LIMIT_PER_SECOND", "0")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Subscriptions.RenewalConcurrency != 8 || cfg.Subscriptions.RenewalChargeTimeout != 5*time.Second || cfg.Subscriptions.PaymentRateLimit != 0 {
+		t.Fatalf("unexpected renewal config: %+v", cfg.Subscriptions)
+	}
+
+	setEnv(t, "RENEWAL_CONCURRENCY", "0")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for RENEWAL_CONCURRENCY=0")
+	}
+
+	setEnv(t, "RENEWAL_CONCURRENCY", "8")
+	setEnv(t, "PAYMENT_RATE_LIMIT_PER_SECOND", "-1")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for a negative PAYMENT_RATE_LIMIT_PER_SECOND")
+	}
+}
+
+func TestLoadClaimConfig(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	unsetEnv(t, "WORKER_ID")
+	unsetEnv(t, "CLAIM_TTL_MINUTES")
+	unsetEnv(t, "CLAIM_BATCH_SIZE")
+	unsetEnv(t, "BATCH_MAX_PER_RUN")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Subscriptions.WorkerID == "" {
+		t.Fatal("expected a default worker id")
+	}
+	if cfg.Subscriptions.ClaimTTL != 15*time.Minute || cfg.Subscriptions.ClaimBatchSize != 100 {
+		t.Fatalf("unexpected claim defaults: %v %d", cfg.Subscriptions.ClaimTTL, cfg.Subscriptions.ClaimBatchSize)
+	}
+	if cfg.Subscriptions.BatchMaxPerRun != 1000 {
+		t.Fatalf("expected default per-run cap 1000, got %d", cfg.Subscriptions.BatchMaxPerRun)
+	}
+
+	setEnv(t, "WORKER_ID", "renew-1")
+	setEnv(t, "CLAIM_TTL_MINUTES", "5")
+	setEnv(t, "CLAIM_BATCH_SIZE", "20")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Subscriptions.WorkerID != "renew-1" || cfg.Subscriptions.ClaimTTL != 5*time.Minute || cfg.Subscriptions.ClaimBatchSize != 20 {
+		t.Fatalf("unexpected claim config: %+v", cfg.Subscriptions)
+	}
+	if cfg.Outbox.WorkerID != "renew-1" || cfg.Outbox.ClaimTTL != 5*time.Minute {
+		t.Fatalf("expected the relay to share the lease config, got %+v", cfg.Outbox)
+	}
+	if cfg.Webhooks.WorkerID != "renew-1" || cfg.Webhooks.ClaimTTL != 5*time.Minute {
+		t.Fatalf("expected webhook delivery to share the lease config, got %+v", cfg.Webhooks)
+	}
+
+	setEnv(t, "CLAIM_BATCH_SIZE", "0")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for CLAIM_BATCH_SIZE=0")
+	}
+
+	setEnv(t, "CLAIM_BATCH_SIZE", "20")
+	setEnv(t, "BATCH_MAX_PER_RUN", "0")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for BATCH_MAX_PER_RUN=0")
+	}
+}
+
+func TestLoadSchedulerConfig(t *testing.T) {
+	setEnv(t, "MYSQL_DSN", "root:root@tcp(localhost:3306)/subscriptions?parseTime=true")
+	unsetEnv(t, "AUTO_RENEW_CRON")
+	unsetEnv(t, "SCHEDULER_JITTER_SECONDS")
+	unsetEnv(t, "SCHEDULER_LOCK_NAME")
+	unsetEnv(t, "SCHEDULER_LEADER_CHECK_SECONDS")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Jobs.AutoRenewCron != "" || cfg.Jobs.SchedulerJitter != 5*time.Second || cfg.Jobs.SchedulerLockName != "subscriptions-scheduler" || cfg.Jobs.SchedulerLeaderCheckInterval != 15*time.Second {
+		t.Fatalf("unexpected scheduler defaults: %+v", cfg.Jobs)
+	}
+
+	setEnv(t, "AUTO_RENEW_CRON", "*/5 * * * *")
+	setEnv(t, "SCHEDULER_JITTER_SECONDS", "0")
+	setEnv(t, "SCHEDULER_LOCK_NAME", "billing-scheduler")
+	cfg, err = Load()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if cfg.Jobs.AutoRenewCron != "*/5 * * * *" || cfg.Jobs.SchedulerJitter != 0 || cfg.Jobs.SchedulerLockName != "billing-scheduler" {
+		t.Fatalf("unexpected scheduler config: %+v", cfg.Jobs)
+	}
+
+	setEnv(t, "SCHEDULER_LEADER_CHECK_SECONDS", "0")
+	if _, err := Load(); err == nil {
+		t.Fatal("expected error for SCHEDULER_LEADER_CHECK_SECONDS=0")
+	}
+}
@@ -108,8 +108,15 @@ func TestMain(m *testing.M) {
 		_ = grpcServer.Serve(listener)
 	}()
 
+	stopPaymentMock, err := startPaymentProviderMock()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to start subscriptions payment provider mock: %v\n", err)
+		os.Exit(1)
+	}
+
 	exitCode := m.Run()
 
+	stopPaymentMock()
 	grpcServer.GracefulStop()
 	_ = listener.Close()
 
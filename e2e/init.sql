@@ -20,40 +20,188 @@ CREATE TABLE plan_types (
     description TEXT NULL,
     price_cents INT NOT NULL,
     currency VARCHAR(3) NOT NULL,
-    duration_days INT NOT NULL,
+    interval_unit VARCHAR(10) NOT NULL DEFAULT 'day',
+    interval_count INT NOT NULL,
+    trial_days INT NOT NULL DEFAULT 0,
     features JSON NULL,
+    status SMALLINT NOT NULL DEFAULT 10,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     CONSTRAINT fk_plan_types_subscription_type_id FOREIGN KEY (subscription_type_id) REFERENCES subscription_types(id),
-    UNIQUE INDEX idx_plan_types_subscription_type_id (subscription_type_id),
+    INDEX idx_plan_types_subscription_type_id (subscription_type_id),
     UNIQUE INDEX idx_plan_types_plan_code (plan_code)
 );
 
 CREATE TABLE subscriptions (
     id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
     subscription_type_id BIGINT UNSIGNED NOT NULL,
+    plan_type_id BIGINT UNSIGNED NULL,
+    pending_plan_type_id BIGINT UNSIGNED NULL,
     user_id VARCHAR(255) NULL,
     email VARCHAR(255) NULL,
     status SMALLINT NOT NULL DEFAULT 1,
     start_at DATETIME NULL,
     end_at DATETIME NULL,
     renew_at DATETIME NULL,
+    trial_end_at DATETIME NULL,
+    canceled_at DATETIME NULL,
+    cancel_reason VARCHAR(255) NULL,
+    paused_at DATETIME NULL,
+    resume_at DATETIME NULL,
+    renewal_retry_count INT NOT NULL DEFAULT 0,
+    billing_anchor_day TINYINT NOT NULL DEFAULT 0,
+    quantity INT NOT NULL DEFAULT 1,
     auto_renew TINYINT(1) NOT NULL DEFAULT 0,
+    version BIGINT NOT NULL DEFAULT 1,
+    claimed_by VARCHAR(255) NULL,
+    claimed_until DATETIME NULL,
     created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
     CONSTRAINT fk_subscriptions_subscription_type_id FOREIGN KEY (subscription_type_id) REFERENCES subscription_types(id),
+    CONSTRAINT fk_subscriptions_plan_type_id FOREIGN KEY (plan_type_id) REFERENCES plan_types(id),
+    CONSTRAINT fk_subscriptions_pending_plan_type_id FOREIGN KEY (pending_plan_type_id) REFERENCES plan_types(id),
+    INDEX idx_subscriptions_plan_type_id (plan_type_id),
+    INDEX idx_subscriptions_pending_plan_type_id (pending_plan_type_id),
     INDEX idx_subscriptions_user_id (user_id),
     INDEX idx_subscriptions_email (email),
     INDEX idx_subscriptions_status (status),
     INDEX idx_subscriptions_renew_at (renew_at),
     INDEX idx_subscriptions_end_at (end_at),
+    INDEX idx_subscriptions_resume_at (resume_at),
+    INDEX idx_subscriptions_created_at (created_at),
+    INDEX idx_subscriptions_claimed_by (claimed_by),
     UNIQUE INDEX idx_subscriptions_type_user_email (subscription_type_id, user_id, email)
 );
 
+CREATE TABLE payment_attempts (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    subscription_id BIGINT UNSIGNED NOT NULL,
+    plan_type_id BIGINT UNSIGNED NOT NULL,
+    kind VARCHAR(20) NOT NULL DEFAULT 'initial',
+    amount_cents INT NOT NULL,
+    currency VARCHAR(3) NOT NULL,
+    discount_cents INT NOT NULL DEFAULT 0,
+    quantity INT NOT NULL DEFAULT 1,
+    provider_transaction_id VARCHAR(255) NULL,
+    result_type VARCHAR(20) NULL,
+    status SMALLINT NOT NULL DEFAULT 1,
+    error TEXT NULL,
+    completed_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_payment_attempts_subscription_id FOREIGN KEY (subscription_id) REFERENCES subscriptions(id),
+    CONSTRAINT fk_payment_attempts_plan_type_id FOREIGN KEY (plan_type_id) REFERENCES plan_types(id),
+    INDEX idx_payment_attempts_subscription_id (subscription_id),
+    UNIQUE INDEX idx_payment_attempts_provider_transaction_id (provider_transaction_id)
+);
+
+CREATE TABLE coupons (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(50) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    percent_off INT NOT NULL DEFAULT 0,
+    amount_off_cents INT NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL DEFAULT '',
+    duration VARCHAR(10) NOT NULL,
+    duration_in_periods INT NOT NULL DEFAULT 0,
+    max_redemptions INT NOT NULL DEFAULT 0,
+    times_redeemed INT NOT NULL DEFAULT 0,
+    plan_type_ids VARCHAR(512) NOT NULL DEFAULT '',
+    expires_at DATETIME NULL,
+    status SMALLINT NOT NULL DEFAULT 10,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE INDEX idx_coupons_code (code)
+);
+
+CREATE TABLE coupon_redemptions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    coupon_id BIGINT UNSIGNED NOT NULL,
+    subscription_id BIGINT UNSIGNED NOT NULL,
+    duration_periods INT NOT NULL DEFAULT 0,
+    periods_applied INT NOT NULL DEFAULT 0,
+    status SMALLINT NOT NULL DEFAULT 10,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    CONSTRAINT fk_coupon_redemptions_coupon_id FOREIGN KEY (coupon_id) REFERENCES coupons(id),
+    CONSTRAINT fk_coupon_redemptions_subscription_id FOREIGN KEY (subscription_id) REFERENCES subscriptions(id),
+    UNIQUE INDEX idx_coupon_redemptions_coupon_subscription (coupon_id, subscription_id),
+    INDEX idx_coupon_redemptions_subscription_id_status (subscription_id, status)
+);
+
+CREATE TABLE subscription_events (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    subscription_id BIGINT UNSIGNED NOT NULL,
+    field VARCHAR(20) NOT NULL,
+    old_value VARCHAR(64) NULL,
+    new_value VARCHAR(64) NULL,
+    actor VARCHAR(255) NOT NULL,
+    request_id VARCHAR(255) NULL,
+    reason VARCHAR(64) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_subscription_events_subscription_id FOREIGN KEY (subscription_id) REFERENCES subscriptions(id),
+    INDEX idx_subscription_events_subscription_id (subscription_id)
+);
+
+CREATE TABLE outbox_messages (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    subscription_id BIGINT UNSIGNED NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSON NOT NULL,
+    status TINYINT NOT NULL DEFAULT 1,
+    attempts INT UNSIGNED NOT NULL DEFAULT 0,
+    last_error VARCHAR(1024) NULL,
+    next_attempt_at DATETIME NOT NULL,
+    published_at DATETIME NULL,
+    claimed_by VARCHAR(255) NULL,
+    claimed_until DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_outbox_messages_status_next_attempt_at (status, next_attempt_at),
+    INDEX idx_outbox_messages_subscription_id_status (subscription_id, status, id),
+    INDEX idx_outbox_messages_claimed_by (claimed_by)
+);
+
+CREATE TABLE webhook_endpoints (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    url VARCHAR(2048) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    event_types VARCHAR(512) NOT NULL DEFAULT '',
+    secret VARCHAR(128) NOT NULL,
+    status TINYINT NOT NULL DEFAULT 10,
+    created_by VARCHAR(128) NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_webhook_endpoints_status (status)
+);
+
+CREATE TABLE webhook_deliveries (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    webhook_endpoint_id BIGINT UNSIGNED NOT NULL,
+    event_id BIGINT UNSIGNED NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    subscription_id BIGINT UNSIGNED NOT NULL,
+    payload JSON NOT NULL,
+    status TINYINT NOT NULL DEFAULT 1,
+    attempts INT UNSIGNED NOT NULL DEFAULT 0,
+    response_status SMALLINT UNSIGNED NULL,
+    last_error VARCHAR(1024) NULL,
+    next_attempt_at DATETIME NOT NULL,
+    delivered_at DATETIME NULL,
+    claimed_by VARCHAR(255) NULL,
+    claimed_until DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_webhook_deliveries_endpoint_event (webhook_endpoint_id, event_id),
+    INDEX idx_webhook_deliveries_status_next_attempt_at (status, next_attempt_at),
+    INDEX idx_webhook_deliveries_claimed_by (claimed_by),
+    CONSTRAINT fk_webhook_deliveries_endpoint FOREIGN KEY (webhook_endpoint_id) REFERENCES webhook_endpoints (id) ON DELETE CASCADE
+);
+
 INSERT INTO subscription_types (id, type, display_name, status) VALUES
     (1, 'email', 'Marketing Newsletter', 10),
     (2, 'plan', 'Premium Plan', 10),
     (3, 'email', 'Legacy Inactive', 0);
 
-INSERT INTO plan_types (subscription_type_id, plan_code, display_name, description, price_cents, currency, duration_days, features) VALUES
-    (2, 'premium-monthly', 'Premium Monthly', 'Premium monthly plan', 1999, 'USD', 30, JSON_OBJECT('tier', 'premium'));
+INSERT INTO plan_types (subscription_type_id, plan_code, display_name, description, price_cents, currency, interval_unit, interval_count, features) VALUES
+    (2, 'premium-monthly', 'Premium Monthly', 'Premium monthly plan', 1999, 'USD', 'month', 1, JSON_OBJECT('tier', 'premium'));
@@ -0,0 +1,46 @@
+//go:build e2e
+// +build e2e
+
+package e2e
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/vibast-solutions/ms-go-subscriptions/app/payment"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/payment/paymenttest"
+)
+
+const subscriptionsPaymentMockAddr = "0.0.0.0:38084"
+
+// startPaymentProviderMock serves the fake payment provider on the host so the
+// dockerized service can reach it. The charge outcome is driven by the email
+// prefix: "redirect-" asks for a hosted checkout, "declined-" fails the charge.
+func startPaymentProviderMock() (func(), error) {
+	listener, err := net.Listen("tcp", subscriptionsPaymentMockAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := paymenttest.NewProvider()
+	provider.Outcome = func(req payment.ChargeRequest) string {
+		switch {
+		case strings.HasPrefix(req.Email, "redirect-"):
+			return payment.ChargeStatusRequiresPaymentMethod
+		case strings.HasPrefix(req.Email, "declined-"):
+			return payment.ChargeStatusFailed
+		default:
+			return payment.ChargeStatusSucceeded
+		}
+	}
+
+	server := &http.Server{Handler: provider}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+
+	return func() {
+		_ = server.Close()
+	}, nil
+}
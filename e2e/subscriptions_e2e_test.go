@@ -16,7 +16,9 @@ import (
 	"testing"
 	"time"
 
+	appmiddleware "github.com/vibast-solutions/ms-go-subscriptions/app/middleware"
 	"github.com/vibast-solutions/ms-go-subscriptions/app/types"
+	"github.com/vibast-solutions/ms-go-subscriptions/app/webhooksig"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
@@ -27,6 +29,9 @@ import (
 const (
 	defaultHTTPBase = "http://localhost:38080"
 	defaultGRPCAddr = "localhost:39090"
+
+	webhookProvider = "e2e-provider"
+	webhookSecret   = "e2e-webhook-secret"
 )
 
 type httpClient struct {
@@ -84,6 +89,37 @@ func (c *httpClient) doJSONWithAPIKey(t *testing.T, method, path string, body an
 	return resp, bodyBytes
 }
 
+func (c *httpClient) doSignedWebhook(t *testing.T, path string, body any, provider, secret string) (*http.Response, []byte) {
+	t.Helper()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("json marshal failed: %v", err)
+	}
+	req, err := http.NewRequest(http.MethodPost, c.baseURL+path, bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("new request failed: %v", err)
+	}
+	ts := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(appmiddleware.WebhookProviderHeader, provider)
+	req.Header.Set(appmiddleware.WebhookTimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(appmiddleware.WebhookSignatureHeader, webhooksig.Sign(secret, ts, data))
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		t.Fatalf("http request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read response failed: %v", err)
+	}
+
+	return resp, bodyBytes
+}
+
 func waitForHTTP(baseURL string, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
 	client := &http.Client{Timeout: 2 * time.Second}
@@ -175,9 +211,11 @@ func TestSubscriptionsE2E(t *testing.T) {
 	rawGRPCClient := types.NewSubscriptionsServiceClient(rawConn)
 
 	state := struct {
-		subscriptionID uint64
-		userID         string
-		email          string
+		subscriptionID        uint64
+		planSubscriptionID    uint64
+		pendingSubscriptionID uint64
+		userID                string
+		email                 string
 	}{
 		userID: fmt.Sprintf("sub-e2e-user-%d", time.Now().UnixNano()),
 		email:  fmt.Sprintf("sub-e2e-%d@example.com", time.Now().UnixNano()),
@@ -277,31 +315,14 @@ func TestSubscriptionsE2E(t *testing.T) {
 		}
 	})
 
-	t.Run("HTTPPaymentCallbackFailed", func(t *testing.T) {
+	t.Run("HTTPPaymentCallbackUnknownTransaction", func(t *testing.T) {
 		resp, body := client.doJSON(t, http.MethodPost, "/webhooks/payment-callback", map[string]any{
 			"subscription_id": state.subscriptionID,
 			"status":          "failed",
 			"transaction_id":  fmt.Sprintf("txn-%d", time.Now().UnixNano()),
 		})
-		if resp.StatusCode != http.StatusOK {
-			t.Fatalf("expected 200, got %d body=%s", resp.StatusCode, string(body))
-		}
-
-		resp, body = client.doJSON(t, http.MethodGet, "/subscriptions/"+strconv.FormatUint(state.subscriptionID, 10), nil)
-		if resp.StatusCode != http.StatusOK {
-			t.Fatalf("expected 200, got %d body=%s", resp.StatusCode, string(body))
-		}
-
-		var payload struct {
-			Subscription struct {
-				Status int32 `json:"status"`
-			} `json:"subscription"`
-		}
-		if err := json.Unmarshal(body, &payload); err != nil {
-			t.Fatalf("json unmarshal failed: %v", err)
-		}
-		if payload.Subscription.Status != 1 {
-			t.Fatalf("expected processing status=1 after failed callback, got %d", payload.Subscription.Status)
+		if resp.StatusCode != http.StatusNotFound {
+			t.Fatalf("expected 404 for an unknown transaction, got %d body=%s", resp.StatusCode, string(body))
 		}
 	})
 
@@ -329,16 +350,154 @@ func TestSubscriptionsE2E(t *testing.T) {
 		}
 	})
 
-	t.Run("HTTPPlanCreateFailsUntilPaymentsImplemented", func(t *testing.T) {
-		resp, _ := client.doJSON(t, http.MethodPost, "/subscriptions", map[string]any{
+	t.Run("HTTPPlanCreateChargesProvider", func(t *testing.T) {
+		resp, body := client.doJSON(t, http.MethodPost, "/subscriptions", map[string]any{
 			"subscription_type_id": 2,
 			"user_id":              state.userID + "-plan",
 			"email":                "plan-" + state.email,
 			"start_at":             time.Now().UTC().Format(time.RFC3339),
 			"auto_renew":           true,
 		})
-		if resp.StatusCode != http.StatusInternalServerError {
-			t.Fatalf("expected 500 for plan creation with payment stub, got %d", resp.StatusCode)
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201, got %d body=%s", resp.StatusCode, string(body))
+		}
+
+		var payload struct {
+			Subscription struct {
+				ID     uint64 `json:"id"`
+				Status int32  `json:"status"`
+			} `json:"subscription"`
+			PaymentURL string `json:"payment_url"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("json unmarshal failed: %v", err)
+		}
+		if payload.Subscription.Status != 10 || payload.PaymentURL != "" {
+			t.Fatalf("expected active subscription without payment_url, got body=%s", string(body))
+		}
+		state.planSubscriptionID = payload.Subscription.ID
+	})
+
+	t.Run("HTTPPlanCreateRedirectsToCheckout", func(t *testing.T) {
+		resp, body := client.doJSON(t, http.MethodPost, "/subscriptions", map[string]any{
+			"subscription_type_id": 2,
+			"user_id":              state.userID + "-redirect",
+			"email":                "redirect-" + state.email,
+			"start_at":             time.Now().UTC().Format(time.RFC3339),
+		})
+		if resp.StatusCode != http.StatusCreated {
+			t.Fatalf("expected 201, got %d body=%s", resp.StatusCode, string(body))
+		}
+
+		var payload struct {
+			Subscription struct {
+				ID     uint64 `json:"id"`
+				Status int32  `json:"status"`
+			} `json:"subscription"`
+			PaymentURL string `json:"payment_url"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("json unmarshal failed: %v", err)
+		}
+		if payload.Subscription.Status != 2 || payload.PaymentURL == "" {
+			t.Fatalf("expected pending payment with payment_url, got body=%s", string(body))
+		}
+		state.pendingSubscriptionID = payload.Subscription.ID
+	})
+
+	t.Run("GRPCListPaymentAttempts", func(t *testing.T) {
+		res, err := grpcClient.ListPaymentAttempts(context.Background(), &types.ListPaymentAttemptsRequest{SubscriptionId: state.planSubscriptionID})
+		if err != nil {
+			t.Fatalf("grpc list payment attempts failed: %v", err)
+		}
+		if len(res.GetPaymentAttempts()) != 1 {
+			t.Fatalf("expected one payment attempt, got %+v", res.GetPaymentAttempts())
+		}
+		attempt := res.GetPaymentAttempts()[0]
+		if attempt.GetStatus() != 10 || attempt.GetTransactionId() == "" || attempt.GetAmountCents() != 1999 || attempt.GetKind() != "initial" {
+			t.Fatalf("unexpected payment attempt: %+v", attempt)
+		}
+	})
+
+	t.Run("HTTPPaymentCallbackCompletesCheckout", func(t *testing.T) {
+		attemptsPath := "/subscriptions/" + strconv.FormatUint(state.pendingSubscriptionID, 10) + "/payment-attempts"
+		resp, body := client.doJSON(t, http.MethodGet, attemptsPath, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", resp.StatusCode, string(body))
+		}
+
+		var attempts struct {
+			PaymentAttempts []struct {
+				Status        int32  `json:"status"`
+				TransactionID string `json:"transaction_id"`
+			} `json:"payment_attempts"`
+		}
+		if err := json.Unmarshal(body, &attempts); err != nil {
+			t.Fatalf("json unmarshal failed: %v", err)
+		}
+		if len(attempts.PaymentAttempts) != 1 || attempts.PaymentAttempts[0].Status != 1 || attempts.PaymentAttempts[0].TransactionID == "" {
+			t.Fatalf("expected one pending attempt with a transaction id, got body=%s", string(body))
+		}
+
+		transactionID := attempts.PaymentAttempts[0].TransactionID
+		callback := map[string]any{
+			"subscription_id": state.pendingSubscriptionID,
+			"status":          "success",
+			"transaction_id":  transactionID,
+		}
+		resp, body = client.doSignedWebhook(t, "/webhooks/payment-callback", callback, webhookProvider, "wrong-secret")
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Fatalf("expected 401 for a bad signature, got %d body=%s", resp.StatusCode, string(body))
+		}
+
+		resp, body = client.doSignedWebhook(t, "/webhooks/payment-callback", callback, webhookProvider, webhookSecret)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200 for a signed callback, got %d body=%s", resp.StatusCode, string(body))
+		}
+
+		resp, body = client.doJSON(t, http.MethodPost, "/webhooks/payment-callback", map[string]any{
+			"subscription_id": state.pendingSubscriptionID,
+			"status":          "failed",
+			"transaction_id":  transactionID,
+		})
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200 for a repeated callback, got %d body=%s", resp.StatusCode, string(body))
+		}
+		var callbackResp struct {
+			AlreadyProcessed bool `json:"already_processed"`
+		}
+		if err := json.Unmarshal(body, &callbackResp); err != nil {
+			t.Fatalf("json unmarshal failed: %v", err)
+		}
+		if !callbackResp.AlreadyProcessed {
+			t.Fatalf("expected repeated callback to be reported as already processed, got body=%s", string(body))
+		}
+
+		resp, body = client.doJSON(t, http.MethodGet, attemptsPath, nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", resp.StatusCode, string(body))
+		}
+		if err := json.Unmarshal(body, &attempts); err != nil {
+			t.Fatalf("json unmarshal failed: %v", err)
+		}
+		if len(attempts.PaymentAttempts) != 1 || attempts.PaymentAttempts[0].Status != 10 {
+			t.Fatalf("expected the pending attempt to succeed, got body=%s", string(body))
+		}
+
+		resp, body = client.doJSON(t, http.MethodGet, "/subscriptions/"+strconv.FormatUint(state.pendingSubscriptionID, 10), nil)
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected 200, got %d body=%s", resp.StatusCode, string(body))
+		}
+		var payload struct {
+			Subscription struct {
+				Status int32 `json:"status"`
+			} `json:"subscription"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			t.Fatalf("json unmarshal failed: %v", err)
+		}
+		if payload.Subscription.Status != 10 {
+			t.Fatalf("expected active status after successful callback, got %d", payload.Subscription.Status)
 		}
 	})
 }